@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/config"
+	"bislericli/internal/format"
+	"bislericli/internal/i18n"
+	"bislericli/internal/store"
+)
+
+func runAccount(args []string) error {
+	if len(args) < 1 || isHelpToken(args[0]) {
+		printAccountUsage()
+		return nil
+	}
+	switch args[0] {
+	case "show":
+		return runAccountShow(args[1:])
+	case "set":
+		return runAccountSet(args[1:], "")
+	case "set-email":
+		return runAccountSet(args[1:], "set-email")
+	default:
+		fmt.Printf("Unknown account subcommand: %s\n", args[0])
+		printAccountUsage()
+		return nil
+	}
+}
+
+func printAccountUsage() {
+	fmt.Println("Usage: bislericli account <subcommand> [flags]")
+	fmt.Println("\nAvailable subcommands:")
+	fmt.Println("  show                 Show name, email, phone, default address and wallet")
+	fmt.Println("  set --name \"A B\"     Change the account name")
+	fmt.Println("  set --email <email>  Change the account email (same as 'set-email <email>')")
+}
+
+func accountClient(cfg config.GlobalConfig, profile store.Profile) (*bisleri.Client, error) {
+	if len(profile.Cookies) == 0 {
+		return nil, errors.New(i18n.T("no cookies in profile; run 'bislericli auth login'"))
+	}
+	jar, err := bisleri.JarFromCookies(profile.Cookies)
+	if err != nil {
+		return nil, err
+	}
+	client := bisleri.NewClient(&http.Client{Jar: jar, Timeout: 30 * time.Second}, log.New(profileRedactor(cfg, profile).Writer(os.Stderr), "bisleri: ", log.LstdFlags))
+	client.Cache = store.OpenPageCache(profile.Name)
+	return client, nil
+}
+
+func runAccountShow(args []string) error {
+	fs, profileName := parseScheduleFlags("account show")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, profilePath, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	client, err := accountClient(cfg, profile)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	acct, err := client.FetchAccount(ctx)
+	if err != nil {
+		if errors.Is(err, bisleri.ErrNotAuthenticated) {
+			return errors.New(i18n.T("session expired; please run 'bislericli auth login'"))
+		}
+		return withUpgradeHint(fmt.Errorf("failed to read account details: %w", err))
+	}
+	show := func(label, value string) {
+		if value == "" {
+			value = "-"
+		}
+		fmt.Println(format.KeyValue(label, value))
+	}
+	show("Name", acct.Name)
+	show("Email", acct.Email)
+	show("Phone", acct.Phone)
+	show("Default address", acct.DefaultAddress)
+	switch {
+	case acct.WalletBalance != "":
+		show("Wallet", "linked, balance "+acct.WalletBalance)
+	case acct.WalletLinked:
+		show("Wallet", "linked")
+	default:
+		show("Wallet", "not linked")
+	}
+	// Keep the profile's email current for 'auth login --method password'.
+	if acct.Email != "" && acct.Email != profile.Email {
+		if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+			p.Email = acct.Email
+			return nil
+		}); err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: failed to save email to profile:", err)
+		}
+	}
+	return nil
+}
+
+// runAccountSet edits the account name or email; with mode "set-email" the
+// email is the first argument.
+func runAccountSet(args []string, mode string) error {
+	cmdName := "account set"
+	if mode != "" {
+		cmdName = "account " + mode
+	}
+	fs, profileName := parseScheduleFlags(cmdName)
+	name := fs.String("name", "", "New account name (first and last)")
+	email := fs.String("email", "", "New account email")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if mode == "set-email" {
+		if fs.NArg() < 1 {
+			return errors.New("email required: account set-email <email>")
+		}
+		*email = fs.Arg(0)
+	}
+	var changes bisleri.AccountChanges
+	if *name != "" {
+		first, last, _ := strings.Cut(strings.TrimSpace(*name), " ")
+		changes.FirstName, changes.LastName = first, strings.TrimSpace(last)
+	}
+	if *email != "" {
+		if !strings.Contains(*email, "@") {
+			return fmt.Errorf("invalid email %q", *email)
+		}
+		changes.Email = strings.TrimSpace(*email)
+	}
+	if changes == (bisleri.AccountChanges{}) {
+		return errors.New("nothing to change: pass --name and/or --email")
+	}
+
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, profilePath, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	client, err := accountClient(cfg, profile)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	form, err := client.FetchProfileForm(ctx)
+	if err != nil {
+		if errors.Is(err, bisleri.ErrNotAuthenticated) {
+			return errors.New(i18n.T("session expired; please run 'bislericli auth login'"))
+		}
+		return withUpgradeHint(fmt.Errorf("failed to load the profile form: %w", err))
+	}
+	if bisleri.ProfileFormNeedsPassword(form) {
+		if changes.Password, err = readPassword("Account password (to confirm the change): "); err != nil {
+			return err
+		}
+	}
+	if err := client.UpdateAccount(ctx, form, changes); err != nil {
+		return err
+	}
+	if changes.Email != "" {
+		if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+			p.Email = changes.Email
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	fmt.Println("Account updated.")
+	return nil
+}
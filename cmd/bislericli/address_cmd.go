@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/config"
+	"bislericli/internal/i18n"
+	"bislericli/internal/store"
+)
+
+func runAddress(args []string) error {
+	if len(args) < 1 || isHelpToken(args[0]) {
+		printAddressUsage()
+		return nil
+	}
+	sub := args[0]
+	subArgs := args[1:]
+
+	switch sub {
+	case "list":
+		return runAddressList(subArgs)
+	case "add":
+		return runAddressAdd(subArgs)
+	case "remove":
+		return runAddressRemove(subArgs)
+	case "set-default":
+		return runAddressSetDefault(subArgs)
+	case "set-timeslot":
+		return runAddressSetTimeslot(subArgs)
+	default:
+		fmt.Printf("Unknown address subcommand: %s\n", sub)
+		printAddressUsage()
+		return nil
+	}
+}
+
+func printAddressUsage() {
+	fmt.Println("Usage: bislericli address <subcommand> [flags] [name]")
+	fmt.Println("\nAvailable subcommands:")
+	fmt.Println("  list                        List saved delivery addresses")
+	fmt.Println("  add <name>                  Save the profile's current address under a name")
+	fmt.Println("  add --pick <name>           Choose from the account's addresses on bisleri.com")
+	fmt.Println("  remove <name>               Delete a saved address")
+	fmt.Println("  set-default <name>          Use the address for orders by default")
+	fmt.Println("  set-timeslot <name> <slot>  Preferred delivery slot for the address (\"\" to clear)")
+	fmt.Println("\nPlace an order to a saved address with: bislericli order --address <name>")
+}
+
+func runAddressList(args []string) error {
+	fs, profileName := parseScheduleFlags("address list")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, _, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	if len(profile.Addresses) == 0 {
+		fmt.Println("No saved addresses. Add one with: bislericli address add <name>")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDEFAULT\tADDRESS\tCITY\tPINCODE\tTIMESLOT")
+	for _, a := range profile.Addresses {
+		def := ""
+		if strings.EqualFold(a.Name, profile.DefaultAddress) {
+			def = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", a.Name, def, dashIfEmpty(a.Address.Address1), dashIfEmpty(a.Address.City), dashIfEmpty(a.Address.PostalCode), dashIfEmpty(a.Timeslot))
+	}
+	return w.Flush()
+}
+
+func runAddressAdd(args []string) error {
+	fs, profileName := parseScheduleFlags("address add")
+	pick := fs.Bool("pick", false, "Choose from the addresses on the account instead of the current one")
+	timeslot := fs.String("timeslot", "", "Preferred delivery slot for this address")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("address name required: address add [--pick] <name>")
+	}
+	name := fs.Arg(0)
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, profilePath, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+
+	saved := store.SavedAddress{Name: name, Timeslot: strings.TrimSpace(*timeslot)}
+	if *pick {
+		candidate, err := pickAccountAddress(cfg, profile)
+		if err != nil {
+			return err
+		}
+		saved.ID = candidate.ID
+		saved.Address = candidate.Address
+	} else {
+		if profile.Address == nil || profile.AddressID == "" {
+			return errors.New("profile has no address yet; place an order first or use 'address add --pick <name>'")
+		}
+		saved.ID = profile.AddressID
+		saved.Address = *profile.Address
+	}
+
+	if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+		if err := p.SetAddress(saved); err != nil {
+			return err
+		}
+		if len(p.Addresses) == 1 && p.DefaultAddress == "" && !*pick {
+			p.DefaultAddress = saved.Name
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("Address %q saved.\n", name)
+	return nil
+}
+
+// pickAccountAddress lists the account's addresses from the checkout shipping
+// page and asks which one to save.
+func pickAccountAddress(cfg config.GlobalConfig, profile store.Profile) (bisleri.AddressCandidate, error) {
+	if len(profile.Cookies) == 0 {
+		return bisleri.AddressCandidate{}, errors.New(i18n.T("no cookies in profile; run 'bislericli auth login'"))
+	}
+	jar, err := bisleri.JarFromCookies(profile.Cookies)
+	if err != nil {
+		return bisleri.AddressCandidate{}, err
+	}
+	client := bisleri.NewClient(&http.Client{Jar: jar, Timeout: 30 * time.Second}, log.New(profileRedactor(cfg, profile).Writer(os.Stderr), "bisleri: ", log.LstdFlags))
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	if err := client.BeginCheckout(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: checkout init failed:", err)
+	}
+	shippingHTML, err := client.FetchShippingPage(ctx)
+	if err != nil {
+		return bisleri.AddressCandidate{}, fmt.Errorf("failed to load account addresses: %w", err)
+	}
+	candidates, err := bisleri.ParseAddressCandidates(shippingHTML)
+	if err != nil {
+		return bisleri.AddressCandidate{}, err
+	}
+	if len(candidates) == 0 {
+		return bisleri.AddressCandidate{}, errors.New("no addresses found on the account; add one on bisleri.com first")
+	}
+	// selectAddress would silently take the account default; always ask here.
+	for i := range candidates {
+		candidates[i].IsDefault = false
+	}
+	return selectAddress(candidates), nil
+}
+
+func runAddressRemove(args []string) error {
+	fs, profileName := parseScheduleFlags("address remove")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("address name required: address remove <name>")
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	_, profilePath, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+		return p.RemoveAddress(fs.Arg(0))
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("Address %q removed.\n", fs.Arg(0))
+	return nil
+}
+
+func runAddressSetDefault(args []string) error {
+	fs, profileName := parseScheduleFlags("address set-default")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("address name required: address set-default <name>")
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	_, profilePath, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+		return p.SetDefaultAddress(fs.Arg(0))
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("Orders will be delivered to %q by default.\n", fs.Arg(0))
+	return nil
+}
+
+func runAddressSetTimeslot(args []string) error {
+	fs, profileName := parseScheduleFlags("address set-timeslot")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() != 2 {
+		return errors.New("usage: address set-timeslot <name> \"08:00 AM - 02:00 PM\"")
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	_, profilePath, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	slot := strings.TrimSpace(fs.Arg(1))
+	if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+		a, ok := p.FindAddress(fs.Arg(0))
+		if !ok {
+			return fmt.Errorf("no saved address named %q", fs.Arg(0))
+		}
+		a.Timeslot = slot
+		return p.SetAddress(a)
+	}); err != nil {
+		return err
+	}
+	if slot == "" {
+		fmt.Printf("Address %q uses the default timeslot (%s).\n", fs.Arg(0), cfg.Defaults.Timeslot)
+	} else {
+		fmt.Printf("Address %q prefers timeslot %s.\n", fs.Arg(0), slot)
+	}
+	return nil
+}
+
+// orderTimeslot picks the delivery slot for an order: --timeslot, then the
+// slot saved with the address being delivered to, then the config default.
+func orderTimeslot(override string, profile store.Profile, addressName, fallback string) string {
+	if slot := strings.TrimSpace(override); slot != "" {
+		return slot
+	}
+	if addressName == "" {
+		addressName = profile.DefaultAddress
+	}
+	if addressName != "" {
+		if a, ok := profile.FindAddress(addressName); ok && a.Timeslot != "" {
+			return a.Timeslot
+		}
+	}
+	return fallback
+}
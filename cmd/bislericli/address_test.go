@@ -0,0 +1,70 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"bislericli/internal/store"
+)
+
+func TestOrderTimeslot(t *testing.T) {
+	const fallback = "08:00 AM - 02:00 PM"
+	profile := store.Profile{
+		DefaultAddress: "home",
+		Addresses: []store.SavedAddress{
+			{Name: "home", Timeslot: "02:00 PM - 08:00 PM"},
+			{Name: "Office"},
+		},
+	}
+	tests := []struct {
+		override, address, want string
+	}{
+		{"", "", "02:00 PM - 08:00 PM"},
+		{"", "office", fallback},
+		{"06:00 AM - 09:00 AM", "home", "06:00 AM - 09:00 AM"},
+		{"", "missing", fallback},
+	}
+	for _, tt := range tests {
+		if got := orderTimeslot(tt.override, profile, tt.address, fallback); got != tt.want {
+			t.Fatalf("orderTimeslot(%q, %q) = %q, want %q", tt.override, tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestSaveLoginSessionKeepsAddresses(t *testing.T) {
+	profilePath := filepath.Join(t.TempDir(), "home.json")
+	home := store.Address{FirstName: "Asha", Address1: "42 MG Road", City: "Bengaluru", PostalCode: "560001"}
+	before := store.Profile{
+		Name:           "home",
+		PhoneNumber:    "9876543210",
+		AddressID:      "addr-1",
+		Address:        &home,
+		Addresses:      []store.SavedAddress{{Name: "home", ID: "addr-1", Address: home}, {Name: "office", ID: "addr-2"}},
+		DefaultAddress: "home",
+		LastOrder:      &store.OrderInfo{OrderID: "BS-1001"},
+		Cookies:        []store.Cookie{{Name: "dwsid", Value: "old"}},
+	}
+	if err := store.SaveProfile(profilePath, before); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	got, err := saveLoginSession(profilePath, []store.Cookie{{Name: "dwsid", Value: "new"}}, "", "a@example.com", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := store.LoadProfile(profilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []store.Profile{got, saved} {
+		if len(p.Addresses) != 2 || p.DefaultAddress != "home" || p.Address == nil || p.Address.Address1 != "42 MG Road" ||
+			p.AddressID != "addr-1" || p.LastOrder == nil || p.LastOrder.OrderID != "BS-1001" {
+			t.Fatalf("login lost profile data: %+v", p)
+		}
+		if len(p.Cookies) != 1 || p.Cookies[0].Value != "new" || p.PhoneNumber != "9876543210" || p.Email != "a@example.com" || !p.LastLogin.Equal(now) {
+			t.Fatalf("login session not saved: %+v", p)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"bislericli/internal/debug"
+)
+
+func runDebugArtifacts(args []string) error {
+	if len(args) < 1 || isHelpToken(args[0]) {
+		printDebugArtifactsUsage()
+		return nil
+	}
+	switch args[0] {
+	case "list":
+		return runDebugArtifactsList(args[1:])
+	case "clean":
+		return runDebugArtifactsClean(args[1:])
+	default:
+		fmt.Printf("Unknown debug artifacts subcommand: %s\n", args[0])
+		printDebugArtifactsUsage()
+		return nil
+	}
+}
+
+func runDebugArtifactsList(args []string) error {
+	fs := flag.NewFlagSet("debug artifacts list", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	dir, err := debug.ArtifactsDir()
+	if err != nil {
+		return err
+	}
+	artifacts, err := debug.ListArtifacts()
+	if err != nil {
+		return err
+	}
+	if len(artifacts) == 0 {
+		fmt.Println("No debug artifacts in", dir)
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSIZE\tSAVED")
+	var total int64
+	for _, a := range artifacts {
+		total += a.Size
+		fmt.Fprintf(w, "%s\t%s\t%s\n", a.Name, formatBytes(a.Size), a.ModTime.Format("2006-01-02 15:04"))
+	}
+	w.Flush()
+	fmt.Printf("\n%d file(s), %s in %s\n", len(artifacts), formatBytes(total), dir)
+	return nil
+}
+
+func runDebugArtifactsClean(args []string) error {
+	fs := flag.NewFlagSet("debug artifacts clean", flag.ContinueOnError)
+	olderThan := fs.Duration("older-than", 0, "Only remove artifacts older than this (e.g. 168h); default removes all")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *olderThan < 0 {
+		return errors.New("--older-than must not be negative")
+	}
+	removed, err := debug.CleanArtifacts(*olderThan)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d debug artifact(s).\n", removed)
+	return nil
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+func printDebugArtifactsUsage() {
+	fmt.Println("Usage: bislericli debug artifacts <list|clean> [flags]")
+	fmt.Println("\nDebug page dumps are saved (redacted) when --debug is set and a page fails to parse.")
+	fmt.Println("The directory is capped at", formatBytes(debug.DefaultMaxArtifactBytes)+"; the oldest files are pruned first.")
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"bislericli/internal/backup"
+	"bislericli/internal/config"
+)
+
+const backupPassphraseEnv = "BISLERICLI_BACKUP_PASSPHRASE"
+
+func runBackup(args []string) error {
+	if len(args) < 1 || isHelpToken(args[0]) {
+		printBackupUsage()
+		return nil
+	}
+	switch args[0] {
+	case "create":
+		return runBackupCreate(args[1:])
+	case "restore":
+		return runBackupRestore(args[1:])
+	default:
+		fmt.Printf("Unknown backup subcommand: %s\n", args[0])
+		printBackupUsage()
+		return nil
+	}
+}
+
+func runBackupCreate(args []string) error {
+	fs := flag.NewFlagSet("backup create", flag.ContinueOnError)
+	output := fs.String("output", "", "Output file (default: bislericli-backup-<timestamp>.tar.gz)")
+	encrypt := fs.Bool("encrypt", false, "Encrypt with a passphrase (prompted, or $"+backupPassphraseEnv+")")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	dir, err := config.EnsureConfigDir()
+	if err != nil {
+		return err
+	}
+	passphrase := ""
+	if *encrypt {
+		passphrase, err = readBackupPassphrase(os.Stdin, os.Stderr, true)
+		if err != nil {
+			return err
+		}
+	}
+	dest := *output
+	if dest == "" {
+		ext := ".tar.gz"
+		if *encrypt {
+			ext = ".bak"
+		}
+		dest = "bislericli-backup-" + time.Now().Format("20060102-150405") + ext
+	}
+	dataDir, err := config.DataDir()
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	count, err := backup.Create(&buf, dir, dataDir, passphrase)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(dest, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+	fmt.Printf("Backed up %d file(s) from %s and %s to %s\n", count, dir, dataDir, dest)
+	if !*encrypt {
+		fmt.Println("Note: the backup contains session cookies; keep it private or use --encrypt.")
+	}
+	return nil
+}
+
+func runBackupRestore(args []string) error {
+	fs := flag.NewFlagSet("backup restore", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: bislericli backup restore <file>")
+	}
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	dir, err := config.EnsureConfigDir()
+	if err != nil {
+		return err
+	}
+	passphrase := ""
+	if backup.Encrypted(data) {
+		passphrase, err = readBackupPassphrase(os.Stdin, os.Stderr, false)
+		if err != nil {
+			return err
+		}
+	}
+	dataDir, err := config.DataDir()
+	if err != nil {
+		return err
+	}
+	restored, err := backup.Restore(data, dir, dataDir, passphrase)
+	if err != nil {
+		return err
+	}
+	for _, name := range restored {
+		fmt.Println("  restored", name)
+	}
+	fmt.Printf("Restored %d file(s) into %s and %s\n", len(restored), dir, dataDir)
+	return nil
+}
+
+// readBackupPassphrase uses $BISLERICLI_BACKUP_PASSPHRASE when set, otherwise
+// prompts (twice when creating, to catch typos).
+func readBackupPassphrase(input io.Reader, output io.Writer, confirm bool) (string, error) {
+	if pass := os.Getenv(backupPassphraseEnv); pass != "" {
+		return pass, nil
+	}
+	reader := bufio.NewReader(input)
+	prompt := func(label string) (string, error) {
+		fmt.Fprint(output, label)
+		line, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+	pass, err := prompt("Passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if pass == "" {
+		return "", errors.New("passphrase must not be empty")
+	}
+	if confirm {
+		again, err := prompt("Confirm passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != pass {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	return pass, nil
+}
+
+func printBackupUsage() {
+	fmt.Println("Usage: bislericli backup <subcommand> [flags]")
+	fmt.Println("\nAvailable subcommands:")
+	fmt.Println("  create [--output file] [--encrypt]  Archive profiles, order history and config")
+	fmt.Println("  restore <file>                      Restore an archive into the config directory")
+}
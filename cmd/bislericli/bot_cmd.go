@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/jobs"
+	"bislericli/internal/telegram"
+)
+
+// botPollWait is how long each Telegram long poll waits for messages.
+const botPollWait = 30 * time.Second
+
+const botHelp = `Commands:
+/order [jars] – place an order (default: the usual quantity)
+/status – last order, next scheduled run and jars left
+/balance – the last wallet balance seen
+/sync – refresh the order history`
+
+func runBot(args []string) error {
+	fs, profileName := parseScheduleFlags("bot")
+	token := fs.String("telegram-token", "", "Telegram bot token from @BotFather (default: telegram.token from config)")
+	fs.Usage = func() {
+		fmt.Println("Usage: bislericli bot [--telegram-token TOKEN] [--profile NAME]")
+		fmt.Println("\nRuns a Telegram bot that answers /order, /status, /balance and /sync from the")
+		fmt.Println("chats in telegram.allowedChats. Orders run as jobs with the same limits and")
+		fmt.Println("policy checks as 'serve'. Messages from other chats are ignored and their chat")
+		fmt.Println("ID is printed, so you can allow them.")
+		fmt.Println()
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	summary.begin("bot")
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	if *token == "" {
+		*token = cfg.Telegram.Token
+	}
+	if *token == "" {
+		return errors.New("no bot token: pass --telegram-token or set telegram.token (create a bot with @BotFather)")
+	}
+	allowed := map[int64]bool{}
+	for _, raw := range cfg.Telegram.AllowedChats {
+		id, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil {
+			return fmt.Errorf("telegram.allowedChats: %q is not a numeric chat ID", raw)
+		}
+		allowed[id] = true
+	}
+	q, err := jobs.Open()
+	if err != nil {
+		return err
+	}
+	b := &bot{
+		client:  telegram.NewClient(*token),
+		orders:  newServer(cfg, resolveProfileName(*profileName, cfg), q),
+		allowed: allowed,
+		pending: map[string]int64{},
+	}
+	summary.setProfile(b.orders.profile)
+
+	lc, end := startLifecycle()
+	defer end()
+	ctx := lc.Context()
+
+	messages := make(chan telegram.Message)
+	go b.poll(ctx, messages)
+	fmt.Printf("Telegram bot running for profile '%s' (%d allowed chat(s))\n", b.orders.profile, len(allowed))
+	if len(allowed) == 0 {
+		fmt.Println("No chats are allowed yet: message the bot, then add the chat ID it prints to telegram.allowedChats.")
+	}
+	for {
+		runQueuedJobs(ctx, b.orders.profile)
+		b.reportFinished(ctx)
+		select {
+		case <-ctx.Done():
+			fmt.Println("Bot stopped.")
+			return nil
+		case m := <-messages:
+			if text := b.reply(m); text != "" {
+				b.send(ctx, m.Chat.ID, text)
+			}
+		case <-b.orders.wake:
+		case <-time.After(jobPoll):
+		}
+	}
+}
+
+// bot answers chat commands. Orders and syncs become jobs; the chat that
+// asked is told when they finish.
+type bot struct {
+	client  *telegram.Client
+	orders  *server
+	allowed map[int64]bool
+	// pending maps unfinished job IDs to the chat to report them to.
+	pending map[string]int64
+}
+
+// poll long-polls Telegram for messages until ctx ends.
+func (b *bot) poll(ctx context.Context, out chan<- telegram.Message) {
+	var offset int64
+	for ctx.Err() == nil {
+		updates, err := b.client.Updates(ctx, offset, botPollWait)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			fmt.Fprintln(os.Stderr, "Warning:", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(10 * time.Second):
+			}
+			continue
+		}
+		for _, u := range updates {
+			offset = u.ID + 1
+			if u.Message == nil {
+				continue
+			}
+			select {
+			case out <- *u.Message:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (b *bot) send(ctx context.Context, chatID int64, text string) {
+	if err := b.client.Send(ctx, chatID, text); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to reply to chat %d: %v\n", chatID, err)
+	}
+}
+
+// reply handles one message and returns the answer; it is empty for chats
+// that are not allowed, which get no answer at all.
+func (b *bot) reply(m telegram.Message) string {
+	if !b.allowed[m.Chat.ID] {
+		fmt.Fprintf(os.Stderr, "Ignored message from chat %d (not in telegram.allowedChats)\n", m.Chat.ID)
+		return ""
+	}
+	cmd, args, ok := telegram.ParseCommand(m.Text)
+	if !ok {
+		return "Send /help for the commands I understand."
+	}
+	source := "telegram:" + strconv.FormatInt(m.Chat.ID, 10)
+	cfg := b.orders.cfg
+	switch cmd {
+	case "start", "help":
+		return botHelp
+	case "order":
+		req := orderRequest{}
+		if len(args) > 0 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				return "Usage: /order [jars], e.g. /order 2"
+			}
+			req.Qty = n
+		}
+		job, _, err := b.orders.enqueueOrder(req, source)
+		if err != nil {
+			return "Order not placed: " + err.Error()
+		}
+		b.pending[job.ID] = m.Chat.ID
+		qty := req.Qty
+		if qty == 0 {
+			qty = cfg.Defaults.OrderQuantity
+		}
+		return fmt.Sprintf("Ordering %d jar(s) (job %s). I'll tell you when it's done.", qty, job.ID)
+	case "sync":
+		job, err := b.orders.queue.Enqueue(jobs.Sync, b.orders.profile, []string{"--quiet"}, source)
+		if err != nil {
+			return "Sync not started: " + err.Error()
+		}
+		b.pending[job.ID] = m.Chat.ID
+		return fmt.Sprintf("Syncing order history (job %s).", job.ID)
+	case "status":
+		profile, _, err := loadOrCreateProfile(b.orders.profile)
+		if err != nil {
+			return "Status unavailable: " + err.Error()
+		}
+		return strings.Join([]string{
+			"Last order: " + lastOrderSummary(b.orders.profile, profile),
+			"Next run: " + nextRunSummary(cfg, profile),
+			"Jars left: " + jarsRemainingSummary(cfg, b.orders.profile),
+			"Wallet: " + lastSeenWalletBalance(b.orders.profile),
+		}, "\n")
+	case "balance":
+		if balance := lastSeenWalletBalance(b.orders.profile); balance != "-" {
+			return "Wallet: " + balance
+		}
+		return "No wallet balance seen yet; it is read when an order is placed."
+	default:
+		return "Unknown command /" + cmd + ". Send /help."
+	}
+}
+
+// reportFinished tells chats about their jobs that have finished.
+func (b *bot) reportFinished(ctx context.Context) {
+	for id, chatID := range b.pending {
+		job, err := b.orders.queue.Get(id)
+		if err != nil && !errors.Is(err, jobs.ErrNotFound) {
+			continue
+		}
+		if err == nil && !job.Done() {
+			continue
+		}
+		delete(b.pending, id)
+		b.send(ctx, chatID, jobOutcome(b.orders.profile, job, err))
+	}
+}
+
+// jobOutcome describes a finished job for a chat.
+func jobOutcome(profileName string, job jobs.Job, err error) string {
+	switch {
+	case err != nil:
+		return "Lost track of a job: " + err.Error()
+	case job.Status == jobs.Canceled:
+		return fmt.Sprintf("Job %s was canceled.", job.ID)
+	case job.Status == jobs.Failed:
+		return fmt.Sprintf("Job %s failed: %s", job.ID, job.Error)
+	}
+	profile, _, err := loadOrCreateProfile(profileName)
+	if err != nil {
+		return fmt.Sprintf("Job %s finished.", job.ID)
+	}
+	if job.Kind != jobs.PlaceOrder {
+		return "Sync finished. Last order: " + lastOrderSummary(profileName, profile)
+	}
+	if profile.LastOrder != nil && !profile.LastOrder.PlacedAt.Before(job.Started) {
+		return "Order placed: " + placedOrderSummary(*profile.LastOrder)
+	}
+	return "No order was placed; see 'bislericli jobs list' on the server."
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bislericli/internal/config"
+	"bislericli/internal/jobs"
+	"bislericli/internal/telegram"
+)
+
+func TestBotReply(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	cfg := config.GlobalConfig{
+		Defaults: config.Defaults{OrderQuantity: 2, MaxQuantity: 4, AdaptiveQuantity: true},
+		Serve:    config.Serve{WebhookDailyLimit: 1},
+	}
+	q := jobs.OpenAt(filepath.Join(t.TempDir(), "jobs.json"))
+	b := &bot{orders: newServer(cfg, "home", q), allowed: map[int64]bool{42: true}, pending: map[string]int64{}}
+	say := func(chat int64, text string) string {
+		return b.reply(telegram.Message{Chat: telegram.Chat{ID: chat}, Text: text})
+	}
+
+	if got := say(7, "/order 2"); got != "" {
+		t.Errorf("chat not allowed got a reply: %q", got)
+	}
+	if got := say(42, "/order two"); !strings.HasPrefix(got, "Usage:") {
+		t.Errorf("/order two = %q", got)
+	}
+	if got := say(42, "/order 9"); !strings.Contains(got, "maxQuantity") {
+		t.Errorf("/order above the maximum = %q", got)
+	}
+	if got := say(42, "/order@family_bot 3"); !strings.HasPrefix(got, "Ordering 3 jar(s)") {
+		t.Errorf("/order 3 = %q", got)
+	}
+	if got := say(42, "/order"); !strings.Contains(got, "rate limit") {
+		t.Errorf("second order over the daily limit = %q", got)
+	}
+	if got := say(42, "/balance"); !strings.HasPrefix(got, "No wallet balance") {
+		t.Errorf("/balance = %q", got)
+	}
+	if got := say(42, "/frobnicate"); !strings.HasPrefix(got, "Unknown command") {
+		t.Errorf("/frobnicate = %q", got)
+	}
+
+	all, err := q.List()
+	if err != nil || len(all) != 1 {
+		t.Fatalf("queue = %+v, %v; want one job", all, err)
+	}
+	if job := all[0]; job.Source != "telegram:42" || strings.Join(job.Args, " ") != "--unattended --lock-wait 10m --qty 3" || b.pending[job.ID] != 42 {
+		t.Errorf("job = %+v, pending = %v", job, b.pending)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"bislericli/internal/money"
+	"bislericli/internal/store"
+)
+
+// monthSpend sums synced order amounts that fall in the same calendar month as now.
+func monthSpend(orders []store.SavedOrder, now time.Time) money.Money {
+	var total money.Money
+	for _, o := range orders {
+		if o.ParsedDate.IsZero() {
+			continue
+		}
+		if o.ParsedDate.Year() == now.Year() && o.ParsedDate.Month() == now.Month() {
+			total += o.Amount
+		}
+	}
+	return total
+}
+
+// withUnsyncedOrder returns the synced orders plus the profile's last placed
+// order when no sync has picked it up yet.
+func withUnsyncedOrder(orders []store.SavedOrder, last *store.OrderInfo) []store.SavedOrder {
+	if last == nil || last.OrderID == "" || last.PlacedAt.IsZero() {
+		return orders
+	}
+	for _, o := range orders {
+		if o.OrderID == last.OrderID {
+			return orders
+		}
+	}
+	amount, _ := money.Parse(last.TotalPrice)
+	var items []string
+	for _, item := range last.Items {
+		items = append(items, fmt.Sprintf("%d x %s", item.Quantity, item.ProductID))
+	}
+	pending := store.SavedOrder{
+		OrderID:    last.OrderID,
+		Date:       last.PlacedAt.Format("02 Jan 2006"),
+		ParsedDate: last.PlacedAt,
+		Status:     "Placed",
+		Total:      last.TotalPrice,
+		Amount:     amount,
+		Items:      strings.Join(items, ", "),
+	}
+	return append(append([]store.SavedOrder(nil), orders...), pending)
+}
+
+// checkBudget compares the month's spend (synced orders plus any unsynced ones,
+// such as the profile's last order and earlier batches of a split order) and
+// the pending order total against the configured monthly budget. It returns an
+// error only when strict is set and the budget would be exceeded.
+func checkBudget(profileName string, unsynced []*store.OrderInfo, budget, orderTotal money.Money, strict bool) error {
+	if budget <= 0 {
+		return nil
+	}
+	var synced []store.SavedOrder
+	if history, err := store.LoadOrderHistory(profileName); err == nil {
+		synced = history.Orders
+	} else {
+		fmt.Fprintln(os.Stderr, "Warning: no synced history for budget check; run 'bislericli sync'")
+	}
+	for _, o := range unsynced {
+		synced = withUnsyncedOrder(synced, o)
+	}
+	spent := monthSpend(synced, time.Now())
+	projected := spent + orderTotal
+	if projected <= budget {
+		return nil
+	}
+	if strict {
+		return &budgetError{Projected: projected, Budget: budget, Spent: spent}
+	}
+	fmt.Fprintf(os.Stderr, "Warning: order would bring this month's spend to %s, over the %s budget (%s already spent)\n", projected, budget, spent)
+	return nil
+}
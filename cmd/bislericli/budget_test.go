@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"bislericli/internal/money"
+	"bislericli/internal/store"
+)
+
+func TestWithUnsyncedOrder(t *testing.T) {
+	rupees := func(s string) money.Money {
+		m, _ := money.Parse(s)
+		return m
+	}
+	placed := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	synced := []store.SavedOrder{{OrderID: "BS-1", ParsedDate: placed.AddDate(0, 0, -7), Amount: rupees("180")}}
+	last := &store.OrderInfo{
+		OrderID:    "BS-2",
+		PlacedAt:   placed,
+		TotalPrice: "₹200.00",
+		Items:      []store.OrderItem{{ProductID: "Bis-20LTR-Product", Quantity: 2}},
+	}
+
+	orders := withUnsyncedOrder(synced, last)
+	if len(orders) != 2 {
+		t.Fatalf("expected unsynced order to be appended, got %d orders", len(orders))
+	}
+	if got := orders[1]; got.Amount != rupees("200") || got.Items != "2 x Bis-20LTR-Product" || !got.ParsedDate.Equal(placed) {
+		t.Fatalf("unexpected pending order: %+v", got)
+	}
+	if got := monthSpend(orders, placed); got != rupees("380") {
+		t.Fatalf("monthSpend = %v, want 380", got)
+	}
+
+	last.OrderID = "BS-1"
+	if got := withUnsyncedOrder(synced, last); len(got) != 1 {
+		t.Fatalf("synced order should not be duplicated, got %d orders", len(got))
+	}
+}
+
+func TestCheckBudgetCountsEarlierBatches(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	budget, _ := money.Parse("500")
+	batchTotal, _ := money.Parse("200")
+	want, _ := money.Parse("600")
+	if err := store.SaveOrderHistory("home", nil); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	batch := func(id string) *store.OrderInfo {
+		return &store.OrderInfo{OrderID: id, PlacedAt: now, TotalPrice: "₹200.00"}
+	}
+
+	// A third batch of 200 after two placed ones would reach 600.
+	placed := []*store.OrderInfo{nil, batch("BS-1"), batch("BS-2")}
+	err := checkBudget("home", placed, budget, batchTotal, true)
+	var be *budgetError
+	if !errors.As(err, &be) || be.Projected != want {
+		t.Fatalf("expected budget error for third batch, got %v", err)
+	}
+	if err := checkBudget("home", placed[:2], budget, batchTotal, true); err != nil {
+		t.Fatalf("second batch is within budget: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/cookiejar"
+	"os"
+	"strings"
+	"time"
+
+	"bislericli/internal/address"
+	"bislericli/internal/bisleri"
+	"bislericli/internal/config"
+	"bislericli/internal/format"
+	"bislericli/internal/geocode"
+)
+
+func runCheck(args []string) error {
+	if len(args) < 1 || isHelpToken(args[0]) {
+		printCheckUsage()
+		return nil
+	}
+	switch args[0] {
+	case "pincode":
+		return runCheckPincode(args[1:])
+	default:
+		fmt.Printf("Unknown check subcommand: %s\n", args[0])
+		printCheckUsage()
+		return nil
+	}
+}
+
+func printCheckUsage() {
+	fmt.Println("Usage: bislericli check <subcommand> [flags]")
+	fmt.Println("\nAvailable subcommands:")
+	fmt.Println("  pincode <code>   Check whether 20L jars are delivered to a pincode")
+}
+
+func runCheckPincode(args []string) error {
+	fs := flag.NewFlagSet("check pincode", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("pincode required: check pincode <code>")
+	}
+	pin := strings.TrimSpace(fs.Arg(0))
+	if !address.ValidPincode(pin) {
+		return fmt.Errorf("invalid pincode %q: must be 6 digits", pin)
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(format.KeyValue("Pincode", pin))
+	if state, ok := address.StateForPincode(pin); ok {
+		fmt.Println(format.KeyValue("State", state))
+	}
+
+	// A fresh session keeps the check from changing any profile's location.
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return err
+	}
+	client := bisleri.NewClient(&http.Client{Jar: jar, Timeout: 30 * time.Second}, log.New(os.Stderr, "bisleri: ", log.LstdFlags))
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	result, err := client.CheckPincode(ctx, pin)
+	if err == nil {
+		if result.City != "" {
+			fmt.Println(format.KeyValue("City", result.City))
+		}
+		if result.Message != "" {
+			fmt.Println(format.KeyValue("Site says", result.Message))
+		}
+		if !result.Serviceable {
+			return fmt.Errorf("Bisleri does not deliver 20L jars to %s", pin)
+		}
+		fmt.Println(format.KeyValue("Delivery", format.Check()+" available"))
+		printSlotOptions(result.Timeslots)
+		return nil
+	}
+	fmt.Fprintln(os.Stderr, "Warning: pincode check unavailable, falling back to the city list:", err)
+	return checkPincodeByCity(ctx, cfg, client, pin)
+}
+
+// checkPincodeByCity answers from the list of served cities when the site's
+// pincode check fails; the pincode's city comes from geocoding when enabled.
+func checkPincodeByCity(ctx context.Context, cfg config.GlobalConfig, client *bisleri.Client, pin string) error {
+	options, err := client.FetchCityOptions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load the city list: %w", err)
+	}
+	if len(options) == 0 {
+		return withUpgradeHint(errors.New("could not find the city list on the site"))
+	}
+	if !cfg.Geocoding.Enabled {
+		fmt.Println(format.KeyValue("Served cities", strings.Join(options, ", ")))
+		fmt.Println("Enable geocoding in config.json to match the pincode to a city automatically.")
+		return nil
+	}
+	lookupCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	place, err := geocode.NewClient(cfg.Geocoding.Endpoint, cfg.Geocoding.Email).LookupPincode(lookupCtx, pin)
+	if err != nil || place.City == "" {
+		fmt.Println(format.KeyValue("Served cities", strings.Join(options, ", ")))
+		return fmt.Errorf("could not resolve the city for %s", pin)
+	}
+	fmt.Println(format.KeyValue("City", place.City))
+	city, ok := matchCityOption(place.City, options)
+	if !ok {
+		fmt.Println(format.KeyValue("Served cities", strings.Join(options, ", ")))
+		return fmt.Errorf("Bisleri does not list %s among its delivery cities", place.City)
+	}
+	fmt.Println(format.KeyValue("Delivery", format.Check()+" "+city+" is served (individual pincodes are confirmed at checkout)"))
+	return nil
+}
+
+func printSlotOptions(slots []bisleri.Timeslot) {
+	if len(slots) == 0 {
+		return
+	}
+	var labels []string
+	for _, s := range slots {
+		label := s.Label
+		if !s.Available {
+			label += " (full)"
+		}
+		labels = append(labels, label)
+	}
+	fmt.Println(format.KeyValue("Timeslots", strings.Join(labels, ", ")))
+}
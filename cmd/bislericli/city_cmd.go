@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/config"
+	"bislericli/internal/i18n"
+	"bislericli/internal/store"
+)
+
+func runCity(args []string) error {
+	if len(args) < 1 || isHelpToken(args[0]) {
+		printCityUsage()
+		return nil
+	}
+	switch args[0] {
+	case "list":
+		return runCityList(args[1:])
+	case "set":
+		return runCitySet(args[1:])
+	default:
+		fmt.Printf("Unknown city subcommand: %s\n", args[0])
+		printCityUsage()
+		return nil
+	}
+}
+
+func printCityUsage() {
+	fmt.Println("Usage: bislericli city <subcommand> [flags]")
+	fmt.Println("\nAvailable subcommands:")
+	fmt.Println("  list         List cities Bisleri currently delivers to")
+	fmt.Println("  set <name>   Change the delivery city (checked against the live list)")
+}
+
+// fetchCityOptions returns the site's serviceable cities, from the page
+// cache when it is fresh.
+func fetchCityOptions(ctx context.Context, cfg config.GlobalConfig, profile store.Profile) (*bisleri.Client, []string, error) {
+	if len(profile.Cookies) == 0 {
+		return nil, nil, errors.New(i18n.T("no cookies in profile; run 'bislericli auth login'"))
+	}
+	jar, err := bisleri.JarFromCookies(profile.Cookies)
+	if err != nil {
+		return nil, nil, err
+	}
+	client := bisleri.NewClient(&http.Client{Jar: jar, Timeout: 30 * time.Second}, log.New(profileRedactor(cfg, profile).Writer(os.Stderr), "bisleri: ", log.LstdFlags))
+	client.Cache = store.OpenPageCache(profile.Name)
+	options, err := client.FetchCityOptions(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load city options: %w", err)
+	}
+	if len(options) == 0 {
+		return nil, nil, withUpgradeHint(errors.New("could not find the city list on the site"))
+	}
+	return client, options, nil
+}
+
+func runCityList(args []string) error {
+	fs, profileName := parseScheduleFlags("city list")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, _, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	_, options, err := fetchCityOptions(ctx, cfg, profile)
+	if err != nil {
+		return err
+	}
+	for _, city := range options {
+		marker := "  "
+		if strings.EqualFold(city, profile.PreferredCity) {
+			marker = "* "
+		}
+		fmt.Println(marker + city)
+	}
+	return nil
+}
+
+func runCitySet(args []string) error {
+	fs, profileName := parseScheduleFlags("city set")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("city name required: city set <name>")
+	}
+	requested := strings.Join(fs.Args(), " ")
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, profilePath, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	client, options, err := fetchCityOptions(ctx, cfg, profile)
+	if err != nil {
+		return err
+	}
+	city, ok := matchCityOption(requested, options)
+	if !ok {
+		return fmt.Errorf("%q is not a serviceable city; available cities: %s", requested, strings.Join(options, ", "))
+	}
+	if err := client.SetCityLocation(ctx, city); err != nil {
+		return err
+	}
+	var cleared bool
+	if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+		cleared = applyCityChange(p, city)
+		return nil
+	}); err != nil {
+		return err
+	}
+	fmt.Println("Delivery city set to:", city)
+	if cleared {
+		fmt.Println("The saved address is in another city and was cleared; the next order will pick one from your account.")
+	}
+	return nil
+}
+
+// applyCityChange records the new preferred city and drops an active address
+// from a different city so the next order captures a fresh one. Named saved
+// addresses are kept, but a default in the old city is unset.
+func applyCityChange(p *store.Profile, city string) bool {
+	p.PreferredCity = city
+	if p.Address == nil || p.Address.City == "" {
+		return false
+	}
+	if _, same := matchCityOption(p.Address.City, []string{city}); same {
+		return false
+	}
+	p.Address = nil
+	p.AddressID = ""
+	p.AddressSource = ""
+	if def, ok := p.FindAddress(p.DefaultAddress); ok {
+		if _, same := matchCityOption(def.Address.City, []string{city}); !same {
+			p.DefaultAddress = ""
+		}
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	"bislericli/internal/store"
+)
+
+func TestApplyCityChange(t *testing.T) {
+	home := store.Address{Address1: "1 MG Road", City: "Bangalore"}
+	profile := store.Profile{
+		Address:        &home,
+		AddressID:      "addr-1",
+		DefaultAddress: "home",
+		Addresses:      []store.SavedAddress{{Name: "home", ID: "addr-1", Address: home}},
+	}
+
+	if applyCityChange(&profile, "Bengaluru") {
+		t.Fatalf("alias of the same city should keep the address")
+	}
+	if profile.PreferredCity != "Bengaluru" || profile.AddressID != "addr-1" {
+		t.Fatalf("unexpected profile after same-city change: %+v", profile)
+	}
+
+	if !applyCityChange(&profile, "Pune") {
+		t.Fatalf("expected address to be cleared on city change")
+	}
+	if profile.Address != nil || profile.AddressID != "" || profile.DefaultAddress != "" {
+		t.Fatalf("stale address kept after city change: %+v", profile)
+	}
+	if len(profile.Addresses) != 1 {
+		t.Fatalf("named addresses should be kept, got %d", len(profile.Addresses))
+	}
+}
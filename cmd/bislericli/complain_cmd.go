@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/config"
+	"bislericli/internal/format"
+	"bislericli/internal/store"
+)
+
+// complaintReasons are the values accepted by 'complain --reason', with the
+// subject line used for each.
+var complaintReasons = map[string]string{
+	"late":    "Late delivery",
+	"damaged": "Damaged jar",
+	"missing": "Missing items",
+	"other":   "Order issue",
+}
+
+// complaintOrder is what the draft says about the order, from the synced
+// history and the audit log.
+type complaintOrder struct {
+	OrderID   string
+	Placed    string
+	Total     string
+	Items     string
+	Status    string
+	Slot      string
+	ETA       string
+	Delivered string
+}
+
+func runComplain(args []string) error {
+	fs, profileName := parseScheduleFlags("complain")
+	reason := fs.String("reason", "late", "What went wrong: late, damaged, missing or other")
+	details := fs.String("details", "", "Extra details to add to the message")
+	submit := fs.Bool("submit", false, "File the complaint through the website's contact form when it has one")
+	yes := fs.Bool("yes", false, "Submit without asking for confirmation")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("order ID required: complain [--reason late|damaged|missing|other] <orderID>")
+	}
+	subject, ok := complaintReasons[*reason]
+	if !ok {
+		return fmt.Errorf("invalid --reason %q (use late, damaged, missing or other)", *reason)
+	}
+	orderID := strings.ToUpper(strings.TrimSpace(fs.Arg(0)))
+
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	name := resolveProfileName(*profileName, cfg)
+	profile, _, err := loadOrCreateProfile(name)
+	if err != nil {
+		return err
+	}
+	order, found := lookupComplaintOrder(name, profile, orderID)
+	if !found {
+		fmt.Fprintf(os.Stderr, "Warning: order %s is not in the local history; run 'bislericli sync' to include its details\n", orderID)
+	}
+	complaint := bisleri.Complaint{
+		OrderID: orderID,
+		Subject: fmt.Sprintf("%s - order %s", subject, orderID),
+		Message: draftComplaint(order, *reason, *details, profile),
+		Email:   profile.Email,
+		Phone:   profile.PhoneNumber,
+	}
+	if profile.Address != nil {
+		complaint.Name = strings.TrimSpace(profile.Address.FirstName + " " + profile.Address.LastName)
+		if complaint.Phone == "" {
+			complaint.Phone = profile.Address.Phone
+		}
+	}
+
+	var support bisleri.SupportPage
+	var client *bisleri.Client
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	if len(profile.Cookies) > 0 {
+		if client, err = accountClient(cfg, profile); err == nil {
+			support, err = client.FetchSupportPage(ctx)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: could not read the contact page:", err)
+		}
+	}
+
+	if *submit && support.Form != nil && client != nil {
+		printComplaint(complaint)
+		if !*yes {
+			fmt.Print("Submit this complaint to Bisleri customer care? [y/N]: ")
+			line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if answer := strings.ToLower(strings.TrimSpace(line)); answer != "y" && answer != "yes" {
+				fmt.Println("Not submitted.")
+				return nil
+			}
+		}
+		if err := client.SubmitComplaint(ctx, *support.Form, complaint); err != nil {
+			return withUpgradeHint(fmt.Errorf("failed to submit complaint: %w", err))
+		}
+		fmt.Println(format.Check(), "Complaint submitted for order", orderID)
+		return nil
+	}
+
+	printComplaint(complaint)
+	if *submit {
+		fmt.Println("\nThe website has no contact form this CLI can submit; send the message above instead.")
+	}
+	printSupportContacts(support)
+	return nil
+}
+
+// lookupComplaintOrder finds the order in the synced history, the last order
+// and the audit log.
+func lookupComplaintOrder(profileName string, profile store.Profile, orderID string) (complaintOrder, bool) {
+	order := complaintOrder{OrderID: orderID}
+	found := false
+	if history, err := store.LoadOrderHistory(profileName); err == nil {
+		for _, o := range withUnsyncedOrder(history.Orders, profile.LastOrder) {
+			if !strings.EqualFold(o.OrderID, orderID) {
+				continue
+			}
+			found = true
+			order.Placed = o.Date
+			if !o.ParsedDate.IsZero() {
+				order.Placed = o.ParsedDate.Format("02 Jan 2006")
+			}
+			order.Total, order.Items, order.Status = o.Total, o.Items, o.Status
+			if !o.DeliveredAt.IsZero() {
+				order.Delivered = o.DeliveredAt.Format("02 Jan 2006, 03:04 PM")
+			}
+		}
+	}
+	entries, _ := store.LoadAuditLog(profileName)
+	for _, e := range entries {
+		if e.Result == "success" && strings.EqualFold(e.OrderID, orderID) {
+			found = true
+			order.Slot, order.ETA = e.Timeslot, e.DeliveryETA
+			if order.Placed == "" {
+				order.Placed = e.Timestamp.Format("02 Jan 2006, 03:04 PM")
+			}
+			if order.Total == "" {
+				order.Total = e.Total
+			}
+		}
+	}
+	if last := profile.LastOrder; last != nil && strings.EqualFold(last.OrderID, orderID) {
+		found = true
+		if order.Slot == "" {
+			order.Slot = last.Timeslot
+		}
+		if order.ETA == "" {
+			order.ETA = last.DeliveryETA
+		}
+	}
+	return order, found
+}
+
+// draftComplaint writes the message body: what went wrong, then the order
+// details customer care asks for.
+func draftComplaint(o complaintOrder, reason, details string, profile store.Profile) string {
+	var b strings.Builder
+	b.WriteString("Hello Bisleri customer care,\n\n")
+	switch reason {
+	case "late":
+		fmt.Fprintf(&b, "My order %s was not delivered in the requested timeslot.", o.OrderID)
+	case "damaged":
+		fmt.Fprintf(&b, "A jar delivered with my order %s was damaged.", o.OrderID)
+	case "missing":
+		fmt.Fprintf(&b, "Items were missing from my order %s.", o.OrderID)
+	default:
+		fmt.Fprintf(&b, "I have a problem with my order %s.", o.OrderID)
+	}
+	if details = strings.TrimSpace(details); details != "" {
+		b.WriteString(" " + details)
+	}
+	b.WriteString("\n\nOrder details:\n")
+	line := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "- %s: %s\n", label, value)
+		}
+	}
+	line("Order ID", o.OrderID)
+	line("Placed", o.Placed)
+	line("Items", o.Items)
+	line("Total", o.Total)
+	line("Requested timeslot", o.Slot)
+	line("Promised delivery", o.ETA)
+	line("Delivered", o.Delivered)
+	line("Current status", o.Status)
+	if profile.Address != nil {
+		line("Delivery address", strings.Join(nonEmpty(profile.Address.Address1, profile.Address.Address2, profile.Address.City, profile.Address.PostalCode), ", "))
+	}
+	line("Registered phone", profile.PhoneNumber)
+	b.WriteString("\nPlease look into this and let me know the resolution.\n")
+	return b.String()
+}
+
+func printComplaint(c bisleri.Complaint) {
+	fmt.Println(format.KeyValue("Subject", c.Subject))
+	fmt.Println()
+	fmt.Println(c.Message)
+}
+
+func printSupportContacts(page bisleri.SupportPage) {
+	fmt.Println()
+	if len(page.Phones) > 0 {
+		fmt.Println(format.KeyValue("Customer care", strings.Join(page.Phones, ", ")))
+	}
+	if len(page.Emails) > 0 {
+		fmt.Println(format.KeyValue("Email", strings.Join(page.Emails, ", ")))
+	}
+	link := page.URL
+	if link == "" {
+		link = "https://www.bisleri.com/contact-us"
+	}
+	fmt.Println(format.KeyValue("Contact page", link))
+}
+
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
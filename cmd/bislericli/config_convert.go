@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"bislericli/internal/config"
+	"bislericli/internal/fileutil"
+)
+
+// runConfigConvert rewrites the config file in another format. The old file
+// is kept as <name>.bak so it no longer shadows or is shadowed by the new one.
+func runConfigConvert(args []string) error {
+	fs := flag.NewFlagSet("config convert", flag.ContinueOnError)
+	to := fs.String("to", "", "Target format: yaml, toml or json")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	format := strings.ToLower(strings.TrimSpace(*to))
+	if format == "yml" {
+		format = "yaml"
+	}
+	switch format {
+	case "yaml", "toml", "json":
+	default:
+		return fmt.Errorf("invalid --to %q (want yaml, toml or json)", *to)
+	}
+	// Loading first creates a default config when there is none, and checks
+	// the current file decodes.
+	if _, err := config.LoadGlobalConfig(); err != nil {
+		return err
+	}
+	from, err := config.ConfigFilePath()
+	if err != nil {
+		return err
+	}
+	if config.FormatOf(from) == format {
+		fmt.Println("Config is already", format+":", from)
+		return nil
+	}
+	data, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	if data, err = config.ToJSON(from, data); err != nil {
+		return err
+	}
+	out, err := config.FromJSON(format, data)
+	if err != nil {
+		return err
+	}
+	dest := filepath.Join(filepath.Dir(from), "config."+format)
+	if err := fileutil.WriteAtomic(dest, out, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(from, from+fileutil.BackupSuffix); err != nil {
+		return err
+	}
+	fmt.Printf("Converted %s to %s (old file kept as %s).\n", filepath.Base(from), dest, filepath.Base(from)+fileutil.BackupSuffix)
+	if format != "json" {
+		fmt.Println("Add comments freely; bislericli does not rewrite this file.")
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"bislericli/internal/address"
+	"bislericli/internal/apitoken"
+	"bislericli/internal/config"
+	"bislericli/internal/notify"
+	"bislericli/internal/schedule"
+)
+
+// configProblem is one issue found by config validate; Key is the dotted
+// path of the setting in config.json.
+type configProblem struct {
+	Key    string
+	Detail string
+}
+
+// timeslotPattern matches slot labels as the site shows them, e.g.
+// "08:00 AM - 02:00 PM".
+var timeslotPattern = regexp.MustCompile(`(?i)^\d{1,2}:\d{2}\s*[AP]M\s*-\s*\d{1,2}:\d{2}\s*[AP]M$`)
+
+// e164Digits matches a phone number in international form without the "+".
+var e164Digits = regexp.MustCompile(`^[1-9]\d{7,14}$`)
+
+func runConfigValidate(args []string) error {
+	if len(args) > 0 && isHelpToken(args[0]) {
+		fmt.Println("Usage: bislericli config validate")
+		fmt.Println("\nChecks the config file for unknown keys, schedule and timeslot syntax, URLs and other values.")
+		fmt.Println("Set \"strict\": true in the config to refuse to load it with unknown keys.")
+		return nil
+	}
+	path, err := config.ConfigFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Println("No config file yet; built-in defaults apply.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if data, err = config.ToJSON(path, data); err != nil {
+		return err
+	}
+	cfg, err := config.Decode(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := config.ApplyEnv(&cfg); err != nil {
+		return err
+	}
+	if overrides := config.EnvOverrides(); len(overrides) > 0 {
+		fmt.Println("Environment overrides:", strings.Join(overrides, ", "))
+	}
+	unknown, err := config.UnknownKeys(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	var problems []configProblem
+	for _, k := range unknown {
+		detail := "unknown key (ignored)"
+		if k.Suggestion != "" {
+			detail = fmt.Sprintf("unknown key (did you mean %s?)", k.Suggestion)
+		}
+		problems = append(problems, configProblem{Key: k.Path, Detail: detail})
+	}
+	problems = append(problems, validateConfig(cfg)...)
+	if len(problems) == 0 {
+		fmt.Println(path, "is valid.")
+		return nil
+	}
+	for _, p := range problems {
+		fmt.Printf("  %s: %s\n", p.Key, p.Detail)
+	}
+	return fmt.Errorf("%s has %d problem(s)", path, len(problems))
+}
+
+// validateConfig checks setting values that decode cleanly but would fail
+// later, such as a schedule the scheduler cannot parse.
+func validateConfig(cfg config.GlobalConfig) []configProblem {
+	var problems []configProblem
+	add := func(key, format string, args ...any) {
+		problems = append(problems, configProblem{Key: key, Detail: fmt.Sprintf(format, args...)})
+	}
+	d := cfg.Defaults
+	if _, err := schedule.Parse(d.Schedule, d.ScheduleTime); err != nil {
+		add("defaults.schedule", "%v", err)
+	}
+	if _, err := schedule.ParseBlackout(cfg.Blackout.Weekdays, cfg.Blackout.Dates); err != nil {
+		add("blackout", "%v", err)
+	}
+	if !timeslotPattern.MatchString(d.Timeslot) {
+		add("defaults.timeslot", "%q is not a slot like \"08:00 AM - 02:00 PM\"", d.Timeslot)
+	}
+	for _, slot := range cfg.Policy.AllowedTimeslots {
+		if !timeslotPattern.MatchString(slot) {
+			add("policy.allowedTimeslots", "%q is not a slot like \"08:00 AM - 02:00 PM\"", slot)
+		}
+	}
+	if d.OrderQuantity < 0 {
+		add("defaults.orderQuantity", "must be positive, got %d", d.OrderQuantity)
+	}
+	if d.ReturnJars > d.OrderQuantity {
+		add("defaults.returnJars", "%d exceeds orderQuantity %d", d.ReturnJars, d.OrderQuantity)
+	}
+	if d.MinQuantity > d.MaxQuantity {
+		add("defaults.minQuantity", "%d exceeds maxQuantity %d", d.MinQuantity, d.MaxQuantity)
+	}
+	if d.MaxPerLine < 0 {
+		add("defaults.maxPerLine", "must not be negative, got %d", d.MaxPerLine)
+	}
+	switch d.BulkSplit {
+	case "", "orders", "off":
+	default:
+		add("defaults.bulkSplit", "%q is not orders or off", d.BulkSplit)
+	}
+	switch cfg.Notifications.Digest {
+	case "", "week", "month":
+	default:
+		add("notifications.digest", "%q is not week or month", cfg.Notifications.Digest)
+	}
+	if wa := cfg.Notifications.WhatsApp; wa.Token != "" || wa.PhoneNumberID != "" || len(wa.To) > 0 {
+		if wa.Token == "" || wa.PhoneNumberID == "" || len(wa.To) == 0 {
+			add("notifications.whatsapp", "set token, phoneNumberId and to together")
+		}
+		for _, to := range wa.To {
+			if !e164Digits.MatchString(to) {
+				add("notifications.whatsapp.to", "%q is not a number in international form, digits only (e.g. 919876543210)", to)
+			}
+		}
+		if wa.Endpoint != "" {
+			if err := checkEndpointURL(wa.Endpoint); err != nil {
+				add("notifications.whatsapp.endpoint", "%v", err)
+			}
+		}
+	}
+	if sig := cfg.Notifications.Signal; sig.Account != "" || sig.Group != "" || len(sig.To) > 0 {
+		if sig.Account == "" {
+			add("notifications.signal.account", "required to send with signal-cli")
+		}
+		if sig.Group == "" && len(sig.To) == 0 {
+			add("notifications.signal", "set a group or at least one number in to")
+		}
+	}
+	for event, t := range cfg.Notifications.Templates {
+		if !slices.Contains(notify.Events, event) {
+			add("notifications.templates."+event, "unknown event (want one of %s)", strings.Join(notify.Events, ", "))
+			continue
+		}
+		if err := (notify.Template{Title: t.Title, Body: t.Body}).Check(); err != nil {
+			add("notifications.templates."+event, "%v", err)
+		}
+	}
+	switch strings.ToLower(cfg.Language) {
+	case "", "en", "hi":
+	default:
+		add("language", "%q is not en or hi", cfg.Language)
+	}
+	if _, err := config.Location(cfg.Timezone); err != nil {
+		add("timezone", "%v", err)
+	}
+	if cfg.Geocoding.Endpoint != "" {
+		if err := checkEndpointURL(cfg.Geocoding.Endpoint); err != nil {
+			add("geocoding.endpoint", "%v", err)
+		}
+	}
+	if cfg.Fingerprints.Endpoint != "" {
+		if err := checkEndpointURL(cfg.Fingerprints.Endpoint); err != nil {
+			add("fingerprints.endpoint", "%v", err)
+		}
+	} else if cfg.Fingerprints.Submit {
+		add("fingerprints.endpoint", "required when submit is set")
+	}
+	if cfg.Serve.Listen != "" {
+		if _, _, err := net.SplitHostPort(cfg.Serve.Listen); err != nil {
+			add("serve.listen", "%v", err)
+		}
+	}
+	for source, secret := range cfg.Serve.WebhookSecrets {
+		if len(secret) < minWebhookSecret {
+			add("serve.webhookSecrets", "secret for %q is shorter than %d characters", source, minWebhookSecret)
+		}
+	}
+	if cfg.OTPRelay.Listen != "" {
+		if _, _, err := net.SplitHostPort(cfg.OTPRelay.Listen); err != nil {
+			add("otpRelay.listen", "%v", err)
+		}
+	}
+	if cfg.OTPRelay.Secret != "" && len(cfg.OTPRelay.Secret) < minWebhookSecret {
+		add("otpRelay.secret", "shorter than %d characters", minWebhookSecret)
+	}
+	for _, id := range cfg.Telegram.AllowedChats {
+		if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+			add("telegram.allowedChats", "%q is not a numeric chat ID", id)
+		}
+	}
+	for name, t := range cfg.Serve.Tokens {
+		if len(t.Hash) != 64 {
+			add("serve.tokens."+name, "hash is incomplete; revoke the token and create it again")
+		}
+		if _, err := apitoken.ParseScopes(strings.Join(t.Scopes, ",")); err != nil {
+			add("serve.tokens."+name, "%v", err)
+		}
+	}
+	if cfg.Serve.WebhookMaxAge < 0 {
+		add("serve.webhookMaxAge", "must not be negative, got %d", cfg.Serve.WebhookMaxAge)
+	}
+	if cfg.Serve.WebhookDailyLimit < 0 {
+		add("serve.webhookDailyLimit", "must not be negative, got %d", cfg.Serve.WebhookDailyLimit)
+	}
+	if cfg.Serve.MaxQuantity < 0 {
+		add("serve.maxQuantity", "must not be negative, got %d", cfg.Serve.MaxQuantity)
+	}
+	if cfg.Inventory.DailyJars < 0 {
+		add("inventory.dailyJars", "must not be negative, got %g", cfg.Inventory.DailyJars)
+	}
+	if cfg.Inventory.ReorderAt < 0 {
+		add("inventory.reorderAt", "must not be negative, got %g", cfg.Inventory.ReorderAt)
+	}
+	switch cfg.Weather.Action {
+	case "", "suggest", "bump":
+	default:
+		add("weather.action", "%q is not suggest or bump", cfg.Weather.Action)
+	}
+	if cfg.Weather.Endpoint != "" {
+		if err := checkEndpointURL(cfg.Weather.Endpoint); err != nil {
+			add("weather.endpoint", "%v", err)
+		}
+	}
+	if cfg.Weather.Days < 0 || cfg.Weather.Days > 16 {
+		add("weather.days", "must be between 1 and 16, got %d", cfg.Weather.Days)
+	}
+	if cfg.Weather.ExtraJars < 0 {
+		add("weather.extraJars", "must not be negative, got %d", cfg.Weather.ExtraJars)
+	}
+	if (cfg.Weather.Latitude == "") != (cfg.Weather.Longitude == "") {
+		add("weather.latitude", "set both latitude and longitude, or neither")
+	}
+	switch cfg.Holidays.Action {
+	case "", "warn", "shift":
+	default:
+		add("holidays.action", "%q is not warn or shift", cfg.Holidays.Action)
+	}
+	if cfg.Holidays.State != "" {
+		if _, ok := address.NormalizeState(cfg.Holidays.State); !ok {
+			add("holidays.state", "unrecognized state %q", cfg.Holidays.State)
+		}
+	}
+	if cfg.Holidays.URL != "" {
+		if err := checkEndpointURL(cfg.Holidays.URL); err != nil {
+			add("holidays.url", "%v", err)
+		}
+	}
+	if cfg.Sheets.SpreadsheetID != "" {
+		if cfg.Sheets.CredentialsFile == "" {
+			add("sheets.credentialsFile", "required when spreadsheetId is set")
+		} else if _, err := os.Stat(cfg.Sheets.CredentialsFile); err != nil {
+			add("sheets.credentialsFile", "%v", err)
+		}
+	}
+	for _, pattern := range cfg.Redaction.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			add("redaction.patterns", "%v", err)
+		}
+	}
+	switch cfg.Client.Rotate {
+	case "", "session", "request":
+	default:
+		add("client.rotate", "%q is not session or request", cfg.Client.Rotate)
+	}
+	for _, agent := range cfg.Client.UserAgents {
+		if strings.TrimSpace(agent) == "" {
+			add("client.userAgents", "empty user agent")
+		}
+	}
+	for name := range cfg.Client.Headers {
+		switch strings.ToLower(name) {
+		case "cookie", "host", "content-length", "content-type":
+			add("client.headers", "%s is set per request and cannot be configured", name)
+		}
+	}
+	for _, h := range []struct {
+		key      string
+		commands []string
+	}{
+		{"hooks.preOrder", cfg.Hooks.PreOrder},
+		{"hooks.postOrderSuccess", cfg.Hooks.PostOrderSuccess},
+		{"hooks.postOrderFailure", cfg.Hooks.PostOrderFailure},
+		{"hooks.postSync", cfg.Hooks.PostSync},
+		{"notifications.commands", cfg.Notifications.Commands},
+	} {
+		for _, command := range h.commands {
+			if strings.TrimSpace(command) == "" {
+				add(h.key, "empty command")
+			}
+		}
+	}
+	return problems
+}
+
+// checkEndpointURL requires an absolute http(s) URL.
+func checkEndpointURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" && u.Scheme != "http" || u.Host == "" {
+		return fmt.Errorf("%q is not an http(s) URL", raw)
+	}
+	return nil
+}
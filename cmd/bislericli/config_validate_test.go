@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"bislericli/internal/config"
+)
+
+func TestValidateConfig(t *testing.T) {
+	if problems := validateConfig(config.DefaultConfig()); len(problems) != 0 {
+		t.Fatalf("default config should be valid, got %+v", problems)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Defaults.Schedule = "fortnightly"
+	cfg.Defaults.Timeslot = "morning"
+	cfg.Notifications.Digest = "weekly"
+	cfg.Geocoding.Endpoint = "nominatim.example.com/search"
+	cfg.Hooks.PostSync = []string{" "}
+	cfg.Notifications.Templates = map[string]config.Template{
+		"orderPlaced":  {Body: "{{.Order.ID"},
+		"orderShipped": {Body: "{{.Order.ID}}"},
+		"orderStatus":  {Title: "{{.Order.ID}} {{.Order.Status}}"},
+	}
+	got := map[string]bool{}
+	for _, p := range validateConfig(cfg) {
+		got[p.Key] = true
+	}
+	for _, key := range []string{"defaults.schedule", "defaults.timeslot", "notifications.digest", "geocoding.endpoint", "hooks.postSync", "notifications.templates.orderPlaced", "notifications.templates.orderShipped"} {
+		if !got[key] {
+			t.Errorf("expected a problem for %s, got %v", key, got)
+		}
+	}
+	if len(got) != 7 {
+		t.Fatalf("unexpected problems: %v", got)
+	}
+}
+
+func TestClientHeaderOptions(t *testing.T) {
+	c := config.Client{UserAgents: []string{"a", "b", "c"}, AcceptLanguage: "en-IN"}
+	o := clientHeaderOptions(c, func(n int) int { return n - 1 })
+	if o.UserAgent != "c" || o.UserAgents != nil || o.AcceptLanguage != "en-IN" {
+		t.Fatalf("session rotation = %+v", o)
+	}
+	c.Rotate = "request"
+	if o := clientHeaderOptions(c, nil); len(o.UserAgents) != 3 || o.UserAgent != "" {
+		t.Fatalf("request rotation = %+v", o)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/config"
+	"bislericli/internal/store"
+)
+
+func runDebugCookies(args []string) error {
+	if len(args) > 0 && isHelpToken(args[0]) {
+		printDebugCookiesUsage()
+		return nil
+	}
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runDebugCookiesList(args)
+	}
+	switch args[0] {
+	case "list":
+		return runDebugCookiesList(args[1:])
+	case "prune":
+		return runDebugCookiesPrune(args[1:])
+	default:
+		fmt.Printf("Unknown debug cookies subcommand: %s\n", args[0])
+		printDebugCookiesUsage()
+		return nil
+	}
+}
+
+func printDebugCookiesUsage() {
+	fmt.Println("Usage: bislericli debug cookies [list|prune] [flags]")
+	fmt.Println("\nAvailable subcommands:")
+	fmt.Println("  list    Show saved cookies with domain, expiry and likely purpose (default)")
+	fmt.Println("  prune   Remove expired and tracking cookies from the profile")
+	fmt.Println("\nPrune flags: --expired-only keeps tracking cookies, --dry-run only lists what would go.")
+}
+
+func runDebugCookiesList(args []string) error {
+	fs, profileName := parseScheduleFlags("debug cookies list")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, _, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	if len(profile.Cookies) == 0 {
+		fmt.Println("No cookies saved. Run: bislericli auth login")
+		return nil
+	}
+	cookies := append([]store.Cookie(nil), profile.Cookies...)
+	sort.SliceStable(cookies, func(i, j int) bool {
+		if cookies[i].Domain != cookies[j].Domain {
+			return cookies[i].Domain < cookies[j].Domain
+		}
+		return cookies[i].Name < cookies[j].Name
+	})
+	now := time.Now()
+	counts := map[string]int{}
+	size := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDOMAIN\tEXPIRES\tKIND\tSIZE")
+	for _, c := range cookies {
+		kind := bisleri.ClassifyCookie(c)
+		counts[kind]++
+		size += len(c.Name) + len(c.Value)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", c.Name, dashIfEmpty(c.Domain), cookieExpiry(c, now), kind, len(c.Value))
+	}
+	w.Flush()
+	fmt.Printf("\n%d cookie(s), %s of names and values", len(cookies), formatBytes(int64(size)))
+	for _, kind := range []string{bisleri.CookieSession, bisleri.CookieSite, bisleri.CookieSecurity, bisleri.CookieTracking, bisleri.CookieUnknown} {
+		if counts[kind] > 0 {
+			fmt.Printf("; %d %s", counts[kind], kind)
+		}
+	}
+	fmt.Println()
+	if counts[bisleri.CookieTracking] > 0 {
+		fmt.Println("Tracking cookies are sent with every request; remove them with 'bislericli debug cookies prune'.")
+	}
+	return nil
+}
+
+func runDebugCookiesPrune(args []string) error {
+	fs, profileName := parseScheduleFlags("debug cookies prune")
+	expiredOnly := fs.Bool("expired-only", false, "Only remove expired cookies, keeping tracking ones")
+	dryRun := fs.Bool("dry-run", false, "List the cookies that would be removed without changing the profile")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, profilePath, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	_, removed := pruneCookies(profile.Cookies, now, !*expiredOnly)
+	if len(removed) == 0 {
+		fmt.Println("Nothing to prune.")
+		return nil
+	}
+	for _, c := range removed {
+		reason := "expired"
+		if !cookieExpired(c, now) {
+			reason = bisleri.ClassifyCookie(c)
+		}
+		fmt.Printf("  %s (%s, %s)\n", c.Name, dashIfEmpty(c.Domain), reason)
+	}
+	if *dryRun {
+		fmt.Printf("Would remove %d of %d cookie(s).\n", len(removed), len(profile.Cookies))
+		return nil
+	}
+	var kept int
+	if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+		p.Cookies, _ = pruneCookies(p.Cookies, now, !*expiredOnly)
+		kept = len(p.Cookies)
+		return nil
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d cookie(s); %d kept.\n", len(removed), kept)
+	return nil
+}
+
+// pruneCookies splits cookies into those to keep and those to drop: expired
+// ones, and tracking ones when tracking is set.
+func pruneCookies(cookies []store.Cookie, now time.Time, tracking bool) (kept, removed []store.Cookie) {
+	for _, c := range cookies {
+		if cookieExpired(c, now) || tracking && bisleri.ClassifyCookie(c) == bisleri.CookieTracking {
+			removed = append(removed, c)
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept, removed
+}
+
+func cookieExpired(c store.Cookie, now time.Time) bool {
+	return c.Expires > 0 && time.Unix(c.Expires, 0).Before(now)
+}
+
+func cookieExpiry(c store.Cookie, now time.Time) string {
+	if c.Expires <= 0 {
+		return "session"
+	}
+	exp := time.Unix(c.Expires, 0)
+	if exp.Before(now) {
+		return "expired " + exp.Format("2006-01-02")
+	}
+	return exp.Format("2006-01-02 15:04")
+}
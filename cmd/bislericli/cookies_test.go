@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"bislericli/internal/store"
+)
+
+func TestPruneCookies(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	cookies := []store.Cookie{
+		{Name: "dwsid", Domain: ".bisleri.com"},
+		{Name: "dwanonymous_1", Domain: ".bisleri.com", Expires: now.Add(-time.Hour).Unix()},
+		{Name: "_ga", Domain: ".bisleri.com", Expires: now.Add(24 * time.Hour).Unix()},
+		{Name: "__cf_bm", Domain: ".bisleri.com", Expires: now.Add(time.Hour).Unix()},
+	}
+	kept, removed := pruneCookies(cookies, now, true)
+	if len(kept) != 2 || kept[0].Name != "dwsid" || kept[1].Name != "__cf_bm" {
+		t.Fatalf("kept = %+v", kept)
+	}
+	if len(removed) != 2 {
+		t.Fatalf("removed = %+v", removed)
+	}
+	kept, removed = pruneCookies(cookies, now, false)
+	if len(kept) != 3 || len(removed) != 1 || removed[0].Name != "dwanonymous_1" {
+		t.Fatalf("expired only: kept %d, removed %+v", len(kept), removed)
+	}
+}
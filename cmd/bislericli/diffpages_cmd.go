@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/debug"
+)
+
+func runDebugDiffPages(args []string) error {
+	fs := flag.NewFlagSet("debug diff-pages", flag.ContinueOnError)
+	all := fs.Bool("all", false, "Also show selectors and values that did not change")
+	limit := fs.Int("limit", 20, "Maximum class names, IDs and fields listed per group (0 for all)")
+	fs.Usage = func() {
+		fmt.Println("Usage: bislericli debug diff-pages [flags] <before.html> <after.html>")
+		fmt.Println("\nCompares two saved pages (e.g. the cart before and after a site update): which")
+		fmt.Println("selectors the parsers rely on stopped or started matching, what the extractors read")
+		fmt.Println("from each, and the class names, IDs and form fields that changed. Names of files in")
+		fmt.Println("the debug artifacts directory can be given without a path.")
+		fmt.Println()
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("expected two page files")
+	}
+	pages := make([]string, 2)
+	for i, name := range fs.Args() {
+		data, err := readSavedPage(name)
+		if err != nil {
+			return err
+		}
+		pages[i] = string(data)
+	}
+	diff, err := bisleri.DiffPages(pages[0], pages[1])
+	if err != nil {
+		return err
+	}
+	changed := printPageDiff(diff, *all, *limit)
+	if changed == 0 {
+		fmt.Println("No changes to the selectors or values the parsers use.")
+	} else {
+		fmt.Printf("%d change(s) affect the parsers; update selectors.json or the extractors.\n", changed)
+	}
+	return nil
+}
+
+// readSavedPage reads path, falling back to a file of that name in the debug
+// artifacts directory.
+func readSavedPage(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if !errors.Is(err, os.ErrNotExist) || filepath.Base(path) != path {
+		return data, err
+	}
+	dir, dirErr := debug.ArtifactsDir()
+	if dirErr != nil {
+		return nil, err
+	}
+	if data, artifactErr := os.ReadFile(filepath.Join(dir, path)); artifactErr == nil {
+		return data, nil
+	}
+	return nil, err
+}
+
+// printPageDiff prints the diff and returns how many parser-relevant changes
+// it found: selectors that stopped or started matching and extracted values
+// that differ.
+func printPageDiff(diff bisleri.PageDiff, all bool, limit int) int {
+	changed := 0
+	var selectors, values []string
+	for _, s := range diff.Selectors {
+		mark := ""
+		if s.Changed() {
+			mark = "!"
+			changed++
+		} else if !all {
+			continue
+		}
+		selectors = append(selectors, fmt.Sprintf("%s\t%s\t%s\t%d\t%d", mark, s.Selector, s.UsedBy, s.Before, s.After))
+	}
+	for _, v := range diff.Values {
+		mark := ""
+		if v.Before != v.After {
+			mark = "!"
+			changed++
+		} else if !all {
+			continue
+		}
+		values = append(values, fmt.Sprintf("%s\t%s\t%s\t%s", mark, v.Field, dashIfEmpty(v.Before), dashIfEmpty(v.After)))
+	}
+	for _, table := range []struct {
+		header string
+		rows   []string
+	}{
+		{"\tSELECTOR\tUSED BY\tBEFORE\tAFTER", selectors},
+		{"\tVALUE\tBEFORE\tAFTER", values},
+	} {
+		if len(table.rows) == 0 {
+			continue
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, table.header)
+		for _, row := range table.rows {
+			fmt.Fprintln(w, row)
+		}
+		w.Flush()
+		fmt.Println()
+	}
+
+	for _, group := range []struct {
+		title string
+		names []string
+	}{
+		{"Classes removed", diff.ClassesRemoved},
+		{"Classes added", diff.ClassesAdded},
+		{"IDs removed", diff.IDsRemoved},
+		{"IDs added", diff.IDsAdded},
+		{"Form fields removed", diff.FieldsRemoved},
+		{"Form fields added", diff.FieldsAdded},
+	} {
+		if len(group.names) == 0 {
+			continue
+		}
+		names := group.names
+		more := ""
+		if limit > 0 && len(names) > limit {
+			more = fmt.Sprintf(" (+%d more)", len(names)-limit)
+			names = names[:limit]
+		}
+		fmt.Printf("%s (%d): %s%s\n", group.title, len(group.names), strings.Join(names, ", "), more)
+	}
+	return changed
+}
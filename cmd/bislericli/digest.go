@@ -0,0 +1,226 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/money"
+	"bislericli/internal/notify"
+	"bislericli/internal/schedule"
+	"bislericli/internal/store"
+)
+
+// digest summarizes one week or month of ordering for the report command.
+type digest struct {
+	Label          string
+	Orders         []store.SavedOrder
+	Spend          money.Money
+	PrevSpend      money.Money
+	OpeningBalance string
+	ClosingBalance string
+	Upcoming       []time.Time
+}
+
+func runReport(args []string) error {
+	fs, profileName := parseScheduleFlags("report")
+	period := fs.String("period", "month", "Digest period: week or month")
+	current := fs.Bool("current", false, "Cover the period so far instead of the last complete one")
+	send := fs.Bool("send", false, "Also send the digest through the configured notifier")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *period != "week" && *period != "month" {
+		return fmt.Errorf("invalid --period %q (want week or month)", *period)
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	if *send && len(notifiers(cfg.Notifications)) == 0 {
+		return errors.New("no notifier configured; set notifications.desktop, whatsapp, signal or commands in config.json")
+	}
+	name := resolveProfileName(*profileName, cfg)
+	start, end := digestRange(*period, time.Now(), *current)
+	d, err := buildDigest(cfg, name, start, end, *period)
+	if err != nil {
+		return err
+	}
+	title, body := d.title(), d.body()
+	fmt.Println(title)
+	fmt.Println(body)
+	if *send {
+		sendNotification(cfg.Notifications, notify.Message{Event: notify.Digest, Title: title, Body: body})
+	}
+	return nil
+}
+
+// digestRange returns the last complete week (Monday to Sunday) or calendar
+// month before now, or the one in progress when current is set.
+func digestRange(period string, now time.Time, current bool) (time.Time, time.Time) {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var start, next time.Time
+	if period == "week" {
+		start = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		next = start.AddDate(0, 0, 7)
+		if !current {
+			start, next = start.AddDate(0, 0, -7), start
+		}
+	} else {
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		next = start.AddDate(0, 1, 0)
+		if !current {
+			start, next = start.AddDate(0, -1, 0), start
+		}
+	}
+	if current {
+		return start, now
+	}
+	return start, next
+}
+
+func buildDigest(cfg config.GlobalConfig, name string, start, end time.Time, period string) (digest, error) {
+	profile, _, err := loadOrCreateProfile(name)
+	if err != nil {
+		return digest{}, err
+	}
+	var orders []store.SavedOrder
+	if history, err := store.LoadOrderHistory(name); err == nil {
+		orders = history.Orders
+	} else if !os.IsNotExist(err) {
+		return digest{}, fmt.Errorf("failed to load history: %w", err)
+	}
+	orders = withUnsyncedOrder(orders, profile.LastOrder)
+	entries, err := store.LoadAuditLog(name)
+	if err != nil && !os.IsNotExist(err) {
+		return digest{}, fmt.Errorf("failed to load audit log: %w", err)
+	}
+
+	d := digest{Label: "Week of " + start.Format("02 Jan 2006")}
+	prevStart := start.AddDate(0, 0, -7)
+	if period == "month" {
+		d.Label = start.Format("January 2006")
+		prevStart = start.AddDate(0, -1, 0)
+	}
+	for _, o := range orders {
+		switch t := o.ParsedDate; {
+		case t.IsZero():
+		case !t.Before(start) && t.Before(end):
+			d.Orders = append(d.Orders, o)
+			d.Spend += o.Amount
+		case !t.Before(prevStart) && t.Before(start):
+			d.PrevSpend += o.Amount
+		}
+	}
+	d.OpeningBalance, d.ClosingBalance = walletTrend(entries, start, end)
+
+	if plan, err := loadSchedulePlan(cfg, profile); err == nil {
+		for _, run := range plan.Upcoming(time.Now(), 5) {
+			if held, _ := schedule.Held(profile.Schedule, run); !held {
+				d.Upcoming = append(d.Upcoming, run)
+			}
+			if len(d.Upcoming) == 3 {
+				break
+			}
+		}
+	}
+	return d, nil
+}
+
+// walletTrend returns the wallet balance seen at the start and end of the
+// range: the last balance before it (or the first inside it) and the last
+// balance inside it.
+func walletTrend(entries []store.AuditEntry, start, end time.Time) (string, string) {
+	var opening, closing string
+	for _, e := range entries {
+		if e.Timestamp.Before(start) {
+			if e.WalletAfter != "" {
+				opening = e.WalletAfter
+			}
+			continue
+		}
+		if !e.Timestamp.Before(end) {
+			break
+		}
+		if opening == "" && e.WalletBefore != "" {
+			opening = e.WalletBefore
+		}
+		if e.WalletAfter != "" {
+			closing = e.WalletAfter
+		} else if e.WalletBefore != "" {
+			closing = e.WalletBefore
+		}
+	}
+	return opening, closing
+}
+
+func (d digest) title() string {
+	return "Bisleri digest: " + d.Label
+}
+
+func (d digest) body() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Orders: %d, spend %s", len(d.Orders), d.Spend)
+	if d.PrevSpend > 0 {
+		fmt.Fprintf(&b, " (previous period %s)", d.PrevSpend)
+	}
+	b.WriteString("\n")
+	for _, o := range d.Orders {
+		fmt.Fprintf(&b, "  %s  %s  %s  %s\n", o.ParsedDate.Format("02 Jan"), o.OrderID, o.Total, o.Status)
+	}
+	switch {
+	case d.OpeningBalance != "" && d.ClosingBalance != "":
+		fmt.Fprintf(&b, "Wallet: %s -> %s\n", d.OpeningBalance, d.ClosingBalance)
+	case d.OpeningBalance != "":
+		fmt.Fprintf(&b, "Wallet: %s\n", d.OpeningBalance)
+	}
+	if len(d.Upcoming) > 0 {
+		var runs []string
+		for _, r := range d.Upcoming {
+			runs = append(runs, r.Format("Mon 02 Jan 15:04"))
+		}
+		fmt.Fprintf(&b, "Next scheduled: %s\n", strings.Join(runs, ", "))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// maybeSendDigest sends the configured weekly or monthly digest from the
+// scheduler once per period, after the period has ended.
+func maybeSendDigest(cfg config.GlobalConfig, name string, now time.Time) {
+	period := cfg.Notifications.Digest
+	if period != "week" && period != "month" {
+		return
+	}
+	profile, profilePath, err := loadOrCreateProfile(name)
+	if err != nil {
+		return
+	}
+	start, end := digestRange(period, now, false)
+	if profile.Schedule != nil && !profile.Schedule.LastDigest.Before(end) {
+		return
+	}
+	d, err := buildDigest(cfg, name, start, end, period)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: digest failed:", err)
+		return
+	}
+	fmt.Println(d.title())
+	fmt.Println(d.body())
+	sendNotification(cfg.Notifications, notify.Message{Event: notify.Digest, Title: d.title(), Body: d.body()})
+	if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+		if p.Schedule == nil {
+			p.Schedule = &store.ScheduleState{}
+		}
+		p.Schedule.LastDigest = now
+		return nil
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: failed to record digest:", err)
+	}
+}
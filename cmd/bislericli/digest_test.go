@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"bislericli/internal/store"
+)
+
+func TestDigestRange(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC) // Friday
+	start, end := digestRange("week", now, false)
+	if start.Format("2006-01-02") != "2026-10-05" || end.Format("2006-01-02") != "2026-10-12" {
+		t.Fatalf("last week = %s..%s", start, end)
+	}
+	start, end = digestRange("month", now, false)
+	if start.Format("2006-01-02") != "2026-09-01" || end.Format("2006-01-02") != "2026-10-01" {
+		t.Fatalf("last month = %s..%s", start, end)
+	}
+	start, end = digestRange("month", now, true)
+	if start.Format("2006-01-02") != "2026-10-01" || !end.Equal(now) {
+		t.Fatalf("current month = %s..%s", start, end)
+	}
+}
+
+func TestWalletTrend(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 9, d, 10, 0, 0, 0, time.UTC) }
+	entries := []store.AuditEntry{
+		{Timestamp: day(1).AddDate(0, 0, -5), WalletAfter: "₹900"},
+		{Timestamp: day(10), WalletBefore: "₹1,400", WalletAfter: "₹1,200"},
+		{Timestamp: day(20), WalletBefore: "₹1,200", WalletAfter: "₹1,000"},
+		{Timestamp: day(1).AddDate(0, 1, 2), WalletAfter: "₹800"},
+	}
+	opening, closing := walletTrend(entries, day(1), day(1).AddDate(0, 1, 0))
+	if opening != "₹900" || closing != "₹1,000" {
+		t.Fatalf("trend = %s -> %s", opening, closing)
+	}
+}
+
+func TestDigestBody(t *testing.T) {
+	d := digest{
+		Label:          "September 2026",
+		Orders:         []store.SavedOrder{{OrderID: "BS-1", Total: "₹200.00", Status: "Delivered", ParsedDate: time.Date(2026, 9, 3, 0, 0, 0, 0, time.UTC), Amount: 20000}},
+		Spend:          20000,
+		OpeningBalance: "₹900",
+		ClosingBalance: "₹700",
+	}
+	body := d.body()
+	for _, want := range []string{"Orders: 1", "BS-1", "Wallet: ₹900 -> ₹700"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("body missing %q:\n%s", want, body)
+		}
+	}
+}
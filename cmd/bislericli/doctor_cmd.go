@@ -0,0 +1,323 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/config"
+	"bislericli/internal/schedule"
+	"bislericli/internal/store"
+)
+
+type checkStatus int
+
+const (
+	checkOK checkStatus = iota
+	checkWarn
+	checkFail
+	checkSkip
+)
+
+func (s checkStatus) String() string {
+	switch s {
+	case checkOK:
+		return "ok"
+	case checkWarn:
+		return "warn"
+	case checkFail:
+		return "FAIL"
+	default:
+		return "skip"
+	}
+}
+
+type doctorCheck struct {
+	Name   string
+	Status checkStatus
+	Detail string
+	Hint   string
+}
+
+const maxClockSkew = 2 * time.Minute
+
+func runDoctor(args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	profileName := fs.String("profile", "", "Profile name to check (default: current/default)")
+	offline := fs.Bool("offline", false, "Skip checks that contact bisleri.com")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	var checks []doctorCheck
+	checks = append(checks, checkConfigDir())
+
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		checks = append(checks, doctorCheck{Name: "Config", Status: checkFail, Detail: err.Error(), Hint: "fix or remove config.json and re-run"})
+	} else {
+		checks = append(checks, checkConfig(cfg))
+	}
+	name := resolveProfileName(*profileName, cfg)
+
+	profile, profileCheck := checkProfile(name)
+	checks = append(checks, profileCheck)
+	checks = append(checks, checkCookies(profile.Cookies, time.Now()))
+	checks = append(checks, checkChrome())
+
+	if *offline {
+		checks = append(checks,
+			doctorCheck{Name: "Connectivity", Status: checkSkip, Detail: "--offline"},
+			doctorCheck{Name: "Clock", Status: checkSkip, Detail: "--offline"},
+			doctorCheck{Name: "Parsers", Status: checkSkip, Detail: "--offline"},
+		)
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
+		defer cancel()
+		connCheck, clockCheck := checkConnectivity(ctx)
+		checks = append(checks, connCheck, clockCheck)
+		if connCheck.Status == checkFail || len(profile.Cookies) == 0 {
+			checks = append(checks, doctorCheck{Name: "Parsers", Status: checkSkip, Detail: "needs connectivity and a logged-in profile"})
+		} else {
+			checks = append(checks, checkParsers(ctx, profile))
+		}
+	}
+
+	failures := 0
+	for _, c := range checks {
+		fmt.Printf("[%-4s] %-13s %s\n", c.Status, c.Name, c.Detail)
+		if c.Hint != "" && (c.Status == checkWarn || c.Status == checkFail) {
+			fmt.Printf("       %-13s -> %s\n", "", c.Hint)
+		}
+		if c.Status == checkFail {
+			failures++
+		}
+	}
+	if failures > 0 {
+		return fmt.Errorf("doctor found %d problem(s)", failures)
+	}
+	return nil
+}
+
+func checkConfigDir() doctorCheck {
+	c := doctorCheck{Name: "Config dir"}
+	dir, err := config.EnsureConfigDir()
+	if err != nil {
+		c.Status, c.Detail, c.Hint = checkFail, err.Error(), "check that your home directory is writable"
+		return c
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		c.Status, c.Detail = checkFail, err.Error()
+		return c
+	}
+	c.Detail = dir
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		c.Status = checkWarn
+		c.Detail = fmt.Sprintf("%s is %s (readable by other users)", dir, info.Mode().Perm())
+		c.Hint = fmt.Sprintf("chmod 700 %q", dir)
+	}
+	return c
+}
+
+func checkConfig(cfg config.GlobalConfig) doctorCheck {
+	c := doctorCheck{Name: "Config", Detail: "loaded"}
+	if cfg.Defaults.Schedule != "" {
+		if _, err := schedule.Parse(cfg.Defaults.Schedule, cfg.Defaults.ScheduleTime); err != nil {
+			c.Status, c.Detail, c.Hint = checkFail, err.Error(), "fix defaults.schedule/scheduleTime in config.json"
+			return c
+		}
+	}
+	if _, err := schedule.ParseBlackout(cfg.Blackout.Weekdays, cfg.Blackout.Dates); err != nil {
+		c.Status, c.Detail, c.Hint = checkFail, err.Error(), "fix the blackout section in config.json"
+		return c
+	}
+	if problems := validateConfig(cfg); len(problems) > 0 {
+		c.Status, c.Detail, c.Hint = checkWarn, fmt.Sprintf("%s: %s", problems[0].Key, problems[0].Detail), "run 'bislericli config validate'"
+	}
+	return c
+}
+
+func checkProfile(name string) (store.Profile, doctorCheck) {
+	c := doctorCheck{Name: "Profile"}
+	path, err := config.ProfilePath(name)
+	if err != nil {
+		c.Status, c.Detail = checkFail, err.Error()
+		return store.Profile{}, c
+	}
+	profile, err := store.LoadProfile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			c.Status, c.Detail, c.Hint = checkFail, fmt.Sprintf("profile %q not found", name), "run 'bislericli auth login'"
+		} else {
+			c.Status, c.Detail, c.Hint = checkFail, err.Error(), "the profile file is unreadable; log in again to recreate it"
+		}
+		return store.Profile{}, c
+	}
+	c.Detail = fmt.Sprintf("%s (last login %s)", name, profile.LastLogin.Format("2006-01-02"))
+	if profile.Address == nil {
+		c.Status, c.Hint = checkWarn, "no address saved yet; it is captured on the first order"
+		c.Detail += ", no address"
+	} else if err := bisleri.ValidateShippingAddress(*profile.Address); err != nil {
+		c.Status, c.Hint = checkWarn, "update the address on bisleri.com, then re-run 'bislericli auth login'"
+		c.Detail += ", " + err.Error()
+	}
+	return profile, c
+}
+
+// checkCookies reports expired session cookies and warns when the earliest
+// persistent cookie expires within a day.
+func checkCookies(cookies []store.Cookie, now time.Time) doctorCheck {
+	c := doctorCheck{Name: "Cookies"}
+	if len(cookies) == 0 {
+		c.Status, c.Detail, c.Hint = checkFail, "no cookies saved", "run 'bislericli auth login'"
+		return c
+	}
+	expired := 0
+	var earliest time.Time
+	for _, ck := range cookies {
+		if ck.Expires <= 0 {
+			continue
+		}
+		exp := time.Unix(ck.Expires, 0)
+		if exp.Before(now) {
+			expired++
+			continue
+		}
+		if earliest.IsZero() || exp.Before(earliest) {
+			earliest = exp
+		}
+	}
+	c.Detail = fmt.Sprintf("%d saved", len(cookies))
+	switch {
+	case expired == len(cookies):
+		c.Status, c.Hint = checkFail, "session expired; run 'bislericli auth login'"
+		c.Detail += ", all expired"
+	case expired > 0:
+		c.Status, c.Hint = checkWarn, "some cookies expired; log in again if orders fail"
+		c.Detail += fmt.Sprintf(", %d expired", expired)
+	case !earliest.IsZero() && earliest.Sub(now) < 24*time.Hour:
+		c.Status, c.Hint = checkWarn, "log in again soon to avoid a failed scheduled order"
+		c.Detail += ", next expiry " + earliest.Format("2006-01-02 15:04")
+	}
+	return c
+}
+
+// chromeCandidates mirrors the executables chromedp looks for.
+var chromeCandidates = map[string][]string{
+	"darwin": {
+		"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+		"/Applications/Chromium.app/Contents/MacOS/Chromium",
+	},
+	"windows": {"chrome", "chrome.exe"},
+}
+
+var defaultChromeCandidates = []string{
+	"headless_shell", "headless-shell", "chromium", "chromium-browser",
+	"google-chrome", "google-chrome-stable", "google-chrome-beta", "google-chrome-unstable",
+}
+
+func checkChrome() doctorCheck {
+	c := doctorCheck{Name: "Chrome"}
+	candidates := append(chromeCandidates[runtime.GOOS], defaultChromeCandidates...)
+	for _, candidate := range candidates {
+		if path, err := exec.LookPath(candidate); err == nil {
+			c.Detail = path
+			return c
+		}
+	}
+	c.Status = checkWarn
+	c.Detail = "not found"
+	c.Hint = "install Google Chrome or Chromium for browser login; OTP login works without it"
+	return c
+}
+
+func checkConnectivity(ctx context.Context) (doctorCheck, doctorCheck) {
+	conn := doctorCheck{Name: "Connectivity"}
+	clock := doctorCheck{Name: "Clock"}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, "https://www.bisleri.com/", nil)
+	if err != nil {
+		conn.Status, conn.Detail = checkFail, err.Error()
+		clock.Status = checkSkip
+		return conn, clock
+	}
+	start := time.Now()
+	resp, err := (&http.Client{Timeout: 15 * time.Second}).Do(req)
+	if err != nil {
+		conn.Status, conn.Detail, conn.Hint = checkFail, err.Error(), "check your network, proxy or DNS settings"
+		clock.Status, clock.Detail = checkSkip, "no server response"
+		return conn, clock
+	}
+	resp.Body.Close()
+	conn.Detail = fmt.Sprintf("bisleri.com %s in %s", resp.Status, time.Since(start).Round(time.Millisecond))
+	if resp.StatusCode >= 500 {
+		conn.Status, conn.Hint = checkWarn, "the site may be down; try again later"
+	}
+
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		clock.Status, clock.Detail = checkSkip, "server did not send a Date header"
+		return conn, clock
+	}
+	skew := time.Since(serverTime)
+	if skew < 0 {
+		skew = -skew
+	}
+	clock.Detail = fmt.Sprintf("skew %s", skew.Round(time.Second))
+	if skew > maxClockSkew {
+		clock.Status, clock.Hint = checkWarn, "sync your system clock (NTP); cookie expiry and schedules depend on it"
+	}
+	return conn, clock
+}
+
+// checkParsers fetches live pages with the profile's session and runs the
+// parsers the order flow depends on.
+func checkParsers(ctx context.Context, profile store.Profile) doctorCheck {
+	c := doctorCheck{Name: "Parsers"}
+	jar, err := bisleri.JarFromCookies(profile.Cookies)
+	if err != nil {
+		c.Status, c.Detail = checkFail, err.Error()
+		return c
+	}
+	client := bisleri.NewClient(&http.Client{Jar: jar, Timeout: 20 * time.Second}, log.New(os.Stderr, "bisleri: ", log.LstdFlags))
+	if err := client.VerifyAuthenticated(ctx); err != nil {
+		c.Status, c.Detail, c.Hint = checkFail, "session check failed: "+err.Error(), "run 'bislericli auth login'"
+		return c
+	}
+	ordersHTML, _, err := client.FetchPage(ctx, "/my-orders")
+	if err != nil {
+		c.Status, c.Detail = checkFail, "orders page: "+err.Error()
+		return c
+	}
+	_, diag, err := bisleri.ParseOrdersWithDiagnostics(ordersHTML)
+	if err != nil {
+		c.Status, c.Detail, c.Hint = checkFail, "orders page: "+err.Error(), "the site layout may have changed; run 'bislericli update'"
+		return c
+	}
+	cartHTML, err := client.FetchCartPage(ctx)
+	if err != nil {
+		c.Status, c.Detail = checkFail, "cart page: "+err.Error()
+		return c
+	}
+	if _, ok := bisleri.ExtractCartCount(cartHTML); !ok {
+		c.Status, c.Detail, c.Hint = checkWarn, "cart page: cart count not found", "the site layout may have changed; run 'bislericli update'"
+		return c
+	}
+	if !diag.Complete() {
+		c.Status, c.Detail, c.Hint = checkWarn, "orders page: "+diag.String(), "the site layout may have changed; run 'bislericli update' or set orderCard in selectors.json"
+		return c
+	}
+	c.Detail = "orders and cart pages parsed (" + diag.String() + ")"
+	return c
+}
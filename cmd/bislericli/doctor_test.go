@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"bislericli/internal/store"
+)
+
+func TestCheckCookies(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour).Unix()
+	soon := now.Add(2 * time.Hour).Unix()
+	later := now.Add(72 * time.Hour).Unix()
+
+	tests := []struct {
+		name    string
+		cookies []store.Cookie
+		want    checkStatus
+	}{
+		{"none", nil, checkFail},
+		{"all expired", []store.Cookie{{Expires: past}}, checkFail},
+		{"some expired", []store.Cookie{{Expires: past}, {Expires: later}}, checkWarn},
+		{"expiring soon", []store.Cookie{{Expires: soon}, {Expires: later}}, checkWarn},
+		{"session only", []store.Cookie{{Expires: 0}}, checkOK},
+		{"healthy", []store.Cookie{{Expires: later}}, checkOK},
+	}
+	for _, tt := range tests {
+		if got := checkCookies(tt.cookies, now).Status; got != tt.want {
+			t.Fatalf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
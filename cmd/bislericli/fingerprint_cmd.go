@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/debug"
+)
+
+// fingerprintSettings is the config's fingerprints section, set at startup.
+var fingerprintSettings config.Fingerprints
+
+// noteParseFailure records the structure of a page an extractor failed on
+// when fingerprinting is enabled, and submits it when that is enabled too.
+// Failures here never affect the command.
+func noteParseFailure(extractor, page, html string) {
+	if !fingerprintSettings.Enabled {
+		return
+	}
+	fp, err := debug.RecordFingerprint(debug.NewFingerprint(extractor, page, version, html))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: failed to record page fingerprint:", err)
+		return
+	}
+	if !fingerprintSettings.Submit {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := submitFingerprints(ctx, []debug.Fingerprint{fp}); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: failed to submit page fingerprint:", err)
+	}
+}
+
+// submitFingerprints posts fps to the configured endpoint and marks them
+// submitted in the local record.
+func submitFingerprints(ctx context.Context, fps []debug.Fingerprint) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	if err := debug.SubmitFingerprints(ctx, client, fingerprintSettings.Endpoint, fps); err != nil {
+		return err
+	}
+	all, err := debug.LoadFingerprints()
+	if err != nil {
+		return err
+	}
+	for i := range all {
+		for _, fp := range fps {
+			if all[i].Hash == fp.Hash && all[i].Extractor == fp.Extractor && all[i].Version == fp.Version && all[i].Count == fp.Count {
+				all[i].Submitted = true
+			}
+		}
+	}
+	return debug.SaveFingerprints(all)
+}
+
+func runDebugFingerprints(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("debug fingerprints "+action, flag.ContinueOnError)
+	showShape := fs.Bool("shape", false, "Print each fingerprint's structural tokens (list)")
+	fs.Usage = func() {
+		fmt.Println("Usage: bislericli debug fingerprints [list|submit|clear] [flags]")
+		fmt.Println("\nWith fingerprints.enabled in config.json, a page a parser fails on is recorded as a")
+		fmt.Println("hash of its structure (tag, class, form field and data-* attribute names; no text or")
+		fmt.Println("values). submit posts the unsubmitted ones to fingerprints.endpoint.")
+		fmt.Println()
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	fps, err := debug.LoadFingerprints()
+	if err != nil {
+		return err
+	}
+	switch action {
+	case "list":
+		if len(fps) == 0 {
+			if !fingerprintSettings.Enabled {
+				fmt.Println("No fingerprints recorded; set fingerprints.enabled in config.json to record them.")
+			} else {
+				fmt.Println("No fingerprints recorded.")
+			}
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "HASH\tEXTRACTOR\tPAGE\tVERSION\tCOUNT\tLAST SEEN\tSUBMITTED")
+		for _, fp := range fps {
+			submitted := "no"
+			if fp.Submitted {
+				submitted = "yes"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", fp.Hash, fp.Extractor, dashIfEmpty(fp.Page), fp.Version, fp.Count, fp.LastSeen.Local().Format("2006-01-02 15:04"), submitted)
+		}
+		w.Flush()
+		if *showShape {
+			for _, fp := range fps {
+				fmt.Printf("\n%s (%s): %s\n", fp.Hash, fp.Extractor, strings.Join(fp.Shape, " "))
+			}
+		}
+		return nil
+	case "submit":
+		if fingerprintSettings.Endpoint == "" {
+			return errors.New("set fingerprints.endpoint in config.json to submit fingerprints")
+		}
+		var pending []debug.Fingerprint
+		for _, fp := range fps {
+			if !fp.Submitted {
+				pending = append(pending, fp)
+			}
+		}
+		if len(pending) == 0 {
+			fmt.Println("Nothing to submit.")
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := submitFingerprints(ctx, pending); err != nil {
+			return err
+		}
+		fmt.Printf("Submitted %d fingerprint(s).\n", len(pending))
+		return nil
+	case "clear":
+		if err := debug.SaveFingerprints(nil); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %d fingerprint(s).\n", len(fps))
+		return nil
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown fingerprints action %q", action)
+	}
+}
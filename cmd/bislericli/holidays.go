@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"bislericli/internal/address"
+	"bislericli/internal/config"
+	"bislericli/internal/holidays"
+	"bislericli/internal/notify"
+	"bislericli/internal/schedule"
+	"bislericli/internal/store"
+)
+
+// holidayCalendar returns the holidays for the configured state, or the
+// profile address's state when none is set. ok is false when holiday
+// awareness is off.
+func holidayCalendar(cfg config.GlobalConfig, profile store.Profile) (cal holidays.Calendar, ok bool) {
+	if !cfg.Holidays.Enabled {
+		return holidays.Calendar{}, false
+	}
+	state := cfg.Holidays.State
+	if state == "" && profile.Address != nil {
+		state = profile.Address.StateCode
+	}
+	code, _ := address.NormalizeState(state)
+	fetched, err := holidays.LoadFetched()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: holiday calendar unavailable:", err)
+	}
+	return holidays.New(code, fetched), true
+}
+
+// holidayBlackout lists this year's and next year's holidays as blackout
+// dates, so action "shift" moves runs off them.
+func holidayBlackout(cal holidays.Calendar, now time.Time) []schedule.DateRange {
+	from := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+	var ranges []schedule.DateRange
+	for _, h := range cal.Between(from, from.AddDate(2, 0, -1)) {
+		ranges = append(ranges, schedule.DateRange{From: h.Date, To: h.Date})
+	}
+	return ranges
+}
+
+// warnHolidayRun tells the user when a scheduled run falls on a holiday,
+// while there is still time to skip it.
+func warnHolidayRun(cfg config.GlobalConfig, cal holidays.Calendar, run time.Time) {
+	h, ok := cal.On(run)
+	if !ok {
+		return
+	}
+	msg := fmt.Sprintf("The run on %s falls on %s; deliveries may not happen. Skip it with 'schedule skip %s'.", run.Format("Mon 02 Jan"), h.Name, h.Date)
+	fmt.Println("Holiday:", msg)
+	sendNotification(cfg.Notifications, notify.Message{Event: notify.Holiday, Title: "Scheduled order on a holiday", Body: msg})
+}
+
+func runHolidays(args []string) error {
+	if len(args) > 0 && isHelpToken(args[0]) {
+		printHolidaysUsage()
+		return nil
+	}
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+	switch action {
+	case "list":
+		return runHolidaysList(args)
+	case "fetch":
+		return runHolidaysFetch(args)
+	default:
+		fmt.Printf("Unknown holidays subcommand: %s\n", action)
+		printHolidaysUsage()
+		return nil
+	}
+}
+
+func printHolidaysUsage() {
+	fmt.Println("Usage: bislericli holidays [subcommand] [flags]")
+	fmt.Println("\nAvailable subcommands:")
+	fmt.Println("  list             Public holidays in the coming months for your state (default)")
+	fmt.Println("  fetch            Download the calendar of movable festivals from holidays.url (or --url)")
+}
+
+func runHolidaysList(args []string) error {
+	fs, profileName := parseScheduleFlags("holidays list")
+	days := fs.Int("days", 90, "How many days ahead to list")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, _, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	// Listing works even while the scheduler ignores holidays.
+	cfg.Holidays.Enabled = true
+	cal, _ := holidayCalendar(cfg, profile)
+	if cal.State == "" {
+		fmt.Println("State unknown; showing national holidays only. Set holidays.state or save an address.")
+	}
+	now := time.Now()
+	list := cal.Between(now, now.AddDate(0, 0, *days))
+	if len(list) == 0 {
+		fmt.Println("No holidays in the next", *days, "days.")
+		return nil
+	}
+	for _, h := range list {
+		day, _ := time.Parse(holidays.DateLayout, h.Date)
+		fmt.Printf("%s  %s\n", day.Format("Mon 2006-01-02"), h.Name)
+	}
+	return nil
+}
+
+func runHolidaysFetch(args []string) error {
+	fs := flag.NewFlagSet("holidays fetch", flag.ContinueOnError)
+	url := fs.String("url", "", "Calendar URL (default: holidays.url)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	if *url == "" {
+		*url = cfg.Holidays.URL
+	}
+	if *url == "" {
+		return errors.New("no calendar URL: set holidays.url or pass --url")
+	}
+	if err := checkEndpointURL(*url); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	list, err := holidays.Fetch(ctx, *url)
+	if err != nil {
+		return err
+	}
+	if err := holidays.SaveFetched(list); err != nil {
+		return err
+	}
+	fmt.Printf("Saved %d holiday(s).\n", len(list))
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/schedule"
+	"bislericli/internal/store"
+)
+
+func TestHolidayShift(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	cfg := config.GlobalConfig{Holidays: config.Holidays{Enabled: true, Action: "shift"}}
+	profile := store.Profile{Address: &store.Address{StateCode: "Karnataka"}}
+	cal, ok := holidayCalendar(cfg, profile)
+	if !ok || cal.State != "KA" {
+		t.Fatalf("holidayCalendar state = %q, %v; want KA from the address", cal.State, ok)
+	}
+
+	plan, err := schedule.Parse("daily", "07:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	plan.Blackout.Ranges = holidayBlackout(cal, now)
+	// Karnataka Rajyotsava on 1 November moves that day's run to the 2nd.
+	next := plan.Next(time.Date(2026, 10, 31, 8, 0, 0, 0, time.UTC))
+	if want := time.Date(2026, 11, 2, 7, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("Next = %s, want %s", next, want)
+	}
+	// Next year's national holidays are covered too.
+	if !plan.Blackout.Contains(time.Date(2027, 1, 26, 7, 0, 0, 0, time.UTC)) {
+		t.Error("Republic Day 2027 not blacked out")
+	}
+}
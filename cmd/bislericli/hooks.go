@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"bislericli/internal/hooks"
+)
+
+// runHooks runs the commands configured for event with data as the payload.
+func runHooks(commands []string, event, profile string, data any) error {
+	if len(commands) == 0 {
+		return nil
+	}
+	return hooks.Run(context.Background(), commands, hooks.Event{Event: event, Time: time.Now(), Profile: profile, Data: data})
+}
+
+// notifyHooks runs informational hooks, where a failure only warns.
+func notifyHooks(commands []string, event, profile string, data any) {
+	if err := runHooks(commands, event, profile, data); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning:", err)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/ics"
+	"bislericli/internal/schedule"
+	"bislericli/internal/store"
+)
+
+func runScheduleExportICS(args []string) error {
+	fs, profileName := parseScheduleFlags("schedule export-ics")
+	output := fs.String("output", "bislericli.ics", "File to write (\"-\" for stdout)")
+	count := fs.Int("count", 10, "Number of upcoming scheduled runs to include")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	name := resolveProfileName(*profileName, cfg)
+	profile, _, err := loadOrCreateProfile(name)
+	if err != nil {
+		return err
+	}
+
+	var orders []store.SavedOrder
+	if history, err := store.LoadOrderHistory(name); err == nil {
+		orders = history.Orders
+	} else if !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintln(os.Stderr, "Warning: order history unavailable:", err)
+	}
+	events := orderEvents(withUnsyncedOrder(orders, profile.LastOrder))
+
+	plan, err := loadSchedulePlan(cfg, profile)
+	if err != nil {
+		return err
+	}
+	events = append(events, scheduledRunEvents(plan, profile.Schedule, time.Now(), *count, cfg.Defaults.OrderQuantity)...)
+
+	var w io.Writer = os.Stdout
+	var buf strings.Builder
+	if *output != "-" {
+		w = &buf
+	}
+	if err := ics.Write(w, "Bisleri deliveries", events, time.Now()); err != nil {
+		return err
+	}
+	if *output == "-" {
+		return nil
+	}
+	if err := os.WriteFile(*output, []byte(buf.String()), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d events to %s\n", len(events), *output)
+	return nil
+}
+
+// orderEvents turns order history into all-day events on the order date.
+func orderEvents(orders []store.SavedOrder) []ics.Event {
+	var events []ics.Event
+	for _, o := range orders {
+		if o.OrderID == "" || o.ParsedDate.IsZero() {
+			continue
+		}
+		summary := "Bisleri order " + o.OrderID
+		if o.Status != "" {
+			summary += " (" + o.Status + ")"
+		}
+		var details []string
+		if o.Total != "" {
+			details = append(details, "Total: "+o.Total)
+		}
+		if o.Items != "" {
+			details = append(details, "Items: "+o.Items)
+		}
+		events = append(events, ics.Event{
+			UID:         "order-" + o.OrderID + "@bislericli",
+			Start:       o.ParsedDate,
+			AllDay:      true,
+			Summary:     summary,
+			Description: strings.Join(details, "\n"),
+		})
+	}
+	return events
+}
+
+// scheduledRunEvents projects the next n scheduled runs that are not skipped,
+// paused or blacked out.
+func scheduledRunEvents(plan schedule.Plan, state *store.ScheduleState, now time.Time, n, quantity int) []ics.Event {
+	var events []ics.Event
+	for _, run := range plan.Upcoming(now, n) {
+		if held, _ := schedule.Held(state, run); held {
+			continue
+		}
+		events = append(events, ics.Event{
+			UID:         "run-" + run.Format("200601021504") + "@bislericli",
+			Start:       run,
+			End:         run.Add(30 * time.Minute),
+			Summary:     fmt.Sprintf("Scheduled Bisleri order (%d jars)", quantity),
+			Description: "Placed automatically by 'bislericli schedule run'.",
+		})
+	}
+	return events
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/config"
+	"bislericli/internal/money"
+	"bislericli/internal/store"
+)
+
+func runOrdersImport(args []string) error {
+	fs, profileName := parseScheduleFlags("orders import")
+	file := fs.String("file", "", "CSV file with columns order_id,date,total[,status,items]")
+	dryRun := fs.Bool("dry-run", false, "Parse the file and report what would be imported without saving")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *file == "" {
+		return errors.New("--file is required: orders import --file old.csv")
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	orders, err := parseOrdersCSV(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *file, err)
+	}
+	if *dryRun {
+		fmt.Printf("%d orders parsed from %s (dry run, nothing saved).\n", len(orders), *file)
+		return nil
+	}
+	added, err := store.ImportOrders(resolveProfileName(*profileName, cfg), orders)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d of %d orders (%d already in history).\n", added, len(orders), len(orders)-added)
+	return nil
+}
+
+// parseOrdersCSV reads historical orders from CSV with a header row. Column
+// names are case-insensitive; order_id, date and total are required, status
+// (default "Delivered") and items are optional, and other columns are ignored.
+func parseOrdersCSV(r io.Reader) ([]store.SavedOrder, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("file is empty")
+		}
+		return nil, err
+	}
+	cols := make(map[string]int)
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"order_id", "date", "total"} {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("missing %q column (need order_id,date,total)", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		i, ok := cols[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var orders []store.SavedOrder
+	seen := make(map[string]bool)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		id := field(record, "order_id")
+		if id == "" {
+			return nil, fmt.Errorf("line %d: order_id is empty", line)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("line %d: duplicate order_id %s", line, id)
+		}
+		seen[id] = true
+		date, err := parseImportDate(field(record, "date"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		total := field(record, "total")
+		amount, ok := money.Parse(total)
+		if !ok {
+			return nil, fmt.Errorf("line %d: invalid total %q", line, total)
+		}
+		status := field(record, "status")
+		if status == "" {
+			status = "Delivered"
+		}
+		orders = append(orders, store.SavedOrder{
+			OrderID:    id,
+			Date:       date.Format("02 Jan 2006"),
+			ParsedDate: date,
+			Status:     status,
+			Total:      amount.String(),
+			Amount:     amount,
+			Items:      field(record, "items"),
+		})
+	}
+	return orders, nil
+}
+
+func parseImportDate(value string) (time.Time, error) {
+	if t, ok := bisleri.ParseDate(value, time.Local); ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q (use YYYY-MM-DD or DD/MM/YYYY)", value)
+}
+
+func runOrdersMerge(args []string) error {
+	fs := flag.NewFlagSet("orders merge", flag.ContinueOnError)
+	from := fs.String("from", "", "Profile whose order history is merged in")
+	into := fs.String("into", "", "Profile that receives the merged history")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *from == "" || *into == "" {
+		return errors.New("usage: orders merge --from <profile> --into <profile>")
+	}
+	if *from == *into {
+		return errors.New("--from and --into must be different profiles")
+	}
+	source, err := loadExistingProfile(*from)
+	if err != nil {
+		return err
+	}
+	target, err := loadExistingProfile(*into)
+	if err != nil {
+		return err
+	}
+	if err := sameAccount(source, target); err != nil {
+		return err
+	}
+	history, err := store.LoadOrderHistory(*from)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("profile %q has no synced orders", *from)
+		}
+		return fmt.Errorf("failed to load history: %w", err)
+	}
+	// Orders only the other profile has are kept across syncs like imported ones.
+	added, err := store.ImportOrders(*into, withUnsyncedOrder(history.Orders, source.LastOrder))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Merged %d orders from %q into %q.\n", added, *from, *into)
+	return nil
+}
+
+// loadExistingProfile loads a profile without creating it when it is missing.
+func loadExistingProfile(name string) (store.Profile, error) {
+	path, err := config.ProfilePath(name)
+	if err != nil {
+		return store.Profile{}, err
+	}
+	profile, err := store.LoadProfile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store.Profile{}, fmt.Errorf("profile %q does not exist", name)
+	}
+	return profile, err
+}
+
+// sameAccount checks that two profiles were logged in with the same phone
+// number, i.e. they belong to one Bisleri account.
+func sameAccount(a, b store.Profile) error {
+	pa, pb := normalizePhoneNumber(a.PhoneNumber), normalizePhoneNumber(b.PhoneNumber)
+	if pa == "" || pb == "" {
+		return errors.New("both profiles need a phone number to confirm they share an account; run 'bislericli auth login' for each")
+	}
+	if pa != pb {
+		return fmt.Errorf("profiles %q and %q belong to different phone numbers", a.Name, b.Name)
+	}
+	return nil
+}
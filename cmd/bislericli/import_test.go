@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"bislericli/internal/store"
+)
+
+func TestParseOrdersCSV(t *testing.T) {
+	input := "\ufeffOrder_ID,Date,Total,Status,Items,Notes\n" +
+		"BS-100,2024-03-05,\"₹1,200\",,\"3 x 20L Jar\",paid cash\n" +
+		"BS-101,15/04/2024,200,Cancelled,,\n"
+	orders, err := parseOrdersCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseOrdersCSV: %v", err)
+	}
+	if len(orders) != 2 {
+		t.Fatalf("expected 2 orders, got %d", len(orders))
+	}
+	first := orders[0]
+	if first.OrderID != "BS-100" || first.Status != "Delivered" || first.Amount.Plain() != "1,200.00" || first.Items != "3 x 20L Jar" {
+		t.Fatalf("unexpected first order: %+v", first)
+	}
+	if got := first.ParsedDate.Format("2006-01-02"); got != "2024-03-05" {
+		t.Fatalf("first date = %s", got)
+	}
+	if got := orders[1].ParsedDate.Format("2006-01-02"); got != "2024-04-15" || orders[1].Status != "Cancelled" {
+		t.Fatalf("unexpected second order: %+v", orders[1])
+	}
+}
+
+func TestParseOrdersCSVErrors(t *testing.T) {
+	cases := map[string]string{
+		"missing column": "order_id,date\nBS-1,2024-01-01\n",
+		"bad date":       "order_id,date,total\nBS-1,yesterday,200\n",
+		"bad total":      "order_id,date,total\nBS-1,2024-01-01,abc\n",
+		"duplicate":      "order_id,date,total\nBS-1,2024-01-01,200\nBS-1,2024-01-02,200\n",
+	}
+	for name, input := range cases {
+		if _, err := parseOrdersCSV(strings.NewReader(input)); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestSameAccount(t *testing.T) {
+	a := store.Profile{Name: "home", PhoneNumber: "+91 98765-43210"}
+	if err := sameAccount(a, store.Profile{Name: "laptop", PhoneNumber: "9876543210"}); err != nil {
+		t.Fatalf("expected same account: %v", err)
+	}
+	if err := sameAccount(a, store.Profile{Name: "office", PhoneNumber: "9000000000"}); err == nil {
+		t.Fatal("expected a mismatch error")
+	}
+	if err := sameAccount(a, store.Profile{Name: "new"}); err == nil {
+		t.Fatal("expected an error for a profile without a phone number")
+	}
+}
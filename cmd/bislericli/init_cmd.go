@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/schedule"
+)
+
+// runInit walks a new user through login, address, city and the order,
+// schedule and notification defaults, reusing the individual commands for the
+// steps that talk to the site.
+func runInit(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	profileName := fs.String("profile", "", "Profile to set up (default: current/default)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	name := resolveProfileName(*profileName, cfg)
+	profileArg := []string{"--profile", name}
+	p := newPrompter(os.Stdin, os.Stdout)
+
+	fmt.Printf("Setting up profile %q. Press Enter to keep the value in [brackets].\n", name)
+
+	fmt.Println("\n1. Login")
+	profile, _, err := loadOrCreateProfile(name)
+	if err != nil {
+		return err
+	}
+	if len(profile.Cookies) == 0 || p.confirm("A session is already saved. Log in again?", false) {
+		if err := runAuth(append([]string{"login"}, profileArg...)); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("\n2. Delivery city")
+	if profile, _, err = loadOrCreateProfile(name); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	_, options, err := fetchCityOptions(ctx, cfg, profile)
+	cancel()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: skipping city setup:", err)
+	} else {
+		city := p.ask("City", resolveDefaultCity(profile.PreferredCity, options))
+		if err := runCitySet(append(profileArg, city)); err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: city not set; run 'bislericli city set <name>' later:", err)
+		}
+	}
+
+	fmt.Println("\n3. Delivery address")
+	if profile, _, err = loadOrCreateProfile(name); err != nil {
+		return err
+	}
+	if profile.Address != nil && profile.AddressID != "" {
+		fmt.Println("Current address:", strings.TrimSpace(profile.Address.Address1+", "+profile.Address.City))
+	}
+	if profile.Address == nil || profile.AddressID == "" || !p.confirm("Use this address?", true) {
+		label := p.ask("Name for the address", "home")
+		if err := runAddressAdd(append(profileArg, "--pick", label)); err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: address not saved; run 'bislericli address add --pick <name>' later:", err)
+		} else if err := runAddressSetDefault(append(profileArg, label)); err != nil {
+			return err
+		}
+	} else if len(profile.Addresses) == 0 {
+		label := p.ask("Name for the address", "home")
+		if err := runAddressAdd(append(profileArg, label)); err != nil {
+			return err
+		}
+	}
+
+	// The steps above may have saved the config (current profile, city).
+	if cfg, err = config.LoadGlobalConfig(); err != nil {
+		return err
+	}
+
+	fmt.Println("\n4. Order defaults")
+	cfg.Defaults.OrderQuantity = p.askInt("Jars per order", cfg.Defaults.OrderQuantity, 1)
+	cfg.Defaults.ReturnJars = min(p.askInt("Empty jars to return", min(cfg.Defaults.ReturnJars, cfg.Defaults.OrderQuantity), 0), cfg.Defaults.OrderQuantity)
+	cfg.Defaults.Timeslot = p.ask("Delivery timeslot", cfg.Defaults.Timeslot)
+
+	fmt.Println("\n5. Schedule")
+	for {
+		spec := p.ask("Order days (daily, weekly, twice-weekly, thrice-weekly or e.g. mon,thu)", cfg.Defaults.Schedule)
+		at := p.ask("Time of day (HH:MM)", cfg.Defaults.ScheduleTime)
+		if _, err := schedule.Parse(spec, at); err != nil {
+			fmt.Println(err)
+			continue
+		}
+		cfg.Defaults.Schedule, cfg.Defaults.ScheduleTime = spec, at
+		break
+	}
+
+	fmt.Println("\n6. Notifications")
+	cfg.Notifications.Desktop = p.confirm("Desktop notifications for scheduled orders?", cfg.Notifications.Desktop)
+	cfg.Notifications.Digest = p.choose("Spend digest (none, week or month)", cfg.Notifications.Digest, "none", "week", "month")
+
+	cfg.CurrentProfile = name
+	if err := config.SaveGlobalConfig(cfg); err != nil {
+		return err
+	}
+	cfgPath, err := config.ConfigFilePath()
+	if err != nil {
+		return err
+	}
+	fmt.Println("\nSetup complete; settings saved to", cfgPath)
+	fmt.Println("Place an order with 'bislericli order', or run 'bislericli schedule run' to order on schedule.")
+	return nil
+}
+
+// resolveDefaultCity is the city suggested by the wizard: the profile's city
+// when it is serviceable, otherwise the first option.
+func resolveDefaultCity(current string, options []string) string {
+	if match, ok := matchCityOption(current, options); ok {
+		return match
+	}
+	if len(options) > 0 {
+		return options[0]
+	}
+	return current
+}
+
+// prompter asks questions with defaults on one line each.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{in: bufio.NewReader(in), out: out}
+}
+
+// ask returns the answer, or def when the answer is empty or input ends.
+func (p *prompter) ask(question, def string) string {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+	line, _ := p.in.ReadString('\n')
+	if line = strings.TrimSpace(line); line == "" {
+		return def
+	}
+	return line
+}
+
+// askInt asks until the answer is a whole number of at least minValue.
+func (p *prompter) askInt(question string, def, minValue int) int {
+	for {
+		answer := p.ask(question, strconv.Itoa(def))
+		n, err := strconv.Atoi(answer)
+		if err == nil && n >= minValue {
+			return n
+		}
+		if answer == strconv.Itoa(def) {
+			return def
+		}
+		fmt.Fprintf(p.out, "Enter a number of at least %d.\n", minValue)
+	}
+}
+
+func (p *prompter) confirm(question string, def bool) bool {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	fmt.Fprintf(p.out, "%s [%s]: ", question, hint)
+	line, _ := p.in.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	}
+	return def
+}
+
+// choose asks until the answer is one of options. The first option stands
+// for an empty value.
+func (p *prompter) choose(question, def string, options ...string) string {
+	if def == "" {
+		def = options[0]
+	}
+	for {
+		answer := strings.ToLower(p.ask(question, def))
+		for i, opt := range options {
+			if answer != opt {
+				continue
+			}
+			if i == 0 {
+				return ""
+			}
+			return opt
+		}
+		if answer == def {
+			return def
+		}
+		fmt.Fprintf(p.out, "Choose one of: %s.\n", strings.Join(options, ", "))
+	}
+}
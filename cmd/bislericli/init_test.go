@@ -0,0 +1,40 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestPrompterDefaultsAndRetries(t *testing.T) {
+	p := newPrompter(strings.NewReader("\nabc\n0\n3\nYES\nfortnight\nMonth\n"), io.Discard)
+	if got := p.ask("City", "Mumbai"); got != "Mumbai" {
+		t.Fatalf("empty answer should keep the default, got %q", got)
+	}
+	if got := p.askInt("Jars per order", 2, 1); got != 3 {
+		t.Fatalf("askInt should retry until a valid number, got %d", got)
+	}
+	if !p.confirm("Desktop notifications?", false) {
+		t.Fatalf("confirm should accept YES")
+	}
+	if got := p.choose("Digest", "", "none", "week", "month"); got != "month" {
+		t.Fatalf("choose = %q, want month", got)
+	}
+	// Input is exhausted: every question falls back to its default.
+	if got := p.choose("Digest", "", "none", "week", "month"); got != "" {
+		t.Fatalf("the first option should stand for an empty value, got %q", got)
+	}
+	if got := p.askInt("Jars per order", 2, 1); got != 2 {
+		t.Fatalf("askInt at EOF = %d, want default", got)
+	}
+}
+
+func TestResolveDefaultCity(t *testing.T) {
+	options := []string{"Mumbai", "Pune"}
+	if got := resolveDefaultCity("pune", options); got != "Pune" {
+		t.Fatalf("resolveDefaultCity = %q, want Pune", got)
+	}
+	if got := resolveDefaultCity("Delhi", options); got != "Mumbai" {
+		t.Fatalf("unserviceable city should fall back to the first option, got %q", got)
+	}
+}
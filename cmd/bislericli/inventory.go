@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/inventory"
+	"bislericli/internal/notify"
+	"bislericli/internal/schedule"
+	"bislericli/internal/store"
+)
+
+// jarEstimate is the inventory model's view of a profile right now.
+type jarEstimate struct {
+	Jars float64
+	// Rate is jars used a day; zero when it is neither configured nor
+	// learnable yet.
+	Rate    float64
+	Learned bool
+}
+
+// DaysLeft is how long the jars last at the current rate.
+func (e jarEstimate) DaysLeft() (float64, bool) {
+	if e.Rate <= 0 {
+		return 0, false
+	}
+	return e.Jars / e.Rate, true
+}
+
+// consumptionRate returns the configured daily rate or, when unset, the one
+// learned from synced orders.
+func consumptionRate(cfg config.GlobalConfig, profileName string) (rate float64, learned bool) {
+	if cfg.Inventory.DailyJars > 0 {
+		return cfg.Inventory.DailyJars, false
+	}
+	history, err := store.LoadOrderHistory(profileName)
+	if err != nil {
+		return 0, false
+	}
+	rate, ok := inventory.LearnRate(history.Orders, cfg.Defaults.OrderQuantity)
+	return rate, ok
+}
+
+// estimateJars runs the inventory model; ok is false until a delivery or a
+// count has been recorded.
+func estimateJars(cfg config.GlobalConfig, profileName string, now time.Time) (jarEstimate, bool) {
+	ledger, err := inventory.Load(profileName)
+	if err != nil {
+		return jarEstimate{}, false
+	}
+	rate, learned := consumptionRate(cfg, profileName)
+	jars, ok := inventory.Estimate(ledger.Events, rate, now)
+	return jarEstimate{Jars: jars, Rate: rate, Learned: learned}, ok
+}
+
+// jarsRemainingSummary is the status line for the inventory model.
+func jarsRemainingSummary(cfg config.GlobalConfig, profileName string) string {
+	est, ok := estimateJars(cfg, profileName, time.Now())
+	if !ok {
+		return "unknown (run 'bislericli sync' to record deliveries, or 'bislericli jars set <n>')"
+	}
+	summary := fmt.Sprintf("≈%.1f", est.Jars)
+	days, ok := est.DaysLeft()
+	if !ok {
+		return summary + " (usage rate unknown; set inventory.dailyJars)"
+	}
+	source := "configured"
+	if est.Learned {
+		source = "from ordering cadence"
+	}
+	summary += fmt.Sprintf(" (%.2f/day %s, lasts ~%.0f days)", est.Rate, source, days)
+	if cfg.Inventory.ReorderAt > 0 && est.Jars <= cfg.Inventory.ReorderAt {
+		summary += ", time to reorder"
+	}
+	return summary
+}
+
+// recordDeliveries adds delivered orders to the inventory ledger. Orders the
+// site marks delivered without a time count from their order date.
+func recordDeliveries(profileName string, orders []store.SavedOrder) {
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return
+	}
+	if _, err := inventory.Update(profileName, func(l *inventory.Ledger) error {
+		for _, o := range orders {
+			at := o.DeliveredAt
+			if at.IsZero() && isDelivered(o.Status) {
+				at = o.ParsedDate
+			}
+			if at.IsZero() {
+				continue
+			}
+			l.Add(inventory.Event{Time: at, Kind: inventory.Delivery, Jars: float64(schedule.OrderQuantity(o, cfg.Defaults.OrderQuantity)), OrderID: o.OrderID})
+		}
+		return nil
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: failed to update jar inventory:", err)
+		return
+	}
+	maybeRemindReorder(cfg, profileName)
+}
+
+// maybeRemindReorder tells the user once the estimate falls to
+// inventory.reorderAt, and not again until jars are added.
+func maybeRemindReorder(cfg config.GlobalConfig, profileName string) {
+	if cfg.Inventory.ReorderAt <= 0 {
+		return
+	}
+	est, ok := estimateJars(cfg, profileName, time.Now())
+	if !ok || est.Jars > cfg.Inventory.ReorderAt {
+		return
+	}
+	remind := false
+	if _, err := inventory.Update(profileName, func(l *inventory.Ledger) error {
+		remind, l.Reminded = !l.Reminded, true
+		return nil
+	}); err != nil || !remind {
+		return
+	}
+	body := fmt.Sprintf("About %.1f jar(s) left", est.Jars)
+	if days, ok := est.DaysLeft(); ok {
+		body += fmt.Sprintf(", enough for ~%.0f day(s)", days)
+	}
+	body += ". Order with 'bislericli order'."
+	fmt.Println("Running low:", body)
+	sendNotification(cfg.Notifications, notify.Message{Event: notify.RunningLow, Title: "Time to reorder water", Body: body})
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/format"
+	"bislericli/internal/inventory"
+	"bislericli/internal/store"
+)
+
+func runJars(args []string) error {
+	if len(args) > 0 && isHelpToken(args[0]) {
+		printJarsUsage()
+		return nil
+	}
+	action := "show"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+	switch action {
+	case "show":
+		return runJarsShow(args)
+	case "set", "consume":
+		return runJarsAdjust(inventory.Kind(action), args)
+	default:
+		fmt.Printf("Unknown jars subcommand: %s\n", action)
+		printJarsUsage()
+		return nil
+	}
+}
+
+func printJarsUsage() {
+	fmt.Println("Usage: bislericli jars [subcommand] [flags]")
+	fmt.Println("\nAvailable subcommands:")
+	fmt.Println("  show             Estimated jars left and recent deliveries and corrections (default)")
+	fmt.Println("  set <n>          Correct the estimate to the jars you count now")
+	fmt.Println("  consume [n]      Record jars used beyond the usual rate (default 1), e.g. guests or a damaged jar")
+	fmt.Println("\nset and consume take --note and are recorded in the audit log ('orders audit').")
+}
+
+func runJarsShow(args []string) error {
+	fs, profileName := parseScheduleFlags("jars show")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	name := resolveProfileName(*profileName, cfg)
+	fmt.Println(format.KeyValue("Jars remaining", jarsRemainingSummary(cfg, name)))
+	ledger, err := inventory.Load(name)
+	if err != nil {
+		return err
+	}
+	events := ledger.Events
+	if len(events) == 0 {
+		return nil
+	}
+	if len(events) > 10 {
+		events = events[len(events)-10:]
+	}
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tEVENT\tJARS\tDETAIL")
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		detail := e.Note
+		if e.OrderID != "" {
+			detail = "order " + e.OrderID
+		}
+		fmt.Fprintf(w, "%s\t%s\t%g\t%s\n", e.Time.Format("2006-01-02 15:04"), e.Kind, e.Jars, dashIfEmpty(detail))
+	}
+	return w.Flush()
+}
+
+// runJarsAdjust records a counted level (set) or extra use (consume) in the
+// inventory ledger and the audit log.
+func runJarsAdjust(kind inventory.Kind, args []string) error {
+	var raw string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		raw, args = args[0], args[1:]
+	}
+	fs, profileName := parseScheduleFlags("jars " + string(kind))
+	note := fs.String("note", "", "Why, e.g. \"guests\" or \"jar cracked\"")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if raw == "" && fs.NArg() > 0 {
+		raw = fs.Arg(0)
+	}
+	jars := 1.0
+	switch {
+	case raw != "":
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil || n < 0 || n > 100 {
+			return fmt.Errorf("invalid jar count %q", raw)
+		}
+		jars = n
+	case kind == inventory.Set:
+		return errors.New("usage: bislericli jars set <n> [--note TEXT]")
+	}
+	if kind == inventory.Consume && jars == 0 {
+		return errors.New("nothing to consume")
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	name := resolveProfileName(*profileName, cfg)
+	now := time.Now()
+	if _, err := inventory.Update(name, func(l *inventory.Ledger) error {
+		l.Add(inventory.Event{Time: now, Kind: kind, Jars: jars, Note: strings.TrimSpace(*note)})
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := store.AppendAuditEntry(name, store.AuditEntry{
+		Timestamp:  now,
+		Profile:    name,
+		Result:     "inventory",
+		Adjustment: string(kind),
+		Jars:       jars,
+		Note:       strings.TrimSpace(*note),
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: failed to write audit log:", err)
+	}
+	if kind == inventory.Set {
+		fmt.Printf("Recorded %g jar(s) on hand.\n", jars)
+	} else {
+		fmt.Printf("Recorded %g extra jar(s) used.\n", jars)
+	}
+	fmt.Println(format.KeyValue("Jars remaining", jarsRemainingSummary(cfg, name)))
+	maybeRemindReorder(cfg, name)
+	return nil
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"bislericli/internal/auth"
+	"bislericli/internal/bisleri"
+	"bislericli/internal/config"
+	"bislericli/internal/fileutil"
+	"bislericli/internal/jobs"
+	"bislericli/internal/store"
+)
+
+// jobPoll is how often the scheduler looks for queued jobs while it waits for
+// the next run.
+const jobPoll = time.Minute
+
+func runJobs(args []string) error {
+	if len(args) < 1 || isHelpToken(args[0]) {
+		printJobsUsage()
+		return nil
+	}
+	switch args[0] {
+	case "list":
+		return runJobsList(args[1:])
+	case "add":
+		return runJobsAdd(args[1:])
+	case "retry":
+		return runJobsRetry(args[1:])
+	case "cancel":
+		return runJobsCancel(args[1:])
+	default:
+		fmt.Printf("Unknown jobs subcommand: %s\n", args[0])
+		printJobsUsage()
+		return nil
+	}
+}
+
+func printJobsUsage() {
+	fmt.Println("Usage: bislericli jobs <subcommand> [flags]")
+	fmt.Println("\nAvailable subcommands:")
+	fmt.Println("  list                 Show queued, running and finished jobs")
+	fmt.Println("  add <kind> [-- ...]  Queue a place-order, sync or refresh-auth job for the scheduler")
+	fmt.Println("  retry <id>           Queue a failed or canceled job again (--now to run it here)")
+	fmt.Println("  cancel <id>          Cancel a queued job")
+	fmt.Println("\nThe scheduler ('schedule run') records its orders as jobs and runs queued jobs")
+	fmt.Printf("for its profile within %s.\n", jobPoll)
+}
+
+func runJobsList(args []string) error {
+	fs := flag.NewFlagSet("jobs list", flag.ContinueOnError)
+	profileName := fs.String("profile", "", "Only jobs for this profile")
+	status := fs.String("status", "", "Only jobs with this status (queued, running, succeeded, failed, canceled)")
+	limit := fs.Int("limit", 20, "Maximum jobs to show (0 for all)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	q, err := jobs.Open()
+	if err != nil {
+		return err
+	}
+	all, err := q.List()
+	if err != nil {
+		return err
+	}
+	var shown []jobs.Job
+	for _, j := range all {
+		if *profileName != "" && j.Profile != *profileName || *status != "" && string(j.Status) != *status {
+			continue
+		}
+		shown = append(shown, j)
+	}
+	if len(shown) == 0 {
+		fmt.Println("No jobs.")
+		return nil
+	}
+	more := 0
+	if *limit > 0 && len(shown) > *limit {
+		more = len(shown) - *limit
+		shown = shown[:*limit]
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tKIND\tPROFILE\tSTATUS\tTRIES\tCREATED\tFINISHED\tERROR")
+	for _, j := range shown {
+		finished := "-"
+		if !j.Finished.IsZero() {
+			finished = j.Finished.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\n", j.ID, j.Kind, j.Profile, j.Status, j.Attempts, j.Created.Format("2006-01-02 15:04"), finished, dashIfEmpty(shortError(j.Error)))
+	}
+	w.Flush()
+	if more > 0 {
+		fmt.Printf("(%d older; use --limit 0 to show all)\n", more)
+	}
+	return nil
+}
+
+func runJobsAdd(args []string) error {
+	fs, profileName := parseScheduleFlags("jobs add")
+	fs.Usage = func() {
+		fmt.Println("Usage: bislericli jobs add [--profile NAME] <place-order|sync|refresh-auth> [-- command flags]")
+		fmt.Println("\nFlags after -- are passed to the command, e.g. 'jobs add place-order -- --qty 3'.")
+		fmt.Println()
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return errors.New("missing job kind")
+	}
+	kind := jobs.Kind(fs.Arg(0))
+	if !validJobKind(kind) {
+		return fmt.Errorf("unknown job kind %q", kind)
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	name := resolveProfileName(*profileName, cfg)
+	q, err := jobs.Open()
+	if err != nil {
+		return err
+	}
+	cmdArgs := fs.Args()[1:]
+	if len(cmdArgs) > 0 && cmdArgs[0] == "--" {
+		cmdArgs = cmdArgs[1:]
+	}
+	job, err := q.Enqueue(kind, name, cmdArgs, "cli")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Queued job %s (%s for profile '%s'); 'schedule run' picks it up, see 'bislericli jobs list'.\n", job.ID, job.Kind, job.Profile)
+	return nil
+}
+
+func runJobsRetry(args []string) error {
+	fs := flag.NewFlagSet("jobs retry", flag.ContinueOnError)
+	now := fs.Bool("now", false, "Run the job in this process instead of leaving it to the scheduler")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: bislericli jobs retry [--now] <id>")
+	}
+	q, err := jobs.Open()
+	if err != nil {
+		return err
+	}
+	job, err := q.Retry(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if !*now {
+		fmt.Printf("Job %s queued again; 'schedule run' picks it up, or retry with --now.\n", job.ID)
+		return nil
+	}
+	if job, err = q.Claim(job.ID); err != nil {
+		return err
+	}
+	return runJob(q, job)
+}
+
+func runJobsCancel(args []string) error {
+	if len(args) != 1 || isHelpToken(args[0]) {
+		return errors.New("usage: bislericli jobs cancel <id>")
+	}
+	q, err := jobs.Open()
+	if err != nil {
+		return err
+	}
+	job, err := q.Cancel(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Canceled job %s (%s).\n", job.ID, job.Kind)
+	return nil
+}
+
+// shortError keeps the first line of an error, cut to fit a table row.
+func shortError(msg string) string {
+	msg, _, _ = strings.Cut(msg, "\n")
+	if r := []rune(msg); len(r) > 60 {
+		msg = string(r[:59]) + "…"
+	}
+	return msg
+}
+
+func validJobKind(kind jobs.Kind) bool {
+	for _, k := range jobs.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// runNewJob records an operation as a job and runs it here. A queue that
+// cannot be written is warned about and the operation runs anyway.
+func runNewJob(kind jobs.Kind, profileName string, args []string, source string) error {
+	q, err := jobs.Open()
+	if err == nil {
+		var job jobs.Job
+		if job, err = q.Enqueue(kind, profileName, args, source); err == nil {
+			if job, err = q.Claim(job.ID); err == nil {
+				return runJob(q, job)
+			}
+		}
+	}
+	fmt.Fprintln(os.Stderr, "Warning: job not recorded:", err)
+	return executeJob(jobs.Job{Kind: kind, Profile: profileName, Args: args})
+}
+
+// runJob runs a claimed job and records its outcome.
+func runJob(q *jobs.Queue, job jobs.Job) error {
+	err := executeJob(job)
+	if _, finishErr := q.Finish(job.ID, err); finishErr != nil {
+		fmt.Fprintln(os.Stderr, "Warning: failed to record job result:", finishErr)
+	}
+	return err
+}
+
+func executeJob(job jobs.Job) error {
+	args := append([]string{"--profile", job.Profile}, job.Args...)
+	switch job.Kind {
+	case jobs.PlaceOrder:
+		return runOrder(args)
+	case jobs.Sync:
+		return runSync(args)
+	case jobs.RefreshAuth:
+		return refreshAuth(job.Profile)
+	}
+	return fmt.Errorf("unknown job kind %q", job.Kind)
+}
+
+// runQueuedJobs runs the profile's queued jobs one after another until none
+// is left or ctx ends. Jobs left running by a process that exited are marked
+// failed first so they can be retried.
+func runQueuedJobs(ctx context.Context, profileName string) {
+	q, err := jobs.Open()
+	if err != nil {
+		return
+	}
+	if _, err := q.Recover(fileutil.ProcessAlive); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: failed to read job queue:", err)
+		return
+	}
+	for ctx.Err() == nil {
+		job, ok, err := q.Next(profileName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: failed to read job queue:", err)
+			return
+		}
+		if !ok {
+			return
+		}
+		fmt.Printf("Running job %s (%s %s)\n", job.ID, job.Kind, strings.Join(job.Args, " "))
+		if err := runJob(q, job); err != nil {
+			fmt.Fprintf(os.Stderr, "Job %s failed: %v\n", job.ID, err)
+		}
+	}
+}
+
+// refreshAuth checks the profile's session and, when it has expired, logs in
+// again with the password saved in the keychain. It never prompts, so it can
+// run unattended.
+func refreshAuth(profileName string) error {
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, profilePath, err := loadOrCreateProfile(profileName)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(lifecycle.Context(), 2*time.Minute)
+	defer cancel()
+	if len(profile.Cookies) > 0 {
+		sess, err := newSession(cfg, &profile, profilePath, 30*time.Second)
+		if err != nil {
+			return err
+		}
+		err = sess.verify(ctx)
+		if err == nil {
+			fmt.Println("Session is valid.")
+			return nil
+		}
+		if !errors.Is(err, bisleri.ErrNotAuthenticated) {
+			return err
+		}
+	}
+	if profile.Email == "" {
+		return errors.New("session expired and the profile has no email for password login; run 'bislericli auth login'")
+	}
+	password, err := auth.LoadPassword(profile.Email)
+	if err != nil || password == "" {
+		return errors.New("session expired and no password is saved in the keychain; run 'bislericli auth login --method password --save-password'")
+	}
+	cookies, err := auth.LoginWithPassword(ctx, profile.Email, password)
+	if err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+	if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+		p.Cookies = cookies
+		p.LastLogin = time.Now()
+		return nil
+	}); err != nil {
+		return err
+	}
+	fmt.Println("Logged in again with the saved password.")
+	return nil
+}
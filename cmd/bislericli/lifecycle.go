@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+	"time"
+
+	appruntime "bislericli/internal/runtime"
+)
+
+// shutdownGrace is how long a stopping command waits for an order that is
+// being submitted before aborting it.
+const shutdownGrace = 2 * time.Minute
+
+// lifecycle is the shutdown state of the running long-running mode; nil when
+// the command is not one, which makes every use a no-op.
+var lifecycle *appruntime.Lifecycle
+
+// startLifecycle installs SIGINT/SIGTERM handling for a long-running mode and
+// returns the lifecycle with the function that ends it. A mode entered from
+// another, such as an order waiting for a slot under the scheduler, shares
+// the outer lifecycle.
+func startLifecycle() (*appruntime.Lifecycle, func()) {
+	if lifecycle != nil {
+		return lifecycle, func() {}
+	}
+	lc := appruntime.Start(shutdownGrace, os.Stderr)
+	lifecycle = lc
+	return lc, func() {
+		lc.Close()
+		lifecycle = nil
+	}
+}
@@ -8,20 +8,29 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math/rand/v2"
 	"net/http"
 	"os"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"strings"
 	"text/tabwriter"
 	"time"
 
+	"bislericli/internal/address"
 	"bislericli/internal/auth"
 	"bislericli/internal/bisleri"
 	"bislericli/internal/config"
 	"bislericli/internal/debug"
+	"bislericli/internal/fileutil"
 	"bislericli/internal/format"
+	"bislericli/internal/geocode"
+	"bislericli/internal/i18n"
+	"bislericli/internal/money"
+	"bislericli/internal/notify"
+	"bislericli/internal/policy"
+	"bislericli/internal/progress"
+	"bislericli/internal/redact"
 	"bislericli/internal/store"
 )
 
@@ -37,8 +46,12 @@ var (
 )
 
 func main() {
-	if err := run(); err != nil {
+	err := run()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+	summary.print(os.Stderr, err)
+	if err != nil {
 		os.Exit(1)
 	}
 }
@@ -51,8 +64,18 @@ func run() error {
 
 	cmd := os.Args[1]
 	args := os.Args[2:]
+	money.Symbol = format.CurrencySymbol()
+	cfg, loaded := startupConfig()
+	initLanguage(cfg)
+	loadTimezone(cfg)
+	loadSelectorOverrides()
+	if loaded {
+		loadClientOptions(cfg)
+	}
 
 	switch cmd {
+	case "init":
+		return runInit(args)
 	case "auth":
 		return runAuth(args)
 	case "profile":
@@ -69,9 +92,45 @@ func run() error {
 		return runConfig(args)
 	case "schedule":
 		return runSchedule(args)
+	case "report":
+		return runReport(args)
 	case "version":
 		fmt.Println(version)
 		return nil
+	case "update":
+		return runUpdate(args)
+	case "doctor":
+		return runDoctor(args)
+	case "status":
+		return runStatus(args)
+	case "address":
+		return runAddress(args)
+	case "city":
+		return runCity(args)
+	case "slots":
+		return runSlots(args)
+	case "jobs":
+		return runJobs(args)
+	case "serve":
+		return runServe(args)
+	case "bot":
+		return runBot(args)
+	case "jars":
+		return runJars(args)
+	case "holidays":
+		return runHolidays(args)
+	case "account":
+		return runAccount(args)
+	case "offers":
+		return runOffers(args)
+	case "products":
+		return runProducts(args)
+	case "check":
+		return runCheck(args)
+	case "complain":
+		return runComplain(args)
+	case "backup":
+		return runBackup(args)
 	case "debug":
 		return runDebug(args)
 	case "-h", "--help", "help":
@@ -95,21 +154,40 @@ func printUsage() {
 	fmt.Fprintln(w, "  auth status\tCheck current login status")
 	fmt.Fprintln(w, "  profile list\tList all available profiles")
 	fmt.Fprintln(w, "  profile use\tSwitch to a different profile")
+	fmt.Fprintln(w, "  address\tManage named delivery addresses")
+	fmt.Fprintln(w, "  city set\tChange the delivery city")
+	fmt.Fprintln(w, "  account\tShow or edit account name and email")
 	w.Flush()
 
 	fmt.Println("\nOrders & Stats:")
 	fmt.Fprintln(w, "  order\tPlace a new water can order")
 	fmt.Fprintln(w, "  orders\tView your order history")
+	fmt.Fprintln(w, "  status\tOne-screen summary of session, wallet, last and next order")
+	fmt.Fprintln(w, "  jars\tEstimated jars left; correct it with 'jars set' or 'jars consume'")
+	fmt.Fprintln(w, "  holidays\tUpcoming public holidays for your state; 'holidays fetch' updates the calendar")
 	fmt.Fprintln(w, "  sync\tFetch and cache recent data from server")
 	fmt.Fprintln(w, "  stats\tAnalyze spending habits and patterns")
 	fmt.Fprintln(w, "  schedule\tManage recurring order schedules")
+	fmt.Fprintln(w, "  jobs\tList, queue, retry or cancel scheduler jobs")
+	fmt.Fprintln(w, "  serve\tAccept signed order webhooks and run queued jobs")
+	fmt.Fprintln(w, "  bot\tTelegram bot: /order, /status, /balance and /sync from allowed chats")
+	fmt.Fprintln(w, "  report\tWeekly or monthly digest of orders, spend and wallet")
+	fmt.Fprintln(w, "  offers list\tCurrent promotions and coupon codes for your city")
+	fmt.Fprintln(w, "  products list\tProduct IDs, pack sizes, prices and availability")
+	fmt.Fprintln(w, "  slots list\tDelivery slots the site offers and whether the configured one matches")
+	fmt.Fprintln(w, "  check pincode\tCheck whether jars are delivered to a pincode")
+	fmt.Fprintln(w, "  complain\tDraft or file a customer-care complaint about an order")
 	w.Flush()
 
 	fmt.Println("\nConfiguration:")
+	fmt.Fprintln(w, "  init\tGuided first-run setup: login, city, address, defaults, schedule")
 	fmt.Fprintln(w, "  config show\tDisplay current configuration")
+	fmt.Fprintln(w, "  doctor\tCheck environment, profile and site health")
+	fmt.Fprintln(w, "  backup\tCreate or restore a backup of profiles and history")
 	w.Flush()
 	fmt.Println("\nFlags:")
 	fmt.Println("  version            Show version information")
+	fmt.Println("  update             Update to the latest release (--check to only report)")
 	fmt.Println("  --help             Show this help message")
 	fmt.Println()
 	fmt.Println("Note: flags like --profile are command-specific.")
@@ -128,8 +206,10 @@ func runAuth(args []string) error {
 	case "login":
 		fs := flag.NewFlagSet("auth login", flag.ContinueOnError)
 		profileName := fs.String("profile", "", "profile name")
-		method := fs.String("method", "otp", "login method: otp (default) or browser")
+		method := fs.String("method", "otp", "login method: otp (default), password or browser")
 		phone := fs.String("phone", "", "phone number (10 digits, will prompt if not provided)")
+		email := fs.String("email", "", "account email for --method password (will prompt if not provided)")
+		savePassword := fs.Bool("save-password", false, "store the password in the OS keychain (--method password)")
 		if err := fs.Parse(subArgs); err != nil {
 			if errors.Is(err, flag.ErrHelp) {
 				return nil
@@ -148,6 +228,7 @@ func runAuth(args []string) error {
 
 		var cookies []store.Cookie
 		var phoneNumber string
+		loginEmail := existingProfile.Email
 
 		switch *method {
 		case "browser":
@@ -156,6 +237,22 @@ func runAuth(args []string) error {
 			if err != nil {
 				return err
 			}
+		case "password":
+			if *email != "" {
+				loginEmail = *email
+			}
+			if loginEmail == "" {
+				fmt.Print("Email: ")
+				input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+				loginEmail = strings.TrimSpace(input)
+			}
+			if !strings.Contains(loginEmail, "@") {
+				return fmt.Errorf("invalid email %q", loginEmail)
+			}
+			cookies, err = passwordLogin(context.Background(), loginEmail, *savePassword)
+			if err != nil {
+				return fmt.Errorf("login failed: %w", err)
+			}
 		case "otp":
 			fallthrough
 		default:
@@ -186,38 +283,26 @@ func runAuth(args []string) error {
 				return fmt.Errorf("invalid phone number: must be 10 digits, got %d", len(phoneNumber))
 			}
 
-			cookies, err = auth.LoginWithOTP(context.Background(), phoneNumber)
+			cookies, err = auth.LoginWithOTP(context.Background(), phoneNumber, otpLogFor(profilePath, existingProfile, phoneNumber))
 			if err != nil {
 				return fmt.Errorf("login failed: %w", err)
 			}
 		}
 
-		profile := store.Profile{
-			Name:        name,
-			Cookies:     cookies,
-			PhoneNumber: phoneNumber,
-			LastLogin:   time.Now(),
-		}
-
-		// Preserve existing profile data
-		if existingProfile.AddressID != "" {
-			profile.AddressID = existingProfile.AddressID
-		}
-		if existingProfile.Address != nil {
-			profile.Address = existingProfile.Address
-		}
-		if existingProfile.PreferredCity != "" {
-			profile.PreferredCity = existingProfile.PreferredCity
-		}
-		if phoneNumber == "" && existingProfile.PhoneNumber != "" {
-			profile.PhoneNumber = existingProfile.PhoneNumber
-		}
-
-		if err := store.SaveProfile(profilePath, profile); err != nil {
+		profile, err := saveLoginSession(profilePath, cookies, phoneNumber, loginEmail, time.Now())
+		if err != nil {
 			return err
 		}
-		if err := tryCaptureAddress(profilePath, &profile); err == nil {
-			_ = store.SaveProfile(profilePath, profile)
+		// A named default address was chosen by the user; leave it alone.
+		if profile.DefaultAddress == "" {
+			if err := tryCaptureAddress(profilePath, &profile); err == nil {
+				_, _ = store.UpdateProfile(profilePath, func(p *store.Profile) error {
+					p.AddressID = profile.AddressID
+					p.Address = profile.Address
+					p.AddressSource = profile.AddressSource
+					return nil
+				})
+			}
 		}
 		cfg.CurrentProfile = name
 		if err := config.SaveGlobalConfig(cfg); err != nil {
@@ -225,6 +310,8 @@ func runAuth(args []string) error {
 		}
 		fmt.Println("Login captured for profile:", name)
 		return nil
+	case "otp-relay":
+		return runAuthOTPRelay(subArgs)
 	case "status":
 		fs := flag.NewFlagSet("auth status", flag.ContinueOnError)
 		profileName := fs.String("profile", "", "profile name")
@@ -254,10 +341,14 @@ func runAuth(args []string) error {
 		if profile.PhoneNumber != "" {
 			fmt.Println(format.KeyValue("Phone", profile.PhoneNumber))
 		}
+		if profile.Email != "" {
+			fmt.Println(format.KeyValue("Email", profile.Email))
+		}
 		return nil
 	case "logout":
 		fs := flag.NewFlagSet("auth logout", flag.ContinueOnError)
 		profileName := fs.String("profile", "", "profile name")
+		forgetPassword := fs.Bool("forget-password", false, "also remove the keychain password saved by --save-password")
 		if err := fs.Parse(subArgs); err != nil {
 			if errors.Is(err, flag.ErrHelp) {
 				return nil
@@ -283,10 +374,20 @@ func runAuth(args []string) error {
 				fmt.Fprintln(os.Stderr, "Warning: remote logout failed:", err)
 			}
 		}
-		profile.Cookies = nil
-		if err := store.SaveProfile(profilePath, profile); err != nil {
+		if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+			p.Cookies = nil
+			return nil
+		}); err != nil {
 			return err
 		}
+		if err := store.ClearPageCache(name); err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: failed to clear cached pages:", err)
+		}
+		if *forgetPassword && profile.Email != "" {
+			if err := auth.DeletePassword(profile.Email); err != nil {
+				fmt.Fprintln(os.Stderr, "Warning: failed to remove saved password:", err)
+			}
+		}
 		fmt.Println("Logged out profile:", name)
 		return nil
 	default:
@@ -363,95 +464,194 @@ func runOrder(args []string) error {
 	quantity := fs.Int("qty", 0, "Number of 20L jars to order")
 	returnJars := fs.Int("return", -1, "Number of empty jars to return (default: matches order qty)")
 	allowExtra := fs.Bool("allow-extra", false, "Proceed even if cart contains other items")
+	strictBudget := fs.Bool("strict-budget", false, "Abort instead of warning when the order exceeds the monthly budget")
 	debug := fs.Bool("debug", false, "Enable verbose debug logging")
+	quiet := fs.Bool("quiet", false, "Only print the order ID on success; warnings still go to stderr")
+	progressMode := fs.String("progress", "text", "Progress output: text or json (JSON lines of stage events on stdout)")
+	showTimings := fs.Bool("timings", false, "Print how long each stage of the order took, to stderr")
+	force := fs.Bool("force", false, "Place the order even if a recent order is still pending")
+	note := fs.String("note", "", "Gift message / delivery note for this order")
+	recipientName := fs.String("recipient-name", "", "Deliver to this contact name instead of the address holder")
+	recipientPhone := fs.String("recipient-phone", "", "Contact phone for the recipient (10 digits)")
+	addressName := fs.String("address", "", "Deliver to this saved address instead of the default (see 'address list')")
+	timeslotFlag := fs.String("timeslot", "", "Delivery timeslot for this order (default: the address's preferred slot, then config)")
+	waitForSlot := fs.Bool("wait-for-slot", false, "If every delivery slot is full, keep retrying until one opens")
+	slotPoll := fs.Duration("slot-poll", 5*time.Minute, "Time between retries with --wait-for-slot (minimum 1m)")
+	slotDeadline := fs.Duration("slot-deadline", 6*time.Hour, "Give up waiting for a slot after this long")
+	unattended := fs.Bool("unattended", false, "Enforce the policy rules from config (set by 'schedule run')")
+	forTag := fs.String("for", "", "Attribute the order to a household member or location, e.g. \"office\" (see 'stats --by-tag')")
+	lockWait := fs.Duration("lock-wait", 0, "If another order for the profile is running, wait this long for it instead of exiting")
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
 			return nil
 		}
 		return err
 	}
+	var sinks []progress.Sink
+	switch *progressMode {
+	case "text":
+	case "json":
+		sinks = append(sinks, progress.JSONLines(os.Stdout))
+		*quiet = true
+	default:
+		return fmt.Errorf("invalid --progress %q (want text or json)", *progressMode)
+	}
+	// The tracker always runs: stage timings go to the audit log.
+	tracker := progress.NewTracker(sinks...)
+	defer setQuiet(*quiet)()
+	summary.begin("order")
 	cfg, err := config.LoadGlobalConfig()
 	if err != nil {
 		return err
 	}
 	name := resolveProfileName(*profileName, cfg)
+	summary.setProfile(name)
+	unlock, err := lockOrders(name, *lockWait)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	// Loaded under the lock so the pending-order check sees an order another
+	// process just placed.
 	profile, profilePath, err := loadOrCreateProfile(name)
 	if err != nil {
 		return err
 	}
 	if len(profile.Cookies) == 0 {
-		return errors.New("no cookies in profile; run 'bislericli auth login'")
+		return errors.New(i18n.T("no cookies in profile; run 'bislericli auth login'"))
+	}
+	if *addressName != "" {
+		saved, ok := profile.FindAddress(*addressName)
+		if !ok {
+			return fmt.Errorf("no saved address named %q; see 'bislericli address list'", *addressName)
+		}
+		// Only this order uses it; the profile's default address is not changed.
+		profile.Address = &saved.Address
+		profile.AddressID = saved.ID
+		*addressName = saved.Name
 	}
 
+	timeslot := orderTimeslot(*timeslotFlag, profile, *addressName, cfg.Defaults.Timeslot)
+
 	if *quantity == 0 {
 		*quantity = cfg.Defaults.OrderQuantity
 	}
 	if *quantity <= 0 {
-		return errors.New("quantity must be a positive number")
+		return errors.New(i18n.T("quantity must be a positive number"))
 	}
 	if *returnJars < 0 {
 		*returnJars = *quantity
 	}
 	if *returnJars > *quantity {
-		return fmt.Errorf("return jars (%d) cannot exceed order quantity (%d)", *returnJars, *quantity)
+		return errors.New(i18n.T("return jars (%d) cannot exceed order quantity (%d)", *returnJars, *quantity))
+	}
+	batches := splitOrder(*quantity, *returnJars, cfg.Defaults.MaxPerLine)
+	if len(batches) > 1 {
+		switch cfg.Defaults.BulkSplit {
+		case "", "orders":
+		case "off":
+			return fmt.Errorf("quantity %d exceeds the %d jars allowed per order; lower --qty or set defaults.bulkSplit to \"orders\"", *quantity, cfg.Defaults.MaxPerLine)
+		default:
+			return fmt.Errorf("invalid defaults.bulkSplit %q (want orders or off)", cfg.Defaults.BulkSplit)
+		}
+	}
+	if *unattended {
+		if err := policy.CheckTimeslot(cfg.Policy, timeslot); err != nil {
+			return err
+		}
+		if err := policy.CheckWeeklyOrders(cfg.Policy, recentOrderTimes(name, profile.LastOrder), time.Now(), len(batches)); err != nil {
+			return err
+		}
+	}
+	if *waitForSlot && *slotPoll < time.Minute {
+		return errors.New("--slot-poll must be at least 1m")
+	}
+	if *recipientPhone != "" {
+		*recipientPhone = normalizePhoneNumber(*recipientPhone)
+		if len(*recipientPhone) != 10 {
+			return fmt.Errorf("invalid --recipient-phone: must be 10 digits, got %d", len(*recipientPhone))
+		}
 	}
 
-	runOrderOnce := func() error {
-		fmt.Printf("Placing order: %d jar(s), returning %d jar(s)\n", *quantity, *returnJars)
+	// stage is the flow stage the current attempt reached and stageClient its
+	// client, for the recovery panel when the order fails.
+	// endCommit releases the lifecycle's hold on the attempt once it reached
+	// the commit stages, after its audit entry is written.
+	var (
+		stage       string
+		stageClient *bisleri.Client
+		endCommit   func()
+	)
+	enter := func(s string) {
+		stage = s
+		tracker.Enter(s)
+	}
+	// placedOrders holds the last order from before this run and each batch
+	// placed since, so a split order's budget check counts every earlier batch.
+	placedOrders := []*store.OrderInfo{profile.LastOrder}
+
+	runOrderOnce := func(audit *store.AuditEntry) error {
+		progressln(i18n.T("Placing order: %d jar(s), returning %d jar(s)", *quantity, *returnJars))
 
-		jar, err := bisleri.JarFromCookies(profile.Cookies)
+		enter("session")
+		sess, err := newSession(cfg, &profile, profilePath, 40*time.Second)
 		if err != nil {
 			return err
 		}
-		client := bisleri.NewClient(&http.Client{Jar: jar, Timeout: 40 * time.Second}, log.New(os.Stderr, "bisleri: ", log.LstdFlags))
+		client := sess.Client
+		stageClient = client
 		if *debug {
 			client.Debug = true
 		}
+		red := profileRedactor(cfg, profile)
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		ctx, cancel := context.WithTimeout(lifecycle.Context(), 5*time.Minute)
 		defer cancel()
 
-		fmt.Println("Checking session...")
-		if err := client.VerifyAuthenticated(ctx); err != nil {
+		progressln(i18n.T("Checking session..."))
+		if err := sess.verify(ctx); err != nil {
 			return err
 		}
+		if !*force {
+			if err := checkPendingOrder(ctx, sess, name, profile.LastOrder, cfg.Defaults.PendingOrderDays); err != nil {
+				return err
+			}
+		}
 
-		fmt.Println("Preparing cart...")
-		cartHTML, cartErr := client.FetchCartPage(ctx)
+		enter("cart")
+		progressln(i18n.T("Preparing cart..."))
+		cart, cartErr := sess.cart(ctx)
 		if cartErr == nil {
-			updatedHTML, err := ensureCityLocation(ctx, client, profilePath, &profile, cartHTML)
-			if err != nil {
+			if cart, err = sess.ensureCity(ctx, cart); err != nil {
 				return err
 			}
-			cartHTML = updatedHTML
 		}
+		jarID := jarProductID(profile.PreferredCity)
+		var cartItems []bisleri.CartItem
 		if cartErr == nil {
-			cartItems := bisleri.ExtractCartItems(cartHTML)
-			if count, ok := bisleri.ExtractCartCount(cartHTML); ok && count > 0 && len(cartItems) == 0 {
-				return errors.New("unable to parse cart items; please clear cart or try again")
+			cartItems = cart.Items
+			if cart.Unparsed() {
+				return withUpgradeHint(errors.New("unable to parse cart items; please clear cart or try again"))
 			}
-			extraItems := filterExtraItems(cartItems, productID20L)
+			extraItems := filterExtraItems(cartItems, jarID)
 			if len(extraItems) > 0 && !*allowExtra {
-				return fmt.Errorf("cart contains other items; clear cart or pass --allow-extra (items: %s)", strings.Join(extraItems, ", "))
+				return &cartConflictError{Items: extraItems}
 			}
-			if uuid, existingQty, ok := bisleri.ExtractCartItem(cartHTML, productID20L); ok && uuid != "" {
-				if existingQty != *quantity {
-					fmt.Println("Updating cart quantity...")
-					if err := client.UpdateQuantity(ctx, productID20L, uuid, *quantity); err != nil {
+			if item, ok := cart.Item(jarID); ok && item.UUID != "" {
+				if item.Quantity != *quantity {
+					progressln(i18n.T("Updating cart quantity..."))
+					if err := client.UpdateQuantity(ctx, jarID, item.UUID, *quantity); err != nil {
 						return err
 					}
 				} else {
-					fmt.Println("Cart already at desired quantity.")
+					progressln(i18n.T("Cart already at desired quantity."))
 				}
 			} else {
 				if len(cartItems) > 0 && !*allowExtra {
-					return errors.New("cart is not empty; clear cart or pass --allow-extra")
-				}
-				fmt.Println("Adding product to cart...")
-				if err := client.AddProduct(ctx, productID20L, *quantity); err != nil {
-					return err
+					return &cartConflictError{}
 				}
-				if err := confirmCartQuantity(ctx, client, productID20L, *quantity, *allowExtra); err != nil {
+				progressln(i18n.T("Adding product to cart..."))
+				if err := addJarToCart(ctx, client, profile.PreferredCity, &jarID, *quantity, *allowExtra); err != nil {
 					return err
 				}
 			}
@@ -460,15 +660,14 @@ func runOrder(args []string) error {
 				return cartErr
 			}
 			fmt.Fprintln(os.Stderr, "Warning: unable to fetch cart; proceeding to add product:", cartErr)
-			fmt.Println("Adding product to cart...")
-			if err := client.AddProduct(ctx, productID20L, *quantity); err != nil {
-				return err
-			}
-			if err := confirmCartQuantity(ctx, client, productID20L, *quantity, *allowExtra); err != nil {
+			progressln(i18n.T("Adding product to cart..."))
+			if err := addJarToCart(ctx, client, profile.PreferredCity, &jarID, *quantity, *allowExtra); err != nil {
 				return err
 			}
 		}
-		fmt.Println("Setting return jars...")
+		audit.Items = orderedItems(cartItems, jarID, *quantity)
+		enter("return-jars")
+		progressln(i18n.T("Setting return jars..."))
 		if err := client.UpdateJarQuantity(ctx, *returnJars); err != nil {
 			return err
 		}
@@ -481,17 +680,7 @@ func runOrder(args []string) error {
 		}
 
 		if profile.Address != nil && profile.AddressID != "" {
-			addr := *profile.Address
-			if profile.PreferredCity != "" && !strings.EqualFold(addr.City, profile.PreferredCity) {
-				addr.City = profile.PreferredCity
-			}
-			if addr.City == "" {
-				addr.City = profile.PreferredCity
-			}
-			normalizeStateCode(&addr)
-			if addr.Country == "" {
-				addr.Country = "IN"
-			}
+			addr := orderAddress(profile)
 			if addressReadyForLocation(addr) {
 				if err := client.SetSavedAddressLocation(ctx, addr, profile.AddressID); err != nil && *debug {
 					fmt.Fprintln(os.Stderr, "bisleri: set saved address warning:", err)
@@ -508,7 +697,10 @@ func runOrder(args []string) error {
 		case <-time.After(300 * time.Millisecond):
 		}
 
-		fmt.Println("Fetching shipping details...")
+		var jarPrice, prevJarPrice money.Money
+
+		enter("shipping")
+		progressln(i18n.T("Fetching shipping details..."))
 		// Try BeginCheckout first, with retry logic
 		var beginErr error
 		for attempt := 1; attempt <= 2; attempt++ {
@@ -535,7 +727,7 @@ func runOrder(args []string) error {
 		if err != nil {
 			var statusErr *bisleri.HTTPStatusError
 			if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusInternalServerError {
-				fmt.Println("Shipping page returned 500. Initializing checkout and retrying...")
+				progressln("Shipping page returned 500. Initializing checkout and retrying...")
 				if retryErr := client.BeginCheckout(ctx); retryErr != nil && *debug {
 					fmt.Fprintln(os.Stderr, "bisleri: checkout retry warning:", retryErr)
 				}
@@ -550,18 +742,17 @@ func runOrder(args []string) error {
 		}
 		csrfToken, err := bisleri.ExtractCSRFToken(shippingHTML)
 		if err != nil {
-			return fmt.Errorf("failed to parse csrf token (session expired?): %w", err)
+			noteParseFailure("csrf token", "shipping", shippingHTML)
+			return withUpgradeHint(fmt.Errorf("failed to parse csrf token (session expired?): %w", err))
 		}
 		shipmentUUID, err := bisleri.ExtractShipmentUUID(shippingHTML)
 		if err != nil {
 			// Debug: save shipping HTML to file ONLY if debug is enabled
 			if *debug {
-				debugFile := debugFilePath("shipping_page_debug.html")
-				if writeErr := os.WriteFile(debugFile, []byte(shippingHTML), 0600); writeErr == nil {
-					fmt.Fprintf(os.Stderr, "Debug: Shipping HTML saved to %s\n", debugFile)
-				}
+				writeDebugFile("shipping_page_debug.html", shippingHTML, red, "Shipping HTML")
 			}
-			return fmt.Errorf("failed to parse shipment UUID: %w", err)
+			noteParseFailure("shipment uuid", "shipping", shippingHTML)
+			return withUpgradeHint(fmt.Errorf("failed to parse shipment UUID: %w", err))
 		}
 
 		if profile.Address == nil || profile.AddressID == "" {
@@ -570,134 +761,400 @@ func runOrder(args []string) error {
 				return err
 			}
 			if len(candidates) == 0 {
-				return errors.New("no address found in account; set a default address on bisleri.com and retry")
+				return errors.New(i18n.T("no address found in account; set a default address on bisleri.com and retry"))
 			}
 			choice := selectAddress(candidates)
 			profile.AddressID = choice.ID
 			profile.Address = &choice.Address
 			profile.AddressSource = "shipping-page"
-			ensureAddressComplete(profile.Address)
-			if err := store.SaveProfile(profilePath, profile); err != nil {
+			ensureAddressComplete(ctx, profile.Address, cfg.Geocoding)
+			if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+				p.AddressID = profile.AddressID
+				p.Address = profile.Address
+				p.AddressSource = profile.AddressSource
+				return nil
+			}); err != nil {
 				return err
 			}
 		}
 
 		if !bisleri.AddressIsComplete(*profile.Address) {
-			ensureAddressComplete(profile.Address)
-			if err := store.SaveProfile(profilePath, profile); err != nil {
+			ensureAddressComplete(ctx, profile.Address, cfg.Geocoding)
+			if err := saveOrderAddress(profilePath, profile, *addressName); err != nil {
 				return err
 			}
 		}
 
-		fmt.Println("Submitting shipping info...")
-		if err := client.SubmitShipping(ctx, shipmentUUID, csrfToken, cfg.Defaults.Timeslot, *profile.Address, profile.AddressID); err != nil {
+		slots := bisleri.ExtractTimeslots(shippingHTML)
+		timeslot = canonicalTimeslot(slots, timeslot)
+		if open, known := bisleri.SlotOpen(slots, timeslot); known && !open {
+			if timeslot == "" {
+				return bisleri.ErrNoSlotAvailable
+			}
+			return fmt.Errorf("%w (%s)", bisleri.ErrNoSlotAvailable, timeslot)
+		}
+
+		shippingAddr := withRecipient(orderAddress(profile), *recipientName, *recipientPhone)
+		if err := client.CheckShippingAddress(ctx, shippingAddr); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("%w (update the address on bisleri.com or fix the profile, then retry)", err)
+		}
+		if *recipientName != "" || *recipientPhone != "" {
+			progressln(format.KeyValue("Recipient", strings.TrimSpace(shippingAddr.FirstName+" "+shippingAddr.LastName)+", "+shippingAddr.Phone))
+		}
+
+		// From here the order is committed step by step; a shutdown request
+		// lets these steps finish instead of leaving the order half placed.
+		ctx, endCommit, err = lifecycle.Critical(ctx)
+		if err != nil {
 			return err
 		}
 
-		fmt.Println("Fetching payment page...")
+		enter("submit-shipping")
+		progressln(i18n.T("Submitting shipping info..."))
+		if err := client.SubmitShipping(ctx, shipmentUUID, csrfToken, timeslot, *note, shippingAddr, profile.AddressID); err != nil {
+			return err
+		}
+		audit.Timeslot = timeslot
+
+		enter("payment-page")
+		progressln(i18n.T("Fetching payment page..."))
 		paymentHTML, err := client.FetchPaymentPage(ctx)
 		if err != nil {
 			return err
 		}
 		if balance, ok := bisleri.ExtractWalletBalance(paymentHTML); ok {
-			fmt.Println(format.KeyValue("Wallet balance", balance))
+			audit.WalletBefore = balance
+			progressln(format.KeyValue(i18n.T("Wallet balance"), balance))
 		}
 		if total, ok := bisleri.ExtractOrderTotal(paymentHTML); ok {
-			fmt.Println(format.KeyValue("Order total", total))
+			audit.Total = total
+			progressln(format.KeyValue(i18n.T("Order total"), total))
 		}
 		// Check order total and wallet balance
 		if total, okTotal := bisleri.ExtractOrderTotal(paymentHTML); okTotal {
-			if totalAmount, okTot := bisleri.ParseINRAmount(total); okTot {
+			if totalAmount, okTot := money.Parse(total); okTot {
 				if totalAmount <= 0 {
 					if *debug {
-						debugFile := debugFilePath("payment_page_fail_total.html")
-						if writeErr := os.WriteFile(debugFile, []byte(paymentHTML), 0600); writeErr == nil {
-							fmt.Fprintf(os.Stderr, "Debug: Payment HTML saved to %s\n", debugFile)
-						}
+						writeDebugFile("payment_page_fail_total.html", paymentHTML, red, "Payment HTML")
 					}
 					return fmt.Errorf("invalid order total detected (%s); check debug html", total)
 				}
 
+				if err := checkBudget(name, placedOrders, cfg.Defaults.MonthlyBudget, totalAmount, *strictBudget); err != nil {
+					return err
+				}
+				if unit, ok := jarUnitPrice(totalAmount, *quantity, *returnJars, audit.Items); ok {
+					jarPrice = unit
+					prevJarPrice = notePriceChange(name, profile.PreferredCity, unit)
+				}
+
 				// Balance check
 				if balance, okBal := bisleri.ExtractWalletBalance(paymentHTML); okBal {
-					if balAmount, okBalPars := bisleri.ParseINRAmount(balance); okBalPars {
+					if balAmount, okBalPars := money.Parse(balance); okBalPars {
 						if balAmount < totalAmount {
-							return fmt.Errorf("insufficient wallet balance (%s) for order total (%s)", balance, total)
+							return &walletShortError{Balance: balance, Total: total}
 						}
 					}
 				} else {
-					fmt.Println("Warning: could not detect wallet balance")
+					fmt.Fprintln(os.Stderr, i18n.T("Warning: could not detect wallet balance"))
+				}
+				if *unattended {
+					if err := checkCheckoutPolicy(cfg.Policy, jarPrice, audit.WalletBefore, totalAmount); err != nil {
+						return err
+					}
 				}
 			} else {
-				return fmt.Errorf("failed to parse order total amount: %s", total)
+				noteParseFailure("order total", "payment", paymentHTML)
+				return withUpgradeHint(fmt.Errorf("failed to parse order total amount: %s", total))
 			}
 		} else {
 			if *debug {
-				debugFile := debugFilePath("payment_page_no_total.html")
-				if writeErr := os.WriteFile(debugFile, []byte(paymentHTML), 0600); writeErr == nil {
-					fmt.Fprintf(os.Stderr, "Debug: Payment HTML saved to %s\n", debugFile)
-				}
+				writeDebugFile("payment_page_no_total.html", paymentHTML, red, "Payment HTML")
 			}
-			return errors.New("failed to detect order total on payment page")
+			noteParseFailure("order total", "payment", paymentHTML)
+			return withUpgradeHint(errors.New("failed to detect order total on payment page"))
 		}
 		paymentCSRF, err := bisleri.ExtractCSRFToken(paymentHTML)
 		if err != nil {
 			paymentCSRF = csrfToken
 		}
-		fmt.Println("Submitting payment (Bisleri Wallet)...")
+		enter("payment")
+		progressln(i18n.T("Submitting payment (Bisleri Wallet)..."))
 		if err := client.SubmitPayment(ctx, shipmentUUID, paymentCSRF, *profile.Address); err != nil {
 			return err
 		}
-		fmt.Println("Placing order...")
-		orderID, err := client.PlaceOrder(ctx)
+		enter("place-order")
+		progressln(i18n.T("Placing order..."))
+		placed, err := client.PlaceOrder(ctx)
 		if err != nil {
 			return err
 		}
+		orderID := placed.OrderID
 		if orderID == "" {
 			return errors.New("order placement did not return a valid order ID; check wallet or order history")
 		}
-		fmt.Println("Order placed:", orderID)
-		profile.LastOrder = &store.OrderInfo{OrderID: orderID, PlacedAt: time.Now()}
-		if err := store.SaveProfile(profilePath, profile); err != nil {
+		if *quiet && *progressMode == "text" {
+			fmt.Println(orderID)
+		} else {
+			progressln(i18n.T("Order placed:"), orderID)
+		}
+		audit.OrderID = orderID
+		summary.addOrder(orderID, audit.Total)
+		if jarPrice > 0 {
+			recordJarPrice(name, profile.PreferredCity, orderID, jarPrice, prevJarPrice)
+		}
+		lastOrder := &store.OrderInfo{
+			OrderID:    orderID,
+			PlacedAt:   time.Now(),
+			TotalPrice: audit.Total,
+			Quantity:   audit.Quantity,
+			ReturnJars: audit.ReturnJars,
+			Timeslot:   audit.Timeslot,
+			Items:      audit.Items,
+			Tag:        audit.Tag,
+		}
+		if confirmationHTML, err := client.FetchOrderConfirmation(ctx, placed); err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: could not load order confirmation:", err)
+		} else if eta, ok := bisleri.ExtractDeliveryETA(confirmationHTML); ok {
+			lastOrder.DeliveryETA = eta
+			audit.DeliveryETA = eta
+			progressln(format.KeyValue(i18n.T("Estimated delivery"), eta))
+		}
+		profile.LastOrder = lastOrder
+		placedOrders = append(placedOrders, lastOrder)
+		if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+			p.LastOrder = lastOrder
+			return nil
+		}); err != nil {
 			fmt.Fprintln(os.Stderr, "Warning: failed to save order info:", err)
 		}
 		if postPaymentHTML, err := client.FetchPaymentPage(ctx); err == nil {
 			if balance, ok := bisleri.ExtractWalletBalance(postPaymentHTML); ok {
-				fmt.Println(format.KeyValue("Wallet balance (post-order)", balance))
+				audit.WalletAfter = balance
+				progressln(format.KeyValue(i18n.T("Wallet balance (post-order)"), balance))
+			}
+		}
+		if cfg.Defaults.SyncAfterOrder {
+			progressln(i18n.T("Syncing order history..."))
+			if _, err := syncOrders(ctx, client, name); err != nil {
+				fmt.Fprintln(os.Stderr, "Warning: post-order sync failed; run 'bislericli sync':", err)
 			}
 		}
 
 		return nil
 	}
 
-	err = runOrderOnce()
-	if !errors.Is(err, bisleri.ErrNotAuthenticated) {
+	var lastAudit store.AuditEntry
+	attemptOrder := func() error {
+		audit := store.AuditEntry{
+			Timestamp:  time.Now(),
+			Profile:    name,
+			Quantity:   *quantity,
+			ReturnJars: *returnJars,
+			Tag:        strings.TrimSpace(*forTag),
+		}
+		stage, stageClient, endCommit = "", nil, nil
+		// Drop stages timed between attempts, such as a login.
+		tracker.TakeTimings()
+		err := runOrderOnce(&audit)
+		tracker.Finish(err)
+		audit.Timings = tracker.TakeTimings()
+		if *showTimings {
+			printTimings(os.Stderr, audit.Timings)
+		}
+		if err != nil && stage != "" {
+			err = &orderError{Stage: stage, Err: err}
+		}
+		if err == nil {
+			tracker.Result("order", map[string]string{"orderId": audit.OrderID, "total": audit.Total, "deliveryEta": audit.DeliveryETA})
+		}
+		recordOrderAttempt(name, audit, err)
+		if endCommit != nil {
+			endCommit()
+		}
+		lastAudit = audit
 		return err
 	}
 
-	confirmed, timedOut, err := confirmLoginPrompt(os.Stdin, os.Stdout, loginPromptTimeout)
-	if err != nil {
+	placeOrder := func() error {
+		err := attemptOrder()
+		if !errors.Is(err, bisleri.ErrNotAuthenticated) {
+			return err
+		}
+
+		if *unattended && cfg.OTPRelay.Secret != "" {
+			loginCtx, loginCancel := context.WithTimeout(context.Background(), orderReauthTimeout)
+			defer loginCancel()
+			enter("login")
+			if err := relaySessionForOrder(loginCtx, cfg, profilePath, &profile, os.Stdout); err != nil {
+				tracker.Finish(err)
+				return &orderError{Stage: "login", Err: fmt.Errorf("automatic login failed: %w", err)}
+			}
+			tracker.Finish(nil)
+			progressln(i18n.T("Retrying order after login..."))
+			return attemptOrder()
+		}
+
+		confirmed, timedOut, err := confirmLoginPrompt(os.Stdin, os.Stdout, loginPromptTimeout)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			if timedOut {
+				return errors.New("session expired; login confirmation timed out after 10s. please run 'bislericli auth login'")
+			}
+			return errors.New(i18n.T("session expired; please run 'bislericli auth login'"))
+		}
+
+		loginCtx, loginCancel := context.WithTimeout(context.Background(), orderReauthTimeout)
+		defer loginCancel()
+		enter("login")
+		if err := refreshSessionForOrder(loginCtx, profilePath, &profile, os.Stdin, os.Stdout); err != nil {
+			tracker.Finish(err)
+			return &orderError{Stage: "login", Err: fmt.Errorf("automatic login failed: %w", err)}
+		}
+		tracker.Finish(nil)
+
+		progressln(i18n.T("Retrying order after login..."))
+		err = attemptOrder()
+		if errors.Is(err, bisleri.ErrNotAuthenticated) {
+			return errors.New("session expired after re-login; please run 'bislericli auth login'")
+		}
 		return err
 	}
-	if !confirmed {
-		if timedOut {
-			return errors.New("session expired; login confirmation timed out after 10s. please run 'bislericli auth login'")
+
+	// placeWhenSlotOpens retries placeOrder while every slot is full when
+	// --wait-for-slot is set, until the deadline or Ctrl+C.
+	placeWhenSlotOpens := func() error {
+		err := placeOrder()
+		if !*waitForSlot || !errors.Is(err, bisleri.ErrNoSlotAvailable) {
+			return err
 		}
-		return errors.New("session expired; please run 'bislericli auth login'")
+		lc, end := startLifecycle()
+		defer end()
+		ctx := lc.Context()
+		deadline := time.Now().Add(*slotDeadline)
+		for errors.Is(err, bisleri.ErrNoSlotAvailable) {
+			if time.Now().Add(*slotPoll).After(deadline) {
+				return fmt.Errorf("gave up after %s: %w", *slotDeadline, err)
+			}
+			progressln(i18n.T("No delivery slot open; retrying at %s...", time.Now().Add(*slotPoll).Format("15:04")))
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(*slotPoll):
+			}
+			err = placeOrder()
+		}
+		if err == nil && profile.LastOrder != nil {
+			sendNotification(cfg.Notifications, notify.Message{
+				Event: notify.OrderPlaced,
+				Title: "Bisleri delivery slot opened; order placed",
+				Body:  placedOrderSummary(*profile.LastOrder),
+				Order: placedOrderNotice(*profile.LastOrder),
+			})
+		}
+		return err
 	}
 
-	loginCtx, loginCancel := context.WithTimeout(context.Background(), orderReauthTimeout)
-	defer loginCancel()
-	if err := refreshSessionForOrder(loginCtx, profilePath, &profile, os.Stdin, os.Stdout); err != nil {
-		return fmt.Errorf("automatic login failed: %w", err)
+	if len(batches) > 1 {
+		progressln(i18n.T("Splitting %d jar(s) into %d orders of at most %d.", *quantity, len(batches), cfg.Defaults.MaxPerLine))
 	}
+	for i, batch := range batches {
+		*quantity, *returnJars = batch.Quantity, batch.ReturnJars
+		if i > 0 {
+			// The previous batch is the pending order the check would flag.
+			*force = true
+			progressln(i18n.T("Placing order %d of %d...", i+1, len(batches)))
+		}
+		preOrder := map[string]any{"quantity": *quantity, "returnJars": *returnJars, "tag": strings.TrimSpace(*forTag)}
+		if err := runHooks(cfg.Hooks.PreOrder, "pre-order", name, preOrder); err != nil {
+			return fmt.Errorf("order blocked: %w", err)
+		}
+		if err := placeWhenSlotOpens(); err != nil {
+			lastAudit.Result, lastAudit.Error = "failure", err.Error()
+			notifyHooks(cfg.Hooks.PostOrderFailure, "post-order-failure", name, lastAudit)
+			reportOrderFailure(os.Stderr, err, args, stageClient)
+			if i > 0 {
+				return fmt.Errorf("order %d of %d failed after %d were placed: %w", i+1, len(batches), i, err)
+			}
+			return err
+		}
+		lastAudit.Result = "success"
+		notifyHooks(cfg.Hooks.PostOrderSuccess, "post-order-success", name, lastAudit)
+	}
+	return nil
+}
+
+// orderBatch is one order of a bulk request split by splitOrder.
+type orderBatch struct {
+	Quantity   int
+	ReturnJars int
+}
+
+// splitOrder breaks quantity into orders of at most maxPerLine jars, handing
+// out empty-jar returns from the first order on. maxPerLine <= 0 means no cap.
+func splitOrder(quantity, returnJars, maxPerLine int) []orderBatch {
+	if maxPerLine <= 0 || quantity <= maxPerLine {
+		return []orderBatch{{Quantity: quantity, ReturnJars: returnJars}}
+	}
+	var batches []orderBatch
+	for quantity > 0 {
+		b := orderBatch{Quantity: min(quantity, maxPerLine)}
+		b.ReturnJars = min(returnJars, b.Quantity)
+		returnJars -= b.ReturnJars
+		quantity -= b.Quantity
+		batches = append(batches, b)
+	}
+	return batches
+}
+
+// lockOrders takes the profile's order lock, so a manual order and the
+// scheduler never run the flow at the same time. With wait, it says what it
+// is waiting for before blocking.
+func lockOrders(profileName string, wait time.Duration) (func(), error) {
+	command := "bislericli " + strings.Join(os.Args[1:], " ")
+	unlock, err := store.LockOrders(profileName, command, 0)
+	var held *fileutil.HeldError
+	if errors.As(err, &held) && wait > 0 {
+		progressf("Another order for profile '%s' is in progress (%v); waiting up to %s...\n", profileName, held, wait)
+		unlock, err = store.LockOrders(profileName, command, wait)
+	}
+	if errors.As(err, &held) {
+		return nil, fmt.Errorf("another order for profile '%s' is in progress: %v; wait for it to finish or pass --lock-wait", profileName, held)
+	}
+	return unlock, err
+}
 
-	fmt.Println("Retrying order after login...")
-	err = runOrderOnce()
-	if errors.Is(err, bisleri.ErrNotAuthenticated) {
-		return errors.New("session expired after re-login; please run 'bislericli auth login'")
+func recordOrderAttempt(profileName string, audit store.AuditEntry, err error) {
+	audit.Result = "success"
+	if err != nil {
+		audit.Result = "failure"
+		audit.Error = err.Error()
+	}
+	if appendErr := store.AppendAuditEntry(profileName, audit); appendErr != nil {
+		fmt.Fprintln(os.Stderr, "Warning: failed to write audit log:", appendErr)
 	}
-	return err
+}
+
+// printTimings writes an order attempt's stage durations and their total.
+func printTimings(out io.Writer, timings []progress.Timing) {
+	if len(timings) == 0 {
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Stage timings:")
+	var total time.Duration
+	for _, t := range timings {
+		d := time.Duration(t.ElapsedMS) * time.Millisecond
+		total += d
+		fmt.Fprintf(w, "  %s\t%s\n", t.Stage, d)
+	}
+	fmt.Fprintf(w, "  total\t%s\n", total)
+	w.Flush()
 }
 
 func runConfig(args []string) error {
@@ -705,6 +1162,14 @@ func runConfig(args []string) error {
 		printConfigUsage()
 		return nil
 	}
+	switch args[0] {
+	case "set-city":
+		return runCitySet(args[1:])
+	case "validate":
+		return runConfigValidate(args[1:])
+	case "convert":
+		return runConfigConvert(args[1:])
+	}
 	if args[0] != "show" {
 		fmt.Printf("Unknown config subcommand: %s\n", args[0])
 		printConfigUsage()
@@ -725,21 +1190,14 @@ func runConfig(args []string) error {
 		return err
 	}
 	fmt.Println(format.KeyValue("Profiles", profilesDir))
-	return nil
-}
-
-func runSchedule(args []string) error {
-	if len(args) > 0 && isHelpToken(args[0]) {
-		printScheduleUsage()
-		return nil
-	}
-	cfg, err := config.LoadGlobalConfig()
+	dataDir, err := config.DataDir()
 	if err != nil {
 		return err
 	}
-	fmt.Println("Schedule:", cfg.Defaults.Schedule)
-	fmt.Println("Default quantity:", cfg.Defaults.OrderQuantity)
-	fmt.Println("Default return jars:", cfg.Defaults.ReturnJars)
+	fmt.Println(format.KeyValue("Data dir", dataDir))
+	if overrides := config.EnvOverrides(); len(overrides) > 0 {
+		fmt.Println(format.KeyValue("Env overrides", strings.Join(overrides, ", ")))
+	}
 	return nil
 }
 
@@ -752,21 +1210,41 @@ func runDebug(args []string) error {
 
 	switch sub {
 	case "order":
+		fs, profileName := parseScheduleFlags("debug order")
+		fixtures := fs.String("fixtures", "", "Write the capture and checkout pages to this directory as test fixtures")
+		if err := fs.Parse(args[1:]); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil
+			}
+			return err
+		}
 		cfg, err := config.LoadGlobalConfig()
 		if err != nil {
 			return err
 		}
-		name := resolveProfileName("", cfg)
+		name := resolveProfileName(*profileName, cfg)
 		profile, _, err := loadOrCreateProfile(name)
 		if err != nil {
 			return err
 		}
 		if len(profile.Cookies) == 0 {
-			return errors.New("no cookies in profile; run 'bislericli auth login'")
+			return errors.New(i18n.T("no cookies in profile; run 'bislericli auth login'"))
 		}
 
 		fmt.Println("Starting debug order flow for profile:", name)
-		return debug.RunOrderDebug(context.Background(), profile)
+		return debug.RunOrderDebug(context.Background(), profile, profileRedactor(cfg, profile), debug.OrderDebugOptions{FixturesDir: *fixtures})
+	case "report":
+		return runDebugReport(args[1:])
+	case "artifacts":
+		return runDebugArtifacts(args[1:])
+	case "cookies":
+		return runDebugCookies(args[1:])
+	case "diff-pages":
+		return runDebugDiffPages(args[1:])
+	case "fingerprints":
+		return runDebugFingerprints(args[1:])
+	case "simulate":
+		return runDebugSimulate(args[1:])
 	default:
 		fmt.Printf("Unknown debug subcommand: %s\n", sub)
 		printDebugUsage()
@@ -789,7 +1267,9 @@ func printAuthUsage() {
 	fmt.Println("  login    Interactive login to Bisleri account")
 	fmt.Println("  logout   Logout from the current session")
 	fmt.Println("  status   Check current login status")
+	fmt.Println("  otp-relay  Log in with the OTP forwarded by a phone automation app")
 	fmt.Println("\nTip: OTP login supports typing 'r' on the OTP prompt to resend.")
+	fmt.Println("Accounts with a password can use: auth login --method password [--email you@example.com] [--save-password]")
 }
 
 func printProfileUsage() {
@@ -802,19 +1282,22 @@ func printProfileUsage() {
 func printConfigUsage() {
 	fmt.Println("Usage: bislericli config <subcommand>")
 	fmt.Println("\nAvailable subcommands:")
-	fmt.Println("  show   Display current configuration")
-}
-
-func printScheduleUsage() {
-	fmt.Println("Usage: bislericli schedule")
-	fmt.Println()
-	fmt.Println("Show current default scheduling values.")
+	fmt.Println("  show       Display current configuration")
+	fmt.Println("  set-city   Change the delivery city (same as 'city set')")
+	fmt.Println("  validate   Check the config file for unknown keys and invalid values")
+	fmt.Println("  convert    Rewrite the config as YAML, TOML or JSON: convert --to yaml")
 }
 
 func printDebugUsage() {
 	fmt.Println("Usage: bislericli debug <subcommand>")
 	fmt.Println("\nAvailable subcommands:")
-	fmt.Println("  order   Start debug order flow")
+	fmt.Println("  order        Walk cart to checkout in Chrome and capture its requests (--fixtures DIR)")
+	fmt.Println("  report       Build a redacted issue report bundle (zip)")
+	fmt.Println("  artifacts    List or clean saved debug page dumps")
+	fmt.Println("  cookies      List saved cookies or prune expired and tracking ones")
+	fmt.Println("  diff-pages   Compare two saved pages for changes that affect the parsers")
+	fmt.Println("  fingerprints List, submit or clear fingerprints of pages that failed to parse")
+	fmt.Println("  simulate     Run the order flow offline against captured fixtures (--fixtures DIR)")
 }
 
 func resolveProfileName(flagValue string, cfg config.GlobalConfig) string {
@@ -943,27 +1426,6 @@ func matchCityOption(candidate string, options []string) (string, bool) {
 	return "", false
 }
 
-func normalizeStateCode(addr *store.Address) {
-	if addr == nil {
-		return
-	}
-	if len(addr.StateCode) == 2 {
-		return
-	}
-	if addr.Address1 == "" {
-		return
-	}
-	re := regexp.MustCompile(`\\b[A-Z]{2}\\b`)
-	matches := re.FindAllString(addr.Address1, -1)
-	for _, m := range matches {
-		if m == "IN" {
-			continue
-		}
-		addr.StateCode = m
-		return
-	}
-}
-
 func addressReadyForLocation(addr store.Address) bool {
 	return addr.Address1 != "" && addr.City != "" && addr.StateCode != "" && addr.PostalCode != "" && addr.Country != ""
 }
@@ -985,7 +1447,7 @@ func parseIndex(value string, max int) (int, error) {
 	return n - 1, nil
 }
 
-func ensureAddressComplete(addr *store.Address) {
+func ensureAddressComplete(ctx context.Context, addr *store.Address, geo config.Geocoding) {
 	reader := bufio.NewReader(os.Stdin)
 	prompt := func(label string, current *string) {
 		if *current != "" {
@@ -1009,12 +1471,45 @@ func ensureAddressComplete(addr *store.Address) {
 		prompt("Landmark (optional)", &addr.NearByLandmark)
 	}
 	prompt("City", &addr.City)
-	prompt("State code (e.g. KA)", &addr.StateCode)
-	prompt("Postal code", &addr.PostalCode)
+	for !address.ValidPincode(addr.PostalCode) {
+		if addr.PostalCode != "" {
+			fmt.Println("Invalid pincode; expected 6 digits.")
+			addr.PostalCode = ""
+		}
+		prompt("Postal code", &addr.PostalCode)
+	}
+	addr.PostalCode = strings.TrimSpace(addr.PostalCode)
+	address.NormalizeStateCode(addr)
+	if _, ok := address.NormalizeState(addr.StateCode); !ok {
+		addr.StateCode = ""
+	}
+	// The pincode's postal circle is only a guess; show it for confirmation.
+	if guess, ok := address.StateForPincode(addr.PostalCode); ok && addr.StateCode == "" {
+		fmt.Printf("State [%s, from pincode %s]: ", guess, addr.PostalCode)
+		line, _ := reader.ReadString('\n')
+		addr.StateCode = guess
+		if line = strings.TrimSpace(line); line != "" {
+			addr.StateCode = line
+		}
+	}
+	for {
+		if code, ok := address.NormalizeState(addr.StateCode); ok {
+			addr.StateCode = code
+			break
+		}
+		if addr.StateCode != "" {
+			fmt.Println("Unrecognized state; enter a state name or two-letter code.")
+			addr.StateCode = ""
+		}
+		prompt("State (name or code, e.g. KA)", &addr.StateCode)
+	}
 	prompt("Phone", &addr.Phone)
 	if addr.Country == "" {
 		addr.Country = "IN"
 	}
+	if geo.Enabled && (addr.Latitude == "" || addr.Longitude == "") {
+		fillCoordinates(ctx, reader, addr, geo)
+	}
 	if addr.Latitude == "" {
 		prompt("Latitude (optional)", &addr.Latitude)
 	}
@@ -1023,6 +1518,27 @@ func ensureAddressComplete(addr *store.Address) {
 	}
 }
 
+// fillCoordinates geocodes the address and asks the user to confirm the match
+// before storing the coordinates.
+func fillCoordinates(ctx context.Context, reader *bufio.Reader, addr *store.Address, geo config.Geocoding) {
+	lookupCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	fmt.Println("Looking up coordinates for address...")
+	result, err := geocode.NewClient(geo.Endpoint, geo.Email).Lookup(lookupCtx, *addr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: geocoding failed:", err)
+		return
+	}
+	fmt.Printf("Found: %s (%s, %s)\n", result.DisplayName, result.Latitude, result.Longitude)
+	fmt.Print("Use these coordinates? [Y/n]: ")
+	line, _ := reader.ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(line))
+	if answer == "" || answer == "y" || answer == "yes" {
+		addr.Latitude = result.Latitude
+		addr.Longitude = result.Longitude
+	}
+}
+
 func filterExtraItems(items []bisleri.CartItem, productID string) []string {
 	allowed := map[string]bool{
 		strings.ToLower(productID):                         true,
@@ -1043,6 +1559,71 @@ func filterExtraItems(items []bisleri.CartItem, productID string) []string {
 	return extras
 }
 
+// orderAddress is the profile's address as an order sends it: in the
+// preferred city, with a normalized state code and a country.
+func orderAddress(profile store.Profile) store.Address {
+	addr := *profile.Address
+	if profile.PreferredCity != "" && !strings.EqualFold(addr.City, profile.PreferredCity) {
+		addr.City = profile.PreferredCity
+	}
+	address.NormalizeStateCode(&addr)
+	if addr.Country == "" {
+		addr.Country = "IN"
+	}
+	return addr
+}
+
+// saveOrderAddress persists a completed address into the saved address the
+// order used (--address or the default) and the profile.
+func saveOrderAddress(profilePath string, profile store.Profile, addressName string) error {
+	if addressName == "" {
+		addressName = profile.DefaultAddress
+	}
+	_, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+		if addressName == "" {
+			p.Address = profile.Address
+			return nil
+		}
+		return p.SetAddress(store.SavedAddress{Name: addressName, ID: profile.AddressID, Address: *profile.Address})
+	})
+	return err
+}
+
+// withRecipient returns addr with the contact replaced for deliveries to
+// someone else; empty values keep the saved contact.
+func withRecipient(addr store.Address, name, phone string) store.Address {
+	if name = strings.TrimSpace(name); name != "" {
+		addr.FirstName, addr.LastName = name, ""
+		if i := strings.LastIndex(name, " "); i > 0 {
+			addr.FirstName, addr.LastName = strings.TrimSpace(name[:i]), name[i+1:]
+		}
+	}
+	if phone != "" {
+		addr.Phone = phone
+	}
+	return addr
+}
+
+// orderedItems lists what the order will contain: the jar product at quantity
+// plus any other products already in the cart. Empty-jar and deposit lines are
+// bookkeeping and are left out.
+func orderedItems(cartItems []bisleri.CartItem, productID string, quantity int) []store.OrderItem {
+	items := []store.OrderItem{{ProductID: productID, Quantity: quantity}}
+	for _, extra := range filterExtraItems(cartItems, productID) {
+		if extra == "unknown-item" {
+			continue
+		}
+		qty := 1
+		for _, item := range cartItems {
+			if item.ProductID == extra && item.Quantity > 0 {
+				qty = item.Quantity
+			}
+		}
+		items = append(items, store.OrderItem{ProductID: extra, Quantity: qty})
+	}
+	return items
+}
+
 func confirmCartQuantity(ctx context.Context, client *bisleri.Client, productID string, quantity int, allowExtra bool) error {
 	const maxAttempts = 4
 	var lastErr error
@@ -1054,15 +1635,16 @@ func confirmCartQuantity(ctx context.Context, client *bisleri.Client, productID
 			}
 			lastErr = err
 		} else {
-			items := bisleri.ExtractCartItems(cartHTML)
-			if count, ok := bisleri.ExtractCartCount(cartHTML); ok && count > 0 && len(items) == 0 {
+			cart := bisleri.ParseCartPage(cartHTML)
+			if cart.Unparsed() {
 				lastErr = errors.New("unable to parse cart items")
 			} else {
-				extraItems := filterExtraItems(items, productID)
+				extraItems := filterExtraItems(cart.Items, productID)
 				if len(extraItems) > 0 && !allowExtra {
 					return fmt.Errorf("cart contains other items; clear cart or pass --allow-extra (items: %s)", strings.Join(extraItems, ", "))
 				}
-				if uuid, existingQty, ok := bisleri.ExtractCartItem(cartHTML, productID); ok && uuid != "" {
+				if item, ok := cart.Item(productID); ok && item.UUID != "" {
+					uuid, existingQty := item.UUID, item.Quantity
 					if existingQty == 0 {
 						// Quantity parsing can be unreliable; accept presence of item after ensuring update request succeeds.
 						if err := client.UpdateQuantity(ctx, productID, uuid, quantity); err != nil {
@@ -1079,7 +1661,7 @@ func confirmCartQuantity(ctx context.Context, client *bisleri.Client, productID
 					} else {
 						lastErr = fmt.Errorf("cart quantity was %d, updated to %d", existingQty, quantity)
 					}
-				} else if count, ok := bisleri.ExtractCartCount(cartHTML); ok && count == 0 {
+				} else if cart.HasCount && cart.Count == 0 {
 					lastErr = errors.New("cart still empty")
 				} else {
 					lastErr = errors.New("product not yet visible in cart")
@@ -1102,31 +1684,6 @@ func confirmCartQuantity(ctx context.Context, client *bisleri.Client, productID
 	return fmt.Errorf("unable to confirm cart quantity after add: %v", lastErr)
 }
 
-func ensureCityLocation(ctx context.Context, client *bisleri.Client, profilePath string, profile *store.Profile, cartHTML string) (string, error) {
-	selectedCity, ok := bisleri.ExtractSelectedCity(cartHTML)
-	if ok && selectedCity != "" {
-		return cartHTML, nil
-	}
-	options := bisleri.ExtractCityOptions(cartHTML)
-	city := resolveCity(*profile, options)
-	if city == "" {
-		return cartHTML, nil
-	}
-	fmt.Println("Setting delivery city:", city)
-	if err := client.SetCityLocation(ctx, city); err != nil {
-		return cartHTML, err
-	}
-	profile.PreferredCity = city
-	if err := store.SaveProfile(profilePath, *profile); err != nil {
-		fmt.Fprintln(os.Stderr, "Warning: failed to save preferred city:", err)
-	}
-	refreshed, err := client.FetchCartPage(ctx)
-	if err != nil {
-		return cartHTML, err
-	}
-	return refreshed, nil
-}
-
 func tryCaptureAddress(profilePath string, profile *store.Profile) error {
 	jar, err := bisleri.JarFromCookies(profile.Cookies)
 	if err != nil {
@@ -1176,8 +1733,8 @@ func loadOrCreateProfile(name string) (store.Profile, string, error) {
 	if err := os.MkdirAll(filepath.Dir(profilePath), 0o700); err != nil {
 		return store.Profile{}, "", err
 	}
-	profile = store.Profile{Name: name}
-	if err := store.SaveProfile(profilePath, profile); err != nil {
+	profile, err = store.CreateProfile(profilePath, name)
+	if err != nil {
 		return store.Profile{}, "", err
 	}
 	return profile, profilePath, nil
@@ -1225,17 +1782,42 @@ func refreshSessionForOrder(ctx context.Context, profilePath string, profile *st
 		return err
 	}
 	fmt.Fprintln(output, "Starting OTP login...")
-	cookies, err := otpLoginFn(ctx, phoneNumber)
+	cookies, err := otpLoginFn(ctx, phoneNumber, otpLogFor(profilePath, *profile, phoneNumber))
 	if err != nil {
 		return fmt.Errorf("login failed: %w", err)
 	}
+	return saveRefreshedSession(profilePath, profile, cookies, phoneNumber)
+}
+
+// saveRefreshedSession stores a new login in profile and on disk. Only the
+// session is saved so a per-order address override is not persisted.
+// saveLoginSession records a new login on the profile. Only the session
+// changes; addresses, schedule state and the last order stay as they are. OTP
+// sends were recorded on disk during login and are only pruned here.
+func saveLoginSession(profilePath string, cookies []store.Cookie, phoneNumber, email string, now time.Time) (store.Profile, error) {
+	return store.UpdateProfile(profilePath, func(p *store.Profile) error {
+		p.Cookies = cookies
+		if phoneNumber != "" {
+			p.PhoneNumber = phoneNumber
+		}
+		p.Email = email
+		p.LastLogin = now
+		p.OTPSends = pruneOTPSends(p.OTPSends, now)
+		return nil
+	})
+}
+
+func saveRefreshedSession(profilePath string, profile *store.Profile, cookies []store.Cookie, phoneNumber string) error {
 	profile.Cookies = cookies
 	profile.PhoneNumber = phoneNumber
 	profile.LastLogin = time.Now()
-	if err := store.SaveProfile(profilePath, *profile); err != nil {
-		return err
-	}
-	return nil
+	_, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+		p.Cookies = profile.Cookies
+		p.PhoneNumber = profile.PhoneNumber
+		p.LastLogin = profile.LastLogin
+		return nil
+	})
+	return err
 }
 
 func resolvePhoneNumberForOTP(savedPhone string, input io.Reader, output io.Writer) (string, error) {
@@ -1267,14 +1849,26 @@ func resolvePhoneNumberForOTP(savedPhone string, input io.Reader, output io.Writ
 	return phoneNumber, nil
 }
 
-func debugFilePath(name string) string {
-	dir, err := config.ConfigDir()
+// profileRedactor builds a redactor for the profile's personal data plus the
+// configured patterns; an invalid pattern falls back to the built-in rules.
+func profileRedactor(cfg config.GlobalConfig, profile store.Profile) *redact.Redactor {
+	red, err := redact.New(cfg.Redaction.Patterns)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning:", err)
+		red, _ = redact.New(nil)
+	}
+	red.AddProfile(profile)
+	return red
+}
+
+// writeDebugFile saves a redacted page dump to the debug artifacts directory.
+func writeDebugFile(name, content string, red *redact.Redactor, label string) {
+	path, err := debug.SaveArtifact(name, []byte(red.String(content)))
 	if err != nil {
-		return filepath.Join(os.TempDir(), name)
+		fmt.Fprintf(os.Stderr, "Warning: failed to save %s: %v\n", label, err)
+		return
 	}
-	debugDir := filepath.Join(dir, "debug")
-	_ = os.MkdirAll(debugDir, 0o700)
-	return filepath.Join(debugDir, name)
+	fmt.Fprintf(os.Stderr, "Debug: %s saved to %s\n", label, path)
 }
 
 func normalizePhoneNumber(phoneNumber string) string {
@@ -1288,3 +1882,83 @@ func normalizePhoneNumber(phoneNumber string) string {
 	}
 	return phoneNumber
 }
+
+// loadSelectorOverrides installs parser overrides from selectors.json in the
+// config dir, if present. A broken file is reported and ignored.
+func loadSelectorOverrides() {
+	path, err := config.SelectorsFilePath()
+	if err != nil {
+		return
+	}
+	selectors, err := bisleri.LoadSelectors(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err == nil {
+		err = bisleri.SetSelectors(selectors)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: ignoring selector overrides:", err)
+	}
+}
+
+// startupConfig loads the config once for the process-wide settings below.
+// loaded is false when there is no config file yet, or when it fails to load;
+// that failure is left for the command itself to report.
+func startupConfig() (cfg config.GlobalConfig, loaded bool) {
+	path, err := config.ConfigFilePath()
+	if err != nil {
+		return cfg, false
+	}
+	if _, err := os.Stat(path); err != nil {
+		return cfg, false
+	}
+	cfg, err = config.LoadGlobalConfig()
+	if err != nil {
+		return config.GlobalConfig{}, false
+	}
+	return cfg, true
+}
+
+// loadClientOptions applies the config's client section (user agent, extra
+// headers) to every client the command creates, and its fingerprints section
+// to parse failures.
+func loadClientOptions(cfg config.GlobalConfig) {
+	bisleri.SetHeaderOptions(clientHeaderOptions(cfg.Client, rand.IntN))
+	fingerprintSettings = cfg.Fingerprints
+}
+
+// clientHeaderOptions turns the client config into header options. With
+// session rotation, pick chooses the one agent used for the whole command.
+func clientHeaderOptions(c config.Client, pick func(int) int) bisleri.HeaderOptions {
+	o := bisleri.HeaderOptions{UserAgent: c.UserAgent, AcceptLanguage: c.AcceptLanguage, Headers: c.Headers}
+	if len(c.UserAgents) > 0 {
+		if c.Rotate == "request" {
+			o.UserAgents = c.UserAgents
+		} else {
+			o.UserAgent = c.UserAgents[pick(len(c.UserAgents))]
+		}
+	}
+	return o
+}
+
+// loadTimezone makes the configured timezone the process's local time, so
+// schedules, slot times and stats buckets follow it rather than the machine's
+// zone (UTC on most servers).
+func loadTimezone(cfg config.GlobalConfig) {
+	loc, err := config.Location(cfg.Timezone)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: ignoring timezone:", err)
+		return
+	}
+	time.Local = loc
+}
+
+// initLanguage selects the output language from BISLERICLI_LANG, config or
+// the locale. Terminals that cannot render Devanagari stay in English.
+func initLanguage(cfg config.GlobalConfig) {
+	if !format.Terminal().Unicode {
+		return
+	}
+	i18n.Set(i18n.Resolve(cfg.Language, os.Getenv))
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/notify"
+	"bislericli/internal/store"
+)
+
+// namedNotifier pairs a notifier with the name warnings use for it.
+type namedNotifier struct {
+	name string
+	notify.Notifier
+}
+
+// notifiers lists the notifiers enabled under notifications.
+func notifiers(n config.Notifications) []namedNotifier {
+	var out []namedNotifier
+	if n.Desktop {
+		out = append(out, namedNotifier{"desktop", notify.DesktopNotifier{}})
+	}
+	if w := n.WhatsApp; w.Token != "" && len(w.To) > 0 {
+		out = append(out, namedNotifier{"WhatsApp", notify.WhatsApp{Endpoint: w.Endpoint, PhoneNumberID: w.PhoneNumberID, Token: w.Token, To: w.To}})
+	}
+	if s := n.Signal; s.Account != "" && (s.Group != "" || len(s.To) > 0) {
+		out = append(out, namedNotifier{"Signal", notify.Signal{Command: s.Command, Account: s.Account, Group: s.Group, To: s.To}})
+	}
+	for _, c := range n.Commands {
+		out = append(out, namedNotifier{fmt.Sprintf("command %q", c), notify.Command(c)})
+	}
+	return out
+}
+
+// sendNotification applies the event's template to m and delivers it through
+// every notifier configured under notifications. A failure only warns so
+// unattended runs carry on.
+func sendNotification(n config.Notifications, m notify.Message) {
+	targets := notifiers(n)
+	if len(targets) == 0 {
+		return
+	}
+	if t, ok := n.Templates[m.Event]; ok {
+		rendered, err := notify.Template{Title: t.Title, Body: t.Body}.Render(m)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: notifications.templates.%s: %v; using the default text\n", m.Event, err)
+		}
+		m = rendered
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for _, t := range targets {
+		if err := t.Notify(ctx, m); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s notification failed: %v\n", t.name, err)
+		}
+	}
+}
+
+// placedOrderNotice is the order of a placed-order message.
+func placedOrderNotice(o store.OrderInfo) *notify.Order {
+	return &notify.Order{
+		ID:       o.OrderID,
+		Date:     o.PlacedAt.Format("2006-01-02"),
+		Total:    o.TotalPrice,
+		Quantity: o.Quantity,
+		ETA:      o.DeliveryETA,
+	}
+}
+
+// savedOrderNotice is the order of a sync --watch message; from is the
+// status before the change.
+func savedOrderNotice(o store.SavedOrder, from string) *notify.Order {
+	return &notify.Order{ID: o.OrderID, Status: o.Status, PreviousStatus: from, Date: o.Date, Total: o.Total}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"bislericli/internal/config"
+	"bislericli/internal/notify"
+)
+
+func TestSendNotificationTemplate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	dest := filepath.Join(t.TempDir(), "message.json")
+	n := config.Notifications{
+		Commands: []string{"cat > " + dest},
+		Templates: map[string]config.Template{
+			"orderStatus": {Body: "{{.Order.ID}}: {{.Order.Status}}"},
+		},
+	}
+	sendNotification(n, notify.Message{
+		Event: notify.OrderStatus,
+		Title: "Bisleri order BS-7",
+		Body:  "Placed -> Delivered",
+		Order: &notify.Order{ID: "BS-7", Status: "Delivered", PreviousStatus: "Placed"},
+	})
+	data, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Data notify.Message `json:"data"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil || got.Data.Title != "Bisleri order BS-7" || got.Data.Body != "BS-7: Delivered" {
+		t.Errorf("payload = %s (%v)", data, err)
+	}
+}
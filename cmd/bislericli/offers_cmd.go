@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/cookiejar"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/config"
+)
+
+func runOffers(args []string) error {
+	if len(args) < 1 || isHelpToken(args[0]) {
+		printOffersUsage()
+		return nil
+	}
+	switch args[0] {
+	case "list":
+		return runOffersList(args[1:])
+	default:
+		fmt.Printf("Unknown offers subcommand: %s\n", args[0])
+		printOffersUsage()
+		return nil
+	}
+}
+
+func printOffersUsage() {
+	fmt.Println("Usage: bislericli offers <subcommand> [flags]")
+	fmt.Println("\nAvailable subcommands:")
+	fmt.Println("  list   Show current promotions and coupon codes for your city")
+}
+
+func runOffersList(args []string) error {
+	fs, profileName := parseScheduleFlags("offers list")
+	city := fs.String("city", "", "City to filter by (default: the profile's city)")
+	all := fs.Bool("all", false, "Show offers for every city")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, _, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	// Offers are public; the session is used when there is one so city
+	// specific banners match the account.
+	var jar http.CookieJar
+	if len(profile.Cookies) > 0 {
+		jar, err = bisleri.JarFromCookies(profile.Cookies)
+	} else {
+		jar, err = cookiejar.New(nil)
+	}
+	if err != nil {
+		return err
+	}
+	client := bisleri.NewClient(&http.Client{Jar: jar, Timeout: 30 * time.Second}, log.New(profileRedactor(cfg, profile).Writer(os.Stderr), "bisleri: ", log.LstdFlags))
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	offers, err := client.FetchOffers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load offers: %w", err)
+	}
+
+	filter := *city
+	if filter == "" {
+		filter = profile.PreferredCity
+	}
+	if *all {
+		filter = ""
+	}
+	var shown []bisleri.Offer
+	for _, o := range offers {
+		if o.AppliesTo(filter) {
+			shown = append(shown, o)
+		}
+	}
+	if len(shown) == 0 {
+		if filter != "" {
+			fmt.Printf("No current offers for %s.\n", filter)
+		} else {
+			fmt.Println("No current offers.")
+		}
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "CODE\tOFFER\tEXPIRES\tCITIES")
+	for _, o := range shown {
+		cities := "all"
+		if len(o.Cities) > 0 {
+			cities = strings.Join(o.Cities, ", ")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", dashIfEmpty(o.Code), dashIfEmpty(o.Title), dashIfEmpty(o.Expires), cities)
+	}
+	w.Flush()
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"bislericli/internal/auth"
 	"bislericli/internal/store"
 )
 
@@ -49,7 +50,7 @@ func TestRefreshSessionForOrderPersistsCookies(t *testing.T) {
 	expectedCookies := []store.Cookie{
 		{Name: "dwsid", Value: "new-session", Domain: ".bisleri.com", Path: "/"},
 	}
-	otpLoginFn = func(ctx context.Context, phone string) ([]store.Cookie, error) {
+	otpLoginFn = func(ctx context.Context, phone string, sends *auth.OTPLog) ([]store.Cookie, error) {
 		if phone != expectedPhone {
 			t.Fatalf("unexpected phone passed to OTP login: %s", phone)
 		}
@@ -9,13 +9,33 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"bislericli/internal/bisleri"
 	"bislericli/internal/config"
+	"bislericli/internal/format"
+	"bislericli/internal/i18n"
+	"bislericli/internal/progress"
+	"bislericli/internal/store"
 )
 
 func runOrders(args []string) error {
+	if len(args) > 0 && args[0] == "audit" {
+		return runOrdersAudit(args[1:])
+	}
+	if len(args) > 0 && args[0] == "sync" {
+		return runSync(args[1:])
+	}
+	if len(args) > 0 && args[0] == "import" {
+		return runOrdersImport(args[1:])
+	}
+	if len(args) > 0 && args[0] == "merge" {
+		return runOrdersMerge(args[1:])
+	}
+	if len(args) > 0 && args[0] == "rate" {
+		return runOrdersRate(args[1:])
+	}
 	fs := flag.NewFlagSet("orders", flag.ContinueOnError)
 	profileName := fs.String("profile", "", "Profile name to use (default: current/default)")
 	limit := fs.Int("limit", 10, "Maximum number of recent orders to display")
@@ -38,7 +58,7 @@ func runOrders(args []string) error {
 	}
 
 	if len(profile.Cookies) == 0 {
-		return errors.New("no cookies in profile; run 'bislericli auth login'")
+		return errors.New(i18n.T("no cookies in profile; run 'bislericli auth login'"))
 	}
 
 	jar, err := bisleri.JarFromCookies(profile.Cookies)
@@ -46,7 +66,7 @@ func runOrders(args []string) error {
 		return err
 	}
 
-	client := bisleri.NewClient(&http.Client{Jar: jar, Timeout: 30 * time.Second}, log.New(os.Stderr, "bisleri: ", log.LstdFlags))
+	client := bisleri.NewClient(&http.Client{Jar: jar, Timeout: 30 * time.Second}, log.New(profileRedactor(cfg, profile).Writer(os.Stderr), "bisleri: ", log.LstdFlags))
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -61,14 +81,15 @@ func runOrders(args []string) error {
 	// Check if we got redirected (not logged in)
 	if resp != nil && resp.Request != nil && resp.Request.URL != nil {
 		if !strings.Contains(resp.Request.URL.Path, "/my-orders") {
-			return errors.New("session expired; please run 'bislericli auth login'")
+			return errors.New(i18n.T("session expired; please run 'bislericli auth login'"))
 		}
 	}
 
 	// Parse orders
 	orders, err := bisleri.ParseOrders(ordersHTML)
 	if err != nil {
-		return fmt.Errorf("failed to parse orders: %w", err)
+		noteParseFailure("orders", "my-orders", ordersHTML)
+		return withUpgradeHint(fmt.Errorf("failed to parse orders: %w", err))
 	}
 
 	if len(orders) == 0 {
@@ -82,10 +103,10 @@ func runOrders(args []string) error {
 	}
 
 	// Display orders in a nice table format
-	fmt.Printf("\nOrder History (showing %d order(s)):\n\n", len(orders))
-	fmt.Println(strings.Repeat("─", 80))
-	fmt.Printf("%-20s  %-12s  %-20s  %-15s\n", "Order ID", "Date", "Status", "Total")
-	fmt.Println(strings.Repeat("─", 80))
+	fmt.Printf("\n%s\n\n", i18n.T("Order History (showing %d order(s)):", len(orders)))
+	fmt.Println(format.Rule(80))
+	fmt.Printf("%-20s  %-12s  %-20s  %-15s\n", i18n.T("Order ID"), i18n.T("Date"), i18n.T("Status"), i18n.T("Total"))
+	fmt.Println(format.Rule(80))
 
 	for _, order := range orders {
 		orderID := order.OrderID
@@ -106,7 +127,7 @@ func runOrders(args []string) error {
 			status = status[:17] + "..."
 		}
 
-		total := order.Total
+		total := format.Currency(order.Total)
 		if len(total) > 15 {
 			total = total[:12] + "..."
 		}
@@ -114,12 +135,105 @@ func runOrders(args []string) error {
 		fmt.Printf("%-20s  %-12s  %-20s  %-15s\n", orderID, date, status, total)
 
 		if order.Items != "" && len(order.Items) < 60 {
-			fmt.Printf("  └─ %s\n", order.Items)
+			fmt.Printf("  %s %s\n", format.Branch(), order.Items)
 		}
 	}
 
-	fmt.Println(strings.Repeat("─", 80))
-	fmt.Printf("\nMost recent order: %s\n", orders[0].OrderID)
+	fmt.Println(format.Rule(80))
+	fmt.Printf("\n%s\n", i18n.T("Most recent order: %s", orders[0].OrderID))
 
 	return nil
 }
+
+func runOrdersAudit(args []string) error {
+	fs := flag.NewFlagSet("orders audit", flag.ContinueOnError)
+	profileName := fs.String("profile", "", "Profile name to use (default: current/default)")
+	limit := fs.Int("limit", 20, "Maximum number of recent attempts to display (0 for all)")
+	failures := fs.Bool("failures", false, "Show only failed attempts")
+	timings := fs.Bool("timings", false, "Add a column with how long each stage of the attempt took")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	name := resolveProfileName(*profileName, cfg)
+	entries, err := store.LoadAuditLog(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Println("No order attempts recorded yet.")
+			return nil
+		}
+		return fmt.Errorf("failed to load audit log: %w", err)
+	}
+	if *failures {
+		var filtered []store.AuditEntry
+		for _, e := range entries {
+			if e.Result == "failure" {
+				filtered = append(filtered, e)
+			}
+		}
+		entries = filtered
+	}
+	if len(entries) == 0 {
+		fmt.Println("No matching order attempts.")
+		return nil
+	}
+	if *limit > 0 && len(entries) > *limit {
+		entries = entries[len(entries)-*limit:]
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := "Time\tResult\tQty\tReturn\tOrder ID\tTotal\tWallet\tError"
+	if *timings {
+		header = "Time\tResult\tQty\tReturn\tOrder ID\tTotal\tWallet\tTimings\tError"
+	}
+	fmt.Fprintln(w, header)
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		wallet := e.WalletBefore
+		if e.WalletAfter != "" {
+			wallet += " -> " + e.WalletAfter
+		}
+		errText := e.Error
+		if e.Result == "inventory" {
+			errText = fmt.Sprintf("%s %g jar(s)", e.Adjustment, e.Jars)
+			if e.Note != "" {
+				errText += ": " + e.Note
+			}
+		}
+		if r := []rune(errText); len(r) > 60 {
+			errText = string(r[:57]) + "..."
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\t%s\t",
+			e.Timestamp.Format("2006-01-02 15:04"), e.Result, e.Quantity, e.ReturnJars,
+			dashIfEmpty(e.OrderID), dashIfEmpty(e.Total), dashIfEmpty(wallet))
+		if *timings {
+			fmt.Fprintf(w, "%s\t", timingsSummary(e.Timings))
+		}
+		fmt.Fprintln(w, errText)
+	}
+	return w.Flush()
+}
+
+// timingsSummary is a compact "cart=1.2s shipping=800ms" list of stage
+// durations.
+func timingsSummary(timings []progress.Timing) string {
+	parts := make([]string, len(timings))
+	for i, t := range timings {
+		parts[i] = fmt.Sprintf("%s=%s", t.Stage, time.Duration(t.ElapsedMS)*time.Millisecond)
+	}
+	return dashIfEmpty(strings.Join(parts, " "))
+}
+
+func dashIfEmpty(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"bislericli/internal/auth"
+	"bislericli/internal/store"
+)
+
+// otpLogFor returns the OTP requests made for phone in the last hour and
+// records new ones in the profile as they are sent, so a failed login still
+// counts towards the cooldown.
+func otpLogFor(profilePath string, profile store.Profile, phone string) *auth.OTPLog {
+	log := &auth.OTPLog{}
+	for _, s := range profile.OTPSends {
+		if s.Phone == phone {
+			log.Sent = append(log.Sent, s.At)
+		}
+	}
+	log.Record = func(at time.Time) {
+		if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+			p.OTPSends = append(pruneOTPSends(p.OTPSends, at), store.OTPSend{Phone: phone, At: at})
+			return nil
+		}); err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: failed to record OTP request:", err)
+		}
+	}
+	return log
+}
+
+// pruneOTPSends drops sends older than an hour, which no longer affect the
+// cooldown or the hourly limit.
+func pruneOTPSends(sends []store.OTPSend, now time.Time) []store.OTPSend {
+	var kept []store.OTPSend
+	for _, s := range sends {
+		if now.Sub(s.At) < time.Hour {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
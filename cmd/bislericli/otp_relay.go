@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"bislericli/internal/auth"
+	"bislericli/internal/config"
+	"bislericli/internal/notify"
+	"bislericli/internal/store"
+)
+
+const (
+	defaultOTPRelayListen = ":8989"
+	defaultOTPRelayWait   = 10 * time.Minute
+	maxOTPRelayBody       = 4 << 10
+)
+
+// otpRelayLoginFn is the OTP login the relay feeds; tests replace it.
+var otpRelayLoginFn = auth.LoginWithOTPFrom
+
+// otpCodePattern finds a standalone six-digit code, as in "123456 is your
+// OTP for Bisleri", without matching part of a phone number.
+var otpCodePattern = regexp.MustCompile(`(?:^|\D)(\d{6})(?:\D|$)`)
+
+// otpRelay accepts OTPs pushed by a phone automation app on POST /otp and
+// hands them to the login waiting for one.
+type otpRelay struct {
+	secret string
+	codes  chan string
+}
+
+func newOTPRelay(secret string) *otpRelay {
+	return &otpRelay{secret: secret, codes: make(chan string, 1)}
+}
+
+func (r *otpRelay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/otp" {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, errors.New("use POST"))
+		return
+	}
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(r.secret)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, errors.New("missing or wrong secret"))
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxOTPRelayBody))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, errors.New("body too large"))
+		return
+	}
+	code, ok := extractOTP(req.Header.Get("Content-Type"), body)
+	if !ok {
+		writeError(w, http.StatusBadRequest, errors.New("no 6-digit code found"))
+		return
+	}
+	select {
+	case r.codes <- code:
+		writeJSON(w, http.StatusAccepted, map[string]string{"status": "received"})
+	default:
+		writeError(w, http.StatusServiceUnavailable, errors.New("an OTP is already waiting to be checked; try again shortly"))
+	}
+}
+
+// extractOTP finds the code in a relayed message. JSON and form bodies carry
+// it in "otp" or the whole SMS in "text"; any other body is the SMS itself.
+func extractOTP(contentType string, body []byte) (string, bool) {
+	text := string(body)
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/json":
+		var msg struct {
+			OTP  string `json:"otp"`
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return "", false
+		}
+		text = msg.OTP + " " + msg.Text
+	case "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(text)
+		if err != nil {
+			return "", false
+		}
+		text = form.Get("otp") + " " + form.Get("text")
+	}
+	m := otpCodePattern.FindStringSubmatch(text)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// relayLogin logs in with an OTP for phone, taking the code from the phone
+// automation app instead of the terminal. It listens on listen until the
+// login finishes or ctx ends.
+func relayLogin(ctx context.Context, relayCfg config.OTPRelay, listen, phone string, sends *auth.OTPLog, output io.Writer) ([]store.Cookie, error) {
+	if relayCfg.Secret == "" {
+		return nil, errors.New("otpRelay.secret is not set; add a random secret of at least 16 characters to the config")
+	}
+	if len(relayCfg.Secret) < minWebhookSecret {
+		return nil, fmt.Errorf("otpRelay.secret is shorter than %d characters", minWebhookSecret)
+	}
+	if listen == "" {
+		listen = relayCfg.Listen
+	}
+	if listen == "" {
+		listen = defaultOTPRelayListen
+	}
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		return nil, err
+	}
+	relay := newOTPRelay(relayCfg.Secret)
+	srv := &http.Server{Handler: relay, ReadHeaderTimeout: 10 * time.Second}
+	go srv.Serve(ln)
+	defer srv.Close()
+	fmt.Fprintf(output, "Waiting for the OTP on http://%s/otp\n", ln.Addr())
+
+	// Relayed codes become lines of input for the OTP prompt.
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	defer close(done)
+	defer pr.Close()
+	go func() {
+		for {
+			select {
+			case code := <-relay.codes:
+				fmt.Fprintln(output, "OTP received from the relay.")
+				if _, err := fmt.Fprintln(pw, code); err != nil {
+					return
+				}
+			case <-ctx.Done():
+				pw.CloseWithError(fmt.Errorf("no OTP relayed in time: %w", ctx.Err()))
+				return
+			case <-done:
+				return
+			}
+		}
+	}()
+	return otpRelayLoginFn(ctx, phone, sends, pr, output)
+}
+
+// relaySessionForOrder is refreshSessionForOrder for unattended orders: the
+// OTP comes from the relay, so nobody needs to be at the terminal.
+func relaySessionForOrder(ctx context.Context, cfg config.GlobalConfig, profilePath string, profile *store.Profile, output io.Writer) error {
+	if profile.PhoneNumber == "" {
+		return errors.New("no phone number saved in the profile; run 'bislericli auth login' once")
+	}
+	fmt.Fprintln(output, "Session expired; logging in again with an OTP from the relay...")
+	sendNotification(cfg.Notifications, notify.Message{Event: notify.LoginNeeded, Title: "Bisleri login needed", Body: "An OTP was requested; it will be read from the relay."})
+	cookies, err := relayLogin(ctx, cfg.OTPRelay, "", profile.PhoneNumber, otpLogFor(profilePath, *profile, profile.PhoneNumber), output)
+	if err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+	return saveRefreshedSession(profilePath, profile, cookies, profile.PhoneNumber)
+}
+
+func runAuthOTPRelay(args []string) error {
+	fs, profileName := parseScheduleFlags("auth otp-relay")
+	listen := fs.String("listen", "", "Address to listen on (default: otpRelay.listen from config, then "+defaultOTPRelayListen+")")
+	phone := fs.String("phone", "", "Phone number (default: the profile's)")
+	wait := fs.Duration("timeout", defaultOTPRelayWait, "How long to wait for the OTP")
+	fs.Usage = func() {
+		fmt.Println("Usage: bislericli auth otp-relay [--listen ADDR] [--phone NUMBER] [--profile NAME] [--timeout 10m]")
+		fmt.Println("\nRequests a login OTP and waits for a phone automation app to POST it to /otp")
+		fmt.Println("with the otpRelay.secret from the config as a bearer token. See the README for")
+		fmt.Println("Tasker and iOS Shortcuts setups.")
+		fmt.Println()
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	name := resolveProfileName(*profileName, cfg)
+	profile, profilePath, err := loadOrCreateProfile(name)
+	if err != nil {
+		return err
+	}
+	phoneNumber := normalizePhoneNumber(*phone)
+	if phoneNumber == "" {
+		phoneNumber = profile.PhoneNumber
+	}
+	if len(phoneNumber) != 10 {
+		return errors.New("phone number required: pass --phone or log in once with 'bislericli auth login'")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *wait)
+	defer cancel()
+	cookies, err := relayLogin(ctx, cfg.OTPRelay, *listen, phoneNumber, otpLogFor(profilePath, profile, phoneNumber), os.Stdout)
+	if err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+	if err := saveRefreshedSession(profilePath, &profile, cookies, phoneNumber); err != nil {
+		return err
+	}
+	fmt.Println("Login captured for profile:", name)
+	return nil
+}
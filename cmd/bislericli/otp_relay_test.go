@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"bislericli/internal/auth"
+	"bislericli/internal/config"
+	"bislericli/internal/store"
+)
+
+func TestExtractOTP(t *testing.T) {
+	for _, tc := range []struct {
+		contentType, body, want string
+	}{
+		{"text/plain", "482913 is your OTP for Bisleri. Do not share it.", "482913"},
+		{"", "OTP:482913", "482913"},
+		{"application/json", `{"otp":"482913"}`, "482913"},
+		{"application/json; charset=utf-8", `{"text":"Use 482913 to log in","sent":1735689600}`, "482913"},
+		{"application/x-www-form-urlencoded", "text=Your+OTP+is+482913", "482913"},
+		{"text/plain", "Call 9876543210 for help", ""},
+		{"application/json", `{"otp":"48291"}`, ""},
+	} {
+		got, ok := extractOTP(tc.contentType, []byte(tc.body))
+		if got != tc.want || ok != (tc.want != "") {
+			t.Errorf("extractOTP(%q, %q) = %q, %v; want %q", tc.contentType, tc.body, got, ok, tc.want)
+		}
+	}
+}
+
+func TestOTPRelay(t *testing.T) {
+	const secret = "0123456789abcdef"
+	relay := newOTPRelay(secret)
+	post := func(auth, body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/otp", strings.NewReader(body))
+		req.Header.Set("Authorization", auth)
+		rec := httptest.NewRecorder()
+		relay.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := post("Bearer wrong", "123456"); code != http.StatusUnauthorized {
+		t.Errorf("wrong secret: status %d, want 401", code)
+	}
+	if code := post("Bearer "+secret, "no code here"); code != http.StatusBadRequest {
+		t.Errorf("no code: status %d, want 400", code)
+	}
+	if code := post("Bearer "+secret, "123456"); code != http.StatusAccepted {
+		t.Errorf("valid: status %d, want 202", code)
+	}
+	if code := post("Bearer "+secret, "654321"); code != http.StatusServiceUnavailable {
+		t.Errorf("second code while one waits: status %d, want 503", code)
+	}
+	if code := <-relay.codes; code != "123456" {
+		t.Errorf("relayed code = %q", code)
+	}
+}
+
+func TestRelayLogin(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	orig := otpRelayLoginFn
+	defer func() { otpRelayLoginFn = orig }()
+	otpRelayLoginFn = func(ctx context.Context, phone string, _ *auth.OTPLog, input io.Reader, _ io.Writer) ([]store.Cookie, error) {
+		line, err := bufio.NewReader(input).ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		return []store.Cookie{{Name: "otp", Value: strings.TrimSpace(line)}}, nil
+	}
+
+	go func() {
+		for i := 0; i < 50; i++ {
+			req, _ := http.NewRequest(http.MethodPost, "http://"+addr+"/otp", strings.NewReader("Your OTP is 246810"))
+			req.Header.Set("Authorization", "Bearer 0123456789abcdef")
+			if resp, err := http.DefaultClient.Do(req); err == nil {
+				resp.Body.Close()
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	cookies, err := relayLogin(ctx, config.OTPRelay{Secret: "0123456789abcdef"}, addr, "9876543210", nil, io.Discard)
+	if err != nil {
+		t.Fatalf("relayLogin: %v", err)
+	}
+	if len(cookies) != 1 || cookies[0].Value != "246810" {
+		t.Errorf("cookies = %+v, want the relayed code", cookies)
+	}
+
+	// Without a code the login gives up when the context ends.
+	short, cancelShort := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelShort()
+	if _, err := relayLogin(short, config.OTPRelay{Secret: "0123456789abcdef"}, "127.0.0.1:0", "9876543210", nil, io.Discard); err == nil {
+		t.Error("relayLogin succeeded without a code")
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// progressOut receives progress chatter from long-running commands. --quiet
+// swaps it for io.Discard so cron jobs only see the final result on stdout
+// and warnings on stderr.
+var progressOut io.Writer = os.Stdout
+
+func progressf(format string, args ...any) {
+	fmt.Fprintf(progressOut, format, args...)
+}
+
+func progressln(args ...any) {
+	fmt.Fprintln(progressOut, args...)
+}
+
+// setQuiet silences progress output until the returned restore func is called.
+func setQuiet(quiet bool) func() {
+	prev := progressOut
+	if quiet {
+		progressOut = io.Discard
+	}
+	return func() { progressOut = prev }
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"bislericli/internal/auth"
+	"bislericli/internal/store"
+)
+
+// passwordLogin logs in with email and password, using the keychain copy of
+// the password when there is one and prompting otherwise.
+func passwordLogin(ctx context.Context, email string, savePassword bool) ([]store.Cookie, error) {
+	stored, err := auth.LoadPassword(email)
+	if err != nil && !errors.Is(err, auth.ErrKeychainUnavailable) {
+		fmt.Fprintln(os.Stderr, "Warning: keychain lookup failed:", err)
+	}
+	password := stored
+	if password == "" {
+		if password, err = readPassword("Password: "); err != nil {
+			return nil, err
+		}
+		if password == "" {
+			return nil, errors.New("password is required")
+		}
+	}
+
+	fmt.Println("Logging in to Bisleri...")
+	cookies, err := auth.LoginWithPassword(ctx, email, password)
+	if err != nil {
+		if stored != "" && errors.Is(err, auth.ErrInvalidCredentials) {
+			return nil, fmt.Errorf("%w (the password saved in the keychain was used; run 'bislericli auth logout --forget-password' to clear it)", err)
+		}
+		return nil, err
+	}
+	if savePassword && stored != password {
+		if err := auth.SavePassword(email, password); err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: password not saved:", err)
+		} else {
+			fmt.Println("Password saved to the keychain.")
+		}
+	}
+	fmt.Println("Login successful!")
+	return cookies, nil
+}
+
+// readPassword prompts for a password without echoing it. When echo cannot
+// be turned off (e.g. on Windows or without a terminal) the input is visible.
+func readPassword(prompt string) (string, error) {
+	fmt.Print(prompt)
+	hidden := runtime.GOOS != "windows" && stty("-echo") == nil
+	if hidden {
+		defer func() {
+			_ = stty("echo")
+			fmt.Println()
+		}()
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func stty(arg string) error {
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
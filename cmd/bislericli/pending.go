@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"bislericli/internal/i18n"
+	"bislericli/internal/store"
+)
+
+// settledStatuses mark orders that will not result in another delivery.
+var settledStatuses = []string{"delivered", "cancel", "complete", "return", "refund", "fail", "reject"}
+
+// findPendingOrder returns the most recent order placed within the last days
+// that has not been delivered or cancelled yet.
+func findPendingOrder(orders []store.SavedOrder, now time.Time, days int) (store.SavedOrder, bool) {
+	if days <= 0 {
+		return store.SavedOrder{}, false
+	}
+	y, m, d := now.AddDate(0, 0, -days).Date()
+	cutoff := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	var pending store.SavedOrder
+	found := false
+	for _, o := range orders {
+		if o.ParsedDate.IsZero() || o.ParsedDate.Before(cutoff) || orderSettled(o.Status) {
+			continue
+		}
+		if !found || o.ParsedDate.After(pending.ParsedDate) {
+			pending = o
+			found = true
+		}
+	}
+	return pending, found
+}
+
+func orderSettled(status string) bool {
+	status = strings.ToLower(status)
+	if strings.Contains(status, "out for delivery") {
+		return false
+	}
+	for _, s := range settledStatuses {
+		if strings.Contains(status, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPendingOrder looks for an undelivered recent order, live from
+// /my-orders when possible and otherwise from local history, and asks before
+// placing another one. Unanswered prompts abort so unattended runs never
+// double up.
+func checkPendingOrder(ctx context.Context, sess *session, profileName string, last *store.OrderInfo, days int) error {
+	if days <= 0 {
+		return nil
+	}
+	orders, err := sess.orders(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: could not check recent orders; using local history:", err)
+		if history, histErr := store.LoadOrderHistory(profileName); histErr == nil {
+			orders = history.Orders
+		}
+	}
+	pending, ok := findPendingOrder(withUnsyncedOrder(orders, last), time.Now(), days)
+	if !ok {
+		return nil
+	}
+	confirmed, timedOut, err := confirmPendingOrderPrompt(os.Stdin, os.Stderr, pending, loginPromptTimeout)
+	if err != nil {
+		return err
+	}
+	if confirmed {
+		return nil
+	}
+	return &pendingOrderError{OrderID: pending.OrderID, TimedOut: timedOut}
+}
+
+func confirmPendingOrderPrompt(input io.Reader, output io.Writer, pending store.SavedOrder, timeout time.Duration) (confirmed bool, timedOut bool, err error) {
+	status := pending.Status
+	if status == "" {
+		status = "pending"
+	}
+	fmt.Fprint(output, i18n.T("You already have order %s (%s) placed on %s. Place another order? [y/N]: ", pending.OrderID, status, pending.ParsedDate.Format("02 Jan 2006")))
+	line, timedOut, err := readLineWithTimeout(input, timeout)
+	if err != nil {
+		return false, false, err
+	}
+	if timedOut {
+		fmt.Fprintln(output)
+		return false, true, nil
+	}
+	answer := strings.TrimSpace(strings.ToLower(line))
+	return answer == "y" || answer == "yes", false, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"bislericli/internal/store"
+)
+
+func TestFindPendingOrder(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	day := func(offset int) time.Time {
+		return time.Date(2026, 10, 16+offset, 0, 0, 0, 0, time.UTC)
+	}
+	orders := []store.SavedOrder{
+		{OrderID: "BS-1", ParsedDate: day(-10), Status: "Processing"},
+		{OrderID: "BS-2", ParsedDate: day(-1), Status: "Delivered"},
+		{OrderID: "BS-3", ParsedDate: day(-1), Status: "Cancelled"},
+		{OrderID: "BS-4", ParsedDate: day(-2), Status: "Out for Delivery"},
+	}
+
+	pending, ok := findPendingOrder(orders, now, 2)
+	if !ok || pending.OrderID != "BS-4" {
+		t.Fatalf("expected BS-4 pending, got %q (%v)", pending.OrderID, ok)
+	}
+	if _, ok := findPendingOrder(orders, now, 1); ok {
+		t.Fatalf("expected no pending order within 1 day")
+	}
+	if _, ok := findPendingOrder(orders, now, 0); ok {
+		t.Fatalf("expected check to be disabled for 0 days")
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/money"
+	"bislericli/internal/policy"
+	"bislericli/internal/store"
+)
+
+// recentOrderTimes lists when the profile's orders were placed, from synced
+// history plus an unsynced last order. Cancelled orders are left out.
+func recentOrderTimes(profileName string, last *store.OrderInfo) []time.Time {
+	var orders []store.SavedOrder
+	if history, err := store.LoadOrderHistory(profileName); err == nil {
+		orders = history.Orders
+	}
+	var times []time.Time
+	for _, o := range withUnsyncedOrder(orders, last) {
+		if o.ParsedDate.IsZero() || strings.Contains(strings.ToLower(o.Status), "cancel") {
+			continue
+		}
+		times = append(times, o.ParsedDate)
+	}
+	return times
+}
+
+// checkCheckoutPolicy applies the price and wallet rules at the payment step.
+// A wallet rule with an unreadable balance blocks the order rather than guess.
+func checkCheckoutPolicy(p config.Policy, jarPrice money.Money, walletBefore string, total money.Money) error {
+	balance, ok := money.Parse(walletBefore)
+	if !ok && p.MinWalletBalance > 0 {
+		return &policy.Violation{Rule: "minWalletBalance", Detail: "wallet balance could not be read"}
+	}
+	if jarPrice == 0 && p.MaxJarPrice > 0 {
+		fmt.Fprintln(os.Stderr, "Warning: per-jar price unknown for this cart; maxJarPrice not checked")
+	}
+	return policy.CheckCheckout(p, jarPrice, balance, total)
+}
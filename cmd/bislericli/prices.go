@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/format"
+	"bislericli/internal/money"
+	"bislericli/internal/store"
+)
+
+// jarUnitPrice derives the per-jar price from the checkout total. Only
+// jar-only orders returning every jar qualify; otherwise deposits or other
+// products are mixed into the total.
+func jarUnitPrice(total money.Money, quantity, returnJars int, items []store.OrderItem) (money.Money, bool) {
+	if total <= 0 || quantity <= 0 || returnJars != quantity || len(items) > 1 {
+		return 0, false
+	}
+	return total.Div(quantity), true
+}
+
+// lastJarPrice returns the most recent price observed in city.
+func lastJarPrice(history []store.PriceObservation, city string) (money.Money, bool) {
+	for i := len(history) - 1; i >= 0; i-- {
+		if strings.EqualFold(history[i].City, city) {
+			return history[i].UnitPrice, true
+		}
+	}
+	return 0, false
+}
+
+// notePriceChange prints a highlighted notice when the per-jar price differs
+// from the last one observed, and returns the previous price (0 if none).
+func notePriceChange(profileName, city string, price money.Money) money.Money {
+	history, err := store.LoadPriceHistory(profileName)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintln(os.Stderr, "Warning: failed to read price history:", err)
+	}
+	prev, ok := lastJarPrice(history, city)
+	if !ok {
+		return 0
+	}
+	if prev != price {
+		fmt.Fprintln(os.Stderr, format.Highlight(fmt.Sprintf("Price change: 20L jar is now %s (was %s)", price, prev)))
+	}
+	return prev
+}
+
+// recordJarPrice appends the observation when it is the first for the city or
+// the price changed.
+func recordJarPrice(profileName, city, orderID string, price, prev money.Money) {
+	if prev == price {
+		return
+	}
+	obs := store.PriceObservation{Timestamp: time.Now(), City: city, OrderID: orderID, UnitPrice: price, Previous: prev}
+	if err := store.AppendPriceObservation(profileName, obs); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: failed to record price:", err)
+	}
+}
+
+func runStatsPrices(args []string) error {
+	fs := flag.NewFlagSet("stats prices", flag.ContinueOnError)
+	profileName := fs.String("profile", "", "Profile name to use (default: current/default)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	name := resolveProfileName(*profileName, cfg)
+	history, err := store.LoadPriceHistory(name)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to load price history: %w", err)
+	}
+	if len(history) == 0 {
+		fmt.Println("No prices recorded yet; the per-jar price is captured when jar-only orders are placed with this CLI.")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "Date\tCity\tPer jar\tChange\tOrder\t")
+	for _, obs := range history {
+		change := "first seen"
+		if obs.Previous != 0 {
+			diff := obs.UnitPrice - obs.Previous
+			sign := "+"
+			if diff < 0 {
+				sign, diff = "-", -diff
+			}
+			change = sign + diff.String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n", obs.Timestamp.Format("2006-01-02"), dashIfEmpty(obs.City), obs.UnitPrice, change, dashIfEmpty(obs.OrderID))
+	}
+	return w.Flush()
+}
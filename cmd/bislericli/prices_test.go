@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"bislericli/internal/money"
+	"bislericli/internal/store"
+)
+
+func TestJarUnitPrice(t *testing.T) {
+	total, _ := money.Parse("₹200.00")
+	jar := []store.OrderItem{{ProductID: productID20L, Quantity: 2}}
+	if unit, ok := jarUnitPrice(total, 2, 2, jar); !ok || unit.Plain() != "100.00" {
+		t.Fatalf("jarUnitPrice = %v, %v; want 100.00", unit, ok)
+	}
+	if _, ok := jarUnitPrice(total, 2, 1, jar); ok {
+		t.Fatalf("orders with a jar deposit must not yield a unit price")
+	}
+	extra := append(jar, store.OrderItem{ProductID: "BIS-1LTR-12", Quantity: 1})
+	if _, ok := jarUnitPrice(total, 2, 2, extra); ok {
+		t.Fatalf("orders with other products must not yield a unit price")
+	}
+}
+
+func TestLastJarPrice(t *testing.T) {
+	history := []store.PriceObservation{
+		{City: "Pune", UnitPrice: 9000},
+		{City: "Mumbai", UnitPrice: 10000},
+		{City: "pune", UnitPrice: 9500},
+	}
+	if got, ok := lastJarPrice(history, "Pune"); !ok || got != 9500 {
+		t.Fatalf("lastJarPrice(Pune) = %v, %v", got, ok)
+	}
+	if _, ok := lastJarPrice(history, "Delhi"); ok {
+		t.Fatalf("expected no price for an unseen city")
+	}
+}
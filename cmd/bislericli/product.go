@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/store"
+)
+
+// jarProductID returns the 20L jar product ID for city: the one catalog
+// discovery last resolved there, or the built-in ID.
+func jarProductID(city string) string {
+	if cached, ok := store.CachedJarProduct(city); ok {
+		return cached.ID
+	}
+	return productID20L
+}
+
+// addJarToCart adds the jar and waits for it to show up in the cart. If that
+// fails, the catalog is searched for the jar in case the site renamed the
+// product; a new ID is cached for the city and the add is retried once.
+func addJarToCart(ctx context.Context, client *bisleri.Client, city string, productID *string, quantity int, allowExtra bool) error {
+	err := addAndConfirm(ctx, client, *productID, quantity, allowExtra)
+	if err == nil || errors.Is(err, bisleri.ErrNotAuthenticated) || ctx.Err() != nil {
+		return err
+	}
+	found, ok := discoverJarProduct(ctx, client)
+	if !ok || strings.EqualFold(found.ID, *productID) {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Warning: adding %s failed (%v); the catalog now lists the 20L jar as %s (%s)\n", *productID, err, found.ID, found.Name)
+	if saveErr := store.SaveJarProduct(city, store.CachedProduct{ID: found.ID, Name: found.Name, ResolvedAt: time.Now()}); saveErr != nil {
+		fmt.Fprintln(os.Stderr, "Warning: failed to cache product ID:", saveErr)
+	}
+	*productID = found.ID
+	return addAndConfirm(ctx, client, found.ID, quantity, allowExtra)
+}
+
+func addAndConfirm(ctx context.Context, client *bisleri.Client, productID string, quantity int, allowExtra bool) error {
+	if err := client.AddProduct(ctx, productID, quantity); err != nil {
+		return err
+	}
+	return confirmCartQuantity(ctx, client, productID, quantity, allowExtra)
+}
+
+// discoverJarProduct searches the catalog for the 20 litre jar by name.
+func discoverJarProduct(ctx context.Context, client *bisleri.Client) (bisleri.Product, bool) {
+	for _, query := range []string{"20 litre jar", "20L"} {
+		products, err := client.SearchProducts(ctx, query)
+		if err != nil {
+			continue
+		}
+		if p, ok := bisleri.FindJarProduct(products); ok {
+			return p, true
+		}
+	}
+	return bisleri.Product{}, false
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/cookiejar"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/config"
+	"bislericli/internal/money"
+	"bislericli/internal/store"
+)
+
+func runProducts(args []string) error {
+	if len(args) < 1 || isHelpToken(args[0]) {
+		printProductsUsage()
+		return nil
+	}
+	switch args[0] {
+	case "list":
+		return runProductsList(args[1:])
+	default:
+		fmt.Printf("Unknown products subcommand: %s\n", args[0])
+		printProductsUsage()
+		return nil
+	}
+}
+
+func printProductsUsage() {
+	fmt.Println("Usage: bislericli products <subcommand> [flags]")
+	fmt.Println("\nAvailable subcommands:")
+	fmt.Println("  list   Show product IDs, pack sizes, prices and availability for a city")
+}
+
+func runProductsList(args []string) error {
+	fs, profileName := parseScheduleFlags("products list")
+	city := fs.String("city", "", "List products for another city (does not change the profile's city)")
+	availableOnly := fs.Bool("available", false, "Only show products that can be ordered")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	name := resolveProfileName(*profileName, cfg)
+	profile, _, err := loadOrCreateProfile(name)
+	if err != nil {
+		return err
+	}
+
+	// Another city is looked up in a fresh session so the account's selected
+	// city stays as it is.
+	var jar http.CookieJar
+	if len(profile.Cookies) > 0 && *city == "" {
+		jar, err = bisleri.JarFromCookies(profile.Cookies)
+	} else {
+		jar, err = cookiejar.New(nil)
+	}
+	if err != nil {
+		return err
+	}
+	client := bisleri.NewClient(&http.Client{Jar: jar, Timeout: 30 * time.Second}, log.New(profileRedactor(cfg, profile).Writer(os.Stderr), "bisleri: ", log.LstdFlags))
+	if len(profile.Cookies) > 0 && *city == "" {
+		client.Cache = store.OpenPageCache(name)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	want := *city
+	if want == "" && len(profile.Cookies) == 0 {
+		want = profile.PreferredCity
+	}
+	if want != "" {
+		if err := client.SetCityLocation(ctx, want); err != nil {
+			return fmt.Errorf("failed to select city %s: %w", want, err)
+		}
+	}
+	products, shownCity, err := client.ListProducts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load products: %w", err)
+	}
+	if len(products) == 0 {
+		return withUpgradeHint(errors.New("no products found on the listing page"))
+	}
+	if shownCity == "" {
+		shownCity = want
+	}
+	if shownCity == "" {
+		shownCity = profile.PreferredCity
+	}
+	if shownCity != "" {
+		fmt.Println("City:", shownCity)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tPACK\tPRICE\tAVAILABLE")
+	for _, p := range products {
+		if *availableOnly && !p.Available {
+			continue
+		}
+		available := "yes"
+		if !p.Available {
+			available = "no"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.ID, p.Name, dashIfEmpty(p.PackSize), dashIfEmpty(p.Price), available)
+	}
+	w.Flush()
+
+	if jar, ok := bisleri.FindJarProduct(products); ok && shownCity != "" {
+		if price, ok := money.Parse(jar.Price); ok {
+			notePriceChange(name, shownCity, price)
+		}
+	}
+	return nil
+}
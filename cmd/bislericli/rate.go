@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/config"
+	"bislericli/internal/format"
+	"bislericli/internal/i18n"
+)
+
+// autoRating is the feedback 'sync --watch --rate' leaves on orders as they
+// are delivered; zero Stars turns it off.
+type autoRating struct {
+	Stars   int
+	Comment string
+}
+
+func runOrdersRate(args []string) error {
+	fs, profileName := parseScheduleFlags("orders rate")
+	stars := fs.Int("stars", 5, "Rating from 1 to 5 stars")
+	comment := fs.String("comment", "", "Optional feedback comment")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("order ID required: orders rate [--stars 1-5] [--comment text] <orderID>")
+	}
+	if *stars < 1 || *stars > 5 {
+		return fmt.Errorf("--stars must be between 1 and 5, got %d", *stars)
+	}
+	orderID := strings.ToUpper(strings.TrimSpace(fs.Arg(0)))
+
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, _, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	client, err := accountClient(cfg, profile)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	if err := client.RateOrder(ctx, orderID, *stars, strings.TrimSpace(*comment)); err != nil {
+		switch {
+		case errors.Is(err, bisleri.ErrNotAuthenticated):
+			return errors.New(i18n.T("session expired; please run 'bislericli auth login'"))
+		case errors.Is(err, bisleri.ErrFeedbackUnavailable):
+			return fmt.Errorf("order %s cannot be rated: the order history shows no feedback form for it (not delivered yet, already rated, or the site does not take ratings)", orderID)
+		}
+		return withUpgradeHint(fmt.Errorf("failed to rate order %s: %w", orderID, err))
+	}
+	fmt.Println(format.Check(), fmt.Sprintf("Rated order %s %d/5.", orderID, *stars))
+	return nil
+}
+
+// isDelivered reports whether an order status means the jars have arrived.
+func isDelivered(status string) bool {
+	status = strings.ToLower(status)
+	return strings.Contains(status, "delivered") && !strings.Contains(status, "out for") && !strings.Contains(status, "not ")
+}
+
+// rateDelivered leaves the automatic rating on orders whose status changed to
+// delivered in this watch cycle. Orders first seen already delivered are
+// left alone so a first sync does not rate the whole history. Failures are
+// warnings: the watch goes on.
+func rateDelivered(ctx context.Context, client *bisleri.Client, changes []orderChange, rating autoRating) {
+	if rating.Stars == 0 {
+		return
+	}
+	for _, c := range changes {
+		if c.New || !isDelivered(c.Order.Status) || isDelivered(c.FromStatus) {
+			continue
+		}
+		rateCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		err := client.RateOrder(rateCtx, c.Order.OrderID, rating.Stars, rating.Comment)
+		cancel()
+		switch {
+		case errors.Is(err, bisleri.ErrFeedbackUnavailable):
+			fmt.Fprintf(os.Stderr, "Warning: order %s has no feedback form; not rated\n", c.Order.OrderID)
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "Warning: failed to rate order %s: %v\n", c.Order.OrderID, err)
+		default:
+			fmt.Printf("[%s] Rated %s %d/5\n", time.Now().Format("15:04"), c.Order.OrderID, rating.Stars)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/i18n"
+	"bislericli/internal/money"
+	"bislericli/internal/policy"
+)
+
+// orderError is a failed order attempt with the flow stage it failed in
+// (the progress stage names: session, cart, shipping, ...).
+type orderError struct {
+	Stage string
+	Err   error
+}
+
+func (e *orderError) Error() string { return e.Err.Error() }
+
+func (e *orderError) Unwrap() error { return e.Err }
+
+// pendingOrderError stops an order while a recent one is still pending.
+type pendingOrderError struct {
+	OrderID  string
+	TimedOut bool
+}
+
+func (e *pendingOrderError) Error() string {
+	if e.TimedOut {
+		return fmt.Sprintf("order %s is still pending; no confirmation received, not placing another (pass --force to override)", e.OrderID)
+	}
+	return fmt.Sprintf("order %s is still pending; not placing another", e.OrderID)
+}
+
+// cartConflictError is a cart holding items the order did not add. Items is
+// empty when they could not be identified.
+type cartConflictError struct {
+	Items []string
+}
+
+func (e *cartConflictError) Error() string {
+	if len(e.Items) == 0 {
+		return "cart is not empty; clear cart or pass --allow-extra"
+	}
+	return fmt.Sprintf("cart contains other items; clear cart or pass --allow-extra (items: %s)", strings.Join(e.Items, ", "))
+}
+
+// walletShortError is a wallet balance below the order total.
+type walletShortError struct {
+	Balance, Total string
+}
+
+func (e *walletShortError) Error() string {
+	return i18n.T("insufficient wallet balance (%s) for order total (%s)", e.Balance, e.Total)
+}
+
+// budgetError is an order refused by --strict-budget.
+type budgetError struct {
+	Projected, Budget, Spent money.Money
+}
+
+func (e *budgetError) Error() string {
+	return fmt.Sprintf("order would bring this month's spend to %s, over the %s budget (%s already spent); aborting (--strict-budget)", e.Projected, e.Budget, e.Spent)
+}
+
+// orderStages describes each stage in the recovery panel.
+var orderStages = map[string]string{
+	"session":         "checking the session",
+	"cart":            "preparing the cart",
+	"return-jars":     "setting return jars",
+	"shipping":        "loading shipping details",
+	"submit-shipping": "submitting the address and slot",
+	"payment-page":    "checking the total and wallet",
+	"payment":         "submitting wallet payment",
+	"place-order":     "placing the order",
+	"login":           "logging in again",
+}
+
+// recovery is the likely cause of a failed order and what to do next.
+type recovery struct {
+	Cause string
+	Next  []string
+}
+
+// diagnose maps a failed order to a recovery. retry is the command that
+// repeats the order; suggestions add flags to it where a flag is the fix.
+func diagnose(stage string, err error, args []string) recovery {
+	retry := retryCommand(args)
+	var (
+		addrErr    *bisleri.AddressValidationError
+		violation  *policy.Violation
+		pending    *pendingOrderError
+		cartErr    *cartConflictError
+		walletErr  *walletShortError
+		budgetErr  *budgetError
+		statusErr  *bisleri.HTTPStatusError
+		networkErr net.Error
+	)
+	switch {
+	case errors.Is(err, bisleri.ErrNotAuthenticated):
+		return recovery{"The Bisleri session expired.", []string{"bislericli auth login", retry}}
+	case errors.Is(err, bisleri.ErrNoSlotAvailable):
+		return recovery{"Every delivery slot is full.", []string{retryCommand(args, "--wait-for-slot"), retryCommand(args, "--timeslot", `"<another slot>"`)}}
+	case errors.As(err, &addrErr):
+		return recovery{"The site rejected the delivery address.", []string{"Fix the address on bisleri.com, or pick another with 'bislericli address add --pick <name>'", retry}}
+	case errors.As(err, &violation):
+		return recovery{fmt.Sprintf("Blocked by the %s policy in config.json.", violation.Rule), []string{"Change policy." + violation.Rule + " in config.json if the order should go through"}}
+	case errors.As(err, &pending):
+		return recovery{fmt.Sprintf("Order %s is still pending.", pending.OrderID), []string{"bislericli orders", retryCommand(args, "--force")}}
+	case errors.As(err, &cartErr):
+		return recovery{"The cart holds items this order did not add.", []string{"Remove them at https://www.bisleri.com/cart", retryCommand(args, "--allow-extra")}}
+	case errors.As(err, &walletErr):
+		return recovery{fmt.Sprintf("Wallet balance %s is below the order total %s.", walletErr.Balance, walletErr.Total), []string{"Top up the Bisleri wallet (see 'bislericli stats wallet' for a suggested amount)", retry}}
+	case errors.As(err, &budgetErr):
+		return recovery{fmt.Sprintf("The order would take this month's spend to %s, over the %s budget.", budgetErr.Projected, budgetErr.Budget), []string{"Raise defaults.monthlyBudget, or drop --strict-budget: " + strings.Replace(retry, " --strict-budget", "", 1)}}
+	case stage == "place-order":
+		return recovery{"The site did not confirm the order; it may still have been placed.", []string{"bislericli sync && bislericli orders", retry + "  (only if the order is not listed)"}}
+	case errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests:
+		return recovery{"Bisleri is rate-limiting requests.", []string{"Wait a few minutes, then: " + retry}}
+	case errors.As(err, &statusErr) && statusErr.StatusCode >= 500:
+		return recovery{fmt.Sprintf("The Bisleri site returned %s.", statusErr.Status), []string{"Wait a few minutes, then: " + retry}}
+	case errors.Is(err, context.DeadlineExceeded) || errors.As(err, &networkErr) && networkErr.Timeout():
+		return recovery{"The Bisleri site did not respond in time.", []string{retry}}
+	}
+	return recovery{"Unexpected error (the site may have changed).", []string{retryCommand(args, "--debug"), "bislericli doctor"}}
+}
+
+// retryCommand rebuilds the order command line, adding extra flags that are
+// not already set.
+func retryCommand(args []string, extra ...string) string {
+	parts := []string{"bislericli", "order"}
+	for _, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\"'$") {
+			a = fmt.Sprintf("%q", a)
+		}
+		parts = append(parts, a)
+	}
+	if len(extra) > 0 && !hasFlag(args, extra[0]) {
+		parts = append(parts, extra...)
+	}
+	return strings.Join(parts, " ")
+}
+
+func hasFlag(args []string, flag string) bool {
+	name := strings.TrimLeft(flag, "-")
+	for _, a := range args {
+		a = strings.TrimLeft(a, "-")
+		if a == name || strings.HasPrefix(a, name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// cartState describes what the cart holds after a failure, so the user knows
+// whether the next attempt starts from a filled cart.
+func cartState(client *bisleri.Client) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	html, err := client.FetchCartPage(ctx)
+	if err != nil {
+		return "unknown (could not load the cart)"
+	}
+	cart := bisleri.ParseCartPage(html)
+	if len(cart.Items) == 0 {
+		if cart.Unparsed() {
+			return fmt.Sprintf("holds %d item(s)", cart.Count)
+		}
+		return "empty"
+	}
+	var items []string
+	for _, item := range cart.Items {
+		items = append(items, fmt.Sprintf("%d x %s", item.Quantity, item.ProductID))
+	}
+	return "still holds " + strings.Join(items, ", ") + "; the next attempt reuses it"
+}
+
+// printRecoveryPanel prints the failed stage, likely cause, cart state and
+// next steps. cart is empty when the cart was not touched.
+func printRecoveryPanel(w io.Writer, stage string, rec recovery, cart string) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, i18n.T("Order failed"))
+	if stage != "" {
+		label := stage
+		if desc, ok := orderStages[stage]; ok {
+			label += " (" + desc + ")"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\n", i18n.T("Stage"), label)
+	}
+	fmt.Fprintf(tw, "  %s\t%s\n", i18n.T("Cause"), rec.Cause)
+	if cart != "" {
+		fmt.Fprintf(tw, "  %s\t%s\n", i18n.T("Cart"), cart)
+	}
+	for i, step := range rec.Next {
+		label := ""
+		if i == 0 {
+			label = i18n.T("Next")
+		}
+		fmt.Fprintf(tw, "  %s\t%d. %s\n", label, i+1, step)
+	}
+	tw.Flush()
+}
+
+// reportOrderFailure prints the recovery panel for err when it carries the
+// stage it failed in. client, when set, is used to report what the cart holds.
+func reportOrderFailure(w io.Writer, err error, args []string, client *bisleri.Client) {
+	var oe *orderError
+	if !errors.As(err, &oe) {
+		return
+	}
+	cart := ""
+	if client != nil && oe.Stage != "session" && oe.Stage != "login" {
+		cart = cartState(client)
+	}
+	printRecoveryPanel(w, oe.Stage, diagnose(oe.Stage, oe.Err, args), cart)
+}
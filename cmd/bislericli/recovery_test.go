@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"bislericli/internal/bisleri"
+)
+
+func TestDiagnoseSuggestsFlagFixes(t *testing.T) {
+	args := []string{"--qty", "2"}
+
+	slot := diagnose("shipping", fmt.Errorf("%w (Morning)", bisleri.ErrNoSlotAvailable), args)
+	if len(slot.Next) == 0 || slot.Next[0] != "bislericli order --qty 2 --wait-for-slot" {
+		t.Fatalf("unexpected slot recovery: %+v", slot)
+	}
+
+	cart := diagnose("cart", &cartConflictError{Items: []string{"Bis-1L"}}, args)
+	if got := cart.Next[len(cart.Next)-1]; got != "bislericli order --qty 2 --allow-extra" {
+		t.Fatalf("cart recovery retry = %q", got)
+	}
+
+	pending := diagnose("session", &pendingOrderError{OrderID: "BS-1"}, []string{"--force"})
+	if got := pending.Next[1]; got != "bislericli order --force" {
+		t.Fatalf("--force should not be added twice, got %q", got)
+	}
+
+	placed := diagnose("place-order", fmt.Errorf("connection reset"), nil)
+	if !strings.Contains(placed.Cause, "may still have been placed") {
+		t.Fatalf("place-order failure should warn about a possible order, got %q", placed.Cause)
+	}
+}
+
+func TestRetryCommandQuotesArgs(t *testing.T) {
+	got := retryCommand([]string{"--note", "ring the bell", "--for=office"})
+	want := `bislericli order --note "ring the bell" --for=office`
+	if got != want {
+		t.Fatalf("retryCommand = %q, want %q", got, want)
+	}
+	if !hasFlag([]string{"--timeslot=Morning"}, "--timeslot") {
+		t.Fatalf("hasFlag should match --flag=value")
+	}
+}
+
+func TestReportOrderFailureNeedsStage(t *testing.T) {
+	var buf bytes.Buffer
+	reportOrderFailure(&buf, fmt.Errorf("quantity must be a positive number"), nil, nil)
+	if buf.Len() != 0 {
+		t.Fatalf("errors without a stage should not print a panel, got %q", buf.String())
+	}
+
+	err := fmt.Errorf("gave up: %w", &orderError{Stage: "session", Err: bisleri.ErrNotAuthenticated})
+	reportOrderFailure(&buf, err, []string{"--qty", "1"}, nil)
+	out := buf.String()
+	for _, want := range []string{"Order failed", "session (checking the session)", "bislericli auth login", "bislericli order --qty 1"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("panel missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Cart") {
+		t.Fatalf("cart state should be skipped without a client:\n%s", out)
+	}
+}
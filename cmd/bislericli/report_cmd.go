@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/debug"
+	"bislericli/internal/report"
+	"bislericli/internal/store"
+)
+
+func runDebugReport(args []string) error {
+	fs := flag.NewFlagSet("debug report", flag.ContinueOnError)
+	profileName := fs.String("profile", "", "Profile name to use (default: current/default)")
+	output := fs.String("output", "", "Output zip path (default: bislericli-report-<timestamp>.zip)")
+	lines := fs.Int("lines", 50, "Number of recent audit log lines to include")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	name := resolveProfileName(*profileName, cfg)
+	cfgPath, err := config.ConfigFilePath()
+	if err != nil {
+		return err
+	}
+	profilePath, err := config.ProfilePath(name)
+	if err != nil {
+		return err
+	}
+	auditPath, err := store.GetAuditPath(name)
+	if err != nil {
+		return err
+	}
+	artifactsDir, err := debug.ArtifactsDir()
+	if err != nil {
+		return err
+	}
+	dest := *output
+	if dest == "" {
+		dest = fmt.Sprintf("bislericli-report-%s.zip", time.Now().Format("20060102-150405"))
+	}
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	buildErr := report.Build(f, report.Options{
+		Version:     version,
+		ConfigPath:  cfgPath,
+		ProfilePath: profilePath,
+		AuditPath:   auditPath,
+		DebugDir:    artifactsDir,
+		LogLines:    *lines,
+		MaxSnapshot: 2 << 20,
+		Patterns:    cfg.Redaction.Patterns,
+	})
+	if closeErr := f.Close(); buildErr == nil {
+		buildErr = closeErr
+	}
+	if buildErr != nil {
+		_ = os.Remove(dest)
+		return buildErr
+	}
+	fmt.Println("Report written to:", dest)
+	fmt.Println("Review it before attaching to an issue; personal data has been redacted.")
+	return nil
+}
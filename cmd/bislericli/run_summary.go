@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runSummary records what one invocation did, for the single line printed
+// to stderr when it exits so cron logs can be grepped for outcomes. Only
+// commands that call begin get a line.
+type runSummary struct {
+	command string
+	profile string
+	started time.Time
+	orders  []string
+	totals  []string
+}
+
+var summary runSummary
+
+// begin starts the summary for command. Commands run inside another one
+// (orders placed by 'schedule run') keep the outer command's summary.
+func (s *runSummary) begin(command string) {
+	if s.command != "" {
+		return
+	}
+	s.command = command
+	s.started = time.Now()
+}
+
+func (s *runSummary) setProfile(name string) {
+	if s.profile == "" {
+		s.profile = name
+	}
+}
+
+// addOrder records a placed order and its total.
+func (s *runSummary) addOrder(id, total string) {
+	s.orders = append(s.orders, id)
+	s.totals = append(s.totals, total)
+}
+
+// line formats the summary as logfmt key=value pairs, e.g.
+//
+//	bislericli: time=2026-10-16T09:00:03+05:30 command=order profile=home result=ok order=BS-1 total=₹180 duration=4.2s
+//
+// Several orders are comma-separated; missing values are "-".
+func (s *runSummary) line(now time.Time, err error) string {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	fields := []string{
+		"time=" + now.Format(time.RFC3339),
+		"command=" + logfmtValue(s.command),
+		"profile=" + logfmtValue(s.profile),
+		"result=" + result,
+		"order=" + logfmtValue(strings.Join(s.orders, ",")),
+		"total=" + logfmtValue(strings.Join(s.totals, ",")),
+		"duration=" + now.Sub(s.started).Round(100*time.Millisecond).String(),
+	}
+	if err != nil {
+		fields = append(fields, "error="+strconv.Quote(err.Error()))
+	}
+	return "bislericli: " + strings.Join(fields, " ")
+}
+
+// print writes the line if a command began a summary.
+func (s *runSummary) print(w io.Writer, err error) {
+	if s.command == "" {
+		return
+	}
+	fmt.Fprintln(w, s.line(time.Now(), err))
+}
+
+func logfmtValue(v string) string {
+	if v == "" {
+		return "-"
+	}
+	if strings.ContainsAny(v, " \t\"=") {
+		return strconv.Quote(v)
+	}
+	return v
+}
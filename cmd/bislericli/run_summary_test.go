@@ -0,0 +1,31 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunSummaryLine(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.FixedZone("IST", 19800))
+	s := runSummary{}
+	s.begin("schedule run")
+	s.begin("order")
+	s.started = start
+	s.setProfile("home")
+	s.setProfile("office")
+	s.addOrder("BS-1", "₹180")
+	s.addOrder("BS-2", "₹90")
+	got := s.line(start.Add(4230*time.Millisecond), nil)
+	want := `bislericli: time=2026-10-16T09:00:04+05:30 command="schedule run" profile=home result=ok order=BS-1,BS-2 total=₹180,₹90 duration=4.2s`
+	if got != want {
+		t.Errorf("line =\n%s\nwant\n%s", got, want)
+	}
+
+	s = runSummary{command: "order", started: start}
+	got = s.line(start.Add(time.Second), errors.New(`cart has "other" items`))
+	want = `bislericli: time=2026-10-16T09:00:01+05:30 command=order profile=- result=error order=- total=- duration=1s error="cart has \"other\" items"`
+	if got != want {
+		t.Errorf("line =\n%s\nwant\n%s", got, want)
+	}
+}
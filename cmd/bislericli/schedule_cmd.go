@@ -0,0 +1,520 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/jobs"
+	"bislericli/internal/notify"
+	"bislericli/internal/policy"
+	"bislericli/internal/schedule"
+	"bislericli/internal/store"
+)
+
+func runSchedule(args []string) error {
+	if len(args) > 0 && isHelpToken(args[0]) {
+		printScheduleUsage()
+		return nil
+	}
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runScheduleShow(args)
+	}
+	sub := args[0]
+	subArgs := args[1:]
+
+	switch sub {
+	case "show":
+		return runScheduleShow(subArgs)
+	case "next", "calendar":
+		return runScheduleNext(subArgs)
+	case "skip":
+		return runScheduleSkip(subArgs)
+	case "unskip":
+		return runScheduleUnskip(subArgs)
+	case "pause":
+		return runSchedulePause(subArgs)
+	case "resume":
+		return runScheduleResume(subArgs)
+	case "run":
+		return runScheduleRun(subArgs)
+	case "export-ics":
+		return runScheduleExportICS(subArgs)
+	default:
+		fmt.Printf("Unknown schedule subcommand: %s\n", sub)
+		printScheduleUsage()
+		return nil
+	}
+}
+
+func printScheduleUsage() {
+	fmt.Println("Usage: bislericli schedule [subcommand] [flags]")
+	fmt.Println("\nAvailable subcommands:")
+	fmt.Println("  show     Show current schedule defaults (default)")
+	fmt.Println("  next     List upcoming scheduled runs (alias: calendar)")
+	fmt.Println("  skip     Skip a scheduled run: skip <YYYY-MM-DD|next>")
+	fmt.Println("  unskip   Remove a previously recorded skip date")
+	fmt.Println("  pause    Pause scheduled orders: pause --until YYYY-MM-DD | --days N")
+	fmt.Println("  resume   Clear an active pause")
+	fmt.Println("  run      Run the scheduler (use --once for cron)")
+	fmt.Println("  export-ics  Write past orders and upcoming runs to an iCalendar file")
+}
+
+func loadSchedulePlan(cfg config.GlobalConfig, profile store.Profile) (schedule.Plan, error) {
+	plan, err := schedule.Parse(cfg.Defaults.Schedule, cfg.Defaults.ScheduleTime)
+	if err != nil {
+		return schedule.Plan{}, fmt.Errorf("invalid schedule in config: %w", err)
+	}
+	plan.Blackout, err = schedule.ParseBlackout(cfg.Blackout.Weekdays, cfg.Blackout.Dates)
+	if err != nil {
+		return schedule.Plan{}, fmt.Errorf("invalid blackout in config: %w", err)
+	}
+	if cal, ok := holidayCalendar(cfg, profile); ok && cfg.Holidays.Action == "shift" {
+		plan.Blackout.Ranges = append(plan.Blackout.Ranges, holidayBlackout(cal, time.Now())...)
+	}
+	return plan, nil
+}
+
+func parseScheduleFlags(name string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	profileName := fs.String("profile", "", "Profile name to use (default: current/default)")
+	return fs, profileName
+}
+
+func runScheduleShow(args []string) error {
+	fs, profileName := parseScheduleFlags("schedule show")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, _, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	fmt.Println("Schedule:", cfg.Defaults.Schedule)
+	fmt.Println("Schedule time:", cfg.Defaults.ScheduleTime)
+	fmt.Println("Default quantity:", cfg.Defaults.OrderQuantity)
+	fmt.Println("Default return jars:", cfg.Defaults.ReturnJars)
+	if cfg.Defaults.AdaptiveQuantity {
+		fmt.Printf("Adaptive quantity: on (%d-%d jars)\n", cfg.Defaults.MinQuantity, cfg.Defaults.MaxQuantity)
+	}
+	if len(cfg.Blackout.Weekdays) > 0 {
+		fmt.Println("Blackout weekdays:", strings.Join(cfg.Blackout.Weekdays, ", "))
+	}
+	if len(cfg.Blackout.Dates) > 0 {
+		fmt.Println("Blackout dates:", strings.Join(cfg.Blackout.Dates, ", "))
+	}
+	if state := profile.Schedule; state != nil {
+		if !state.PausedUntil.IsZero() && time.Now().Before(state.PausedUntil) {
+			fmt.Println("Paused until:", state.PausedUntil.Format(schedule.DateLayout))
+		}
+		if len(state.SkipDates) > 0 {
+			fmt.Println("Skipped dates:", strings.Join(state.SkipDates, ", "))
+		}
+		if !state.LastRun.IsZero() {
+			fmt.Println("Last run:", state.LastRun.Format("2006-01-02 15:04"))
+		}
+	}
+	if plan, err := loadSchedulePlan(cfg, profile); err == nil {
+		if next, ok := nextActiveRun(plan, profile.Schedule, time.Now()); ok {
+			fmt.Println("Next run:", next.Format("Mon 2006-01-02 15:04"))
+		}
+	}
+	return nil
+}
+
+func runScheduleNext(args []string) error {
+	fs, profileName := parseScheduleFlags("schedule next")
+	count := fs.Int("count", 5, "Number of upcoming runs to list")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, _, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	plan, err := loadSchedulePlan(cfg, profile)
+	if err != nil {
+		return err
+	}
+	cal, holidayAware := holidayCalendar(cfg, profile)
+	for _, run := range plan.Upcoming(time.Now(), *count) {
+		line := run.Format("Mon 2006-01-02 15:04")
+		if held, reason := schedule.Held(profile.Schedule, run); held {
+			line += "  (" + reason + ")"
+		} else if h, ok := cal.On(run); ok && holidayAware {
+			line += "  (holiday: " + h.Name + ")"
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runScheduleSkip(args []string) error {
+	fs, profileName := parseScheduleFlags("schedule skip")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("date required: schedule skip <YYYY-MM-DD|next>")
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, profilePath, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	date := fs.Arg(0)
+	if date == "next" {
+		plan, err := loadSchedulePlan(cfg, profile)
+		if err != nil {
+			return err
+		}
+		next, ok := nextActiveRun(plan, profile.Schedule, time.Now())
+		if !ok {
+			return errors.New("no upcoming scheduled run to skip")
+		}
+		date = next.Format(schedule.DateLayout)
+	} else if _, err := time.ParseInLocation(schedule.DateLayout, date, time.Local); err != nil {
+		return fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", date)
+	}
+	if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+		if p.Schedule == nil {
+			p.Schedule = &store.ScheduleState{}
+		}
+		schedule.PruneSkips(p.Schedule, time.Now())
+		schedule.AddSkip(p.Schedule, date)
+		return nil
+	}); err != nil {
+		return err
+	}
+	fmt.Println("Scheduled order skipped for:", date)
+	return nil
+}
+
+func runScheduleUnskip(args []string) error {
+	fs, profileName := parseScheduleFlags("schedule unskip")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("date required: schedule unskip <YYYY-MM-DD>")
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	_, profilePath, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+		if p.Schedule == nil || !schedule.RemoveSkip(p.Schedule, fs.Arg(0)) {
+			return fmt.Errorf("no skip recorded for %s", fs.Arg(0))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	fmt.Println("Skip removed for:", fs.Arg(0))
+	return nil
+}
+
+func runSchedulePause(args []string) error {
+	fs, profileName := parseScheduleFlags("schedule pause")
+	until := fs.String("until", "", "Resume scheduled orders on this date (YYYY-MM-DD)")
+	days := fs.Int("days", 0, "Pause scheduled orders for this many days")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	var resumeAt time.Time
+	switch {
+	case *until != "" && *days > 0:
+		return errors.New("use either --until or --days, not both")
+	case *until != "":
+		t, err := time.ParseInLocation(schedule.DateLayout, *until, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", *until)
+		}
+		resumeAt = t
+	case *days > 0:
+		now := time.Now()
+		resumeAt = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, *days)
+	default:
+		return errors.New("pause requires --until YYYY-MM-DD or --days N")
+	}
+	if !resumeAt.After(time.Now()) {
+		return errors.New("pause end must be in the future")
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	_, profilePath, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+		if p.Schedule == nil {
+			p.Schedule = &store.ScheduleState{}
+		}
+		p.Schedule.PausedUntil = resumeAt
+		return nil
+	}); err != nil {
+		return err
+	}
+	fmt.Println("Scheduled orders paused until:", resumeAt.Format(schedule.DateLayout))
+	return nil
+}
+
+func runScheduleResume(args []string) error {
+	fs, profileName := parseScheduleFlags("schedule resume")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, profilePath, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	if profile.Schedule == nil || profile.Schedule.PausedUntil.IsZero() {
+		fmt.Println("Scheduled orders are not paused.")
+		return nil
+	}
+	if _, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+		if p.Schedule != nil {
+			p.Schedule.PausedUntil = time.Time{}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	fmt.Println("Scheduled orders resumed.")
+	return nil
+}
+
+func runScheduleRun(args []string) error {
+	fs, profileName := parseScheduleFlags("schedule run")
+	once := fs.Bool("once", false, "Place the order if a run is due now, then exit (for cron)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	summary.begin("schedule run")
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	name := resolveProfileName(*profileName, cfg)
+	summary.setProfile(name)
+	profile, _, err := loadOrCreateProfile(name)
+	if err != nil {
+		return err
+	}
+	plan, err := loadSchedulePlan(cfg, profile)
+	if err != nil {
+		return err
+	}
+	cal, holidayAware := holidayCalendar(cfg, profile)
+
+	if *once {
+		defer maybeSendDigest(cfg, name, time.Now())
+		var lastRun time.Time
+		if profile.Schedule != nil {
+			lastRun = profile.Schedule.LastRun
+		}
+		due, ok := plan.Due(time.Now(), lastRun)
+		if !ok {
+			fmt.Println("No scheduled run due.")
+			return nil
+		}
+		return executeScheduledRun(cfg, name, due)
+	}
+
+	lc, end := startLifecycle()
+	defer end()
+	ctx := lc.Context()
+
+	fmt.Printf("Scheduler started for profile '%s' (%s at %s, %s)\n", name, cfg.Defaults.Schedule, cfg.Defaults.ScheduleTime, time.Local)
+	for {
+		maybeSendDigest(cfg, name, time.Now())
+		next := plan.Next(time.Now())
+		if next.IsZero() {
+			return errors.New("schedule has no upcoming runs")
+		}
+		fmt.Println("Next run:", next.Format("Mon 2006-01-02 15:04"))
+		if holidayAware {
+			warnHolidayRun(cfg, cal, next)
+		}
+		for waiting := true; waiting; waiting = wallNow().Before(next) {
+			runQueuedJobs(ctx, name)
+			maybeRemindReorder(cfg, name)
+			select {
+			case <-ctx.Done():
+				fmt.Println("Scheduler stopped.")
+				return nil
+			case <-time.After(min(next.Sub(wallNow()), jobPoll)):
+			}
+		}
+		if err := executeScheduledRun(cfg, name, next); err != nil {
+			fmt.Fprintln(os.Stderr, "Scheduled order failed:", err)
+		}
+		if ctx.Err() != nil {
+			fmt.Println("Scheduler stopped.")
+			return nil
+		}
+	}
+}
+
+// wallNow is the current time without its monotonic reading. Timers and
+// monotonic time stop while a machine sleeps, so the scheduler wakes at least
+// every jobPoll and compares wall clocks; after a resume the run that came due
+// starts within a minute rather than hours late.
+func wallNow() time.Time {
+	return time.Now().Round(0)
+}
+
+// executeScheduledRun places the scheduled order for runAt unless the profile
+// has it skipped or paused. The profile is reloaded so skips recorded while the
+// scheduler was waiting are honored.
+func executeScheduledRun(cfg config.GlobalConfig, profileName string, runAt time.Time) error {
+	profile, profilePath, err := loadOrCreateProfile(profileName)
+	if err != nil {
+		return err
+	}
+	if held, reason := schedule.Held(profile.Schedule, runAt); held {
+		fmt.Printf("Scheduled run %s not placed (%s).\n", runAt.Format(schedule.DateLayout), reason)
+		sendNotification(cfg.Notifications, notify.Message{
+			Event: notify.OrderSkipped,
+			Title: "Bisleri scheduled order skipped",
+			Body:  fmt.Sprintf("%s: %s", runAt.Format(schedule.DateLayout), reason),
+			Error: reason,
+		})
+		return markScheduledRun(profilePath, runAt)
+	}
+	fmt.Println("Running scheduled order:", runAt.Format("Mon 2006-01-02 15:04"))
+	// A manual order in progress gets time to finish; the pending-order check
+	// then decides whether this run still orders.
+	orderArgs := []string{"--unattended", "--lock-wait", "10m"}
+	qty := cfg.Defaults.OrderQuantity
+	if cfg.Defaults.AdaptiveQuantity {
+		qty = scheduledQuantity(cfg, profileName, runAt)
+	}
+	if adjusted := heatAdjustedQuantity(cfg, profile, qty); cfg.Defaults.AdaptiveQuantity || adjusted != qty {
+		orderArgs = append(orderArgs, "--qty", strconv.Itoa(adjusted))
+	}
+	started := time.Now()
+	orderErr := runNewJob(jobs.PlaceOrder, profileName, orderArgs, "scheduler")
+	if orderErr != nil && lifecycle.Stopping() {
+		// Interrupted by shutdown; the run stays due so the next start
+		// places it (the pending-order check guards a half-finished one).
+		fmt.Printf("Scheduled run %s interrupted; it will run when the scheduler next starts.\n", runAt.Format(schedule.DateLayout))
+		return orderErr
+	}
+	if err := markScheduledRun(profilePath, runAt); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: failed to record scheduled run:", err)
+	}
+	var m notify.Message
+	var violation *policy.Violation
+	if errors.As(orderErr, &violation) {
+		m = notify.Message{Event: notify.OrderBlocked, Title: "Bisleri scheduled order blocked by policy", Body: violation.Error(), Error: violation.Error()}
+	} else if orderErr != nil {
+		m = notify.Message{Event: notify.OrderFailed, Title: "Bisleri scheduled order failed", Body: orderErr.Error(), Error: orderErr.Error()}
+	} else if updated, _, err := loadOrCreateProfile(profileName); err == nil && updated.LastOrder != nil && !updated.LastOrder.PlacedAt.Before(started) {
+		m = notify.Message{Event: notify.OrderPlaced, Title: "Bisleri order placed", Body: placedOrderSummary(*updated.LastOrder), Order: placedOrderNotice(*updated.LastOrder)}
+	}
+	if m.Event != "" {
+		sendNotification(cfg.Notifications, m)
+	}
+	return orderErr
+}
+
+// placedOrderSummary is a one-line description of a just-placed order for
+// notifications.
+func placedOrderSummary(o store.OrderInfo) string {
+	parts := []string{o.OrderID}
+	if o.TotalPrice != "" {
+		parts = append(parts, o.TotalPrice)
+	}
+	if o.DeliveryETA != "" {
+		parts = append(parts, "ETA "+o.DeliveryETA)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// scheduledQuantity applies adaptive sizing from synced history, falling back to
+// the configured default when history is unavailable.
+func scheduledQuantity(cfg config.GlobalConfig, profileName string, runAt time.Time) int {
+	history, err := store.LoadOrderHistory(profileName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: adaptive quantity unavailable (run 'bislericli sync'):", err)
+		return cfg.Defaults.OrderQuantity
+	}
+	qty, reason := schedule.AdaptiveQuantity(history.Orders, runAt, cfg.Defaults.OrderQuantity, cfg.Defaults.MinQuantity, cfg.Defaults.MaxQuantity)
+	fmt.Printf("Adaptive quantity: %d (%s)\n", qty, reason)
+	return qty
+}
+
+func markScheduledRun(profilePath string, runAt time.Time) error {
+	_, err := store.UpdateProfile(profilePath, func(p *store.Profile) error {
+		if p.Schedule == nil {
+			p.Schedule = &store.ScheduleState{}
+		}
+		p.Schedule.LastRun = runAt
+		schedule.PruneSkips(p.Schedule, runAt)
+		return nil
+	})
+	return err
+}
+
+func nextActiveRun(plan schedule.Plan, state *store.ScheduleState, from time.Time) (time.Time, bool) {
+	cursor := from
+	for i := 0; i < 366; i++ {
+		next := plan.Next(cursor)
+		if next.IsZero() {
+			return time.Time{}, false
+		}
+		if held, _ := schedule.Held(state, next); !held {
+			return next, true
+		}
+		cursor = next
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"bislericli/internal/apitoken"
+	"bislericli/internal/config"
+	"bislericli/internal/jobs"
+	"bislericli/internal/money"
+	"bislericli/internal/store"
+	"bislericli/internal/webhook"
+	"bislericli/internal/webui"
+)
+
+// Serve defaults, used when the config's serve section leaves them unset.
+const (
+	defaultServeListen       = "127.0.0.1:8787"
+	defaultWebhookMaxAge     = 5 * time.Minute
+	defaultWebhookDailyLimit = 2
+	// minWebhookSecret keeps secrets long enough that they cannot be guessed.
+	minWebhookSecret = 16
+	maxWebhookBody   = 16 << 10
+)
+
+func runServe(args []string) error {
+	if len(args) > 0 && args[0] == "token" {
+		return runServeToken(args[1:])
+	}
+	fs, profileName := parseScheduleFlags("serve")
+	listen := fs.String("listen", "", "Address to listen on (default: serve.listen from config, then "+defaultServeListen+")")
+	ui := fs.Bool("ui", false, "Also serve the web dashboard at /")
+	fs.Usage = func() {
+		fmt.Println("Usage: bislericli serve [--listen ADDR] [--profile NAME] [--ui]")
+		fmt.Println("       bislericli serve token <create|list|revoke>")
+		fmt.Println("\nAccepts signed order webhooks on POST /webhook/order and API requests with a")
+		fmt.Println("token from 'serve token create', and runs orders as jobs (see 'bislericli jobs")
+		fmt.Println("list') along with jobs queued with 'jobs add'. Webhook senders are configured in")
+		fmt.Println("serve.webhookSecrets; see the README for the signature. --ui adds a dashboard")
+		fmt.Println("for people who do not use a terminal; it signs in with an API token.")
+		fmt.Println()
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	summary.begin("serve")
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	if len(cfg.Serve.WebhookSecrets) == 0 && len(cfg.Serve.Tokens) == 0 {
+		return errors.New("nothing to serve: add webhook senders to serve.webhookSecrets in the config (source name → secret) or create an API token with 'bislericli serve token create'")
+	}
+	for source, secret := range cfg.Serve.WebhookSecrets {
+		if len(secret) < minWebhookSecret {
+			return fmt.Errorf("serve.webhookSecrets: secret for %q is shorter than %d characters", source, minWebhookSecret)
+		}
+	}
+	if *ui && len(cfg.Serve.Tokens) == 0 {
+		return errors.New("the dashboard signs in with an API token; create one with 'bislericli serve token create --name home --scopes read,order'")
+	}
+	addr := *listen
+	if addr == "" {
+		addr = cfg.Serve.Listen
+	}
+	if addr == "" {
+		addr = defaultServeListen
+	}
+	q, err := jobs.Open()
+	if err != nil {
+		return err
+	}
+	srv := newServer(cfg, resolveProfileName(*profileName, cfg), q)
+	srv.ui = *ui
+	summary.setProfile(srv.profile)
+
+	lc, end := startLifecycle()
+	defer end()
+	ctx := lc.Context()
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	httpSrv := &http.Server{Handler: srv.routes(), ReadHeaderTimeout: 10 * time.Second}
+	served := make(chan error, 1)
+	go func() { served <- httpSrv.Serve(ln) }()
+	if srv.ui {
+		fmt.Printf("Dashboard: http://%s/\n", ln.Addr())
+	}
+	fmt.Printf("Serving on http://%s for profile '%s' (%d webhook sender(s), %d API token(s), %d order(s) per sender or token per day)\n", ln.Addr(), srv.profile, len(cfg.Serve.WebhookSecrets), len(cfg.Serve.Tokens), srv.dailyLimit)
+
+	for {
+		runQueuedJobs(ctx, srv.profile)
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			_ = httpSrv.Shutdown(shutdownCtx)
+			fmt.Println("Server stopped.")
+			return nil
+		case err := <-served:
+			return err
+		case <-srv.wake:
+		case <-time.After(jobPoll):
+		}
+	}
+}
+
+// server handles serve mode's HTTP requests. Accepted orders become queued
+// jobs; the serve loop runs them.
+type server struct {
+	cfg         config.GlobalConfig
+	profile     string
+	queue       *jobs.Queue
+	verifier    *webhook.Verifier
+	tokens      map[string]config.APIToken
+	dailyLimit  int
+	maxQuantity int
+	wake        chan struct{}
+	ui          bool
+}
+
+func newServer(cfg config.GlobalConfig, profileName string, q *jobs.Queue) *server {
+	maxAge := defaultWebhookMaxAge
+	if cfg.Serve.WebhookMaxAge > 0 {
+		maxAge = time.Duration(cfg.Serve.WebhookMaxAge) * time.Second
+	}
+	limit := defaultWebhookDailyLimit
+	if cfg.Serve.WebhookDailyLimit > 0 {
+		limit = cfg.Serve.WebhookDailyLimit
+	}
+	maxQuantity := cfg.Serve.MaxQuantity
+	if maxQuantity == 0 && cfg.Defaults.AdaptiveQuantity {
+		maxQuantity = cfg.Defaults.MaxQuantity
+	}
+	return &server{
+		cfg:         cfg,
+		profile:     profileName,
+		queue:       q,
+		verifier:    webhook.NewVerifier(cfg.Serve.WebhookSecrets, maxAge, limit, 24*time.Hour),
+		tokens:      cfg.Serve.Tokens,
+		dailyLimit:  limit,
+		maxQuantity: maxQuantity,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("POST /webhook/order", s.handleOrderWebhook)
+	mux.HandleFunc("POST /api/order", s.authorize(apitoken.ScopeOrder, s.handleAPIOrder))
+	mux.HandleFunc("GET /api/status", s.authorize(apitoken.ScopeRead, s.handleStatus))
+	mux.HandleFunc("GET /api/jobs", s.authorize(apitoken.ScopeRead, s.handleJobs))
+	mux.HandleFunc("GET /api/jobs/{id}", s.authorize(apitoken.ScopeRead, s.handleJob))
+	if s.ui {
+		mux.Handle("GET /", webui.Handler())
+	}
+	return mux
+}
+
+type tokenKey struct{}
+
+// authorize admits requests whose bearer token holds scope, passing the
+// token's name on in the request context.
+func (s *server) authorize(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		name, token, known := apitoken.Match(s.tokens, strings.TrimSpace(raw))
+		if !ok || !known {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("missing or unknown API token"))
+			return
+		}
+		if !apitoken.HasScope(token, scope) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("token %q lacks the %s scope", name, scope))
+			return
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), tokenKey{}, name)))
+	}
+}
+
+// statusResponse is the body of GET /api/status.
+type statusResponse struct {
+	Profile       string         `json:"profile"`
+	Wallet        *walletStatus  `json:"wallet"`
+	JarsRemaining *float64       `json:"jarsRemaining"`
+	LastOrder     string         `json:"lastOrder"`
+	NextRun       string         `json:"nextRun"`
+	OrderQuantity int            `json:"orderQuantity"`
+	CanOrder      bool           `json:"canOrder"`
+	History       []monthlySpend `json:"history"`
+}
+
+type walletStatus struct {
+	Balance string    `json:"balance"`
+	AsOf    time.Time `json:"asOf"`
+}
+
+type monthlySpend struct {
+	Month  string      `json:"month"`
+	Orders int         `json:"orders"`
+	Total  money.Money `json:"total"`
+}
+
+func (s *server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	profile, _, err := loadOrCreateProfile(s.profile)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	resp := statusResponse{
+		Profile:       s.profile,
+		LastOrder:     lastOrderSummary(s.profile, profile),
+		OrderQuantity: s.cfg.Defaults.OrderQuantity,
+		CanOrder:      apitoken.HasScope(s.tokens[r.Context().Value(tokenKey{}).(string)], apitoken.ScopeOrder),
+		History:       []monthlySpend{},
+	}
+	if balance, at, ok := lastWalletBalance(s.profile); ok {
+		resp.Wallet = &walletStatus{Balance: balance, AsOf: at}
+	}
+	if est, ok := estimateJars(s.cfg, s.profile, time.Now()); ok {
+		jars := math.Round(est.Jars*10) / 10
+		resp.JarsRemaining = &jars
+	}
+	if resp.NextRun = nextRunSummary(s.cfg, profile); resp.NextRun == "none" {
+		resp.NextRun = ""
+	}
+	if history, err := store.LoadOrderHistory(s.profile); err == nil {
+		periods := periodTotals(history.Orders, "month")
+		if len(periods) > 12 {
+			periods = periods[len(periods)-12:]
+		}
+		for _, p := range periods {
+			resp.History = append(resp.History, monthlySpend{Month: p.Label, Orders: p.Count, Total: p.Total})
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// orderRequest is the body of POST /webhook/order and POST /api/order.
+// Omitted fields take the order command's defaults.
+type orderRequest struct {
+	Qty      int    `json:"qty,omitempty"`
+	Return   *int   `json:"return,omitempty"`
+	Timeslot string `json:"timeslot,omitempty"`
+}
+
+func (s *server) handleOrderWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+	source, err := s.verifier.Verify(r.Header, body)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Rejected webhook from %s: %v\n", r.RemoteAddr, err)
+		status := http.StatusUnauthorized
+		if errors.Is(err, webhook.ErrReplayed) {
+			status = http.StatusConflict
+		}
+		writeError(w, status, err)
+		return
+	}
+	s.queueOrder(w, body, "webhook:"+source)
+}
+
+func (s *server) handleAPIOrder(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+	s.queueOrder(w, body, "token:"+r.Context().Value(tokenKey{}).(string))
+}
+
+// queueOrder validates an order request from an authenticated source and,
+// within the source's daily limit, queues it as a job.
+func (s *server) queueOrder(w http.ResponseWriter, body []byte, source string) {
+	var req orderRequest
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if len(body) > 0 {
+		if err := dec.Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid body: %w", err))
+			return
+		}
+	}
+	job, status, err := s.enqueueOrder(req, source)
+	if err != nil {
+		writeError(w, status, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]string{"job": job.ID, "status": string(job.Status)})
+}
+
+// enqueueOrder queues an order from an authenticated source within the
+// source's daily limit and wakes the loop that runs jobs. On failure it
+// returns the HTTP status that fits the error.
+func (s *server) enqueueOrder(req orderRequest, source string) (jobs.Job, int, error) {
+	args, err := s.orderArgs(req)
+	if err != nil {
+		return jobs.Job{}, http.StatusBadRequest, err
+	}
+	if err := s.verifier.Allow(source); err != nil {
+		fmt.Fprintf(os.Stderr, "Rejected order from %s: %v\n", source, err)
+		return jobs.Job{}, http.StatusTooManyRequests, err
+	}
+	job, err := s.queue.Enqueue(jobs.PlaceOrder, s.profile, args, source)
+	if err != nil {
+		return jobs.Job{}, http.StatusInternalServerError, err
+	}
+	fmt.Printf("Queued job %s from %s\n", job.ID, source)
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return job, http.StatusAccepted, nil
+}
+
+func (s *server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	all, err := s.queue.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	limit := 20
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", raw))
+			return
+		}
+	}
+	shown := []jobs.Job{}
+	for _, j := range all {
+		if j.Profile == s.profile && (limit == 0 || len(shown) < limit) {
+			shown = append(shown, j)
+		}
+	}
+	writeJSON(w, http.StatusOK, shown)
+}
+
+func (s *server) handleJob(w http.ResponseWriter, r *http.Request) {
+	job, err := s.queue.Get(r.PathValue("id"))
+	if errors.Is(err, jobs.ErrNotFound) || err == nil && job.Profile != s.profile {
+		writeError(w, http.StatusNotFound, jobs.ErrNotFound)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// orderArgs turns an order request into order flags. These orders always run
+// unattended, so the config's policy guardrails apply to them.
+func (s *server) orderArgs(req orderRequest) ([]string, error) {
+	args := []string{"--unattended", "--lock-wait", "10m"}
+	if req.Qty < 0 {
+		return nil, errors.New("qty must be positive")
+	}
+	if s.maxQuantity > 0 && req.Qty > s.maxQuantity {
+		return nil, fmt.Errorf("qty exceeds maxQuantity (%d)", s.maxQuantity)
+	}
+	if req.Qty > 0 {
+		args = append(args, "--qty", strconv.Itoa(req.Qty))
+	}
+	if req.Return != nil {
+		if *req.Return < 0 || req.Qty > 0 && *req.Return > req.Qty {
+			return nil, errors.New("return must be between 0 and qty")
+		}
+		args = append(args, "--return", strconv.Itoa(*req.Return))
+	}
+	if req.Timeslot != "" {
+		if len(req.Timeslot) > 64 {
+			return nil, errors.New("timeslot is too long")
+		}
+		args = append(args, "--timeslot", req.Timeslot)
+	}
+	return args, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
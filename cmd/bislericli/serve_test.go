@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"bislericli/internal/apitoken"
+	"bislericli/internal/config"
+	"bislericli/internal/jobs"
+	"bislericli/internal/webhook"
+)
+
+func TestOrderWebhook(t *testing.T) {
+	cfg := config.GlobalConfig{
+		Serve: config.Serve{WebhookSecrets: map[string]string{"ha": "0123456789abcdef"}, WebhookDailyLimit: 1, MaxQuantity: 4},
+	}
+	q := jobs.OpenAt(filepath.Join(t.TempDir(), "jobs.json"))
+	h := newServer(cfg, "home", q).routes()
+
+	post := func(body string, ts time.Time) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhook/order", strings.NewReader(body))
+		req.Header.Set(webhook.HeaderSource, "ha")
+		req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(ts.Unix(), 10))
+		req.Header.Set(webhook.HeaderSignature, webhook.Sign("0123456789abcdef", ts, []byte(body)))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	now := time.Now()
+	if code := post(`{"qty":9}`, now); code != http.StatusBadRequest {
+		t.Errorf("qty above maxQuantity: status %d, want 400", code)
+	}
+	if code := post(`{"qty":2,"timeslot":"evening"}`, now); code != http.StatusAccepted {
+		t.Fatalf("valid webhook: status %d, want 202", code)
+	}
+	if code := post(`{"qty":2,"timeslot":"evening"}`, now); code != http.StatusConflict {
+		t.Errorf("replayed webhook: status %d, want 409", code)
+	}
+	if code := post(`{"qty":1}`, now.Add(time.Second)); code != http.StatusTooManyRequests {
+		t.Errorf("over the daily limit: status %d, want 429", code)
+	}
+
+	all, err := q.List()
+	if err != nil || len(all) != 1 {
+		t.Fatalf("queue = %+v, %v; want one job", all, err)
+	}
+	want := "--unattended --lock-wait 10m --qty 2 --timeslot evening"
+	if job := all[0]; job.Kind != jobs.PlaceOrder || job.Profile != "home" || job.Source != "webhook:ha" || strings.Join(job.Args, " ") != want {
+		t.Errorf("job = %+v", job)
+	}
+}
+
+func TestAPITokenScopes(t *testing.T) {
+	readToken, readHash, _ := apitoken.New()
+	orderToken, orderHash, _ := apitoken.New()
+	cfg := config.GlobalConfig{Serve: config.Serve{Tokens: map[string]config.APIToken{
+		"dashboard":  {Hash: readHash, Scopes: []string{apitoken.ScopeRead}},
+		"automation": {Hash: orderHash, Scopes: []string{apitoken.ScopeOrder}},
+	}}}
+	q := jobs.OpenAt(filepath.Join(t.TempDir(), "jobs.json"))
+	h := newServer(cfg, "home", q).routes()
+
+	do := func(method, path, token, body string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	for _, tc := range []struct {
+		method, path, token string
+		want                int
+	}{
+		{"GET", "/api/jobs", "", http.StatusUnauthorized},
+		{"GET", "/api/jobs", "bsl_not-a-token", http.StatusUnauthorized},
+		{"GET", "/api/jobs", readToken, http.StatusOK},
+		{"GET", "/api/jobs", orderToken, http.StatusForbidden},
+		{"POST", "/api/order", readToken, http.StatusForbidden},
+		{"POST", "/api/order", orderToken, http.StatusAccepted},
+		{"GET", "/api/jobs/1", readToken, http.StatusOK},
+		{"GET", "/api/jobs/2", readToken, http.StatusNotFound},
+	} {
+		if code := do(tc.method, tc.path, tc.token, `{"qty":1}`); code != tc.want {
+			t.Errorf("%s %s with %.8s: status %d, want %d", tc.method, tc.path, tc.token, code, tc.want)
+		}
+	}
+	all, _ := q.List()
+	if len(all) != 1 || all[0].Source != "token:automation" {
+		t.Errorf("queue = %+v, want one job from token:automation", all)
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	readToken, readHash, _ := apitoken.New()
+	cfg := config.GlobalConfig{
+		Defaults: config.Defaults{OrderQuantity: 2},
+		Serve:    config.Serve{Tokens: map[string]config.APIToken{"dashboard": {Hash: readHash, Scopes: []string{apitoken.ScopeRead}}}},
+	}
+	srv := newServer(cfg, "home", jobs.OpenAt(filepath.Join(t.TempDir(), "jobs.json")))
+	srv.ui = true
+	h := srv.routes()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Order now") {
+		t.Fatalf("GET / = %d %.80q", rec.Code, rec.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/status", nil)
+	req.Header.Set("Authorization", "Bearer "+readToken)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var status statusResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/status = %d %s (%v)", rec.Code, rec.Body, err)
+	}
+	if status.Profile != "home" || status.OrderQuantity != 2 || status.CanOrder || status.Wallet != nil {
+		t.Errorf("status = %+v", status)
+	}
+}
+
+func TestRemoteOrderQuantityCap(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		defaults config.Defaults
+		serve    config.Serve
+		want     int
+	}{
+		{"no cap without adaptive quantity", config.Defaults{MaxQuantity: 4}, config.Serve{}, 0},
+		{"adaptive maximum", config.Defaults{MaxQuantity: 4, AdaptiveQuantity: true}, config.Serve{}, 4},
+		{"serve setting wins", config.Defaults{MaxQuantity: 4, AdaptiveQuantity: true}, config.Serve{MaxQuantity: 10}, 10},
+	} {
+		s := newServer(config.GlobalConfig{Defaults: tt.defaults, Serve: tt.serve}, "home", nil)
+		if s.maxQuantity != tt.want {
+			t.Errorf("%s: maxQuantity = %d, want %d", tt.name, s.maxQuantity, tt.want)
+		}
+		if _, err := s.orderArgs(orderRequest{Qty: 6}); (err != nil) != (tt.want > 0 && tt.want < 6) {
+			t.Errorf("%s: orderArgs(qty 6) = %v", tt.name, err)
+		}
+	}
+}
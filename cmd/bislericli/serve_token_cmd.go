@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"bislericli/internal/apitoken"
+	"bislericli/internal/config"
+)
+
+func runServeToken(args []string) error {
+	if len(args) < 1 || isHelpToken(args[0]) {
+		printServeTokenUsage()
+		return nil
+	}
+	switch args[0] {
+	case "create":
+		return runServeTokenCreate(args[1:])
+	case "list":
+		return runServeTokenList()
+	case "revoke":
+		return runServeTokenRevoke(args[1:])
+	default:
+		fmt.Printf("Unknown serve token subcommand: %s\n", args[0])
+		printServeTokenUsage()
+		return nil
+	}
+}
+
+func printServeTokenUsage() {
+	fmt.Println("Usage: bislericli serve token <subcommand> [flags]")
+	fmt.Println("\nAvailable subcommands:")
+	fmt.Println("  create --name NAME --scopes read,order  Create an API token; it is shown only once")
+	fmt.Println("  list                                    Show token names, scopes and creation dates")
+	fmt.Println("  revoke <name>                           Delete a token")
+	fmt.Println("\nScopes: read (status, history and jobs) and order (place orders). Restart")
+	fmt.Println("'bislericli serve' for token changes to take effect.")
+}
+
+func runServeTokenCreate(args []string) error {
+	fs := flag.NewFlagSet("serve token create", flag.ContinueOnError)
+	name := fs.String("name", "", "Name for the token, e.g. dashboard")
+	scopes := fs.String("scopes", apitoken.ScopeRead, "Comma-separated scopes: read, order")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if strings.TrimSpace(*name) == "" {
+		return errors.New("usage: bislericli serve token create --name NAME [--scopes read,order]")
+	}
+	parsed, err := apitoken.ParseScopes(*scopes)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	if _, exists := cfg.Serve.Tokens[*name]; exists {
+		return fmt.Errorf("a token named %q already exists; revoke it first", *name)
+	}
+	token, hash, err := apitoken.New()
+	if err != nil {
+		return err
+	}
+	if cfg.Serve.Tokens == nil {
+		cfg.Serve.Tokens = map[string]config.APIToken{}
+	}
+	cfg.Serve.Tokens[*name] = config.APIToken{Hash: hash, Scopes: parsed, Created: time.Now()}
+	if err := config.SaveGlobalConfig(cfg); err != nil {
+		return err
+	}
+	fmt.Printf("Created token '%s' (%s). Copy it now; it is not shown again:\n\n", *name, strings.Join(parsed, ", "))
+	fmt.Println(token)
+	fmt.Println("\nSend it as 'Authorization: Bearer <token>'.")
+	return nil
+}
+
+func runServeTokenList() error {
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	if len(cfg.Serve.Tokens) == 0 {
+		fmt.Println("No API tokens. Create one with 'bislericli serve token create'.")
+		return nil
+	}
+	names := make([]string, 0, len(cfg.Serve.Tokens))
+	for name := range cfg.Serve.Tokens {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSCOPES\tCREATED")
+	for _, name := range names {
+		t := cfg.Serve.Tokens[name]
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, strings.Join(t.Scopes, ","), t.Created.Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
+}
+
+func runServeTokenRevoke(args []string) error {
+	if len(args) != 1 || isHelpToken(args[0]) {
+		return errors.New("usage: bislericli serve token revoke <name>")
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Serve.Tokens[args[0]]; !ok {
+		return fmt.Errorf("no token named %q", args[0])
+	}
+	delete(cfg.Serve.Tokens, args[0])
+	if err := config.SaveGlobalConfig(cfg); err != nil {
+		return err
+	}
+	fmt.Printf("Revoked token '%s'.\n", args[0])
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/config"
+	"bislericli/internal/store"
+)
+
+// session is the client state shared by the steps of one command: the
+// profile's saved cookies loaded into a client, whether the login has been
+// confirmed, and the delivery city the site has selected. Each is settled
+// once, so later steps skip the round trips that would establish it again.
+type session struct {
+	*bisleri.Client
+	profile     *store.Profile
+	profilePath string
+
+	verified   bool
+	ordersHTML string
+	city       string
+}
+
+func newSession(cfg config.GlobalConfig, profile *store.Profile, profilePath string, timeout time.Duration) (*session, error) {
+	jar, err := bisleri.JarFromCookies(profile.Cookies)
+	if err != nil {
+		return nil, err
+	}
+	logger := log.New(profileRedactor(cfg, *profile).Writer(os.Stderr), "bisleri: ", log.LstdFlags)
+	return &session{
+		Client:      bisleri.NewClient(&http.Client{Jar: jar, Timeout: timeout}, logger),
+		profile:     profile,
+		profilePath: profilePath,
+	}, nil
+}
+
+// verify confirms the login once per session. It loads /my-orders, which
+// the pending-order check reads next, so the page is kept for orders.
+func (s *session) verify(ctx context.Context) error {
+	if s.verified {
+		return nil
+	}
+	_, err := s.ordersPage(ctx)
+	return err
+}
+
+func (s *session) ordersPage(ctx context.Context) (string, error) {
+	if s.ordersHTML != "" {
+		return s.ordersHTML, nil
+	}
+	body, resp, err := s.FetchPage(ctx, "/my-orders")
+	if err != nil {
+		return "", err
+	}
+	if resp.Request != nil && resp.Request.URL != nil && !strings.HasPrefix(resp.Request.URL.Path, "/my-orders") {
+		return "", sessionExpiredError{}
+	}
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("auth check failed: %s", resp.Status)
+	}
+	if body == "" {
+		return "", errors.New("auth check failed: empty response")
+	}
+	s.verified = true
+	s.ordersHTML = body
+	return body, nil
+}
+
+// orders parses the order list from the page loaded by verify.
+func (s *session) orders(ctx context.Context) ([]store.SavedOrder, error) {
+	page, err := s.ordersPage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch orders: %w", err)
+	}
+	orders, _, err := parseSavedOrders(page)
+	return orders, err
+}
+
+// cart fetches and parses the cart page, noting the city it shows as
+// selected. A cart page only loads for a logged-in account, so it also
+// counts as verification.
+func (s *session) cart(ctx context.Context) (bisleri.CartPage, error) {
+	cartHTML, err := s.FetchCartPage(ctx)
+	if err != nil {
+		return bisleri.CartPage{}, err
+	}
+	s.verified = true
+	page := bisleri.ParseCartPage(cartHTML)
+	if page.Unparsed() {
+		noteParseFailure("cart items", "cart", cartHTML)
+	}
+	if page.SelectedCity != "" {
+		s.city = page.SelectedCity
+	}
+	return page, nil
+}
+
+// ensureCity selects the profile's delivery city when the cart page shows
+// none and returns the refreshed cart. The choice is saved to the profile.
+func (s *session) ensureCity(ctx context.Context, cart bisleri.CartPage) (bisleri.CartPage, error) {
+	if s.city != "" {
+		return cart, nil
+	}
+	city := resolveCity(*s.profile, cart.CityOptions)
+	if city == "" {
+		return cart, nil
+	}
+	progressln("Setting delivery city:", city)
+	if err := s.SetCityLocation(ctx, city); err != nil {
+		return cart, err
+	}
+	s.city = city
+	s.profile.PreferredCity = city
+	if _, err := store.UpdateProfile(s.profilePath, func(p *store.Profile) error {
+		p.PreferredCity = city
+		return nil
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: failed to save preferred city:", err)
+	}
+	refreshed, err := s.cart(ctx)
+	if err != nil {
+		return cart, err
+	}
+	return refreshed, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/store"
+)
+
+func TestSessionReusesVerifiedState(t *testing.T) {
+	var mu sync.Mutex
+	hits := make(map[string]int)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/my-orders":
+			w.Write([]byte(`<div class="all-order"><div class="order-section">Order BS-1001</div><div>Total Price <span>₹200</span></div></div>`))
+		case "/mycart":
+			w.Write([]byte(`<html>cart</html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	profile := store.Profile{Name: "test"}
+	sess, err := newSession(config.GlobalConfig{}, &profile, "", 5*time.Second)
+	if err != nil {
+		t.Fatalf("newSession: %v", err)
+	}
+	sess.BaseURL = srv.URL
+	sess.Throttle = 0
+	ctx := context.Background()
+
+	if err := sess.verify(ctx); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if err := sess.verify(ctx); err != nil {
+		t.Fatalf("second verify: %v", err)
+	}
+	orders, err := sess.orders(ctx)
+	if err != nil {
+		t.Fatalf("orders: %v", err)
+	}
+	if len(orders) != 1 || orders[0].OrderID != "BS-1001" {
+		t.Fatalf("unexpected orders: %+v", orders)
+	}
+	if hits["/my-orders"] != 1 {
+		t.Fatalf("expected one /my-orders request, got %d", hits["/my-orders"])
+	}
+
+	// A fresh session that loads the cart first needs no separate check.
+	sess, _ = newSession(config.GlobalConfig{}, &profile, "", 5*time.Second)
+	sess.BaseURL = srv.URL
+	sess.Throttle = 0
+	if _, err := sess.cart(ctx); err != nil {
+		t.Fatalf("cart: %v", err)
+	}
+	if err := sess.verify(ctx); err != nil {
+		t.Fatalf("verify after cart: %v", err)
+	}
+	if hits["/my-orders"] != 1 {
+		t.Fatalf("verify after cart fetched /my-orders again (%d requests)", hits["/my-orders"])
+	}
+}
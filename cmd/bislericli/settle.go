@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/money"
+	"bislericli/internal/store"
+)
+
+type memberShare struct {
+	Member string
+	Amount money.Money
+}
+
+func runStatsSplit(args []string) error {
+	fs, profileName := parseScheduleFlags("stats split")
+	members := fs.String("members", "", "Split equally: a count (\"3\") or names (\"asha,ravi,meera\")")
+	weightSpec := fs.String("weights", "", "Split by weight, e.g. \"home=2,upstairs=1\"")
+	byTag := fs.Bool("by-tag", false, "Charge tagged orders (order --for) to their tag and split the rest")
+	month := fs.String("month", "", "Month to settle as YYYY-MM (default: current month)")
+	paidBy := fs.String("paid-by", "", "Member who paid the bill (default: the first member)")
+	csvOut := fs.Bool("csv", false, "Write the report as CSV")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	names, weights, err := splitMembers(*members, *weightSpec)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 && !*byTag {
+		return errors.New("give --members, --weights or --by-tag")
+	}
+	period := time.Now()
+	if *month != "" {
+		if period, err = time.ParseInLocation("2006-01", *month, time.Local); err != nil {
+			return fmt.Errorf("invalid --month %q (want YYYY-MM)", *month)
+		}
+	}
+
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	name := resolveProfileName(*profileName, cfg)
+	profile, _, err := loadOrCreateProfile(name)
+	if err != nil {
+		return err
+	}
+	history, err := store.LoadOrderHistory(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.New("no synced data found; run 'bislericli sync' first")
+		}
+		return fmt.Errorf("failed to load history: %w", err)
+	}
+	orders := ordersInMonth(withUnsyncedOrder(history.Orders, profile.LastOrder), period)
+
+	var tags map[string]string
+	if *byTag {
+		entries, err := store.LoadAuditLog(name)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to load audit log: %w", err)
+		}
+		tags = orderTags(entries, profile.LastOrder)
+	}
+	shares, total := settleShares(orders, tags, names, weights)
+	if len(shares) == 0 {
+		return errors.New("no members to split between; tag orders with 'order --for' or pass --members")
+	}
+	payer := *paidBy
+	if payer == "" {
+		payer = shares[0].Member
+	}
+
+	if *csvOut {
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"month", "member", "share", "owes_to"})
+		for _, s := range shares {
+			owes := payer
+			if strings.EqualFold(s.Member, payer) {
+				owes = ""
+			}
+			w.Write([]string{period.Format("2006-01"), s.Member, sheetAmount(s.Amount), owes})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	fmt.Printf("Water bill for %s: %d orders, %s\n\n", period.Format("Jan 2006"), len(orders), total)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "MEMBER\tSHARE")
+	for _, s := range shares {
+		fmt.Fprintf(w, "%s\t%s\n", s.Member, s.Amount)
+	}
+	w.Flush()
+	fmt.Println()
+	for _, s := range shares {
+		if strings.EqualFold(s.Member, payer) || s.Amount == 0 {
+			continue
+		}
+		fmt.Printf("%s owes %s %s\n", s.Member, payer, s.Amount)
+	}
+	return nil
+}
+
+// splitMembers reads --members (a count or a name list) or --weights into
+// member names and integer weights.
+func splitMembers(members, weightSpec string) ([]string, []int, error) {
+	if members != "" && weightSpec != "" {
+		return nil, nil, errors.New("use either --members or --weights, not both")
+	}
+	var names []string
+	var weights []int
+	if n, err := strconv.Atoi(strings.TrimSpace(members)); err == nil {
+		if n < 1 {
+			return nil, nil, errors.New("--members must be at least 1")
+		}
+		for i := 1; i <= n; i++ {
+			names = append(names, fmt.Sprintf("member%d", i))
+			weights = append(weights, 1)
+		}
+		return names, weights, nil
+	}
+	for _, part := range strings.Split(members, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			names = append(names, part)
+			weights = append(weights, 1)
+		}
+	}
+	for _, part := range strings.Split(weightSpec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		member, value, ok := strings.Cut(part, "=")
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || strings.TrimSpace(member) == "" || err != nil || weight < 0 {
+			return nil, nil, fmt.Errorf("invalid weight %q (want name=number)", part)
+		}
+		names = append(names, strings.TrimSpace(member))
+		weights = append(weights, weight)
+	}
+	return names, weights, nil
+}
+
+func ordersInMonth(orders []store.SavedOrder, month time.Time) []store.SavedOrder {
+	var in []store.SavedOrder
+	for _, o := range orders {
+		if !o.ParsedDate.IsZero() && o.ParsedDate.Format("2006-01") == month.Format("2006-01") {
+			in = append(in, o)
+		}
+	}
+	return in
+}
+
+// settleShares works out each member's share of the orders. With tags, a
+// tagged order is charged to its tag (added as a member with weight 0 if not
+// listed) and only untagged orders are split; without members the tags seen
+// share the remainder equally.
+func settleShares(orders []store.SavedOrder, tags map[string]string, names []string, weights []int) ([]memberShare, money.Money) {
+	names = append([]string(nil), names...)
+	weights = append([]int(nil), weights...)
+	explicit := len(names) > 0
+	index := make(map[string]int)
+	for i, n := range names {
+		index[strings.ToLower(n)] = i
+	}
+	direct := make([]money.Money, len(names))
+	var total, pool money.Money
+	for _, o := range orders {
+		total += o.Amount
+		tag := tags[o.OrderID]
+		if tag == "" {
+			pool += o.Amount
+			continue
+		}
+		i, ok := index[strings.ToLower(tag)]
+		if !ok {
+			i = len(names)
+			index[strings.ToLower(tag)] = i
+			names = append(names, tag)
+			weights = append(weights, 0)
+			if !explicit {
+				weights[i] = 1
+			}
+			direct = append(direct, 0)
+		}
+		direct[i] += o.Amount
+	}
+	pooled := splitByWeights(pool, weights)
+	shares := make([]memberShare, len(names))
+	for i, n := range names {
+		shares[i] = memberShare{Member: n, Amount: direct[i] + pooled[i]}
+	}
+	if !explicit {
+		sort.SliceStable(shares, func(i, j int) bool { return shares[i].Member < shares[j].Member })
+	}
+	return shares, total
+}
+
+// splitByWeights divides amount in proportion to weights, handing leftover
+// paise to the earliest members so the parts add up exactly.
+func splitByWeights(amount money.Money, weights []int) []money.Money {
+	parts := make([]money.Money, len(weights))
+	sum := 0
+	for _, w := range weights {
+		sum += w
+	}
+	if sum == 0 {
+		return parts
+	}
+	var assigned money.Money
+	for i, w := range weights {
+		parts[i] = amount * money.Money(w) / money.Money(sum)
+		assigned += parts[i]
+	}
+	for i := 0; assigned < amount; i = (i + 1) % len(parts) {
+		if weights[i] > 0 {
+			parts[i]++
+			assigned++
+		}
+	}
+	return parts
+}
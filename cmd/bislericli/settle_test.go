@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+
+	"bislericli/internal/money"
+	"bislericli/internal/store"
+)
+
+func TestSplitByWeightsAddsUp(t *testing.T) {
+	parts := splitByWeights(money.Money(100000), []int{1, 1, 1})
+	if parts[0] != 33334 || parts[1] != 33333 || parts[2] != 33333 {
+		t.Fatalf("unexpected parts: %v", parts)
+	}
+	parts = splitByWeights(money.Money(90000), []int{2, 1, 0})
+	if parts[0] != 60000 || parts[1] != 30000 || parts[2] != 0 {
+		t.Fatalf("unexpected weighted parts: %v", parts)
+	}
+}
+
+func TestSplitMembers(t *testing.T) {
+	names, weights, err := splitMembers("3", "")
+	if err != nil || len(names) != 3 || names[2] != "member3" || weights[0] != 1 {
+		t.Fatalf("count: %v %v %v", names, weights, err)
+	}
+	names, weights, err = splitMembers("", "home=2, upstairs=1")
+	if err != nil || len(names) != 2 || names[1] != "upstairs" || weights[0] != 2 {
+		t.Fatalf("weights: %v %v %v", names, weights, err)
+	}
+	if _, _, err := splitMembers("", "home"); err == nil {
+		t.Fatal("expected an error for a weight without a value")
+	}
+	if _, _, err := splitMembers("2", "home=1"); err == nil {
+		t.Fatal("expected an error when both flags are given")
+	}
+}
+
+func TestSettleSharesByTag(t *testing.T) {
+	orders := []store.SavedOrder{
+		{OrderID: "BS-1", Amount: money.Money(40000)},
+		{OrderID: "BS-2", Amount: money.Money(20000)},
+		{OrderID: "BS-3", Amount: money.Money(10000)},
+	}
+	tags := map[string]string{"BS-1": "office", "BS-2": "home"}
+
+	shares, total := settleShares(orders, tags, nil, nil)
+	if total != 70000 || len(shares) != 2 {
+		t.Fatalf("unexpected result: %v %v", shares, total)
+	}
+	if shares[0].Member != "home" || shares[0].Amount != 25000 || shares[1].Amount != 45000 {
+		t.Fatalf("tags should share untagged spend equally: %+v", shares)
+	}
+
+	shares, _ = settleShares(orders, tags, []string{"home"}, []int{1})
+	if len(shares) != 2 || shares[0].Amount != 30000 || shares[1].Member != "office" || shares[1].Amount != 40000 {
+		t.Fatalf("unlisted tags should only pay their own orders: %+v", shares)
+	}
+}
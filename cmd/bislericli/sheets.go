@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/format"
+	"bislericli/internal/money"
+	"bislericli/internal/sheets"
+	"bislericli/internal/store"
+)
+
+const ordersSheet = "Orders"
+
+// periodSheets names the tab each --group-by exports to.
+var periodSheets = map[string]string{
+	"week":    "Weekly",
+	"month":   "Monthly",
+	"quarter": "Quarterly",
+	"year":    "Yearly",
+}
+
+func runStatsExportSheets(args []string) error {
+	fs, profileName := parseScheduleFlags("stats export-sheets")
+	groupBy := fs.String("group-by", "month", "Group the summary tab by week (ISO), month, quarter or year")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if err := validGrouping(*groupBy); err != nil {
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	name := resolveProfileName(*profileName, cfg)
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	if err := exportProfileToSheets(ctx, cfg.Sheets, name, *groupBy); err != nil {
+		return err
+	}
+	fmt.Println(format.Check(), "Exported stats to Google Sheets.")
+	return nil
+}
+
+// exportProfileToSheets replaces the summary tab for the grouping (Monthly,
+// Weekly, ...) and the Orders tab of the configured spreadsheet with the
+// profile's order history.
+func exportProfileToSheets(ctx context.Context, cfg config.Sheets, name, by string) error {
+	if cfg.SpreadsheetID == "" || cfg.CredentialsFile == "" {
+		return errors.New("set sheets.spreadsheetId and sheets.credentialsFile in config.json first")
+	}
+	profile, _, err := loadOrCreateProfile(name)
+	if err != nil {
+		return err
+	}
+	history, err := store.LoadOrderHistory(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.New("no synced data found; run 'bislericli sync' first")
+		}
+		return fmt.Errorf("failed to load history: %w", err)
+	}
+	orders := withUnsyncedOrder(history.Orders, profile.LastOrder)
+
+	creds, err := sheets.LoadCredentials(cfg.CredentialsFile)
+	if err != nil {
+		return err
+	}
+	client, err := sheets.NewClient(creds)
+	if err != nil {
+		return err
+	}
+	if err := client.Replace(ctx, cfg.SpreadsheetID, periodSheets[by], periodRows(orders, by)); err != nil {
+		return fmt.Errorf("failed to export %s stats: %w", strings.ToLower(periodSheets[by]), err)
+	}
+	if err := client.Replace(ctx, cfg.SpreadsheetID, ordersSheet, orderRows(orders)); err != nil {
+		return fmt.Errorf("failed to export orders: %w", err)
+	}
+	return nil
+}
+
+func periodRows(orders []store.SavedOrder, by string) [][]string {
+	header := strings.ToUpper(by[:1]) + by[1:]
+	rows := [][]string{{header, "Orders", "Total", "Average"}}
+	for _, p := range periodTotals(orders, by) {
+		rows = append(rows, []string{p.Key, strconv.Itoa(p.Count), sheetAmount(p.Total), sheetAmount(p.Total.Div(p.Count))})
+	}
+	return rows
+}
+
+func orderRows(orders []store.SavedOrder) [][]string {
+	rows := [][]string{{"Order ID", "Date", "Status", "Total", "Items"}}
+	for _, o := range orders {
+		date := o.Date
+		if !o.ParsedDate.IsZero() {
+			date = o.ParsedDate.Format("2006-01-02")
+		}
+		rows = append(rows, []string{o.OrderID, date, o.Status, sheetAmount(o.Amount), o.Items})
+	}
+	return rows
+}
+
+// sheetAmount formats rupees without grouping so Sheets reads a number.
+func sheetAmount(m money.Money) string {
+	return strconv.FormatFloat(m.Rupees(), 'f', 2, 64)
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/config"
+	"bislericli/internal/debug"
+	"bislericli/internal/format"
+	"bislericli/internal/money"
+	"bislericli/internal/store"
+)
+
+func runDebugSimulate(args []string) error {
+	fs, profileName := parseScheduleFlags("debug simulate")
+	fixtures := fs.String("fixtures", "", "Directory written by 'debug order --fixtures' (required)")
+	quantity := fs.Int("qty", 0, "Number of 20L jars to order (default: config)")
+	returnJars := fs.Int("return", -1, "Number of empty jars to return (default: matches order qty)")
+	timeslot := fs.String("timeslot", "", "Delivery timeslot (default: the profile's, then config)")
+	allowExtra := fs.Bool("allow-extra", false, "Proceed even if the cart fixture holds other items")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *fixtures == "" {
+		return errors.New("--fixtures is required (capture one with 'bislericli debug order --fixtures DIR')")
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, _, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	if *quantity == 0 {
+		*quantity = cfg.Defaults.OrderQuantity
+	}
+	if *returnJars < 0 {
+		*returnJars = *quantity
+	}
+	if *quantity <= 0 || *returnJars > *quantity {
+		return fmt.Errorf("invalid quantities: ordering %d, returning %d", *quantity, *returnJars)
+	}
+	replay, err := debug.LoadReplay(*fixtures)
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: replay}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	// The client has no cookies and talks only to the replay server.
+	client := bisleri.NewClient(&http.Client{Timeout: 10 * time.Second}, nil)
+	client.BaseURL = "http://" + ln.Addr().String()
+	client.Throttle = 0
+	sim := &simulation{
+		out:        os.Stdout,
+		replay:     replay,
+		client:     client,
+		profile:    profile,
+		quantity:   *quantity,
+		returnJars: *returnJars,
+		timeslot:   orderTimeslot(*timeslot, profile, "", cfg.Defaults.Timeslot),
+		allowExtra: *allowExtra,
+	}
+	fmt.Printf("Simulating an order of %d jar(s), returning %d, against %s (nothing is sent to bisleri.com).\n", sim.quantity, sim.returnJars, *fixtures)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := sim.run(ctx); err != nil {
+		var oe *orderError
+		if errors.As(err, &oe) {
+			fmt.Printf("\n✗ Failed while %s: %v\n", orderStages[oe.Stage], oe.Err)
+		}
+		return err
+	}
+	fmt.Println("\n✓ Simulation finished; every stage parsed its page.")
+	return nil
+}
+
+// simulation runs the order flow's stages against a replay server, printing
+// what each stage read and the requests it made.
+type simulation struct {
+	out        io.Writer
+	replay     *debug.Replay
+	client     *bisleri.Client
+	profile    store.Profile
+	quantity   int
+	returnJars int
+	timeslot   string
+	allowExtra bool
+
+	current string
+}
+
+func (s *simulation) enter(stage string) {
+	s.requests()
+	s.current = stage
+	fmt.Fprintf(s.out, "\n[%s] %s\n", stage, orderStages[stage])
+}
+
+func (s *simulation) value(key, val string) {
+	fmt.Fprintln(s.out, "  "+format.KeyValue(key, dashIfEmpty(val)))
+}
+
+// requests prints the requests the replay answered since the last call.
+func (s *simulation) requests() {
+	for _, r := range s.replay.Served() {
+		fmt.Fprintf(s.out, "  → %s %s %d (%s)\n", r.Method, r.Path, r.Status, r.Source)
+	}
+}
+
+func (s *simulation) fail(err error) error {
+	s.requests()
+	return &orderError{Stage: s.current, Err: err}
+}
+
+func (s *simulation) run(ctx context.Context) error {
+	client := s.client
+	jarID := jarProductID(s.profile.PreferredCity)
+
+	s.enter("cart")
+	cartHTML, err := client.FetchCartPage(ctx)
+	if err != nil {
+		return s.fail(err)
+	}
+	cart := bisleri.ParseCartPage(cartHTML)
+	var lines []string
+	for _, item := range cart.Items {
+		lines = append(lines, fmt.Sprintf("%s x%d", dashIfEmpty(item.ProductID), item.Quantity))
+	}
+	s.value("Items", strings.Join(lines, ", "))
+	if cart.HasCount {
+		s.value("Cart count", strconv.Itoa(cart.Count))
+	}
+	s.value("City", cart.SelectedCity)
+	if cart.Unparsed() {
+		return s.fail(errors.New("unable to parse cart items"))
+	}
+	if extra := filterExtraItems(cart.Items, jarID); len(extra) > 0 && !s.allowExtra {
+		return s.fail(&cartConflictError{Items: extra})
+	}
+	if item, ok := cart.Item(jarID); ok && item.UUID != "" {
+		if item.Quantity != s.quantity {
+			s.value("Action", fmt.Sprintf("update %s from %d to %d", jarID, item.Quantity, s.quantity))
+			if err := client.UpdateQuantity(ctx, jarID, item.UUID, s.quantity); err != nil {
+				return s.fail(err)
+			}
+		} else {
+			s.value("Action", "cart already at the desired quantity")
+		}
+	} else {
+		if len(cart.Items) > 0 && !s.allowExtra {
+			return s.fail(&cartConflictError{})
+		}
+		s.value("Action", fmt.Sprintf("add %d x %s", s.quantity, jarID))
+		if err := client.AddProduct(ctx, jarID, s.quantity); err != nil {
+			return s.fail(err)
+		}
+	}
+
+	s.enter("return-jars")
+	if err := client.UpdateJarQuantity(ctx, s.returnJars); err != nil {
+		return s.fail(err)
+	}
+	s.value("Return jars", strconv.Itoa(s.returnJars))
+
+	s.enter("shipping")
+	if form, err := bisleri.ExtractCheckoutForm(cartHTML); err != nil {
+		s.value("Checkout form", "not found ("+err.Error()+")")
+	} else {
+		s.value("Checkout form", form.Method+" "+form.Action)
+	}
+	if err := client.BeginCheckout(ctx); err != nil {
+		s.value("Checkout init", err.Error())
+	}
+	shippingHTML, err := client.FetchShippingPage(ctx)
+	if err != nil {
+		return s.fail(err)
+	}
+	csrfToken, err := bisleri.ExtractCSRFToken(shippingHTML)
+	if err != nil {
+		return s.fail(fmt.Errorf("failed to parse csrf token: %w", err))
+	}
+	s.value("CSRF token", "found")
+	shipmentUUID, err := bisleri.ExtractShipmentUUID(shippingHTML)
+	if err != nil {
+		return s.fail(fmt.Errorf("failed to parse shipment UUID: %w", err))
+	}
+	s.value("Shipment UUID", shipmentUUID)
+	candidates, _ := bisleri.ParseAddressCandidates(shippingHTML)
+	s.value("Addresses", strconv.Itoa(len(candidates)))
+	addr, addressID := s.profile.Address, s.profile.AddressID
+	if addr == nil || addressID == "" {
+		if len(candidates) == 0 {
+			return s.fail(errors.New("no address in the profile or on the shipping page"))
+		}
+		choice := candidates[0]
+		for _, c := range candidates {
+			if c.IsDefault {
+				choice = c
+				break
+			}
+		}
+		addr, addressID = &choice.Address, choice.ID
+		s.value("Address", "page address "+choice.ID)
+	} else {
+		s.value("Address", "profile address "+addressID)
+	}
+	slots := bisleri.ExtractTimeslots(shippingHTML)
+	var labels []string
+	for _, slot := range slots {
+		label := slot.Label
+		if !slot.Available && !strings.Contains(strings.ToLower(label), "full") {
+			label += " (full)"
+		}
+		labels = append(labels, label)
+	}
+	s.value("Timeslots", strings.Join(labels, ", "))
+	s.timeslot = canonicalTimeslot(slots, s.timeslot)
+	if open, known := bisleri.SlotOpen(slots, s.timeslot); known && !open {
+		return s.fail(fmt.Errorf("%w (%s)", bisleri.ErrNoSlotAvailable, s.timeslot))
+	}
+
+	s.enter("submit-shipping")
+	if err := client.CheckShippingAddress(ctx, *addr); err != nil {
+		return s.fail(err)
+	}
+	if err := client.SubmitShipping(ctx, shipmentUUID, csrfToken, s.timeslot, "", *addr, addressID); err != nil {
+		return s.fail(err)
+	}
+	s.value("Timeslot", s.timeslot)
+
+	s.enter("payment-page")
+	paymentHTML, err := client.FetchPaymentPage(ctx)
+	if err != nil {
+		return s.fail(err)
+	}
+	balance, hasBalance := bisleri.ExtractWalletBalance(paymentHTML)
+	s.value("Wallet balance", balance)
+	total, hasTotal := bisleri.ExtractOrderTotal(paymentHTML)
+	s.value("Order total", total)
+	if !hasTotal {
+		return s.fail(errors.New("failed to detect order total on payment page"))
+	}
+	totalAmount, ok := money.Parse(total)
+	if !ok || totalAmount <= 0 {
+		return s.fail(fmt.Errorf("invalid order total %q", total))
+	}
+	if unit, ok := jarUnitPrice(totalAmount, s.quantity, s.returnJars, nil); ok {
+		s.value("Per jar", unit.String())
+	}
+	if hasBalance {
+		if balAmount, ok := money.Parse(balance); ok && balAmount < totalAmount {
+			return s.fail(&walletShortError{Balance: balance, Total: total})
+		}
+	}
+	paymentCSRF, err := bisleri.ExtractCSRFToken(paymentHTML)
+	if err != nil {
+		paymentCSRF = csrfToken
+	}
+
+	s.enter("payment")
+	if err := client.SubmitPayment(ctx, shipmentUUID, paymentCSRF, *addr); err != nil {
+		return s.fail(err)
+	}
+
+	s.enter("place-order")
+	placed, err := client.PlaceOrder(ctx)
+	if err != nil {
+		return s.fail(err)
+	}
+	s.value("Order ID", placed.OrderID)
+	if html, err := client.FetchOrderConfirmation(ctx, placed); err == nil {
+		eta, _ := bisleri.ExtractDeliveryETA(html)
+		s.value("Estimated delivery", eta)
+	}
+	s.requests()
+	return nil
+}
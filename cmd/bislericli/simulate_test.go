@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/debug"
+	"bislericli/internal/store"
+)
+
+// The golden parser fixtures double as a captured checkout.
+var fixturesDir = filepath.Join("..", "..", "internal", "bisleri", "testdata", "fixtures")
+
+func newTestSimulation(t *testing.T, dir string) (*simulation, *bytes.Buffer) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	replay, err := debug.LoadReplay(dir)
+	if err != nil {
+		t.Fatalf("LoadReplay: %v", err)
+	}
+	srv := httptest.NewServer(replay)
+	t.Cleanup(srv.Close)
+	client := bisleri.NewClient(&http.Client{}, nil)
+	client.BaseURL = srv.URL
+	client.Throttle = 0
+	var out bytes.Buffer
+	return &simulation{
+		out:    &out,
+		replay: replay,
+		client: client,
+		profile: store.Profile{
+			AddressID: "addr-home",
+			Address: &store.Address{
+				FirstName: "Asha", Address1: "Flat 12", City: "Mumbai",
+				PostalCode: "400050", StateCode: "MH", Phone: "9876543210",
+			},
+		},
+		quantity:   2,
+		returnJars: 2,
+		timeslot:   "08:00 AM - 02:00 PM",
+	}, &out
+}
+
+func TestSimulateOrder(t *testing.T) {
+	sim, out := newTestSimulation(t, fixturesDir)
+	if err := sim.run(context.Background()); err != nil {
+		t.Fatalf("run: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"[cart]", "BIS-20LTR01-90 x2", "cart already at the desired quantity",
+		"Shipment UUID: 9f8e7d6c5b4a39281706", "02:00 PM - 08:00 PM (Full)",
+		"Order total: ₹ 200.00", "Per jar: ₹100.00",
+		"Order ID: " + debug.SimulatedOrderID, "(cart.html)", "(stand-in)",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestSimulateOrderStopsAtFailingStage(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"cart.html", "shipping.html", "payment.html"} {
+		data, err := os.ReadFile(filepath.Join(fixturesDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name == "payment.html" {
+			data = bytes.Replace(data, []byte("1,250.00"), []byte("50.00"), 1)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sim, out := newTestSimulation(t, dir)
+	err := sim.run(context.Background())
+	var oe *orderError
+	var short *walletShortError
+	if !errors.As(err, &oe) || oe.Stage != "payment-page" || !errors.As(err, &short) {
+		t.Fatalf("expected a wallet failure at payment-page, got %v\n%s", err, out)
+	}
+	if strings.Contains(out.String(), "[payment]") {
+		t.Errorf("simulation went past the failing stage:\n%s", out)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/config"
+	"bislericli/internal/format"
+	"bislericli/internal/i18n"
+)
+
+func runSlots(args []string) error {
+	if len(args) < 1 || isHelpToken(args[0]) {
+		printSlotsUsage()
+		return nil
+	}
+	switch args[0] {
+	case "list":
+		return runSlotsList(args[1:])
+	default:
+		fmt.Printf("Unknown slots subcommand: %s\n", args[0])
+		printSlotsUsage()
+		return nil
+	}
+}
+
+func printSlotsUsage() {
+	fmt.Println("Usage: bislericli slots <subcommand> [flags]")
+	fmt.Println("\nAvailable subcommands:")
+	fmt.Println("  list   Show the delivery slots the site accepts for your city")
+}
+
+func runSlotsList(args []string) error {
+	fs, profileName := parseScheduleFlags("slots list")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	profile, profilePath, err := loadOrCreateProfile(resolveProfileName(*profileName, cfg))
+	if err != nil {
+		return err
+	}
+	if len(profile.Cookies) == 0 {
+		return errors.New(i18n.T("no cookies in profile; run 'bislericli auth login'"))
+	}
+	sess, err := newSession(cfg, &profile, profilePath, 30*time.Second)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	slots, err := sess.FetchTimeslots(ctx)
+	if errors.Is(err, bisleri.ErrNoTimeslots) {
+		return fmt.Errorf("%w; add a jar to the cart on bisleri.com and retry", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load delivery slots: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SLOT\tPART OF DAY\tSTATUS")
+	for _, s := range slots {
+		status := "open"
+		if !s.Available {
+			status = "full"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Value, dashIfEmpty(bisleri.PartOfDay(s.Label)), status)
+	}
+	w.Flush()
+
+	fmt.Println()
+	for _, c := range []struct{ key, value string }{
+		{"defaults.timeslot", cfg.Defaults.Timeslot},
+		{"address timeslot", orderTimeslot("", profile, "", "")},
+	} {
+		if c.value == "" {
+			continue
+		}
+		if slot, ok := bisleri.MatchTimeslot(slots, c.value); ok {
+			fmt.Printf("%s %q matches %q.\n", c.key, c.value, slot.Value)
+		} else {
+			fmt.Printf("%s %q matches none of these; use a slot above, or morning, afternoon or evening.\n", c.key, c.value)
+		}
+	}
+	return nil
+}
+
+// canonicalTimeslot turns a configured timeslot ("morning", "8am-2pm") into
+// the value the site lists for it, warning when it matches none.
+func canonicalTimeslot(slots []bisleri.Timeslot, timeslot string) string {
+	if timeslot == "" || len(slots) == 0 {
+		return timeslot
+	}
+	slot, ok := bisleri.MatchTimeslot(slots, timeslot)
+	if !ok {
+		values := make([]string, len(slots))
+		for i, s := range slots {
+			values[i] = s.Value
+		}
+		fmt.Fprintf(os.Stderr, "Warning: timeslot %q is not one the site offers (%s); see 'bislericli slots list'\n", timeslot, strings.Join(values, ", "))
+		return timeslot
+	}
+	if slot.Value != timeslot {
+		progressln(format.KeyValue(i18n.T("Timeslot"), slot.Value))
+	}
+	return slot.Value
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	"bislericli/internal/bisleri"
+)
+
+func TestCanonicalTimeslot(t *testing.T) {
+	slots := []bisleri.Timeslot{
+		{Value: "08:00 AM - 02:00 PM", Label: "08:00 AM - 02:00 PM", Available: true},
+		{Value: "02:00 PM - 08:00 PM", Label: "02:00 PM - 08:00 PM", Available: true},
+	}
+	for want, got := range map[string]string{
+		"evening":             "02:00 PM - 08:00 PM",
+		"8am - 2pm":           "08:00 AM - 02:00 PM",
+		"08:00 AM - 02:00 PM": "08:00 AM - 02:00 PM",
+		"night":               "night",
+		"":                    "",
+	} {
+		if c := canonicalTimeslot(slots, want); c != got {
+			t.Errorf("canonicalTimeslot(%q) = %q, want %q", want, c, got)
+		}
+	}
+	if c := canonicalTimeslot(nil, "morning"); c != "morning" {
+		t.Errorf("without slots = %q, want the setting unchanged", c)
+	}
+}
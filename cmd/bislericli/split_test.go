@@ -0,0 +1,23 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitOrder(t *testing.T) {
+	tests := []struct {
+		qty, ret, max int
+		want          []orderBatch
+	}{
+		{2, 2, 0, []orderBatch{{2, 2}}},
+		{4, 4, 4, []orderBatch{{4, 4}}},
+		{10, 10, 4, []orderBatch{{4, 4}, {4, 4}, {2, 2}}},
+		{9, 5, 4, []orderBatch{{4, 4}, {4, 1}, {1, 0}}},
+	}
+	for _, tt := range tests {
+		if got := splitOrder(tt.qty, tt.ret, tt.max); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("splitOrder(%d, %d, %d) = %v, want %v", tt.qty, tt.ret, tt.max, got, tt.want)
+		}
+	}
+}
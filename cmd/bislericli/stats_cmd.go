@@ -6,30 +6,85 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
+	"strings"
 	"text/tabwriter"
 	"time"
 
 	"bislericli/internal/config"
+	"bislericli/internal/i18n"
+	"bislericli/internal/money"
 	"bislericli/internal/store"
 )
 
-type monthStats struct {
-	Yearmonth string // YYYY-MM
-	MonthStr  string // "Jan 2026"
-	Count     int
-	Total     float64
+type periodStats struct {
+	Key   string // sortable: "2026-01", "2026-W05", "2026-Q1", "2026"
+	Label string // "Jan 2026", "2026-W05", "Q1 2026", "2026"
+	Count int
+	Total money.Money
+}
+
+// statsGroupings are the values accepted by --group-by.
+var statsGroupings = []string{"week", "month", "quarter", "year"}
+
+func validGrouping(by string) error {
+	for _, g := range statsGroupings {
+		if by == g {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid --group-by %q (use %s)", by, strings.Join(statsGroupings, ", "))
+}
+
+// periodOf returns the key and label of the period t falls in. Weeks are
+// ISO weeks, so the first days of January can belong to the previous year.
+func periodOf(t time.Time, by string) (key, label string) {
+	switch by {
+	case "week":
+		year, week := t.ISOWeek()
+		key = fmt.Sprintf("%d-W%02d", year, week)
+		return key, key
+	case "quarter":
+		q := (int(t.Month())-1)/3 + 1
+		return fmt.Sprintf("%d-Q%d", t.Year(), q), fmt.Sprintf("Q%d %d", q, t.Year())
+	case "year":
+		key = strconv.Itoa(t.Year())
+		return key, key
+	default:
+		return t.Format("2006-01"), t.Format("Jan 2006")
+	}
 }
 
 func runStats(args []string) error {
+	if len(args) > 0 && args[0] == "wallet" {
+		return runStatsWallet(args[1:])
+	}
+	if len(args) > 0 && args[0] == "prices" {
+		return runStatsPrices(args[1:])
+	}
+	if len(args) > 0 && args[0] == "export-sheets" {
+		return runStatsExportSheets(args[1:])
+	}
+	if len(args) > 0 && args[0] == "split" {
+		return runStatsSplit(args[1:])
+	}
+	if len(args) > 0 && args[0] == "timeslots" {
+		return runStatsTimeslots(args[1:])
+	}
 	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
 	profileName := fs.String("profile", "", "Profile name to use (default: current/default)")
 	viewPatterns := fs.Bool("view-patterns", false, "Analyze ordering patterns (day/time) instead of monthly history")
+	byTag := fs.Bool("by-tag", false, "Split spend by the member/location tag given with 'order --for'")
+	groupBy := fs.String("group-by", "month", "Group history by week (ISO), month, quarter or year")
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
 			return nil
 		}
 		return err
 	}
+	if err := validGrouping(*groupBy); err != nil {
+		return err
+	}
 
 	cfg, err := config.LoadGlobalConfig()
 	if err != nil {
@@ -37,8 +92,7 @@ func runStats(args []string) error {
 	}
 
 	name := resolveProfileName(*profileName, cfg)
-	// We just need the name, but loadOrCreateProfile verifies it exists
-	_, _, err = loadOrCreateProfile(name)
+	profile, _, err := loadOrCreateProfile(name)
 	if err != nil {
 		return err
 	}
@@ -52,7 +106,8 @@ func runStats(args []string) error {
 		return fmt.Errorf("failed to load history: %w", err)
 	}
 
-	orders := history.Orders
+	// Count an order placed since the last sync without waiting for the next one.
+	orders := withUnsyncedOrder(history.Orders, profile.LastOrder)
 	if len(orders) == 0 {
 		fmt.Println("No orders found in local history.")
 		return nil
@@ -62,40 +117,63 @@ func runStats(args []string) error {
 
 	if *viewPatterns {
 		printPatterns(orders)
+	} else if *byTag {
+		entries, err := store.LoadAuditLog(name)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to load audit log: %w", err)
+		}
+		printTagStats(orders, orderTags(entries, profile.LastOrder))
 	} else {
-		printMonthlyStats(orders)
+		printPeriodStats(orders, *groupBy, cfg.Defaults.MonthlyBudget)
 	}
 
 	return nil
 }
 
-func printMonthlyStats(orders []store.SavedOrder) {
-	statsMap := make(map[string]*monthStats)
+// periodTotals groups dated orders by week, month, quarter or year, oldest
+// first.
+func periodTotals(orders []store.SavedOrder, by string) []*periodStats {
+	statsMap := make(map[string]*periodStats)
+	for _, o := range orders {
+		t := o.ParsedDate
+		if t.IsZero() {
+			continue
+		}
+		key, label := periodOf(t, by)
+		if _, exists := statsMap[key]; !exists {
+			statsMap[key] = &periodStats{Key: key, Label: label}
+		}
+		statsMap[key].Count++
+		statsMap[key].Total += o.Amount
+	}
+
+	var keys []string
+	for k := range statsMap {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	periods := make([]*periodStats, 0, len(keys))
+	for _, k := range keys {
+		periods = append(periods, statsMap[k])
+	}
+	return periods
+}
+
+// printPeriodStats prints the per-period table and the overall totals. The
+// monthly budget is only marked when grouping by month.
+func printPeriodStats(orders []store.SavedOrder, by string, budget money.Money) {
+	if by != "month" {
+		budget = 0
+	}
 	var earliest, latest string
 	var totalOrders int
-	var grandTotal float64
+	var grandTotal money.Money
 
 	for _, o := range orders {
-		// Skip invalid orders
-		if o.Amount == 0 && o.Total != "0" && o.Total != "Free" {
-			// Maybe try fix? Already fixed in sync
-		}
-
 		t := o.ParsedDate
 		if t.IsZero() {
 			continue
 		}
-
-		ym := t.Format("2006-01")
-		if _, exists := statsMap[ym]; !exists {
-			statsMap[ym] = &monthStats{
-				Yearmonth: ym,
-				MonthStr:  t.Format("Jan 2006"),
-			}
-		}
-		statsMap[ym].Count++
-		statsMap[ym].Total += o.Amount
-
 		grandTotal += o.Amount
 		totalOrders++
 
@@ -108,43 +186,36 @@ func printMonthlyStats(orders []store.SavedOrder) {
 		}
 	}
 
-	// Sort keys
-	var keys []string
-	for k := range statsMap {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
 	// Print Table
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
 	fmt.Println()
 	fmt.Fprintln(w, "+----------------+----------+---------------+---------------+")
-	fmt.Fprintln(w, "| Period\t| Orders\t| Total\t| Average\t|")
+	fmt.Fprintf(w, "| %s\t| %s\t| %s\t| %s\t|\n", i18n.T("Period"), i18n.T("Orders"), i18n.T("Total"), i18n.T("Average"))
 	fmt.Fprintln(w, "+----------------+----------+---------------+---------------+")
 
-	for _, k := range keys {
-		s := statsMap[k]
-		avg := 0.0
-		if s.Count > 0 {
-			avg = s.Total / float64(s.Count)
+	for _, s := range periodTotals(orders, by) {
+		avg := s.Total.Div(s.Count)
+		period := s.Label
+		if budget > 0 && s.Total > budget {
+			period += " *"
 		}
-		fmt.Fprintf(w, "| %s\t| %d\t| ₹%.2f\t| ₹%.2f\t|\n", s.MonthStr, s.Count, s.Total, avg)
+		fmt.Fprintf(w, "| %s\t| %d\t| %s\t| %s\t|\n", period, s.Count, s.Total, avg)
 	}
 	fmt.Fprintln(w, "+----------------+----------+---------------+---------------+")
 	w.Flush()
+	if budget > 0 {
+		fmt.Printf("* over the %s monthly budget\n", budget)
+	}
 
 	// Print Footer
 	fmt.Println()
 	fmt.Fprintln(w, "+----------+---------------+---------------+---------------+---------------+")
-	fmt.Fprintln(w, "| Orders\t| Total\t| Average\t| Earliest\t| Latest\t|")
+	fmt.Fprintf(w, "| %s\t| %s\t| %s\t| %s\t| %s\t|\n", i18n.T("Orders"), i18n.T("Total"), i18n.T("Average"), i18n.T("Earliest"), i18n.T("Latest"))
 	fmt.Fprintln(w, "+----------+---------------+---------------+---------------+---------------+")
 
-	grandAvg := 0.0
-	if totalOrders > 0 {
-		grandAvg = grandTotal / float64(totalOrders)
-	}
+	grandAvg := grandTotal.Div(totalOrders)
 
-	fmt.Fprintf(w, "| %d\t| ₹%.2f\t| ₹%.2f\t| %s\t| %s\t|\n", totalOrders, grandTotal, grandAvg, earliest, latest)
+	fmt.Fprintf(w, "| %d\t| %s\t| %s\t| %s\t| %s\t|\n", totalOrders, grandTotal, grandAvg, earliest, latest)
 	fmt.Fprintln(w, "+----------+---------------+---------------+---------------+---------------+")
 	w.Flush()
 	fmt.Println()
@@ -172,7 +243,7 @@ func printPatterns(orders []store.SavedOrder) {
 	fmt.Println("Ordering patterns")
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
 	fmt.Fprintln(w, "+----------------+----------+----------+")
-	fmt.Fprintln(w, "| Day\t| Orders\t| Share\t|")
+	fmt.Fprintf(w, "| %s\t| %s\t| %s\t|\n", i18n.T("Day"), i18n.T("Orders"), i18n.T("Share"))
 	fmt.Fprintln(w, "+----------------+----------+----------+")
 
 	// Order from Monday to Sunday
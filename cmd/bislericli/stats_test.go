@@ -0,0 +1,62 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"bislericli/internal/money"
+	"bislericli/internal/store"
+)
+
+func TestPeriodTotals(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 10, 0, 0, 0, time.UTC) }
+	orders := []store.SavedOrder{
+		{ParsedDate: day(2025, 12, 30), Amount: money.FromFloat(180)},
+		{ParsedDate: day(2026, 1, 2), Amount: money.FromFloat(90)},
+		{ParsedDate: day(2026, 1, 6), Amount: money.FromFloat(270)},
+		{ParsedDate: day(2026, 4, 1), Amount: money.FromFloat(180)},
+		{Amount: money.FromFloat(999)},
+	}
+	cases := []struct {
+		by     string
+		keys   []string
+		labels []string
+		counts []int
+	}{
+		// 30 Dec 2025 and 2 Jan 2026 are both in ISO week 1 of 2026.
+		{"week", []string{"2026-W01", "2026-W02", "2026-W14"}, []string{"2026-W01", "2026-W02", "2026-W14"}, []int{2, 1, 1}},
+		{"month", []string{"2025-12", "2026-01", "2026-04"}, []string{"Dec 2025", "Jan 2026", "Apr 2026"}, []int{1, 2, 1}},
+		{"quarter", []string{"2025-Q4", "2026-Q1", "2026-Q2"}, []string{"Q4 2025", "Q1 2026", "Q2 2026"}, []int{1, 2, 1}},
+		{"year", []string{"2025", "2026"}, []string{"2025", "2026"}, []int{1, 3}},
+	}
+	for _, tc := range cases {
+		var keys, labels []string
+		var counts []int
+		var total money.Money
+		for _, p := range periodTotals(orders, tc.by) {
+			keys = append(keys, p.Key)
+			labels = append(labels, p.Label)
+			counts = append(counts, p.Count)
+			total += p.Total
+		}
+		if !reflect.DeepEqual(keys, tc.keys) || !reflect.DeepEqual(labels, tc.labels) || !reflect.DeepEqual(counts, tc.counts) {
+			t.Fatalf("%s: keys %v labels %v counts %v", tc.by, keys, labels, counts)
+		}
+		if total != money.FromFloat(720) {
+			t.Fatalf("%s: total = %v, want 720", tc.by, total)
+		}
+	}
+	if err := validGrouping("day"); err == nil {
+		t.Fatalf("expected an error for --group-by day")
+	}
+}
+
+func TestPeriodRows(t *testing.T) {
+	orders := []store.SavedOrder{{ParsedDate: time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC), Amount: money.FromFloat(180)}}
+	rows := periodRows(orders, "quarter")
+	want := [][]string{{"Quarter", "Orders", "Total", "Average"}, {"2026-Q1", "1", "180.00", "180.00"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("periodRows = %v", rows)
+	}
+}
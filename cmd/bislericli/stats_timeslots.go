@@ -0,0 +1,263 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/config"
+	"bislericli/internal/store"
+)
+
+// slotDelivery is an order placed with a requested timeslot and later shown
+// as delivered.
+type slotDelivery struct {
+	OrderID   string
+	Slot      string
+	Due       time.Time // end of the requested slot
+	Delivered time.Time
+	DateOnly  bool // the site gave a delivery date without a time
+}
+
+func (d slotDelivery) Late() bool {
+	if d.DateOnly {
+		return dayOf(d.Delivered).After(dayOf(d.Due))
+	}
+	return d.Delivered.After(d.Due)
+}
+
+func runStatsTimeslots(args []string) error {
+	fs := flag.NewFlagSet("stats timeslots", flag.ContinueOnError)
+	profileName := fs.String("profile", "", "Profile name to use (default: current/default)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	name := resolveProfileName(*profileName, cfg)
+	profile, _, err := loadOrCreateProfile(name)
+	if err != nil {
+		return err
+	}
+	history, err := store.LoadOrderHistory(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.New("no synced data found; run 'bislericli sync' first")
+		}
+		return fmt.Errorf("failed to load history: %w", err)
+	}
+	entries, err := store.LoadAuditLog(name)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to load audit log: %w", err)
+	}
+
+	deliveries := slotDeliveries(history.Orders, entries, profile.LastOrder)
+	if len(deliveries) == 0 {
+		fmt.Println("No delivered orders with a recorded timeslot yet. Slots are recorded when orders are placed with this CLI; delivery times come from 'bislericli sync'.")
+		return nil
+	}
+
+	type monthAdherence struct {
+		Label           string
+		Delivered, Late int
+	}
+	months := map[string]*monthAdherence{}
+	var late []slotDelivery
+	for _, d := range deliveries {
+		key, label := periodOf(d.Due, "month")
+		if months[key] == nil {
+			months[key] = &monthAdherence{Label: label}
+		}
+		months[key].Delivered++
+		if d.Late() {
+			months[key].Late++
+			late = append(late, d)
+		}
+	}
+	var keys []string
+	for k := range months {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Println()
+	fmt.Fprintln(w, "Month\tDelivered\tOn time\tLate\tOn-time\t")
+	var total, totalLate int
+	for _, k := range keys {
+		m := months[k]
+		total += m.Delivered
+		totalLate += m.Late
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t\n", m.Label, m.Delivered, m.Delivered-m.Late, m.Late, onTimeShare(m.Delivered, m.Late))
+	}
+	fmt.Fprintf(w, "All\t%d\t%d\t%d\t%s\t\n", total, total-totalLate, totalLate, onTimeShare(total, totalLate))
+	w.Flush()
+
+	if len(late) > 0 {
+		fmt.Println("\nLate deliveries:")
+		for _, d := range late {
+			delivered := d.Delivered.Format("2006-01-02 15:04")
+			by := d.Delivered.Sub(d.Due).Round(time.Minute).String()
+			if d.DateOnly {
+				delivered = d.Delivered.Format("2006-01-02")
+				by = fmt.Sprintf("%d day(s)", int(dayOf(d.Delivered).Sub(dayOf(d.Due)).Hours()/24))
+			}
+			fmt.Printf("  %s  slot %s on %s, delivered %s (%s late)\n", d.OrderID, d.Slot, d.Due.Format("2006-01-02"), delivered, by)
+		}
+	}
+	return nil
+}
+
+func onTimeShare(delivered, late int) string {
+	if delivered == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", float64(delivered-late)/float64(delivered)*100)
+}
+
+// slotDeliveries pairs delivered orders with the timeslot and promised date
+// recorded when they were placed. Orders placed on the website have no
+// recorded slot and are skipped.
+func slotDeliveries(orders []store.SavedOrder, entries []store.AuditEntry, last *store.OrderInfo) []slotDelivery {
+	type request struct {
+		placed    time.Time
+		slot, eta string
+	}
+	requests := map[string]request{}
+	for _, e := range entries {
+		if e.Result == "success" && e.OrderID != "" && e.Timeslot != "" {
+			requests[e.OrderID] = request{e.Timestamp, e.Timeslot, e.DeliveryETA}
+		}
+	}
+	if last != nil && last.OrderID != "" && last.Timeslot != "" {
+		requests[last.OrderID] = request{last.PlacedAt, last.Timeslot, last.DeliveryETA}
+	}
+
+	var deliveries []slotDelivery
+	for _, o := range orders {
+		req, ok := requests[o.OrderID]
+		if !ok || o.DeliveredAt.IsZero() {
+			continue
+		}
+		delivered := o.DeliveredAt.In(time.Local)
+		due, ok := slotDeadline(req.placed.In(time.Local), req.slot, req.eta)
+		if !ok {
+			continue
+		}
+		deliveries = append(deliveries, slotDelivery{
+			OrderID:   o.OrderID,
+			Slot:      req.slot,
+			Due:       due,
+			Delivered: delivered,
+			DateOnly:  delivered.Hour() == 0 && delivered.Minute() == 0,
+		})
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].Due.Before(deliveries[j].Due) })
+	return deliveries
+}
+
+var (
+	slotTimeRegex = regexp.MustCompile(`(?i)(\d{1,2})(?::(\d{2}))?\s*(AM|PM)?`)
+	etaDateRegex  = regexp.MustCompile(`\b(\d{1,2})\s+([A-Za-z]{3,9})\.?(?:,?\s+(\d{4}))?`)
+)
+
+// slotWindow parses a slot such as "08:00 AM - 02:00 PM" into its start and
+// end as offsets from midnight.
+func slotWindow(slot string) (start, end time.Duration, ok bool) {
+	parts := strings.SplitN(slot, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	start, okStart := clockOffset(parts[0])
+	end, okEnd := clockOffset(parts[1])
+	if !okStart || !okEnd || end <= start {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+func clockOffset(s string) (time.Duration, bool) {
+	match := slotTimeRegex.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return 0, false
+	}
+	hour, _ := strconv.Atoi(match[1])
+	minute, _ := strconv.Atoi(match[2])
+	switch strings.ToUpper(match[3]) {
+	case "AM":
+		if hour == 12 {
+			hour = 0
+		}
+	case "PM":
+		if hour < 12 {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return 0, false
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, true
+}
+
+// slotDeadline returns the end of the slot an order was due in: on the date
+// promised at checkout when the ETA names one, otherwise the first
+// occurrence of the slot that had not started when the order was placed.
+func slotDeadline(placed time.Time, slot, eta string) (time.Time, bool) {
+	start, end, ok := slotWindow(slot)
+	if !ok {
+		return time.Time{}, false
+	}
+	day := dayOf(placed)
+	if promised, ok := etaDate(eta, placed); ok {
+		day = promised
+	} else if placed.Sub(day) >= start {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day.Add(end), true
+}
+
+// etaDate finds a date such as "17 Oct 2026" or "Sat, 18 Oct" in a delivery
+// ETA. A date without a year is the next one on or after placed.
+func etaDate(eta string, placed time.Time) (time.Time, bool) {
+	match := etaDateRegex.FindStringSubmatch(eta)
+	if match == nil || len(match[2]) < 3 {
+		return time.Time{}, false
+	}
+	month, err := time.Parse("Jan", strings.ToUpper(match[2][:1])+strings.ToLower(match[2][1:3]))
+	if err != nil {
+		return time.Time{}, false
+	}
+	d, _ := strconv.Atoi(match[1])
+	year := placed.Year()
+	if match[3] != "" {
+		year, _ = strconv.Atoi(match[3])
+	}
+	date := time.Date(year, month.Month(), d, 0, 0, 0, 0, placed.Location())
+	if match[3] == "" && date.Before(dayOf(placed)) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return date, true
+}
+
+// parseDeliveredAt parses the delivery time shown on an order, in local time.
+func parseDeliveredAt(s string) time.Time {
+	t, _ := bisleri.ParseDate(s, time.Local)
+	return t
+}
+
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
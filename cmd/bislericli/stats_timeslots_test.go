@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"bislericli/internal/store"
+)
+
+func TestSlotDeadline(t *testing.T) {
+	at := func(d, h, m int) time.Time { return time.Date(2026, 10, d, h, m, 0, 0, time.Local) }
+	slot := "08:00 AM - 02:00 PM"
+	tests := []struct {
+		placed time.Time
+		eta    string
+		want   time.Time
+	}{
+		{at(14, 6, 30), "", at(14, 14, 0)},
+		{at(14, 9, 0), "", at(15, 14, 0)},
+		{at(14, 9, 0), "17 Oct 2026, 08:00 AM - 02:00 PM", at(17, 14, 0)},
+		{at(14, 9, 0), "Sat, 18 Oct | 08:00 AM - 02:00 PM", at(18, 14, 0)},
+	}
+	for _, tt := range tests {
+		got, ok := slotDeadline(tt.placed, slot, tt.eta)
+		if !ok || !got.Equal(tt.want) {
+			t.Fatalf("slotDeadline(%s, %q) = %s, %v; want %s", tt.placed, tt.eta, got, ok, tt.want)
+		}
+	}
+	if _, ok := slotDeadline(at(14, 9, 0), "anytime", ""); ok {
+		t.Fatalf("expected unparseable slot to be skipped")
+	}
+	if got, _ := etaDate("Fri, 2 Jan", time.Date(2026, 12, 30, 9, 0, 0, 0, time.Local)); got.Year() != 2027 {
+		t.Fatalf("ETA without a year should roll into next year, got %s", got)
+	}
+}
+
+func TestSlotDeliveries(t *testing.T) {
+	placed := time.Date(2026, 10, 14, 9, 0, 0, 0, time.Local)
+	entries := []store.AuditEntry{
+		{Result: "success", OrderID: "BS-1", Timestamp: placed, Timeslot: "08:00 AM - 02:00 PM"},
+		{Result: "success", OrderID: "BS-2", Timestamp: placed, Timeslot: "02:00 PM - 08:00 PM", DeliveryETA: "15 Oct 2026"},
+		{Result: "failure", OrderID: "BS-3", Timestamp: placed, Timeslot: "08:00 AM - 02:00 PM"},
+	}
+	orders := []store.SavedOrder{
+		{OrderID: "BS-1", DeliveredAt: parseDeliveredAt("15/10/2026, 1:10 PM")},
+		{OrderID: "BS-2", DeliveredAt: parseDeliveredAt("16/10/2026")},
+		{OrderID: "BS-3", DeliveredAt: parseDeliveredAt("15/10/2026, 9:00 AM")},
+		{OrderID: "BS-4", DeliveredAt: parseDeliveredAt("15/10/2026, 9:00 AM")},
+	}
+	got := slotDeliveries(orders, entries, nil)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 deliveries with a recorded slot, got %+v", got)
+	}
+	if got[0].OrderID != "BS-1" || got[0].Late() {
+		t.Fatalf("BS-1 was delivered inside its slot: %+v", got[0])
+	}
+	if got[1].OrderID != "BS-2" || !got[1].DateOnly || !got[1].Late() {
+		t.Fatalf("BS-2 was delivered a day after the promised date: %+v", got[1])
+	}
+	if onTimeShare(2, 1) != "50%" {
+		t.Fatalf("onTimeShare(2, 1) = %s", onTimeShare(2, 1))
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/format"
+	"bislericli/internal/money"
+	"bislericli/internal/store"
+)
+
+// walletEvent is one movement in the wallet ledger reconstructed from the
+// audit log's before/after balances.
+type walletEvent struct {
+	At      time.Time
+	Kind    string // "debit", "top-up", "unmatched-debit"
+	Amount  money.Money
+	OrderID string
+	Note    string
+}
+
+// walletBalance is the newest balance the audit log recorded, and when.
+type walletBalance struct {
+	Amount money.Money
+	At     time.Time
+	// Stale is set when a later order recorded no balance, so the wallet has
+	// moved since At by an unknown amount.
+	Stale bool
+}
+
+type walletMonth struct {
+	Key    string
+	Label  string
+	Spend  money.Money
+	TopUps money.Money
+}
+
+func runStatsWallet(args []string) error {
+	fs := flag.NewFlagSet("stats wallet", flag.ContinueOnError)
+	profileName := fs.String("profile", "", "Profile name to use (default: current/default)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	name := resolveProfileName(*profileName, cfg)
+
+	entries, err := store.LoadAuditLog(name)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to load audit log: %w", err)
+	}
+	events, balance, auditOrders := reconcileWallet(entries)
+	if len(events) == 0 {
+		fmt.Println("No wallet movements recorded yet; balances are captured when orders are placed with this CLI.")
+		return nil
+	}
+
+	months := map[string]*walletMonth{}
+	var flagged []walletEvent
+	for _, ev := range events {
+		key := ev.At.Format("2006-01")
+		m, ok := months[key]
+		if !ok {
+			m = &walletMonth{Key: key, Label: ev.At.Format("Jan 2006")}
+			months[key] = m
+		}
+		switch ev.Kind {
+		case "debit", "unmatched-debit":
+			m.Spend += ev.Amount
+		case "top-up":
+			m.TopUps += ev.Amount
+		}
+		if ev.Kind == "unmatched-debit" || ev.Note != "" {
+			flagged = append(flagged, ev)
+		}
+	}
+	var keys []string
+	for k := range months {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Println()
+	fmt.Fprintln(w, "Month\tTop-ups\tSpend\tNet\t")
+	var totalSpend money.Money
+	for _, k := range keys {
+		m := months[k]
+		totalSpend += m.Spend
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", m.Label, m.TopUps, m.Spend, m.TopUps-m.Spend)
+	}
+	w.Flush()
+
+	if len(flagged) > 0 {
+		fmt.Println("\nNeeds attention:")
+		for _, ev := range flagged {
+			label := ev.OrderID
+			if label == "" {
+				label = "no order"
+			}
+			note := ev.Note
+			if ev.Kind == "unmatched-debit" {
+				note = "wallet dropped between orders without a recorded order"
+			}
+			fmt.Printf("  %s  %s  %s  (%s)\n", ev.At.Format("2006-01-02"), ev.Amount, label, note)
+		}
+	}
+
+	if history, err := store.LoadOrderHistory(name); err == nil {
+		var missing int
+		for _, o := range history.Orders {
+			if !auditOrders[o.OrderID] && o.ParsedDate.After(events[0].At) {
+				missing++
+			}
+		}
+		if missing > 0 {
+			fmt.Printf("\n%d synced order(s) since the first recorded balance were placed outside this CLI and are not in the ledger.\n", missing)
+		}
+	}
+
+	avgSpend := totalSpend.Div(len(keys))
+	fmt.Println()
+	seen := fmt.Sprintf("%s on %s", balance.Amount, balance.At.Format("2006-01-02"))
+	if balance.Stale {
+		seen += " (later orders recorded no balance)"
+	}
+	fmt.Println(format.KeyValue("Last recorded balance", seen))
+	fmt.Println(format.KeyValue("Average monthly spend", avgSpend.String()))
+	if balance.Stale {
+		fmt.Println("Check the balance on bisleri.com before recharging; it has changed since it was last recorded.")
+	} else if recharge := avgSpend - balance.Amount; recharge > 0 {
+		fmt.Println(format.KeyValue("Suggested recharge to cover a month", money.FromFloat(math.Ceil(recharge.Rupees())).String()))
+	} else {
+		fmt.Println("Balance covers an average month of spend.")
+	}
+	return nil
+}
+
+// reconcileWallet walks successful audit entries in order. Each order's debit
+// is the before/after balance difference, compared against the order total;
+// balance changes between orders are top-ups (increase) or unmatched debits
+// (decrease). The balance returned is the newest after-order balance; orders
+// after it without one mark it stale rather than being skipped silently.
+func reconcileWallet(entries []store.AuditEntry) ([]walletEvent, walletBalance, map[string]bool) {
+	var events []walletEvent
+	orders := map[string]bool{}
+	var balance walletBalance
+	var lastAfter money.Money
+	haveLast := false
+	for _, e := range entries {
+		if e.Result != "success" {
+			continue
+		}
+		orders[e.OrderID] = true
+		before, okBefore := money.Parse(e.WalletBefore)
+		after, okAfter := money.Parse(e.WalletAfter)
+		if okAfter {
+			balance = walletBalance{Amount: after, At: e.Timestamp}
+		} else if !balance.At.IsZero() {
+			balance.Stale = true
+		}
+		if !okBefore {
+			continue
+		}
+		if haveLast {
+			switch gap := before - lastAfter; {
+			case gap > 0:
+				events = append(events, walletEvent{At: e.Timestamp, Kind: "top-up", Amount: gap})
+			case gap < 0:
+				events = append(events, walletEvent{At: e.Timestamp, Kind: "unmatched-debit", Amount: -gap})
+			}
+		}
+		if !okAfter {
+			haveLast = false
+			continue
+		}
+		debit := walletEvent{At: e.Timestamp, Kind: "debit", Amount: before - after, OrderID: e.OrderID}
+		if total, ok := money.Parse(e.Total); ok && total != debit.Amount {
+			debit.Note = fmt.Sprintf("debit differs from order total %s", total)
+		}
+		events = append(events, debit)
+		lastAfter = after
+		haveLast = true
+	}
+	return events, balance, orders
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"bislericli/internal/money"
+	"bislericli/internal/store"
+)
+
+func TestReconcileWalletBalance(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 10, 0, 0, 0, time.UTC) }
+	entries := []store.AuditEntry{
+		{Timestamp: day(1), Result: "success", OrderID: "A1", WalletBefore: "₹1,000", WalletAfter: "₹800", Total: "₹200"},
+		{Timestamp: day(8), Result: "success", OrderID: "A2", WalletBefore: "₹800", WalletAfter: "₹600", Total: "₹200"},
+	}
+	_, balance, _ := reconcileWallet(entries)
+	if balance.Amount != money.FromFloat(600) || !balance.At.Equal(day(8)) || balance.Stale {
+		t.Fatalf("balance = %+v, want ₹600 on day 8", balance)
+	}
+
+	entries = append(entries, store.AuditEntry{Timestamp: day(15), Result: "success", OrderID: "A3", WalletBefore: "₹600", Total: "₹200"})
+	_, balance, _ = reconcileWallet(entries)
+	if balance.Amount != money.FromFloat(600) || !balance.At.Equal(day(8)) || !balance.Stale {
+		t.Fatalf("balance = %+v, want stale ₹600 from day 8", balance)
+	}
+}
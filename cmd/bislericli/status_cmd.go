@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"bislericli/internal/bisleri"
+	"bislericli/internal/config"
+	"bislericli/internal/format"
+	"bislericli/internal/store"
+)
+
+// kioskLiveEvery spaces out the session and cart checks of a refreshing
+// status screen, so a wall display does not hit the site every refresh.
+const kioskLiveEvery = 15 * time.Minute
+
+func runStatus(args []string) error {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	profileName := fs.String("profile", "", "Profile name (default: current/default)")
+	offline := fs.Bool("offline", false, "Skip the session check and cart lookup")
+	refresh := fs.Int("refresh", 0, "Redraw every N seconds until interrupted, for a wall display (0 to print once)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *refresh < 0 || *refresh > 0 && *refresh < 5 {
+		return fmt.Errorf("--refresh must be at least 5 seconds, got %d", *refresh)
+	}
+	if *refresh == 0 {
+		return printStatus(*profileName, !*offline, nil)
+	}
+
+	lc, end := startLifecycle()
+	defer end()
+	ctx := lc.Context()
+	live := &kioskLive{}
+	for {
+		// Clear the screen and move to the top left.
+		fmt.Print("\033[H\033[2J")
+		if err := printStatus(*profileName, !*offline, live); err != nil {
+			fmt.Println("Error:", err)
+		}
+		fmt.Printf("\nUpdated %s; refreshes every %ds. Ctrl+C to exit.\n", time.Now().Format("15:04:05"), *refresh)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Duration(*refresh) * time.Second):
+		}
+	}
+}
+
+// kioskLive keeps the last live check of a refreshing status screen.
+type kioskLive struct {
+	session, cart string
+	checked       time.Time
+}
+
+// printStatus prints the status screen. Config and profile are read each time
+// so a refreshing screen follows changes made by other commands. live, when
+// not nil, reuses a live check younger than kioskLiveEvery.
+func printStatus(profileName string, checkLive bool, live *kioskLive) error {
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	name := resolveProfileName(profileName, cfg)
+	profile, _, err := loadOrCreateProfile(name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(format.KeyValue("Profile", name))
+	fmt.Println(format.KeyValue("Last login", format.Timestamp(profile.LastLogin)))
+
+	session := "unknown (offline)"
+	cart := "-"
+	switch {
+	case len(profile.Cookies) == 0:
+		session = "not logged in (run 'bislericli auth login')"
+	case !checkLive:
+	case live == nil:
+		session, cart = liveStatus(cfg, profile)
+	default:
+		if time.Since(live.checked) >= kioskLiveEvery {
+			live.session, live.cart = liveStatus(cfg, profile)
+			live.checked = time.Now()
+		}
+		session, cart = live.session, live.cart
+	}
+	fmt.Println(format.KeyValue("Session", session))
+	fmt.Println(format.KeyValue("Wallet balance", lastSeenWalletBalance(name)))
+	fmt.Println(format.KeyValue("Jars remaining", jarsRemainingSummary(cfg, name)))
+	fmt.Println(format.KeyValue("Last order", lastOrderSummary(name, profile)))
+	fmt.Println(format.KeyValue("Next scheduled run", nextRunSummary(cfg, profile)))
+	fmt.Println(format.KeyValue("Cart", cart))
+	return nil
+}
+
+// liveStatus checks the session and reads the cart. A cart that loads
+// proves the login, so the separate check only runs when it does not.
+func liveStatus(cfg config.GlobalConfig, profile store.Profile) (state, cart string) {
+	sess, err := newSession(cfg, &profile, "", 20*time.Second)
+	if err != nil {
+		return "error: " + err.Error(), "-"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	page, cartErr := sess.cart(ctx)
+	switch {
+	case cartErr == nil:
+		return "valid", describeCart(page.Items)
+	case errors.Is(cartErr, bisleri.ErrNotAuthenticated):
+		return "expired (run 'bislericli auth login')", "-"
+	}
+	if err := sess.verify(ctx); err != nil {
+		if errors.Is(err, bisleri.ErrNotAuthenticated) {
+			return "expired (run 'bislericli auth login')", "-"
+		}
+		return "unknown (" + err.Error() + ")", "-"
+	}
+	return "valid", "unavailable (" + cartErr.Error() + ")"
+}
+
+func describeCart(items []bisleri.CartItem) string {
+	if len(items) == 0 {
+		return "empty"
+	}
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		label := item.ProductID
+		if strings.EqualFold(item.ProductID, productID20L) {
+			label = "20L jar"
+		}
+		parts = append(parts, fmt.Sprintf("%s x%d", label, item.Quantity))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// lastSeenWalletBalance reports the most recent balance recorded in the audit
+// log; the site only shows the balance during checkout.
+func lastSeenWalletBalance(profileName string) string {
+	balance, at, ok := lastWalletBalance(profileName)
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%s (as of %s)", balance, at.Format("2006-01-02 15:04"))
+}
+
+// lastWalletBalance returns the most recent balance in the audit log and when
+// it was seen.
+func lastWalletBalance(profileName string) (balance string, at time.Time, ok bool) {
+	entries, err := store.LoadAuditLog(profileName)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		balance := e.WalletAfter
+		if balance == "" {
+			balance = e.WalletBefore
+		}
+		if balance != "" {
+			return balance, e.Timestamp, true
+		}
+	}
+	return "", time.Time{}, false
+}
+
+// lastOrderSummary prefers synced history (which has the delivery status) and
+// falls back to the order recorded on the profile.
+func lastOrderSummary(profileName string, profile store.Profile) string {
+	if history, err := store.LoadOrderHistory(profileName); err == nil && len(history.Orders) > 0 {
+		latest := history.Orders[0]
+		for _, o := range history.Orders[1:] {
+			if o.ParsedDate.After(latest.ParsedDate) {
+				latest = o
+			}
+		}
+		date := bisleri.FormatOrderDate(latest.Date)
+		if date == "" {
+			date = latest.Date
+		}
+		summary := fmt.Sprintf("%s on %s, %s", latest.OrderID, date, dashIfEmpty(latest.Status))
+		if profile.LastOrder != nil && profile.LastOrder.PlacedAt.After(history.LastSynced) && profile.LastOrder.OrderID != latest.OrderID {
+			summary = fmt.Sprintf("%s on %s (placed after last sync)", profile.LastOrder.OrderID, profile.LastOrder.PlacedAt.Format("2006-01-02"))
+		}
+		if profile.LastOrder != nil && profile.LastOrder.OrderID == latest.OrderID && profile.LastOrder.DeliveryETA != "" {
+			summary += ", ETA " + profile.LastOrder.DeliveryETA
+		}
+		return summary
+	}
+	if profile.LastOrder != nil {
+		summary := fmt.Sprintf("%s on %s", profile.LastOrder.OrderID, profile.LastOrder.PlacedAt.Format("2006-01-02"))
+		if profile.LastOrder.DeliveryETA != "" {
+			summary += ", ETA " + profile.LastOrder.DeliveryETA
+		}
+		return summary
+	}
+	return "none"
+}
+
+func nextRunSummary(cfg config.GlobalConfig, profile store.Profile) string {
+	plan, err := loadSchedulePlan(cfg, profile)
+	if err != nil {
+		return "invalid schedule (" + err.Error() + ")"
+	}
+	next, ok := nextActiveRun(plan, profile.Schedule, time.Now())
+	if !ok {
+		return "none"
+	}
+	return next.Format("Mon 2006-01-02 15:04")
+}
@@ -13,18 +13,30 @@ import (
 
 	"bislericli/internal/bisleri"
 	"bislericli/internal/config"
+	"bislericli/internal/format"
+	"bislericli/internal/i18n"
+	"bislericli/internal/money"
+	"bislericli/internal/notify"
 	"bislericli/internal/store"
 )
 
 func runSync(args []string) error {
 	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
 	profileName := fs.String("profile", "", "Profile name (default: current/default)")
+	quiet := fs.Bool("quiet", false, "Print nothing on success; warnings still go to stderr")
+	watch := fs.Bool("watch", false, "Keep re-syncing and print new orders and status changes until interrupted")
+	interval := fs.Duration("interval", 5*time.Minute, "Time between syncs with --watch (minimum 1m)")
+	notifyChanges := fs.Bool("notify", false, "With --watch, show desktop notifications for changes (default: notifications.desktop in config)")
+	rateStars := fs.Int("rate", 0, "With --watch, rate orders 1-5 stars as they are delivered (0: off)")
+	rateComment := fs.String("rate-comment", "", "Feedback comment sent with --rate")
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
 			return nil
 		}
 		return err
 	}
+	defer setQuiet(*quiet)()
+	summary.begin("sync")
 
 	cfg, err := config.LoadGlobalConfig()
 	if err != nil {
@@ -32,13 +44,14 @@ func runSync(args []string) error {
 	}
 
 	name := resolveProfileName(*profileName, cfg)
+	summary.setProfile(name)
 	profile, _, err := loadOrCreateProfile(name)
 	if err != nil {
 		return err
 	}
 
 	if len(profile.Cookies) == 0 {
-		return errors.New("no cookies in profile; run 'bislericli auth login'")
+		return errors.New(i18n.T("no cookies in profile; run 'bislericli auth login'"))
 	}
 
 	jar, err := bisleri.JarFromCookies(profile.Cookies)
@@ -46,73 +59,246 @@ func runSync(args []string) error {
 		return err
 	}
 
-	client := bisleri.NewClient(&http.Client{Jar: jar, Timeout: 30 * time.Second}, log.New(os.Stderr, "bisleri: ", log.LstdFlags))
+	client := bisleri.NewClient(&http.Client{Jar: jar, Timeout: 30 * time.Second}, log.New(profileRedactor(cfg, profile).Writer(os.Stderr), "bisleri: ", log.LstdFlags))
+	if *watch {
+		if *interval < time.Minute {
+			return errors.New("--interval must be at least 1m")
+		}
+		if *rateStars < 0 || *rateStars > 5 {
+			return fmt.Errorf("--rate must be between 1 and 5, got %d", *rateStars)
+		}
+		notifications := cfg.Notifications
+		notifications.Desktop = *notifyChanges || cfg.Notifications.Desktop
+		return watchOrders(client, name, *interval, notifications, autoRating{Stars: *rateStars, Comment: strings.TrimSpace(*rateComment)})
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	fmt.Printf("Syncing orders for profile '%s'...\n", name)
+	progressf("Syncing orders for profile '%s'...\n", name)
+	if _, err := syncOrders(ctx, client, name); err != nil {
+		return err
+	}
+
+	progressln(format.Check(), "Sync complete.")
+	if cfg.Sheets.AfterSync {
+		if err := exportProfileToSheets(ctx, cfg.Sheets, name, "month"); err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: Google Sheets export failed:", err)
+		} else {
+			progressln(format.Check(), "Exported stats to Google Sheets.")
+		}
+	}
+	return nil
+}
+
+// syncOrders fetches /my-orders and replaces the profile's local order
+// history with it, returning the number of orders found.
+func syncOrders(ctx context.Context, client *bisleri.Client, name string) (int, error) {
+	savedOrders, diag, err := fetchOrders(ctx, client)
+	if err != nil {
+		return 0, err
+	}
+	progressf("Found %d orders on server.\n", len(savedOrders))
+	if len(diag.Skipped) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: order history may be incomplete: %s; run 'bislericli doctor'\n", diag)
+	}
+
+	if err := store.SaveOrderHistory(name, savedOrders); err != nil {
+		return 0, fmt.Errorf("failed to save history: %w", err)
+	}
+	recordDeliveries(name, savedOrders)
+	if cfg, err := config.LoadGlobalConfig(); err == nil {
+		notifyHooks(cfg.Hooks.PostSync, "post-sync", name, map[string]int{"orders": len(savedOrders)})
+	}
+	return len(savedOrders), nil
+}
+
+// historyWorkers is how many order-history pages sync fetches at once.
+const historyWorkers = 4
 
-	// Fetch orders
+// fetchOrders loads and parses /my-orders, and any further history pages it
+// links to, into the stored order format. The diagnostics cover every page.
+func fetchOrders(ctx context.Context, client *bisleri.Client) ([]store.SavedOrder, bisleri.OrderDiagnostics, error) {
+	var diag bisleri.OrderDiagnostics
 	ordersHTML, resp, err := client.FetchPage(ctx, "/my-orders")
 	if err != nil {
-		return fmt.Errorf("failed to fetch orders: %w", err)
+		return nil, diag, fmt.Errorf("failed to fetch orders: %w", err)
 	}
 	// Check auth
 	if resp != nil && resp.Request != nil && resp.Request.URL != nil {
 		if !strings.Contains(resp.Request.URL.Path, "/my-orders") {
-			return errors.New("session expired; please run 'bislericli auth login'")
+			return nil, diag, sessionExpiredError{}
 		}
 	}
 
-	parsedOrders, err := bisleri.ParseOrders(ordersHTML)
+	orders, diag, err := parseSavedOrders(ordersHTML)
 	if err != nil {
-		return fmt.Errorf("failed to parse orders: %w", err)
+		return nil, diag, err
+	}
+	var shown bool
+	pages, err := client.FetchOrderPages(ctx, ordersHTML, historyWorkers, func(done, total int) {
+		shown = true
+		progressf("\rFetching order history %s", format.ProgressBar(done, total, 20))
+	})
+	if shown {
+		progressln()
+	}
+	if err != nil {
+		return nil, diag, fmt.Errorf("failed to fetch orders: %w", err)
+	}
+	for _, page := range pages {
+		more, pageDiag, err := parseSavedOrders(page)
+		if err != nil {
+			return nil, diag, err
+		}
+		diag.Add(pageDiag)
+		orders = mergeSavedOrders(orders, more)
+	}
+	return orders, diag, nil
+}
+
+// mergeSavedOrders appends the orders from more that are not already listed.
+func mergeSavedOrders(orders, more []store.SavedOrder) []store.SavedOrder {
+	seen := make(map[string]bool, len(orders))
+	for _, o := range orders {
+		seen[o.OrderID] = true
+	}
+	for _, o := range more {
+		if !seen[o.OrderID] {
+			seen[o.OrderID] = true
+			orders = append(orders, o)
+		}
 	}
+	return orders
+}
 
-	fmt.Printf("Found %d orders on server.\n", len(parsedOrders))
+// parseSavedOrders parses a /my-orders page into the stored order format.
+func parseSavedOrders(ordersHTML string) ([]store.SavedOrder, bisleri.OrderDiagnostics, error) {
+	parsedOrders, diag, err := bisleri.ParseOrdersWithDiagnostics(ordersHTML)
+	if err != nil {
+		noteParseFailure("orders", "my-orders", ordersHTML)
+		return nil, diag, withUpgradeHint(fmt.Errorf("failed to parse orders: %w", err))
+	}
+	if len(diag.Skipped) > 0 {
+		noteParseFailure("orders", "my-orders", ordersHTML)
+	}
 
 	// Convert to store format
 	var savedOrders []store.SavedOrder
 	for _, o := range parsedOrders {
-		amount, _ := bisleri.ParseINRAmount(o.Total)
-
-		// Parse date for sorting/stats
-		// Format seen: "05/01/2026, 11:49 AM"
-		cleanedDate := strings.Split(o.Date, ",")[0] // Take part before comma "05/01/2026"
-		cleanedDate = strings.TrimSpace(cleanedDate)
+		amount, _ := money.Parse(o.Total)
 
-		t, err := time.Parse("02/01/2006", cleanedDate)
-		if err != nil {
-			// Try with time if split didn't work or different format
-			formats := []string{
-				"02/01/2006, 03:04 PM",
-				"02/01/2006 03:04 PM",
-				"January 02, 2006",
-				"Jan 02, 2006",
-			}
-			for _, f := range formats {
-				if parsed, err := time.Parse(f, o.Date); err == nil {
-					t = parsed
-					break
-				}
-			}
-		}
+		// Parse date for sorting/stats; format seen: "05/01/2026, 11:49 AM"
+		t, _ := bisleri.ParseDate(o.Date, time.Local)
 
 		savedOrders = append(savedOrders, store.SavedOrder{
-			OrderID:    o.OrderID,
-			Date:       o.Date,
-			ParsedDate: t,
-			Status:     o.Status,
-			Total:      o.Total,
-			Amount:     amount,
-			Items:      o.Items,
+			OrderID:     o.OrderID,
+			Date:        o.Date,
+			ParsedDate:  t,
+			Status:      o.Status,
+			Total:       o.Total,
+			Amount:      amount,
+			Items:       o.Items,
+			DeliveredAt: parseDeliveredAt(o.Delivered),
 		})
 	}
 
-	if err := store.SaveOrderHistory(name, savedOrders); err != nil {
-		return fmt.Errorf("failed to save history: %w", err)
+	return savedOrders, diag, nil
+}
+
+// sessionExpiredError is bisleri.ErrNotAuthenticated in the output language.
+type sessionExpiredError struct{}
+
+func (sessionExpiredError) Error() string {
+	return i18n.T("session expired; please run 'bislericli auth login'")
+}
+
+func (sessionExpiredError) Unwrap() error { return bisleri.ErrNotAuthenticated }
+
+// orderChange is a difference between two syncs of the order history.
+type orderChange struct {
+	Order      store.SavedOrder
+	New        bool
+	FromStatus string
+}
+
+// diffOrders lists orders that are new in cur or whose status changed.
+func diffOrders(prev, cur []store.SavedOrder) []orderChange {
+	known := make(map[string]string, len(prev))
+	for _, o := range prev {
+		known[o.OrderID] = o.Status
+	}
+	var changes []orderChange
+	for _, o := range cur {
+		status, ok := known[o.OrderID]
+		switch {
+		case !ok:
+			changes = append(changes, orderChange{Order: o, New: true})
+		case !strings.EqualFold(status, o.Status):
+			changes = append(changes, orderChange{Order: o, FromStatus: status})
+		}
 	}
+	return changes
+}
 
-	fmt.Println("✓ Sync complete.")
-	return nil
+// watchOrders re-syncs every interval and prints new orders and status
+// changes (e.g. Processing to Out for Delivery) until interrupted, sending
+// them to the desktop and chats in notifications. With a rating set, orders
+// are rated as they turn delivered.
+func watchOrders(client *bisleri.Client, name string, interval time.Duration, notifications config.Notifications, rating autoRating) error {
+	lc, end := startLifecycle()
+	defer end()
+	ctx := lc.Context()
+
+	progressf("Watching orders for profile '%s' every %s (Ctrl+C to stop)...\n", name, interval)
+	for {
+		var prev []store.SavedOrder
+		if history, err := store.LoadOrderHistory(name); err == nil {
+			prev = history.Orders
+		}
+		syncCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+		cur, _, err := fetchOrders(syncCtx, client)
+		cancel()
+		switch {
+		case ctx.Err() != nil:
+			progressln("Watch stopped.")
+			return nil
+		case errors.Is(err, bisleri.ErrNotAuthenticated):
+			return err
+		case err != nil:
+			fmt.Fprintln(os.Stderr, "Warning: sync failed; will retry:", err)
+		default:
+			if err := store.SaveOrderHistory(name, cur); err != nil {
+				fmt.Fprintln(os.Stderr, "Warning: failed to save history:", err)
+			}
+			recordDeliveries(name, cur)
+			stamp := time.Now().Format("15:04")
+			changes := diffOrders(prev, cur)
+			for _, c := range changes {
+				if c.New {
+					fmt.Printf("[%s] New order %s: %s\n", stamp, c.Order.OrderID, dashIfEmpty(c.Order.Status))
+					sendNotification(notifications, notify.Message{
+						Event: notify.OrderNew,
+						Title: "New Bisleri order " + c.Order.OrderID,
+						Body:  dashIfEmpty(c.Order.Status),
+						Order: savedOrderNotice(c.Order, ""),
+					})
+				} else {
+					fmt.Printf("[%s] %s: %s %s %s\n", stamp, c.Order.OrderID, dashIfEmpty(c.FromStatus), format.Arrow(), dashIfEmpty(c.Order.Status))
+					sendNotification(notifications, notify.Message{
+						Event: notify.OrderStatus,
+						Title: "Bisleri order " + c.Order.OrderID,
+						Body:  dashIfEmpty(c.FromStatus) + " -> " + dashIfEmpty(c.Order.Status),
+						Order: savedOrderNotice(c.Order, c.FromStatus),
+					})
+				}
+			}
+			rateDelivered(ctx, client, changes, rating)
+		}
+		select {
+		case <-ctx.Done():
+			progressln("Watch stopped.")
+			return nil
+		case <-time.After(interval):
+		}
+	}
 }
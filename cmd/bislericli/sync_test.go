@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"bislericli/internal/store"
+)
+
+func TestDiffOrders(t *testing.T) {
+	prev := []store.SavedOrder{
+		{OrderID: "BS-1", Status: "Delivered"},
+		{OrderID: "BS-2", Status: "Processing"},
+	}
+	cur := []store.SavedOrder{
+		{OrderID: "BS-1", Status: "Delivered"},
+		{OrderID: "BS-2", Status: "Out for Delivery"},
+		{OrderID: "BS-3", Status: "Processing"},
+	}
+	changes := diffOrders(prev, cur)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if c := changes[0]; c.New || c.Order.OrderID != "BS-2" || c.FromStatus != "Processing" {
+		t.Fatalf("unexpected status change: %+v", c)
+	}
+	if c := changes[1]; !c.New || c.Order.OrderID != "BS-3" {
+		t.Fatalf("unexpected new order: %+v", c)
+	}
+}
+
+func TestIsDelivered(t *testing.T) {
+	for status, want := range map[string]bool{
+		"Delivered":        true,
+		"Order delivered":  true,
+		"Out for Delivery": false,
+		"Not Delivered":    false,
+		"Processing":       false,
+	} {
+		if got := isDelivered(status); got != want {
+			t.Fatalf("isDelivered(%q) = %v, want %v", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"bislericli/internal/i18n"
+	"bislericli/internal/money"
+	"bislericli/internal/store"
+)
+
+const untagged = "(untagged)"
+
+type tagStats struct {
+	Tag   string
+	Count int
+	Total money.Money
+}
+
+// orderTags maps order IDs to the --for tag recorded when they were placed.
+func orderTags(entries []store.AuditEntry, last *store.OrderInfo) map[string]string {
+	tags := make(map[string]string)
+	for _, e := range entries {
+		if e.OrderID != "" && e.Tag != "" {
+			tags[e.OrderID] = e.Tag
+		}
+	}
+	if last != nil && last.OrderID != "" && last.Tag != "" {
+		tags[last.OrderID] = last.Tag
+	}
+	return tags
+}
+
+// tagTotals groups orders by tag, largest spend first. Orders placed on the
+// website or before tagging count as untagged.
+func tagTotals(orders []store.SavedOrder, tags map[string]string) []tagStats {
+	byTag := make(map[string]*tagStats)
+	for _, o := range orders {
+		tag := tags[o.OrderID]
+		if tag == "" {
+			tag = untagged
+		}
+		key := strings.ToLower(tag)
+		if byTag[key] == nil {
+			byTag[key] = &tagStats{Tag: tag}
+		}
+		byTag[key].Count++
+		byTag[key].Total += o.Amount
+	}
+	result := make([]tagStats, 0, len(byTag))
+	for _, s := range byTag {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		return result[i].Tag < result[j].Tag
+	})
+	return result
+}
+
+func printTagStats(orders []store.SavedOrder, tags map[string]string) {
+	stats := tagTotals(orders, tags)
+	var grand money.Money
+	for _, s := range stats {
+		grand += s.Total
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Println()
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", i18n.T("Tag"), i18n.T("Orders"), i18n.T("Total"), i18n.T("Share"))
+	for _, s := range stats {
+		share := 0.0
+		if grand > 0 {
+			share = float64(s.Total) / float64(grand) * 100
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%.1f%%\n", s.Tag, s.Count, s.Total, share)
+	}
+	w.Flush()
+}
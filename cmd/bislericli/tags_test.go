@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"bislericli/internal/money"
+	"bislericli/internal/store"
+)
+
+func TestTagTotals(t *testing.T) {
+	rupees := func(s string) money.Money {
+		m, _ := money.Parse(s)
+		return m
+	}
+	entries := []store.AuditEntry{
+		{OrderID: "BS-1", Tag: "office"},
+		{OrderID: "BS-2", Tag: "Home"},
+		{Result: "failure", Tag: "office"},
+	}
+	last := &store.OrderInfo{OrderID: "BS-4", Tag: "home"}
+	orders := []store.SavedOrder{
+		{OrderID: "BS-1", Amount: rupees("400")},
+		{OrderID: "BS-2", Amount: rupees("200")},
+		{OrderID: "BS-3", Amount: rupees("100")},
+		{OrderID: "BS-4", Amount: rupees("300")},
+	}
+	got := tagTotals(orders, orderTags(entries, last))
+	if len(got) != 3 {
+		t.Fatalf("expected 3 tags, got %+v", got)
+	}
+	if got[0].Tag != "Home" || got[0].Count != 2 || got[0].Total != rupees("500") {
+		t.Fatalf("unexpected first tag: %+v", got[0])
+	}
+	if got[1].Tag != "office" || got[2].Tag != untagged {
+		t.Fatalf("unexpected order: %+v", got)
+	}
+}
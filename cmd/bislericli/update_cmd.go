@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"bislericli/internal/update"
+)
+
+func runUpdate(args []string) error {
+	fs := flag.NewFlagSet("update", flag.ContinueOnError)
+	checkOnly := fs.Bool("check", false, "Only report whether a newer release is available")
+	force := fs.Bool("force", false, "Install the latest release even if it is not newer (or this is a dev build)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	fmt.Println("Checking for updates...")
+	release, err := update.LatestRelease(ctx)
+	if err != nil {
+		return err
+	}
+	newer := update.Newer(version, release.Version())
+	fmt.Println("Current version:", version)
+	fmt.Println("Latest release: ", release.TagName)
+
+	if *checkOnly {
+		if newer {
+			fmt.Println("A newer version is available. Run: bislericli update")
+		} else {
+			fmt.Println("You are up to date.")
+		}
+		return nil
+	}
+	if !newer && !*force {
+		fmt.Println("You are up to date.")
+		return nil
+	}
+	if version == "dev" && !*force {
+		return errors.New("this is a development build; pass --force to replace it with the latest release")
+	}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if update.ManagedByHomebrew(exePath) {
+		return errors.New("installed via Homebrew; run 'brew upgrade maheshrijal/tap/bislericli' instead")
+	}
+	fmt.Printf("Downloading %s...\n", update.ArchiveName(release.Version()))
+	if err := update.Apply(ctx, release, exePath); err != nil {
+		return fmt.Errorf("update failed: %w", err)
+	}
+	fmt.Println("Updated to", release.TagName)
+	return nil
+}
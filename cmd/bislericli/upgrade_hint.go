@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/update"
+)
+
+const releaseCheckTTL = 24 * time.Hour
+
+// withUpgradeHint annotates a parsing failure with a nudge to update when a
+// newer release exists, since site markup changes are usually fixed upstream.
+// The release check is cached and bounded so failures stay fast offline.
+func withUpgradeHint(err error) error {
+	if err == nil {
+		return nil
+	}
+	latest, ok := latestReleaseVersion()
+	if !ok || !update.Newer(version, latest) {
+		return err
+	}
+	return fmt.Errorf("%w\na newer version (v%s) may fix this; run 'bislericli update'", err, latest)
+}
+
+func latestReleaseVersion() (string, bool) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	latest, err := update.CachedLatestVersion(ctx, filepath.Join(dir, "release-check.json"), releaseCheckTTL)
+	if err != nil {
+		return "", false
+	}
+	return latest, true
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/notify"
+	"bislericli/internal/store"
+	"bislericli/internal/weather"
+)
+
+// Heat rule defaults, used when the weather section leaves them unset.
+const (
+	defaultHeatC     = 38.0
+	defaultHeatDays  = 2
+	defaultExtraJars = 1
+)
+
+// heatAdjustedQuantity applies the weather rule to a scheduled order of qty
+// jars. With action "bump" it returns the raised quantity; with "suggest" it
+// only notifies and returns qty. A forecast that cannot be fetched leaves the
+// order as it is.
+func heatAdjustedQuantity(cfg config.GlobalConfig, profile store.Profile, qty int) int {
+	w := cfg.Weather
+	if !w.Enabled {
+		return qty
+	}
+	lat, lon := w.Latitude, w.Longitude
+	if lat == "" && profile.Address != nil {
+		lat, lon = profile.Address.Latitude, profile.Address.Longitude
+	}
+	if lat == "" || lon == "" {
+		fmt.Fprintln(os.Stderr, "Warning: weather check skipped: no coordinates; set weather.latitude/longitude or geocode the address")
+		return qty
+	}
+	days := w.Days
+	if days <= 0 {
+		days = defaultHeatDays
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	forecast, err := weather.NewClient(w.Endpoint).Forecast(ctx, lat, lon, days)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: weather check failed:", err)
+		return qty
+	}
+	hottest, ok := weather.Hottest(forecast)
+	if !ok {
+		return qty
+	}
+	bumped, reason, hot := heatRule(w, cfg.Defaults.MaxQuantity, hottest, qty)
+	if !hot {
+		return qty
+	}
+	if w.Action == "bump" {
+		fmt.Printf("Heat: %s; ordering %d jar(s) instead of %d.\n", reason, bumped, qty)
+		return bumped
+	}
+	msg := fmt.Sprintf("%s; consider %d jar(s) instead of %d.", reason, bumped, qty)
+	fmt.Println("Heat:", msg)
+	sendNotification(cfg.Notifications, notify.Message{Event: notify.Heat, Title: "Hot days ahead", Body: msg})
+	return qty
+}
+
+// heatRule decides whether day is hot enough to need extra jars and how
+// many to order then, capped at maxQty when it is set.
+func heatRule(w config.Weather, maxQty int, day weather.Day, qty int) (bumped int, reason string, hot bool) {
+	threshold := w.HeatC
+	if threshold == 0 {
+		threshold = defaultHeatC
+	}
+	if day.MaxC < threshold {
+		return qty, "", false
+	}
+	extra := w.ExtraJars
+	if extra <= 0 {
+		extra = defaultExtraJars
+	}
+	bumped = qty + extra
+	if maxQty > 0 && bumped > maxQty {
+		bumped = max(qty, maxQty)
+	}
+	return bumped, fmt.Sprintf("%.0f°C forecast on %s (threshold %.0f°C)", day.MaxC, day.Date.Format("Mon 02 Jan"), threshold), true
+}
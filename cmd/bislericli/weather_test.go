@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/weather"
+)
+
+func TestHeatRule(t *testing.T) {
+	day := func(c float64) weather.Day {
+		return weather.Day{Date: time.Date(2026, 5, 21, 0, 0, 0, 0, time.UTC), MaxC: c}
+	}
+	for _, tc := range []struct {
+		name   string
+		w      config.Weather
+		maxQty int
+		temp   float64
+		qty    int
+		want   int
+		hot    bool
+	}{
+		{"below default threshold", config.Weather{}, 4, 37.9, 2, 2, false},
+		{"at default threshold", config.Weather{}, 4, 38, 2, 3, true},
+		{"custom threshold and extra", config.Weather{HeatC: 42, ExtraJars: 2}, 0, 43, 2, 4, true},
+		{"capped at max", config.Weather{ExtraJars: 3}, 4, 45, 2, 4, true},
+		{"already at max", config.Weather{}, 4, 45, 4, 4, true},
+	} {
+		got, _, hot := heatRule(tc.w, tc.maxQty, day(tc.temp), tc.qty)
+		if got != tc.want || hot != tc.hot {
+			t.Errorf("%s: heatRule = %d, %v; want %d, %v", tc.name, got, hot, tc.want, tc.hot)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package address
+
+import (
+	"regexp"
+	"strings"
+
+	"bislericli/internal/store"
+)
+
+// stateCodes maps lower-cased state and union territory names to ISO 3166-2:IN codes.
+var stateCodes = map[string]string{
+	"andaman and nicobar islands": "AN",
+	"andhra pradesh":              "AP",
+	"arunachal pradesh":           "AR",
+	"assam":                       "AS",
+	"bihar":                       "BR",
+	"chandigarh":                  "CH",
+	"chhattisgarh":                "CG",
+	"dadra and nagar haveli and daman and diu": "DH",
+	"delhi":             "DL",
+	"goa":               "GA",
+	"gujarat":           "GJ",
+	"haryana":           "HR",
+	"himachal pradesh":  "HP",
+	"jammu and kashmir": "JK",
+	"jharkhand":         "JH",
+	"karnataka":         "KA",
+	"kerala":            "KL",
+	"ladakh":            "LA",
+	"lakshadweep":       "LD",
+	"madhya pradesh":    "MP",
+	"maharashtra":       "MH",
+	"manipur":           "MN",
+	"meghalaya":         "ML",
+	"mizoram":           "MZ",
+	"nagaland":          "NL",
+	"odisha":            "OD",
+	"puducherry":        "PY",
+	"punjab":            "PB",
+	"rajasthan":         "RJ",
+	"sikkim":            "SK",
+	"tamil nadu":        "TN",
+	"telangana":         "TS",
+	"tripura":           "TR",
+	"uttar pradesh":     "UP",
+	"uttarakhand":       "UK",
+	"west bengal":       "WB",
+}
+
+// nameAliases covers common alternate spellings and former names.
+var nameAliases = map[string]string{
+	"new delhi":              "DL",
+	"nct of delhi":           "DL",
+	"orissa":                 "OD",
+	"pondicherry":            "PY",
+	"uttaranchal":            "UK",
+	"daman and diu":          "DH",
+	"dadra and nagar haveli": "DH",
+	"andaman":                "AN",
+	"j&k":                    "JK",
+	"jammu & kashmir":        "JK",
+	"tamilnadu":              "TN",
+}
+
+// codeAliases maps older or alternate two-letter codes to the canonical ones.
+var codeAliases = map[string]string{
+	"CT": "CG",
+	"OR": "OD",
+	"TG": "TS",
+	"UT": "UK",
+	"DN": "DH",
+	"DD": "DH",
+}
+
+var validCodes = func() map[string]bool {
+	codes := map[string]bool{}
+	for _, code := range stateCodes {
+		codes[code] = true
+	}
+	return codes
+}()
+
+// pincodePrefixes maps leading pincode digits to states. The longest matching
+// prefix wins, so enclaves inside a postal circle are listed down to the exact
+// pincodes they use; districts that straddle a border are left to the circle
+// and must be confirmed by the user.
+var pincodePrefixes = map[string]string{
+	"11": "DL",
+	"12": "HR", "13": "HR",
+	"14": "PB", "15": "PB", "16": "PB",
+	"16000": "CH", "16001": "CH", "16002": "CH", "16003": "CH", "16004": "CH",
+	"17": "HP",
+	"18": "JK", "19": "JK", "194": "LA",
+	"20": "UP", "21": "UP", "22": "UP", "23": "UP", "24": "UP", "25": "UP", "26": "UP", "27": "UP", "28": "UP",
+	"246": "UK", "247": "UK", "248": "UK", "249": "UK", "2625": "UK", "263": "UK",
+	"30": "RJ", "31": "RJ", "32": "RJ", "33": "RJ", "34": "RJ",
+	"36": "GJ", "37": "GJ", "38": "GJ", "39": "GJ",
+	"396210": "DH", "396215": "DH", "396220": "DH", "396230": "DH", "396235": "DH", "396240": "DH", "362520": "DH",
+	"40": "MH", "41": "MH", "42": "MH", "43": "MH", "44": "MH", "403": "GA",
+	"45": "MP", "46": "MP", "47": "MP", "48": "MP", "49": "CG",
+	"50": "TS",
+	"51": "AP", "52": "AP", "53": "AP", "533464": "PY",
+	"56": "KA", "57": "KA", "58": "KA", "59": "KA",
+	"60": "TN", "61": "TN", "62": "TN", "63": "TN", "64": "TN", "6050": "PY",
+	"67": "KL", "68": "KL", "69": "KL", "68255": "LD", "673310": "PY",
+	"70": "WB", "71": "WB", "72": "WB", "73": "WB", "74": "WB", "737": "SK", "744": "AN",
+	"75": "OD", "76": "OD", "77": "OD",
+	"78":  "AS",
+	"790": "AR", "791": "AR", "792": "AR", "793": "ML", "794": "ML", "795": "MN", "796": "MZ", "797": "NL", "798": "NL", "799": "TR",
+	"80": "BR", "81": "BR", "82": "JH", "83": "JH", "84": "BR", "85": "BR",
+	"814": "JH", "815": "JH", "816": "JH", "821": "BR", "823": "BR", "824": "BR",
+}
+
+var (
+	pincodeRegex    = regexp.MustCompile(`^[1-9][0-9]{5}$`)
+	inlineCodeRegex = regexp.MustCompile(`\b[A-Z]{2}\b`)
+)
+
+// NormalizeState converts a state name or code into a canonical two-letter code.
+func NormalizeState(value string) (string, bool) {
+	trimmed := strings.TrimSpace(strings.Trim(value, ",."))
+	if trimmed == "" {
+		return "", false
+	}
+	upper := strings.ToUpper(trimmed)
+	if validCodes[upper] {
+		return upper, true
+	}
+	if code, ok := codeAliases[upper]; ok {
+		return code, true
+	}
+	lower := strings.Join(strings.Fields(strings.ToLower(trimmed)), " ")
+	if code, ok := stateCodes[lower]; ok {
+		return code, true
+	}
+	if code, ok := nameAliases[lower]; ok {
+		return code, true
+	}
+	if code, ok := stateCodes[strings.ReplaceAll(lower, "&", "and")]; ok {
+		return code, true
+	}
+	return "", false
+}
+
+// ValidPincode reports whether pin is a well-formed six-digit Indian pincode.
+func ValidPincode(pin string) bool {
+	return pincodeRegex.MatchString(strings.TrimSpace(pin))
+}
+
+// StateForPincode guesses the state code for a pincode from its postal
+// circle. Circles do not follow state borders everywhere, so the guess must be
+// shown to the user rather than saved silently.
+func StateForPincode(pin string) (string, bool) {
+	pin = strings.TrimSpace(pin)
+	if !ValidPincode(pin) {
+		return "", false
+	}
+	for n := len(pin); n >= 2; n-- {
+		if code, ok := pincodePrefixes[pin[:n]]; ok {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// NormalizeStateCode fills or canonicalizes addr.StateCode, using in order the
+// existing value and state names or codes found in the address lines. It does
+// not guess from the pincode; see StateForPincode.
+func NormalizeStateCode(addr *store.Address) {
+	if addr == nil {
+		return
+	}
+	if code, ok := NormalizeState(addr.StateCode); ok {
+		addr.StateCode = code
+		return
+	}
+	text := addr.Address1 + " " + addr.Address2
+	lower := strings.ToLower(text)
+	best := ""
+	for name, code := range stateCodes {
+		if len(name) > len(best) && strings.Contains(lower, name) {
+			best = name
+			addr.StateCode = code
+		}
+	}
+	if best != "" {
+		return
+	}
+	for _, m := range inlineCodeRegex.FindAllString(text, -1) {
+		if m == "IN" {
+			continue
+		}
+		if code, ok := NormalizeState(m); ok {
+			addr.StateCode = code
+			return
+		}
+	}
+}
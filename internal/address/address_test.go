@@ -0,0 +1,96 @@
+package address
+
+import (
+	"testing"
+
+	"bislericli/internal/store"
+)
+
+func TestNormalizeState(t *testing.T) {
+	cases := map[string]string{
+		"KA":              "KA",
+		"ka":              "KA",
+		"Karnataka":       "KA",
+		" tamil  nadu ":   "TN",
+		"Orissa":          "OD",
+		"OR":              "OD",
+		"TG":              "TS",
+		"Jammu & Kashmir": "JK",
+		"NCT of Delhi":    "DL",
+	}
+	for in, want := range cases {
+		if got, ok := NormalizeState(in); !ok || got != want {
+			t.Errorf("NormalizeState(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+	if _, ok := NormalizeState("Atlantis"); ok {
+		t.Errorf("expected unknown state to fail")
+	}
+}
+
+func TestStateForPincode(t *testing.T) {
+	tests := []struct {
+		pin, place, want string
+	}{
+		{"560103", "Bengaluru", "KA"},
+		{"110001", "New Delhi", "DL"},
+		{"400001", "Mumbai", "MH"},
+		{"403001", "Panaji", "GA"},
+		{"500081", "Hyderabad", "TS"},
+		{"160017", "Chandigarh", "CH"},
+		{"160055", "Mohali", "PB"},
+		{"160062", "Mohali", "PB"},
+		{"605001", "Puducherry", "PY"},
+		{"605602", "Villupuram", "TN"},
+		{"673310", "Mahe", "PY"},
+		{"533464", "Yanam", "PY"},
+		{"682001", "Kochi", "KL"},
+		{"682030", "Kakkanad", "KL"},
+		{"682555", "Kavaratti", "LD"},
+		{"396001", "Valsad", "GJ"},
+		{"396191", "Vapi", "GJ"},
+		{"396230", "Silvassa", "DH"},
+		{"396210", "Daman", "DH"},
+		{"362520", "Diu", "DH"},
+		{"800001", "Patna", "BR"},
+		{"823001", "Gaya", "BR"},
+		{"826001", "Dhanbad", "JH"},
+		{"827001", "Bokaro", "JH"},
+		{"825301", "Hazaribagh", "JH"},
+		{"834001", "Ranchi", "JH"},
+		{"248001", "Dehradun", "UK"},
+		{"263001", "Nainital", "UK"},
+		{"262501", "Pithoragarh", "UK"},
+		{"262001", "Pilibhit", "UP"},
+		{"262701", "Lakhimpur", "UP"},
+	}
+	for _, tt := range tests {
+		if got, ok := StateForPincode(tt.pin); !ok || got != tt.want {
+			t.Errorf("StateForPincode(%s, %s) = %q, %v; want %q", tt.pin, tt.place, got, ok, tt.want)
+		}
+	}
+	for _, bad := range []string{"012345", "56010", "5601034", "abcdef"} {
+		if ValidPincode(bad) {
+			t.Errorf("ValidPincode(%q) = true; want false", bad)
+		}
+	}
+}
+
+func TestNormalizeStateCodeFallbacks(t *testing.T) {
+	addr := store.Address{Address1: "12 MG Road, Bengaluru, Karnataka"}
+	NormalizeStateCode(&addr)
+	if addr.StateCode != "KA" {
+		t.Fatalf("expected KA from address text, got %q", addr.StateCode)
+	}
+	// A state named in the address wins; the pincode is never guessed from.
+	addr = store.Address{Address1: "Tithal Road, Valsad, Gujarat", PostalCode: "396001"}
+	NormalizeStateCode(&addr)
+	if addr.StateCode != "GJ" {
+		t.Fatalf("expected GJ from address text, got %q", addr.StateCode)
+	}
+	addr = store.Address{Address1: "Ravipuram", PostalCode: "682016"}
+	NormalizeStateCode(&addr)
+	if addr.StateCode != "" {
+		t.Fatalf("expected no state without one in the address, got %q", addr.StateCode)
+	}
+}
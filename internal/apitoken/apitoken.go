@@ -0,0 +1,88 @@
+// Package apitoken creates and checks the bearer tokens serve mode's API
+// accepts. A token carries no identity beyond its name; what it may do is
+// set by its scopes.
+package apitoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"bislericli/internal/config"
+)
+
+// Scopes a token can hold.
+const (
+	// ScopeRead allows reading status, history and jobs.
+	ScopeRead = "read"
+	// ScopeOrder allows placing orders.
+	ScopeOrder = "order"
+)
+
+// Scopes lists the valid scopes.
+var Scopes = []string{ScopeRead, ScopeOrder}
+
+const prefix = "bsl_"
+
+// New returns a random token and the hash to store for it.
+func New() (token, hash string, err error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = prefix + hex.EncodeToString(b)
+	return token, Hash(token), nil
+}
+
+// Hash returns the stored form of a token.
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseScopes splits a comma-separated scope list, rejecting unknown scopes.
+func ParseScopes(raw string) ([]string, error) {
+	var scopes []string
+	for _, s := range strings.Split(raw, ",") {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s == "" || HasScope(config.APIToken{Scopes: scopes}, s) {
+			continue
+		}
+		if s != ScopeRead && s != ScopeOrder {
+			return nil, fmt.Errorf("unknown scope %q (want %s)", s, strings.Join(Scopes, ", "))
+		}
+		scopes = append(scopes, s)
+	}
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("no scopes given (want %s)", strings.Join(Scopes, ", "))
+	}
+	return scopes, nil
+}
+
+// Match returns the name and entry of the stored token that token is,
+// comparing hashes in constant time.
+func Match(tokens map[string]config.APIToken, token string) (string, config.APIToken, bool) {
+	if !strings.HasPrefix(token, prefix) {
+		return "", config.APIToken{}, false
+	}
+	hash := []byte(Hash(token))
+	for name, t := range tokens {
+		if subtle.ConstantTimeCompare(hash, []byte(t.Hash)) == 1 {
+			return name, t, true
+		}
+	}
+	return "", config.APIToken{}, false
+}
+
+// HasScope reports whether t holds scope.
+func HasScope(t config.APIToken, scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
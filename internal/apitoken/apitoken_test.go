@@ -0,0 +1,48 @@
+package apitoken
+
+import (
+	"strings"
+	"testing"
+
+	"bislericli/internal/config"
+)
+
+func TestNewAndMatch(t *testing.T) {
+	token, hash, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if !strings.HasPrefix(token, prefix) || strings.Contains(hash, token) || hash != Hash(token) {
+		t.Fatalf("New = %q, %q", token, hash)
+	}
+	other, otherHash, _ := New()
+	if other == token {
+		t.Fatal("New returned the same token twice")
+	}
+	stored := map[string]config.APIToken{
+		"dashboard":  {Hash: otherHash, Scopes: []string{ScopeRead}},
+		"automation": {Hash: hash, Scopes: []string{ScopeOrder, ScopeRead}},
+	}
+	if name, _, ok := Match(stored, token); !ok || name != "automation" {
+		t.Errorf("Match = %q, %v; want automation", name, ok)
+	}
+	for _, bad := range []string{"", hash, prefix + "00", token + "0"} {
+		if name, _, ok := Match(stored, bad); ok {
+			t.Errorf("Match(%q) = %q, want no match", bad, name)
+		}
+	}
+	if !HasScope(stored["automation"], ScopeOrder) || HasScope(stored["dashboard"], ScopeOrder) {
+		t.Error("HasScope did not follow the token's scopes")
+	}
+}
+
+func TestParseScopes(t *testing.T) {
+	if got, err := ParseScopes(" Order, read,order "); err != nil || strings.Join(got, ",") != "order,read" {
+		t.Errorf("ParseScopes = %v, %v; want [order read]", got, err)
+	}
+	for _, bad := range []string{"", "admin", "read,write"} {
+		if _, err := ParseScopes(bad); err == nil {
+			t.Errorf("ParseScopes(%q) succeeded", bad)
+		}
+	}
+}
@@ -35,6 +35,7 @@ var (
 	sendOTPFn       = sendOTP
 	verifyOTPFn     = verifyOTP
 	verifyCookiesFn = verifyCookies
+	otpWaitFn       = countdown
 )
 
 func Login(ctx context.Context) ([]store.Cookie, error) {
@@ -83,8 +84,15 @@ func Login(ctx context.Context) ([]store.Cookie, error) {
 }
 
 // LoginWithOTP performs a terminal-based login using phone number and OTP.
-// This is the primary login method that doesn't require a browser.
-func LoginWithOTP(ctx context.Context, phoneNumber string) ([]store.Cookie, error) {
+// This is the primary login method that doesn't require a browser. sends
+// holds earlier OTP requests for the number and may be nil.
+func LoginWithOTP(ctx context.Context, phoneNumber string, sends *OTPLog) ([]store.Cookie, error) {
+	return LoginWithOTPFrom(ctx, phoneNumber, sends, os.Stdin, os.Stdout)
+}
+
+// LoginWithOTPFrom is LoginWithOTP reading the OTP, one per line, from input
+// instead of the terminal, such as codes relayed from a phone.
+func LoginWithOTPFrom(ctx context.Context, phoneNumber string, sends *OTPLog, input io.Reader, output io.Writer) ([]store.Cookie, error) {
 	// Create HTTP client with cookie jar
 	jar, err := cookiejar.New(nil)
 	if err != nil {
@@ -94,10 +102,14 @@ func LoginWithOTP(ctx context.Context, phoneNumber string) ([]store.Cookie, erro
 		Jar:     jar,
 		Timeout: 30 * time.Second,
 	}
-	return loginWithOTPClient(ctx, client, phoneNumber, os.Stdin, os.Stdout)
+	return loginWithOTPClient(ctx, client, phoneNumber, sends, input, output)
 }
 
-func loginWithOTPClient(ctx context.Context, client *http.Client, phoneNumber string, input io.Reader, output io.Writer) ([]store.Cookie, error) {
+func loginWithOTPClient(ctx context.Context, client *http.Client, phoneNumber string, sends *OTPLog, input io.Reader, output io.Writer) ([]store.Cookie, error) {
+	if sends == nil {
+		sends = &OTPLog{}
+	}
+
 	// Step 1: Get initial session and CSRF token
 	fmt.Fprintln(output, "Connecting to Bisleri...")
 	csrfToken, err := getCSRFTokenFn(ctx, client)
@@ -105,9 +117,27 @@ func loginWithOTPClient(ctx context.Context, client *http.Client, phoneNumber st
 		return nil, fmt.Errorf("failed to get session: %w", err)
 	}
 
+	// send requests an OTP once the local cooldown allows it.
+	send := func() error {
+		wait, err := otpWait(sends.Sent, time.Now())
+		if err != nil {
+			return err
+		}
+		if wait > 0 {
+			if err := otpWaitFn(ctx, output, wait); err != nil {
+				return err
+			}
+		}
+		if err := sendOTPFn(ctx, client, phoneNumber, csrfToken); err != nil {
+			return err
+		}
+		sends.add(time.Now())
+		return nil
+	}
+
 	// Step 2: Send OTP
 	fmt.Fprintf(output, "Sending OTP to +91%s...\n", phoneNumber)
-	if err := sendOTPFn(ctx, client, phoneNumber, csrfToken); err != nil {
+	if err := send(); err != nil {
 		return nil, fmt.Errorf("failed to send OTP: %w", err)
 	}
 	fmt.Fprintln(output, "OTP sent successfully!")
@@ -129,7 +159,7 @@ func loginWithOTPClient(ctx context.Context, client *http.Client, phoneNumber st
 			}
 			resendAttempts++
 			fmt.Fprintf(output, "Resending OTP (%d/%d)...\n", resendAttempts, maxOTPResendAttempts)
-			if err := sendOTPFn(ctx, client, phoneNumber, csrfToken); err != nil {
+			if err := send(); err != nil {
 				return nil, fmt.Errorf("failed to resend OTP: %w", err)
 			}
 			fmt.Fprintln(output, "OTP sent successfully!")
@@ -249,6 +279,9 @@ func sendOTP(ctx context.Context, client *http.Client, phoneNumber, csrfToken st
 		return err
 	}
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%w (server returned %s)", ErrOTPRateLimited, resp.Status)
+	}
 	if resp.StatusCode >= 400 {
 		return fmt.Errorf("server returned %s: %s", resp.Status, string(body))
 	}
@@ -256,11 +289,16 @@ func sendOTP(ctx context.Context, client *http.Client, phoneNumber, csrfToken st
 	// Check response
 	var result struct {
 		Response struct {
-			Status string `json:"Status"`
+			Status  string `json:"Status"`
+			Message string `json:"message"`
 		} `json:"response"`
 	}
 	if err := json.Unmarshal(body, &result); err == nil {
 		if result.Response.Status != "Success" {
+			msg := strings.TrimSpace(result.Response.Status + " " + result.Response.Message)
+			if isRateLimitMessage(msg) {
+				return fmt.Errorf("%w (server said: %s)", ErrOTPRateLimited, msg)
+			}
 			return fmt.Errorf("OTP send failed: %s", result.Response.Status)
 		}
 	}
@@ -312,7 +350,11 @@ func verifyOTP(ctx context.Context, client *http.Client, phoneNumber, otp, csrfT
 		return nil, fmt.Errorf("OTP verification failed: %s", errorResult.Message)
 	}
 
-	// Extract cookies from the jar
+	return jarCookies(client)
+}
+
+// jarCookies returns the Bisleri session cookies collected in client's jar.
+func jarCookies(client *http.Client) ([]store.Cookie, error) {
 	u, _ := url.Parse(bisleriBaseURL)
 	httpCookies := client.Jar.Cookies(u)
 
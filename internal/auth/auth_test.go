@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"bislericli/internal/store"
 )
@@ -16,17 +18,25 @@ func TestLoginWithOTPClientResendThenSuccess(t *testing.T) {
 	oldSendOTPFn := sendOTPFn
 	oldVerifyOTPFn := verifyOTPFn
 	oldVerifyCookiesFn := verifyCookiesFn
+	oldOTPWaitFn := otpWaitFn
 	t.Cleanup(func() {
 		getCSRFTokenFn = oldGetCSRFTokenFn
 		sendOTPFn = oldSendOTPFn
 		verifyOTPFn = oldVerifyOTPFn
 		verifyCookiesFn = oldVerifyCookiesFn
+		otpWaitFn = oldOTPWaitFn
 	})
 
 	getCSRFTokenFn = func(ctx context.Context, client *http.Client) (string, error) {
 		return "csrf-token", nil
 	}
 
+	var waits []time.Duration
+	otpWaitFn = func(ctx context.Context, output io.Writer, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
 	sendCalls := 0
 	sendOTPFn = func(ctx context.Context, client *http.Client, phoneNumber, csrfToken string) error {
 		sendCalls++
@@ -56,6 +66,7 @@ func TestLoginWithOTPClientResendThenSuccess(t *testing.T) {
 		context.Background(),
 		&http.Client{},
 		"9876543210",
+		nil,
 		strings.NewReader("r\n123456\n"),
 		&output,
 	)
@@ -71,6 +82,9 @@ func TestLoginWithOTPClientResendThenSuccess(t *testing.T) {
 	if verifyCalls != 1 {
 		t.Fatalf("expected verifyOTP to be called once, got %d", verifyCalls)
 	}
+	if len(waits) != 1 || waits[0] <= 0 || waits[0] > OTPCooldown {
+		t.Fatalf("expected one cooldown wait before the resend, got %v", waits)
+	}
 }
 
 func TestLoginWithOTPClientResendLimitExceeded(t *testing.T) {
@@ -78,17 +92,25 @@ func TestLoginWithOTPClientResendLimitExceeded(t *testing.T) {
 	oldSendOTPFn := sendOTPFn
 	oldVerifyOTPFn := verifyOTPFn
 	oldVerifyCookiesFn := verifyCookiesFn
+	oldOTPWaitFn := otpWaitFn
 	t.Cleanup(func() {
 		getCSRFTokenFn = oldGetCSRFTokenFn
 		sendOTPFn = oldSendOTPFn
 		verifyOTPFn = oldVerifyOTPFn
 		verifyCookiesFn = oldVerifyCookiesFn
+		otpWaitFn = oldOTPWaitFn
 	})
 
 	getCSRFTokenFn = func(ctx context.Context, client *http.Client) (string, error) {
 		return "csrf-token", nil
 	}
 
+	var waits []time.Duration
+	otpWaitFn = func(ctx context.Context, output io.Writer, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
 	sendCalls := 0
 	sendOTPFn = func(ctx context.Context, client *http.Client, phoneNumber, csrfToken string) error {
 		sendCalls++
@@ -105,6 +127,7 @@ func TestLoginWithOTPClientResendLimitExceeded(t *testing.T) {
 		context.Background(),
 		&http.Client{},
 		"9876543210",
+		nil,
 		strings.NewReader("r\nr\nr\nr\n"),
 		&bytes.Buffer{},
 	)
@@ -118,3 +141,30 @@ func TestLoginWithOTPClientResendLimitExceeded(t *testing.T) {
 		t.Fatalf("expected 4 sendOTP calls (1 initial + 3 resend), got %d", sendCalls)
 	}
 }
+
+func TestOTPWait(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	if wait, err := otpWait(nil, now); err != nil || wait != 0 {
+		t.Fatalf("expected no wait without sends, got %v, %v", wait, err)
+	}
+	wait, err := otpWait([]time.Time{now.Add(-2 * time.Hour), now.Add(-20 * time.Second)}, now)
+	if err != nil || wait != 40*time.Second {
+		t.Fatalf("expected 40s cooldown, got %v, %v", wait, err)
+	}
+	var sent []time.Time
+	for i := 0; i < OTPHourlyLimit; i++ {
+		sent = append(sent, now.Add(-time.Duration(50-i)*time.Minute))
+	}
+	if _, err := otpWait(sent, now); err == nil || !strings.Contains(err.Error(), "10:10") {
+		t.Fatalf("expected hourly limit error naming 10:10, got %v", err)
+	}
+}
+
+func TestIsRateLimitMessage(t *testing.T) {
+	if !isRateLimitMessage("Error: OTP limit exceeded for this number") {
+		t.Fatal("expected limit message to be recognized")
+	}
+	if isRateLimitMessage("Invalid mobile number") {
+		t.Fatal("expected ordinary failure not to be a rate limit")
+	}
+}
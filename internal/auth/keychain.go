@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const keychainService = "bislericli"
+
+// ErrKeychainUnavailable is returned when no supported keychain tool is found.
+var ErrKeychainUnavailable = errors.New("no keychain available (needs macOS 'security' or Linux 'secret-tool')")
+
+// SavePassword stores the password for an account in the OS keychain.
+func SavePassword(account, password string) error {
+	switch {
+	case runtime.GOOS == "darwin":
+		// 'security -i' reads the command from stdin, which keeps the
+		// password out of the argument list other users can see in ps.
+		if strings.ContainsAny(password, "\r\n") {
+			return errors.New("password contains a line break; the keychain cannot store it")
+		}
+		return runSecurityInteractive(securityCommand("add-generic-password", "-U", "-s", keychainService, "-a", account, "-w", password))
+	case hasTool("secret-tool"):
+		return runKeychain(strings.NewReader(password), "secret-tool", "store", "--label", "bislericli login", "service", keychainService, "account", account)
+	}
+	return ErrKeychainUnavailable
+}
+
+// LoadPassword returns the keychain password for an account, or "" when none
+// is stored.
+func LoadPassword(account string) (string, error) {
+	var cmd *exec.Cmd
+	switch {
+	case runtime.GOOS == "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keychainService, "-a", account, "-w")
+	case hasTool("secret-tool"):
+		cmd = exec.Command("secret-tool", "lookup", "service", keychainService, "account", account)
+	default:
+		return "", ErrKeychainUnavailable
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		// Both tools exit non-zero when nothing is stored.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// DeletePassword removes a stored password; a missing entry is not an error.
+func DeletePassword(account string) error {
+	var err error
+	switch {
+	case runtime.GOOS == "darwin":
+		err = runKeychain(nil, "security", "delete-generic-password", "-s", keychainService, "-a", account)
+	case hasTool("secret-tool"):
+		err = runKeychain(nil, "secret-tool", "clear", "service", keychainService, "account", account)
+	default:
+		return ErrKeychainUnavailable
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return nil
+	}
+	return err
+}
+
+func hasTool(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// securityCommand builds one line for 'security -i', double-quoting every
+// argument so spaces and quotes in it survive the tool's own tokenizer.
+func securityCommand(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		a = strings.ReplaceAll(a, `\`, `\\`)
+		a = strings.ReplaceAll(a, `"`, `\"`)
+		quoted[i] = `"` + a + `"`
+	}
+	return strings.Join(quoted, " ") + "\n"
+}
+
+// runSecurityInteractive runs one command through 'security -i'. The tool
+// exits zero even when the command fails, so anything it prints to stderr is
+// treated as the failure.
+func runSecurityInteractive(command string) error {
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(command)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return fmt.Errorf("security: %s", msg)
+	}
+	if err != nil {
+		return fmt.Errorf("security: %w", err)
+	}
+	return nil
+}
+
+func runKeychain(stdin *strings.Reader, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package auth
+
+import "testing"
+
+func TestSecurityCommandQuotesArguments(t *testing.T) {
+	got := securityCommand("add-generic-password", "-a", "me@example.com", "-w", `pa ss"w\rd`)
+	want := `"add-generic-password" "-a" "me@example.com" "-w" "pa ss\"w\\rd"` + "\n"
+	if got != want {
+		t.Errorf("securityCommand = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// OTPCooldown is the minimum gap between OTP requests for one number.
+	OTPCooldown = 60 * time.Second
+	// OTPHourlyLimit caps OTP requests per number in any rolling hour.
+	OTPHourlyLimit = 5
+)
+
+// ErrOTPRateLimited is returned when the site refuses to send another OTP.
+var ErrOTPRateLimited = errors.New("Bisleri is rate-limiting OTP requests for this number; wait 30 minutes before trying again")
+
+// OTPLog carries the recent OTP sends for a phone number across logins so the
+// local cooldown also applies to repeated 'auth login' runs.
+type OTPLog struct {
+	Sent []time.Time
+	// Record persists a send; it may be nil.
+	Record func(time.Time)
+}
+
+func (l *OTPLog) add(t time.Time) {
+	l.Sent = append(l.Sent, t)
+	if l.Record != nil {
+		l.Record(t)
+	}
+}
+
+// otpWait returns how long to wait before another OTP may be requested, or
+// an error when the hourly limit is used up.
+func otpWait(sent []time.Time, now time.Time) (time.Duration, error) {
+	var lastHour []time.Time
+	for _, t := range sent {
+		if now.Sub(t) < time.Hour {
+			lastHour = append(lastHour, t)
+		}
+	}
+	if len(lastHour) >= OTPHourlyLimit {
+		oldest := lastHour[0]
+		for _, t := range lastHour {
+			if t.Before(oldest) {
+				oldest = t
+			}
+		}
+		return 0, fmt.Errorf("%d OTPs were requested for this number in the last hour; try again after %s", len(lastHour), oldest.Add(time.Hour).Format("15:04"))
+	}
+	var wait time.Duration
+	for _, t := range lastHour {
+		if remaining := OTPCooldown - now.Sub(t); remaining > wait {
+			wait = remaining
+		}
+	}
+	return wait, nil
+}
+
+// countdown waits for d, showing the remaining seconds on one line.
+func countdown(ctx context.Context, output io.Writer, d time.Duration) error {
+	deadline := time.Now().Add(d)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		remaining := time.Until(deadline).Round(time.Second)
+		if remaining <= 0 {
+			fmt.Fprint(output, "\r"+strings.Repeat(" ", 40)+"\r")
+			return nil
+		}
+		fmt.Fprintf(output, "\rNext OTP can be sent in %2ds...", int(remaining.Seconds()))
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(output)
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// isRateLimitMessage recognizes the site's rate-limit replies to SendOTP.
+func isRateLimitMessage(msg string) bool {
+	msg = strings.ToLower(msg)
+	for _, marker := range []string{"limit", "too many", "exceed", "blocked", "try again later", "maximum"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"time"
+
+	"bislericli/internal/store"
+)
+
+// ErrInvalidCredentials is returned when the site rejects the email or password.
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
+var passwordLoginFn = passwordLogin
+
+// LoginWithPassword logs in with the email and password of accounts that
+// have one set, using the same Account-Login form as the website.
+func LoginWithPassword(ctx context.Context, email, password string) ([]store.Cookie, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cookie jar: %w", err)
+	}
+	client := &http.Client{
+		Jar:     jar,
+		Timeout: 30 * time.Second,
+	}
+	return loginWithPasswordClient(ctx, client, email, password)
+}
+
+func loginWithPasswordClient(ctx context.Context, client *http.Client, email, password string) ([]store.Cookie, error) {
+	csrfToken, err := getCSRFTokenFn(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	cookies, err := passwordLoginFn(ctx, client, email, password, csrfToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyCookiesFn(cookies); err != nil {
+		return nil, fmt.Errorf("login succeeded but session invalid: %w", err)
+	}
+	return cookies, nil
+}
+
+func passwordLogin(ctx context.Context, client *http.Client, email, password, csrfToken string) ([]store.Cookie, error) {
+	form := url.Values{}
+	form.Set("loginEmail", email)
+	form.Set("loginPassword", password)
+	form.Set("loginRememberMe", "true")
+	form.Set("csrf_token", csrfToken)
+
+	req, err := http.NewRequestWithContext(ctx, "POST",
+		bisleriBaseURL+"/on/demandware.store/Sites-Bis-Site/default/Account-Login",
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Accept", "application/json, text/javascript, */*; q=0.01")
+	req.Header.Set("Origin", bisleriBaseURL)
+	req.Header.Set("Referer", bisleriBaseURL+"/")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("login failed: %s", resp.Status)
+	}
+	if err := passwordLoginError(body); err != nil {
+		return nil, err
+	}
+	return jarCookies(client)
+}
+
+// passwordLoginError reads the Account-Login JSON reply, which reports
+// failures as {"error": ["message"]} or {"success": false}.
+func passwordLoginError(body []byte) error {
+	var result struct {
+		Success *bool           `json:"success"`
+		Error   json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("unexpected login response: %w", err)
+	}
+	var messages []string
+	if len(result.Error) > 0 {
+		var list []string
+		var single string
+		switch {
+		case json.Unmarshal(result.Error, &list) == nil:
+			messages = list
+		case json.Unmarshal(result.Error, &single) == nil && single != "":
+			messages = []string{single}
+		}
+	}
+	if len(messages) == 0 && (result.Success == nil || *result.Success) {
+		return nil
+	}
+	if len(messages) == 0 {
+		return ErrInvalidCredentials
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidCredentials, strings.Join(messages, "; "))
+}
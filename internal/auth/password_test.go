@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"bislericli/internal/store"
+)
+
+func TestPasswordLoginError(t *testing.T) {
+	if err := passwordLoginError([]byte(`{"success":true,"redirectUrl":"/account"}`)); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	err := passwordLoginError([]byte(`{"error":["Invalid login or password. Remember that password is case-sensitive."]}`))
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected invalid credentials, got %v", err)
+	}
+	if err := passwordLoginError([]byte(`{"success":false}`)); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected invalid credentials for success=false, got %v", err)
+	}
+	if err := passwordLoginError([]byte(`<html>`)); err == nil {
+		t.Fatal("expected an error for a non-JSON reply")
+	}
+}
+
+func TestLoginWithPasswordClient(t *testing.T) {
+	oldGetCSRFTokenFn := getCSRFTokenFn
+	oldPasswordLoginFn := passwordLoginFn
+	oldVerifyCookiesFn := verifyCookiesFn
+	t.Cleanup(func() {
+		getCSRFTokenFn = oldGetCSRFTokenFn
+		passwordLoginFn = oldPasswordLoginFn
+		verifyCookiesFn = oldVerifyCookiesFn
+	})
+
+	getCSRFTokenFn = func(ctx context.Context, client *http.Client) (string, error) {
+		return "csrf-token", nil
+	}
+	passwordLoginFn = func(ctx context.Context, client *http.Client, email, password, csrfToken string) ([]store.Cookie, error) {
+		if email != "me@example.com" || password != "secret" || csrfToken != "csrf-token" {
+			t.Fatalf("unexpected login arguments: %s %s %s", email, password, csrfToken)
+		}
+		return []store.Cookie{{Name: "dwsid", Value: "session"}}, nil
+	}
+	verifyCookiesFn = func(cookies []store.Cookie) error { return nil }
+
+	cookies, err := loginWithPasswordClient(context.Background(), &http.Client{}, "me@example.com", "secret")
+	if err != nil {
+		t.Fatalf("loginWithPasswordClient returned error: %v", err)
+	}
+	if len(cookies) != 1 || cookies[0].Value != "session" {
+		t.Fatalf("unexpected cookies: %#v", cookies)
+	}
+}
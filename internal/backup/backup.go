@@ -0,0 +1,282 @@
+// Package backup archives the bislericli config and data directories
+// (profiles, config, order history, audit logs) as a tar.gz, optionally
+// encrypted with a passphrase. Data files are stored under "data/".
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	ErrPassphraseRequired = errors.New("backup is encrypted; a passphrase is required")
+	ErrBadPassphrase      = errors.New("wrong passphrase or corrupted backup")
+)
+
+// magic prefixes encrypted archives; plain archives start with the gzip header.
+const magic = "BCLIBAK1"
+
+const (
+	saltSize   = 16
+	iterations = 210000
+	keySize    = 32
+	// maxIterations bounds the count read back from a header, so a damaged
+	// or hostile file cannot stall the restore in key derivation.
+	maxIterations = 10 * iterations
+)
+
+// dataPrefix holds data-dir files in the archive. It matches the legacy
+// <config>/data layout, so older backups restore into the data dir too.
+const dataPrefix = "data/"
+
+// skipDirs are caches (and the nested legacy data dir) that are not archived
+// from the top of a tree.
+var skipDirs = map[string]bool{"debug-artifacts": true, "data": true}
+
+// skipFile excludes lock files and in-flight temp files from the archive.
+func skipFile(name string) bool {
+	return strings.HasSuffix(name, ".lock") || strings.Contains(name, ".tmp-")
+}
+
+// Create writes an archive of configDir and dataDir to w. An empty passphrase
+// writes a plain tar.gz. It returns the number of files archived.
+func Create(w io.Writer, configDir, dataDir, passphrase string) (int, error) {
+	var buf bytes.Buffer
+	count, err := writeArchive(&buf, configDir, dataDir)
+	if err != nil {
+		return 0, err
+	}
+	if passphrase == "" {
+		_, err = w.Write(buf.Bytes())
+		return count, err
+	}
+	sealed, err := encrypt(buf.Bytes(), passphrase)
+	if err != nil {
+		return 0, err
+	}
+	_, err = w.Write(sealed)
+	return count, err
+}
+
+// Encrypted reports whether data is an encrypted backup.
+func Encrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(magic))
+}
+
+// Restore extracts the archive in data into configDir and dataDir, overwriting
+// files it contains and leaving others untouched. It returns the restored
+// archive paths.
+func Restore(data []byte, configDir, dataDir, passphrase string) ([]string, error) {
+	if Encrypted(data) {
+		if passphrase == "" {
+			return nil, ErrPassphraseRequired
+		}
+		plain, err := decrypt(data, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		data = plain
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("not a bislericli backup: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	var restored []string
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return restored, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		dir, rel := configDir, hdr.Name
+		if strings.HasPrefix(rel, dataPrefix) {
+			dir, rel = dataDir, strings.TrimPrefix(rel, dataPrefix)
+		}
+		name := filepath.FromSlash(rel)
+		if !filepath.IsLocal(name) {
+			return restored, fmt.Errorf("backup contains unsafe path %q", hdr.Name)
+		}
+		target := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+			return restored, err
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return restored, err
+		}
+		if err := os.WriteFile(target, content, 0o600); err != nil {
+			return restored, err
+		}
+		restored = append(restored, hdr.Name)
+	}
+	return restored, nil
+}
+
+func writeArchive(w io.Writer, configDir, dataDir string) (int, error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	count := 0
+	for _, src := range []struct{ dir, prefix string }{{configDir, ""}, {dataDir, dataPrefix}} {
+		n, err := addTree(tw, src.dir, src.prefix)
+		if err != nil {
+			return 0, err
+		}
+		count += n
+	}
+	if err := tw.Close(); err != nil {
+		return 0, err
+	}
+	return count, gz.Close()
+}
+
+func addTree(tw *tar.Writer, dir, prefix string) (int, error) {
+	count := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if skipDirs[rel] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || skipFile(d.Name()) {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{
+			Name:    prefix + filepath.ToSlash(rel),
+			Mode:    0o600,
+			Size:    int64(len(data)),
+			ModTime: time.Now(),
+		}
+		if info, err := d.Info(); err == nil {
+			hdr.ModTime = info.ModTime()
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// encrypt seals plain with AES-256-GCM using a PBKDF2-SHA256 derived key.
+// Layout: magic | salt | uint32 iterations | nonce | ciphertext.
+func encrypt(plain []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, salt, iterations)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	header := make([]byte, 0, len(magic)+saltSize+4+len(nonce))
+	header = append(header, magic...)
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, iterations)
+	header = append(header, nonce...)
+	return gcm.Seal(header, nonce, plain, []byte(magic)), nil
+}
+
+func decrypt(data []byte, passphrase string) ([]byte, error) {
+	rest := data[len(magic):]
+	if len(rest) < saltSize+4 {
+		return nil, ErrBadPassphrase
+	}
+	salt := rest[:saltSize]
+	iter := int(binary.BigEndian.Uint32(rest[saltSize : saltSize+4]))
+	if iter < 1 || iter > maxIterations {
+		return nil, fmt.Errorf("%w (iteration count %d out of range)", ErrBadPassphrase, iter)
+	}
+	rest = rest[saltSize+4:]
+	gcm, err := newGCM(passphrase, salt, iter)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < gcm.NonceSize() {
+		return nil, ErrBadPassphrase
+	}
+	nonce, sealed := rest[:gcm.NonceSize()], rest[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, sealed, []byte(magic))
+	if err != nil {
+		return nil, ErrBadPassphrase
+	}
+	return plain, nil
+}
+
+func newGCM(passphrase string, salt []byte, iter int) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2SHA256([]byte(passphrase), salt, iter, keySize))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// pbkdf2SHA256 implements RFC 8018 PBKDF2 with HMAC-SHA256.
+func pbkdf2SHA256(password, salt []byte, iter, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	hashLen := prf.Size()
+	blocks := (keyLen + hashLen - 1) / hashLen
+	out := make([]byte, 0, blocks*hashLen)
+	u := make([]byte, hashLen)
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(binary.BigEndian.AppendUint32(nil, uint32(block)))
+		u = prf.Sum(u[:0])
+		t := append([]byte(nil), u...)
+		for n := 2; n <= iter; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for i := range t {
+				t[i] ^= u[i]
+			}
+		}
+		out = append(out, t...)
+	}
+	return out[:keyLen]
+}
@@ -0,0 +1,92 @@
+package backup
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPBKDF2KnownVector(t *testing.T) {
+	// RFC 7914 section 11 test vector for PBKDF2-HMAC-SHA256.
+	got := hex.EncodeToString(pbkdf2SHA256([]byte("passwd"), []byte("salt"), 1, 64))
+	want := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"
+	if got != want {
+		t.Fatalf("pbkdf2 mismatch:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestCreateRestoreRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "config.json"), `{"currentProfile":"home"}`)
+	writeFile(t, filepath.Join(src, "profiles", "home.json"), `{"name":"home"}`)
+	writeFile(t, filepath.Join(src, "profiles", "home.json.lock"), "")
+	writeFile(t, filepath.Join(src, "debug-artifacts", "page.html"), "<html>")
+	data := t.TempDir()
+	writeFile(t, filepath.Join(data, "orders_home.json"), `{"orders":[]}`)
+	writeFile(t, filepath.Join(data, "debug-artifacts", "page.html"), "<html>")
+
+	for _, pass := range []string{"", "s3cret"} {
+		var buf bytes.Buffer
+		count, err := Create(&buf, src, data, pass)
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if count != 3 {
+			t.Fatalf("expected 3 files archived, got %d", count)
+		}
+		if Encrypted(buf.Bytes()) != (pass != "") {
+			t.Fatalf("unexpected encryption state for passphrase %q", pass)
+		}
+		if pass != "" {
+			if _, err := Restore(buf.Bytes(), t.TempDir(), t.TempDir(), ""); !errors.Is(err, ErrPassphraseRequired) {
+				t.Fatalf("expected passphrase required, got %v", err)
+			}
+			if _, err := Restore(buf.Bytes(), t.TempDir(), t.TempDir(), "wrong"); !errors.Is(err, ErrBadPassphrase) {
+				t.Fatalf("expected bad passphrase, got %v", err)
+			}
+		}
+		dst, dstData := t.TempDir(), t.TempDir()
+		restored, err := Restore(buf.Bytes(), dst, dstData, pass)
+		if err != nil {
+			t.Fatalf("Restore: %v", err)
+		}
+		if len(restored) != 3 {
+			t.Fatalf("expected 3 restored files, got %v", restored)
+		}
+		if _, err := os.Stat(filepath.Join(dstData, "orders_home.json")); err != nil {
+			t.Fatalf("order history not restored into data dir: %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(dst, "profiles", "home.json"))
+		if err != nil || string(data) != `{"name":"home"}` {
+			t.Fatalf("unexpected restored profile %q (%v)", data, err)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecryptRejectsIterationCountOutOfRange(t *testing.T) {
+	sealed, err := encrypt([]byte("archive"), "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, iter := range []uint32{0, maxIterations + 1, 1<<32 - 1} {
+		data := bytes.Clone(sealed)
+		binary.BigEndian.PutUint32(data[len(magic)+saltSize:], iter)
+		if _, err := decrypt(data, "s3cret"); !errors.Is(err, ErrBadPassphrase) {
+			t.Errorf("iterations %d: got %v, want ErrBadPassphrase", iter, err)
+		}
+	}
+}
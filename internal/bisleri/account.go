@@ -0,0 +1,313 @@
+package bisleri
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	accountPath     = "/account"
+	editProfilePath = "/on/demandware.store/Sites-Bis-Site/default/Account-EditProfile"
+)
+
+var (
+	accountEmailRegex = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	accountPhoneRegex = regexp.MustCompile(`(?:\+91[\s-]*)?\b([6-9]\d{9})\b`)
+)
+
+// Account is the customer information shown on the account settings page.
+type Account struct {
+	Name           string
+	Email          string
+	Phone          string
+	DefaultAddress string
+	WalletLinked   bool
+	WalletBalance  string
+}
+
+// AccountChanges are the profile edits supported by UpdateAccount; empty
+// fields are left unchanged.
+type AccountChanges struct {
+	FirstName string
+	LastName  string
+	Email     string
+	// Password confirms the change when the site asks for it.
+	Password string
+}
+
+// ParseAccount extracts the account details from the account or edit-profile
+// page, preferring form inputs and falling back to the profile card text.
+func ParseAccount(html string) (Account, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return Account{}, err
+	}
+	var acct Account
+	first := profileInput(doc, "firstname")
+	last := profileInput(doc, "lastname")
+	acct.Name = strings.TrimSpace(first + " " + last)
+	acct.Email = profileInput(doc, "email")
+	acct.Phone = profileInput(doc, "phone")
+
+	card := doc.Find(".account-profile, .profile-card, .account-details, .customer-info").First()
+	cardText := leafText(card)
+	if acct.Name == "" {
+		acct.Name = cleanText(card.Find(".name, .customer-name, .account-name").First().Text())
+	}
+	if acct.Email == "" {
+		acct.Email = accountEmailRegex.FindString(cardText)
+	}
+	if acct.Phone == "" {
+		if m := accountPhoneRegex.FindStringSubmatch(cardText); m != nil {
+			acct.Phone = m[1]
+		}
+	}
+	if m := accountPhoneRegex.FindStringSubmatch(acct.Phone); m != nil {
+		acct.Phone = m[1]
+	}
+
+	if candidates, err := ParseAddressCandidates(html); err == nil {
+		for i, c := range candidates {
+			if c.IsDefault || (i == 0 && acct.DefaultAddress == "") {
+				acct.DefaultAddress = strings.Join(strings.Fields(strings.TrimPrefix(strings.TrimSpace(c.RawText), "Default")), " ")
+			}
+			if c.IsDefault {
+				break
+			}
+		}
+	}
+
+	if balance, ok := ExtractWalletBalance(html); ok {
+		acct.WalletLinked = true
+		acct.WalletBalance = balance
+	} else if doc.Find(".bisleri-wallet, .wallet-card, [data-wallet-balance]").Length() > 0 {
+		acct.WalletLinked = true
+	}
+
+	if acct.Name == "" && acct.Email == "" && acct.Phone == "" {
+		return Account{}, errors.New("account details not found on page")
+	}
+	return acct, nil
+}
+
+// profileInput returns the value of the SFCC profile form input whose name
+// ends in field (e.g. dwfrm_profile_customer_firstname).
+func profileInput(doc *goquery.Document, field string) string {
+	var value string
+	doc.Find("input[name]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		name, _ := s.Attr("name")
+		name = strings.ToLower(name)
+		if strings.Contains(name, "confirm") || !strings.HasSuffix(name, field) && !strings.HasSuffix(name, "customer_"+field) {
+			return true
+		}
+		value, _ = s.Attr("value")
+		value = strings.TrimSpace(value)
+		return value == ""
+	})
+	return value
+}
+
+// ExtractProfileForm finds the edit-profile form and its current values.
+func ExtractProfileForm(html string) (CheckoutForm, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return CheckoutForm{}, err
+	}
+	var form *goquery.Selection
+	doc.Find("form").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		action, _ := s.Attr("action")
+		name, _ := s.Attr("name")
+		if strings.Contains(strings.ToLower(action), "saveprofile") || strings.Contains(strings.ToLower(name), "profile") {
+			form = s
+			return false
+		}
+		return true
+	})
+	if form == nil {
+		return CheckoutForm{}, errors.New("edit profile form not found")
+	}
+	action, _ := form.Attr("action")
+	method, _ := form.Attr("method")
+	if method == "" {
+		method = "POST"
+	}
+	fields := url.Values{}
+	form.Find("input[name], select[name]").Each(func(_ int, s *goquery.Selection) {
+		name, _ := s.Attr("name")
+		typ, _ := s.Attr("type")
+		switch strings.ToLower(typ) {
+		case "submit", "button":
+			return
+		case "checkbox", "radio":
+			if _, ok := s.Attr("checked"); !ok {
+				return
+			}
+		}
+		value, _ := s.Attr("value")
+		if goquery.NodeName(s) == "select" {
+			value, _ = s.Find("option[selected]").First().Attr("value")
+		}
+		fields.Set(name, value)
+	})
+	if fields.Get("csrf_token") == "" {
+		if token, err := ExtractCSRFToken(html); err == nil {
+			fields.Set("csrf_token", token)
+		}
+	}
+	return CheckoutForm{
+		Action: strings.TrimSpace(action),
+		Method: strings.ToUpper(strings.TrimSpace(method)),
+		Fields: fields,
+	}, nil
+}
+
+// ProfileFormNeedsPassword reports whether the edit-profile form asks for the
+// account password to confirm changes.
+func ProfileFormNeedsPassword(form CheckoutForm) bool {
+	return profileFieldName(form.Fields, "password") != ""
+}
+
+// applyAccountChanges sets the changed values on the profile form fields,
+// including the confirm-email field SFCC forms carry.
+func applyAccountChanges(fields url.Values, changes AccountChanges) {
+	set := func(suffix, value string) {
+		if value == "" {
+			return
+		}
+		for name := range fields {
+			if strings.HasSuffix(strings.ToLower(name), suffix) {
+				fields.Set(name, value)
+			}
+		}
+	}
+	set("firstname", changes.FirstName)
+	set("lastname", changes.LastName)
+	set("email", changes.Email)
+	set("emailconfirm", changes.Email)
+	set("password", changes.Password)
+}
+
+func profileFieldName(fields url.Values, suffix string) string {
+	for name := range fields {
+		if strings.HasSuffix(strings.ToLower(name), suffix) {
+			return name
+		}
+	}
+	return ""
+}
+
+// FetchAccount loads the account settings page and parses it.
+func (c *Client) FetchAccount(ctx context.Context) (Account, error) {
+	body, err := c.fetchCached(ctx, accountPath, accountPath)
+	if err != nil {
+		return Account{}, err
+	}
+	acct, err := ParseAccount(body)
+	if err == nil && acct.Email != "" && acct.Name != "" {
+		return acct, nil
+	}
+	// The overview page may only link to the profile; the edit form has the
+	// full values.
+	edit, editErr := c.fetchCached(ctx, editProfilePath, "")
+	if editErr != nil {
+		return acct, err
+	}
+	full, editErr := ParseAccount(edit)
+	if editErr != nil {
+		return acct, err
+	}
+	if full.DefaultAddress == "" {
+		full.DefaultAddress = acct.DefaultAddress
+	}
+	full.WalletLinked = full.WalletLinked || acct.WalletLinked
+	if full.WalletBalance == "" {
+		full.WalletBalance = acct.WalletBalance
+	}
+	return full, nil
+}
+
+// FetchProfileForm loads the edit-profile form with its current values.
+func (c *Client) FetchProfileForm(ctx context.Context) (CheckoutForm, error) {
+	body, err := c.fetchPageWithRetry(ctx, editProfilePath, "")
+	if err != nil {
+		return CheckoutForm{}, err
+	}
+	return ExtractProfileForm(body)
+}
+
+// UpdateAccount submits the edit-profile form with changes applied.
+func (c *Client) UpdateAccount(ctx context.Context, form CheckoutForm, changes AccountChanges) error {
+	applyAccountChanges(form.Fields, changes)
+	action := form.Action
+	if action == "" {
+		action = "/on/demandware.store/Sites-Bis-Site/default/Account-SaveProfile"
+	}
+	if strings.HasPrefix(action, "/") {
+		action = c.newURL(action)
+	}
+	req, err := http.NewRequest(http.MethodPost, action, strings.NewReader(form.Fields.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Origin", c.BaseURL)
+	req.Header.Set("Referer", c.newURL(editProfilePath))
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	c.forget(accountPath, editProfilePath)
+	if resp.StatusCode >= 400 {
+		return &HTTPStatusError{Path: action, Status: resp.Status, StatusCode: resp.StatusCode}
+	}
+	body, err := readBody(resp)
+	if err != nil {
+		return err
+	}
+	return saveProfileError(body)
+}
+
+// saveProfileError reads the SaveProfile JSON reply; field errors come back
+// as {"success": false, "fields": {"<field>": "<message>"}}.
+func saveProfileError(body []byte) error {
+	return formReplyError(body, "profile update")
+}
+
+// formReplyError reads the JSON reply SFCC form endpoints send; a non-JSON
+// reply is the page rendered after a redirect and counts as success.
+func formReplyError(body []byte, what string) error {
+	var result struct {
+		Success *bool             `json:"success"`
+		Fields  map[string]string `json:"fields"`
+		Error   []string          `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil
+	}
+	if result.Success == nil || *result.Success {
+		return nil
+	}
+	var problems []string
+	for field, msg := range result.Fields {
+		if msg != "" {
+			problems = append(problems, fmt.Sprintf("%s: %s", field, msg))
+		}
+	}
+	sort.Strings(problems)
+	problems = append(problems, result.Error...)
+	if len(problems) == 0 {
+		return fmt.Errorf("%s rejected", what)
+	}
+	return fmt.Errorf("%s rejected: %s", what, strings.Join(problems, "; "))
+}
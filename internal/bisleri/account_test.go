@@ -0,0 +1,81 @@
+package bisleri
+
+import (
+	"strings"
+	"testing"
+)
+
+const editProfileHTML = `<html><body>
+<form action="/on/demandware.store/Sites-Bis-Site/default/Account-SaveProfile" method="POST" name="dwfrm_profile">
+  <input type="text" name="dwfrm_profile_customer_firstname" value="Asha">
+  <input type="text" name="dwfrm_profile_customer_lastname" value="Rao">
+  <input type="text" name="dwfrm_profile_customer_phone" value="+91 9876543210">
+  <input type="email" name="dwfrm_profile_customer_email" value="asha@example.com">
+  <input type="email" name="dwfrm_profile_customer_emailconfirm" value="asha@example.com">
+  <input type="password" name="dwfrm_profile_login_password" value="">
+  <input type="hidden" name="csrf_token" value="tok">
+  <button type="submit" name="save">Save</button>
+</form>
+<div class="address-card" data-address-id="a1">Default Flat 4, MG Road, Pune 411001</div>
+<div class="bisleri-wallet"><span class="wallet-amount-balance">₹ 150.00</span></div>
+</body></html>`
+
+func TestParseAccount(t *testing.T) {
+	acct, err := ParseAccount(editProfileHTML)
+	if err != nil {
+		t.Fatalf("ParseAccount: %v", err)
+	}
+	if acct.Name != "Asha Rao" || acct.Email != "asha@example.com" || acct.Phone != "9876543210" {
+		t.Fatalf("unexpected account: %+v", acct)
+	}
+	if acct.DefaultAddress != "Flat 4, MG Road, Pune 411001" {
+		t.Fatalf("unexpected default address: %q", acct.DefaultAddress)
+	}
+	if !acct.WalletLinked {
+		t.Fatalf("expected wallet to be linked: %+v", acct)
+	}
+}
+
+func TestParseAccountProfileCard(t *testing.T) {
+	html := `<div class="account-profile"><h3 class="name">Ravi Kumar</h3><p>ravi@example.com</p><p>Mobile: 9123456780</p></div>`
+	acct, err := ParseAccount(html)
+	if err != nil {
+		t.Fatalf("ParseAccount: %v", err)
+	}
+	if acct.Name != "Ravi Kumar" || acct.Email != "ravi@example.com" || acct.Phone != "9123456780" || acct.WalletLinked {
+		t.Fatalf("unexpected account: %+v", acct)
+	}
+	if _, err := ParseAccount("<html><body>Login</body></html>"); err == nil {
+		t.Fatal("expected an error for a page without account details")
+	}
+}
+
+func TestApplyAccountChanges(t *testing.T) {
+	form, err := ExtractProfileForm(editProfileHTML)
+	if err != nil {
+		t.Fatalf("ExtractProfileForm: %v", err)
+	}
+	if !ProfileFormNeedsPassword(form) {
+		t.Fatal("expected the form to need a password")
+	}
+	applyAccountChanges(form.Fields, AccountChanges{Email: "new@example.com", FirstName: "Asha M"})
+	if form.Fields.Get("dwfrm_profile_customer_email") != "new@example.com" || form.Fields.Get("dwfrm_profile_customer_emailconfirm") != "new@example.com" {
+		t.Fatalf("email not updated: %v", form.Fields)
+	}
+	if form.Fields.Get("dwfrm_profile_customer_firstname") != "Asha M" || form.Fields.Get("dwfrm_profile_customer_lastname") != "Rao" {
+		t.Fatalf("name fields wrong: %v", form.Fields)
+	}
+	if form.Fields.Get("csrf_token") != "tok" || form.Fields.Has("save") {
+		t.Fatalf("unexpected form fields: %v", form.Fields)
+	}
+}
+
+func TestSaveProfileError(t *testing.T) {
+	if err := saveProfileError([]byte(`{"success":true}`)); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	err := saveProfileError([]byte(`{"success":false,"fields":{"dwfrm_profile_login_password":"Invalid password"}}`))
+	if err == nil || !strings.Contains(err.Error(), "Invalid password") {
+		t.Fatalf("expected field error, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package bisleri
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// cartParseBudget is the most ParseCartPage may take on a 2 MB cart page.
+const cartParseBudget = 250 * time.Millisecond
+
+// largeCartPage builds a cart page of about size bytes: a few line items in
+// the markup the site uses, then the recommendation carousels and inline
+// scripts that make up most of a real page.
+func largeCartPage(size int) string {
+	var b strings.Builder
+	b.WriteString(`<html><head><script>window.dataLayer=[];</script></head><body>`)
+	b.WriteString(`<form class="location-form" action="/LocationSelector-SetCityLocation" method="post"><select id="citySelect"><option value="Mumbai" selected>Mumbai</option><option value="Pune">Pune</option></select></form>`)
+	for i := 0; i < 3; i++ {
+		uuid := fmt.Sprintf("%016x", 0xabc000+i)
+		pid := fmt.Sprintf("BIS-20LTR0%d-90", i+1)
+		fmt.Fprintf(&b, `<div class="card product-info uuid-%[1]s" data-uuid="%[1]s">
+<div class="line-item-header"><a href="/p/bisleri-20l/%[2]s.html">Bisleri 20L Jar</a></div>
+<div class="line-item-attributes"><p>Size: 20 L</p><p>Price: ₹90.00</p></div>
+<div class="quantity-form"><select class="quantity custom-select" data-uuid="%[1]s" data-pid="%[2]s" data-action="/Cart-UpdateQuantity">
+<option value="1">1</option><option value="2" selected>2</option><option value="3">3</option></select></div>
+<button class="remove-btn" data-pid="%[2]s" data-uuid="%[1]s" data-action="/Cart-RemoveProductLineItem?pid=%[2]s&amp;uuid=%[1]s">Remove</button>
+</div>`, uuid, pid)
+	}
+	b.WriteString(`<form class="checkout-form" action="/on/demandware.store/Sites-Bis-Site/default/Cart-SubmitForm" method="post">
+<input type="hidden" name="csrf_token" value="tok123"><input type="checkbox" name="terms" checked>
+<button type="submit" name="checkout" value="1" class="btn checkout-btn">Proceed to Checkout</button></form>`)
+	for i := 0; b.Len() < size; i++ {
+		fmt.Fprintf(&b, `<div class="recommendation"><div class="product-tile"><div class="image-container"><a href="/p/item-%[1]d.html"><img src="/images/%[1]d.jpg" alt="Item %[1]d"></a></div>
+<div class="tile-body"><div class="pdp-link"><a class="link" href="/p/item-%[1]d.html">Bisleri Item %[1]d</a></div><div class="price"><span class="sales"><span class="value" content="%[1]d.00">₹%[1]d.00</span></span></div></div></div>
+<form class="add-to-cart-form" action="/Cart-AddProduct" method="post"><input type="hidden" name="pid" value="ITEM-%[1]d"><button class="add-to-cart">Add</button></form></div>
+<script>window.dataLayer.push({"event":"impression","id":"ITEM-%[1]d","position":%[1]d});</script>`, i)
+	}
+	b.WriteString(`</body></html>`)
+	return b.String()
+}
+
+// largeOrdersPage builds a my-orders page listing n orders.
+func largeOrdersPage(n int) string {
+	var b strings.Builder
+	b.WriteString(`<html><body><div class="order-history">`)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, `<div class="all-order"><div class="card"><div class="order-section">Order BS-%06d</div>
+<div class="row"><div class="col">Order Placed <span>05/01/2026, 11:49 AM</span></div><div class="col">Total Price <span>₹%d.00</span></div></div>
+<div class="row"><div class="col"><div class="order-status-delivered">Delivered</div></div></div>
+<div class="one-time-order">2 x Bisleri 20L Jar</div></div></div>`, i, 180+i%3*90)
+	}
+	b.WriteString(`</div></body></html>`)
+	return b.String()
+}
+
+func TestLargeCartParseBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("timing check skipped in -short mode")
+	}
+	page := largeCartPage(2 << 20)
+	best := time.Duration(1<<63 - 1)
+	for i := 0; i < 3; i++ {
+		start := time.Now()
+		cart := ParseCartPage(page)
+		best = min(best, time.Since(start))
+		if len(cart.Items) != 3 || cart.SelectedCity != "Mumbai" || len(cart.CityOptions) != 2 {
+			t.Fatalf("unexpected parse: %+v", cart)
+		}
+		if item, ok := cart.Item("BIS-20LTR02-90"); !ok || item.Quantity != 2 {
+			t.Fatalf("unexpected line item: %+v", item)
+		}
+	}
+	if best > cartParseBudget {
+		t.Fatalf("parsing a 2 MB cart page took %s, budget is %s", best, cartParseBudget)
+	}
+}
+
+func BenchmarkParseCartPage(b *testing.B) {
+	page := largeCartPage(2 << 20)
+	b.SetBytes(int64(len(page)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ParseCartPage(page)
+	}
+}
+
+func BenchmarkExtractCartItems(b *testing.B) {
+	page := largeCartPage(2 << 20)
+	b.SetBytes(int64(len(page)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ExtractCartItems(page)
+	}
+}
+
+func BenchmarkExtractCheckoutForm(b *testing.B) {
+	page := largeCartPage(2 << 20)
+	b.SetBytes(int64(len(page)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ExtractCheckoutForm(page); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseOrders(b *testing.B) {
+	page := largeOrdersPage(500)
+	b.SetBytes(int64(len(page)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseOrders(page); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
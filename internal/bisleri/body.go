@@ -0,0 +1,56 @@
+package bisleri
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// readBody reads a response body of at most MaxPageSize bytes, decoding gzip.
+// The client asks for gzip itself, which turns off the transport's
+// transparent decoding, so compressed bodies are always handled here.
+func readBody(resp *http.Response) ([]byte, error) {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	var r io.Reader = resp.Body
+	switch encoding {
+	case "", "identity":
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip response: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	default:
+		return nil, fmt.Errorf("unsupported response encoding %q", encoding)
+	}
+	body, err := readLimited(r)
+	if err != nil {
+		return nil, err
+	}
+	// Some servers and proxies compress without saying so; handing that to
+	// the parsers would look like an empty page.
+	if encoding == "" && len(body) > 2 && body[0] == 0x1f && body[1] == 0x8b {
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip response: %w", err)
+		}
+		defer gz.Close()
+		return readLimited(gz)
+	}
+	return body, nil
+}
+
+func readLimited(r io.Reader) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r, MaxPageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > MaxPageSize {
+		return nil, fmt.Errorf("%w (over %d bytes)", ErrResponseTooLarge, MaxPageSize)
+	}
+	return body, nil
+}
@@ -0,0 +1,76 @@
+package bisleri
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func gzipped(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestFetchPageDecodesGzip(t *testing.T) {
+	page := "<html><body>my orders</body></html>"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("Accept-Encoding = %q", r.Header.Get("Accept-Encoding"))
+		}
+		switch r.URL.Path {
+		case "/labelled":
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(gzipped(t, page))
+		case "/unlabelled":
+			w.Write(gzipped(t, page))
+		case "/brotli":
+			w.Header().Set("Content-Encoding", "br")
+			w.Write([]byte("xx"))
+		default:
+			w.Write([]byte(page))
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(srv.Client(), nil)
+	c.BaseURL = srv.URL
+	c.Throttle = 0
+
+	for _, path := range []string{"/plain", "/labelled", "/unlabelled"} {
+		body, _, err := c.FetchPage(context.Background(), path)
+		if err != nil || body != page {
+			t.Fatalf("%s: got %q, %v", path, body, err)
+		}
+	}
+	if _, _, err := c.FetchPage(context.Background(), "/brotli"); err == nil || !strings.Contains(err.Error(), "unsupported") {
+		t.Fatalf("expected an unsupported encoding error, got %v", err)
+	}
+}
+
+func TestFetchPageSizeLimit(t *testing.T) {
+	huge := strings.Repeat("a", MaxPageSize+1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Compressed, the oversized page is tiny on the wire.
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(gzipped(t, huge))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.Client(), nil)
+	c.BaseURL = srv.URL
+	c.Throttle = 0
+	if _, _, err := c.FetchPage(context.Background(), "/big"); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+	}
+}
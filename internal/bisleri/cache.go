@@ -0,0 +1,100 @@
+package bisleri
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"bislericli/internal/store"
+)
+
+// DefaultPageTTL is how long a cached page is reused without asking the site
+// when the response carries no caching headers of its own.
+const DefaultPageTTL = 10 * time.Minute
+
+// fetchCached fetches a rarely-changing page through c.Cache: a fresh copy
+// is returned without a request, a stale one is revalidated with
+// If-None-Match / If-Modified-Since, and responses are stored as their
+// Cache-Control and Expires headers allow. Without a cache it is
+// fetchPageWithRetry.
+func (c *Client) fetchCached(ctx context.Context, path, expectedPrefix string) (string, error) {
+	if c.Cache == nil {
+		return c.fetchPageWithRetry(ctx, path, expectedPrefix)
+	}
+	key := c.newURL(path)
+	now := time.Now()
+	cached, ok := c.Cache.Get(key)
+	if ok && now.Before(cached.FreshUntil) {
+		c.logf("Cache hit %s", key)
+		return cached.Body, nil
+	}
+	header := http.Header{}
+	if ok && cached.ETag != "" {
+		header.Set("If-None-Match", cached.ETag)
+	}
+	if ok && cached.LastModified != "" {
+		header.Set("If-Modified-Since", cached.LastModified)
+	}
+	body, resp, err := c.fetchPageRetrying(ctx, path, expectedPrefix, header)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		if !ok {
+			return c.fetchPageWithRetry(ctx, path, expectedPrefix)
+		}
+		c.logf("Not modified %s", key)
+		if until, storable := pageFreshness(resp.Header, now); storable {
+			cached.FreshUntil = until
+			c.Cache.Put(key, cached)
+		}
+		return cached.Body, nil
+	}
+	if until, storable := pageFreshness(resp.Header, now); storable {
+		c.Cache.Put(key, store.CachedPage{
+			Body:         body,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			FetchedAt:    now,
+			FreshUntil:   until,
+		})
+	} else if ok {
+		c.Cache.Delete(key)
+	}
+	return body, nil
+}
+
+// forget drops cached pages that a change on the site has made stale.
+func (c *Client) forget(paths ...string) {
+	for _, path := range paths {
+		c.Cache.Delete(c.newURL(path))
+	}
+}
+
+// pageFreshness reads how long a response may be reused and whether it may
+// be stored at all. no-cache pages are stored but revalidated every time.
+func pageFreshness(h http.Header, now time.Time) (time.Time, bool) {
+	directives := map[string]string{}
+	for _, directive := range strings.Split(strings.ToLower(h.Get("Cache-Control")), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		directives[name] = strings.Trim(value, `"`)
+	}
+	if _, ok := directives["no-store"]; ok {
+		return time.Time{}, false
+	}
+	if _, ok := directives["no-cache"]; ok {
+		return now, true
+	}
+	if secs, err := strconv.Atoi(directives["max-age"]); err == nil {
+		return now.Add(time.Duration(secs) * time.Second), true
+	}
+	if expires := h.Get("Expires"); expires != "" {
+		if t, err := http.ParseTime(expires); err == nil {
+			return t, true
+		}
+		return now, true
+	}
+	return now.Add(DefaultPageTTL), true
+}
@@ -0,0 +1,86 @@
+package bisleri
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bislericli/internal/store"
+)
+
+func TestFetchCachedRevalidates(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	var requests, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte("<html>products</html>"))
+	}))
+	defer srv.Close()
+
+	c := NewClient(&http.Client{}, nil)
+	c.BaseURL = srv.URL
+	c.Throttle = 0
+	c.Cache = store.OpenPageCache("test")
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		body, err := c.fetchCached(ctx, productListPath, "")
+		if err != nil || body != "<html>products</html>" {
+			t.Fatalf("fetch %d: %q, %v", i, body, err)
+		}
+	}
+	if requests != 1 {
+		t.Fatalf("expected a fresh page to be reused, got %d requests", requests)
+	}
+
+	// Once stale, the page is revalidated rather than downloaded again.
+	key := c.newURL(productListPath)
+	page, _ := c.Cache.Get(key)
+	page.FreshUntil = time.Now().Add(-time.Second)
+	c.Cache.Put(key, page)
+	body, err := c.fetchCached(ctx, productListPath, "")
+	if err != nil || body != "<html>products</html>" || notModified != 1 {
+		t.Fatalf("revalidation: %q, %v, 304s=%d", body, err, notModified)
+	}
+
+	// The cache file is shared with the next command.
+	if _, ok := store.OpenPageCache("test").Get(key); !ok {
+		t.Fatal("expected the page to be saved")
+	}
+	c.forget(productListPath)
+	if _, ok := c.Cache.Get(key); ok {
+		t.Fatal("expected forget to drop the page")
+	}
+}
+
+func TestPageFreshness(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		header   http.Header
+		until    time.Time
+		storable bool
+	}{
+		{http.Header{}, now.Add(DefaultPageTTL), true},
+		{http.Header{"Cache-Control": {"private, max-age=300"}}, now.Add(5 * time.Minute), true},
+		{http.Header{"Cache-Control": {"no-cache"}}, now, true},
+		{http.Header{"Cache-Control": {"no-cache, no-store, must-revalidate"}}, time.Time{}, false},
+		{http.Header{"Expires": {"Wed, 01 Jan 2025 13:00:00 GMT"}}, now.Add(time.Hour), true},
+		{http.Header{"Expires": {"0"}}, now, true},
+	}
+	for _, tc := range cases {
+		until, storable := pageFreshness(tc.header, now)
+		if !until.Equal(tc.until) || storable != tc.storable {
+			t.Fatalf("%v: got %v %t, want %v %t", tc.header, until, storable, tc.until, tc.storable)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package bisleri
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// CartPage is what the order flow reads from the cart page, taken from one
+// parse of the document. Parsing dominates the cost on large pages, so
+// callers that need more than one of these should use ParseCartPage rather
+// than the separate Extract functions.
+type CartPage struct {
+	Items []CartItem
+	// Count is the header cart badge; HasCount is false when there is none.
+	Count        int
+	HasCount     bool
+	SelectedCity string
+	CityOptions  []string
+}
+
+func ParseCartPage(html string) CartPage {
+	var page CartPage
+	page.Count, page.HasCount = ExtractCartCount(html)
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return page
+	}
+	page.Items = cartItems(doc, html)
+	page.SelectedCity, _ = selectedCity(doc)
+	page.CityOptions = cityOptions(doc)
+	return page
+}
+
+// Item returns the line item for productID.
+func (p CartPage) Item(productID string) (CartItem, bool) {
+	for _, item := range p.Items {
+		if strings.EqualFold(item.ProductID, productID) {
+			return item, true
+		}
+	}
+	return CartItem{}, false
+}
+
+// Unparsed reports a cart whose badge counts items that could not be read.
+func (p CartPage) Unparsed() bool {
+	return p.HasCount && p.Count > 0 && len(p.Items) == 0
+}
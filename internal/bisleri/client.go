@@ -8,8 +8,11 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"bislericli/internal/store"
@@ -18,10 +21,16 @@ import (
 const (
 	defaultBaseURL   = "https://www.bisleri.com"
 	defaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/143.0.0.0 Safari/537.36"
+	// MaxPageSize caps how much of a page is read; real pages are well under
+	// 1 MiB, so anything larger is not a page the parsers should see.
+	MaxPageSize = 8 << 20
 )
 
 var ErrNotAuthenticated = errors.New("session expired; please run 'bislericli auth login'")
 
+// ErrResponseTooLarge is returned for responses over MaxPageSize.
+var ErrResponseTooLarge = errors.New("response too large")
+
 type HTTPStatusError struct {
 	Path       string
 	Status     string
@@ -32,42 +41,90 @@ func (e *HTTPStatusError) Error() string {
 	return fmt.Sprintf("%s request failed: %s", e.Path, e.Status)
 }
 
+// HeaderOptions adjust the headers every new Client sends, for users whose
+// requests are blocked with the defaults.
+type HeaderOptions struct {
+	// UserAgent replaces the built-in user agent.
+	UserAgent string
+	// UserAgents, when set, are used in turn, one per request.
+	UserAgents     []string
+	AcceptLanguage string
+	// Headers are added to every request; they do not replace headers a
+	// request sets itself, such as Content-Type.
+	Headers map[string]string
+}
+
+var headerOptions HeaderOptions
+
+// SetHeaderOptions installs header options for clients created afterwards.
+func SetHeaderOptions(o HeaderOptions) {
+	headerOptions = o
+}
+
 type Client struct {
 	BaseURL   string
 	HTTP      *http.Client
 	UserAgent string
-	Logger    *log.Logger
-	Throttle  time.Duration
-	Debug     bool
+	// UserAgents, when set, rotate per request in place of UserAgent.
+	UserAgents     []string
+	AcceptLanguage string
+	Headers        http.Header
+	Logger         *log.Logger
+	Throttle       time.Duration
+	Debug          bool
+	// Cache, when set, keeps rarely-changing pages between commands.
+	Cache *store.PageCache
+
+	throttleMu sync.Mutex
+	nextSlot   time.Time
+	agentNext  atomic.Uint32
 }
 
 func NewClient(httpClient *http.Client, logger *log.Logger) *Client {
 	if httpClient == nil {
 		httpClient = &http.Client{Timeout: 30 * time.Second}
 	}
+	if httpClient.Transport == nil {
+		httpClient.Transport = Transport()
+	}
 	if logger == nil {
 		logger = log.New(io.Discard, "", 0)
 	}
-	return &Client{
-		BaseURL:   defaultBaseURL,
-		HTTP:      httpClient,
-		UserAgent: defaultUserAgent,
-		Logger:    logger,
-		Throttle:  900 * time.Millisecond,
-		Debug:     false,
+	c := &Client{
+		BaseURL:        defaultBaseURL,
+		HTTP:           httpClient,
+		UserAgent:      defaultUserAgent,
+		UserAgents:     headerOptions.UserAgents,
+		AcceptLanguage: headerOptions.AcceptLanguage,
+		Logger:         logger,
+		Throttle:       900 * time.Millisecond,
+		Debug:          false,
+	}
+	if headerOptions.UserAgent != "" {
+		c.UserAgent = headerOptions.UserAgent
+	}
+	if len(headerOptions.Headers) > 0 {
+		c.Headers = http.Header{}
+		for name, value := range headerOptions.Headers {
+			c.Headers.Set(name, value)
+		}
 	}
+	return c
 }
 
 func (c *Client) do(ctx context.Context, req *http.Request) (*http.Response, error) {
 	c.applyHeaders(req)
-	if c.Throttle > 0 {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-time.After(c.Throttle):
-		}
+	if err := c.wait(ctx); err != nil {
+		return nil, err
 	}
 	c.logf("HTTP %s %s", req.Method, req.URL.String())
+	if c.Debug {
+		ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+			GotConn: func(info httptrace.GotConnInfo) {
+				c.logf("Connection reused=%t idle=%s", info.Reused, info.IdleTime)
+			},
+		})
+	}
 	return c.HTTP.Do(req.WithContext(ctx))
 }
 
@@ -76,15 +133,36 @@ func (c *Client) newURL(path string) string {
 }
 
 func (c *Client) applyHeaders(req *http.Request) {
-	if c.UserAgent != "" {
-		req.Header.Set("User-Agent", c.UserAgent)
+	if agent := c.userAgent(); agent != "" {
+		req.Header.Set("User-Agent", agent)
+	}
+	for name, values := range c.Headers {
+		if req.Header.Get(name) == "" {
+			req.Header[name] = values
+		}
 	}
 	if req.Header.Get("Accept") == "" {
 		req.Header.Set("Accept", "*/*")
 	}
 	if req.Header.Get("Accept-Language") == "" {
-		req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+		lang := c.AcceptLanguage
+		if lang == "" {
+			lang = "en-US,en;q=0.9"
+		}
+		req.Header.Set("Accept-Language", lang)
+	}
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+}
+
+// userAgent returns the next agent from UserAgents, or UserAgent.
+func (c *Client) userAgent() string {
+	if len(c.UserAgents) == 0 {
+		return c.UserAgent
 	}
+	n := c.agentNext.Add(1) - 1
+	return c.UserAgents[int(n)%len(c.UserAgents)]
 }
 
 func (c *Client) AddProduct(ctx context.Context, productID string, quantity int) error {
@@ -191,14 +269,21 @@ func (c *Client) VerifyAuthenticated(ctx context.Context) error {
 }
 
 func (c *Client) fetchPageWithRetry(ctx context.Context, path, expectedPrefix string) (string, error) {
+	body, _, err := c.fetchPageRetrying(ctx, path, expectedPrefix, nil)
+	return body, err
+}
+
+// fetchPageRetrying is fetchPageWithRetry with extra request headers that
+// also returns the final response, for conditional requests.
+func (c *Client) fetchPageRetrying(ctx context.Context, path, expectedPrefix string, header http.Header) (string, *http.Response, error) {
 	const maxAttempts = 3
 	var lastErr error
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		body, resp, err := c.fetchPage(ctx, path)
+		body, resp, err := c.fetchPageHeader(ctx, path, header)
 		if err == nil && resp != nil {
 			c.logf("Response %s %s", resp.Status, resp.Request.URL.String())
 			if err := validateResponsePath(resp, expectedPrefix); err != nil {
-				return "", err
+				return "", resp, err
 			}
 			if resp.StatusCode >= 400 {
 				statusErr := &HTTPStatusError{Path: path, Status: resp.Status, StatusCode: resp.StatusCode}
@@ -206,10 +291,10 @@ func (c *Client) fetchPageWithRetry(ctx context.Context, path, expectedPrefix st
 					lastErr = statusErr
 					c.logf("Retrying %s after status %s (attempt %d/%d)", path, resp.Status, attempt, maxAttempts)
 				} else {
-					return "", statusErr
+					return "", resp, statusErr
 				}
 			} else {
-				return body, nil
+				return body, resp, nil
 			}
 		} else if err != nil {
 			lastErr = err
@@ -220,7 +305,7 @@ func (c *Client) fetchPageWithRetry(ctx context.Context, path, expectedPrefix st
 			delay := time.Duration(attempt) * time.Second
 			select {
 			case <-ctx.Done():
-				return "", ctx.Err()
+				return "", nil, ctx.Err()
 			case <-time.After(delay):
 			}
 		}
@@ -228,23 +313,30 @@ func (c *Client) fetchPageWithRetry(ctx context.Context, path, expectedPrefix st
 	if lastErr == nil {
 		lastErr = errors.New("unknown error")
 	}
-	return "", fmt.Errorf("request failed after retries: %w", lastErr)
+	return "", nil, fmt.Errorf("request failed after retries: %w", lastErr)
 }
 
 func (c *Client) fetchPage(ctx context.Context, path string) (string, *http.Response, error) {
+	return c.fetchPageHeader(ctx, path, nil)
+}
+
+func (c *Client) fetchPageHeader(ctx context.Context, path string, header http.Header) (string, *http.Response, error) {
 	url := c.newURL(path)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return "", nil, err
 	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
 	resp, err := c.do(ctx, req)
 	if err != nil {
 		return "", resp, err
 	}
 	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+	body, err := readBody(resp)
 	if err != nil {
-		return "", resp, err
+		return "", resp, fmt.Errorf("%s: %w", path, err)
 	}
 	return string(body), resp, nil
 }
@@ -363,9 +455,26 @@ func (c *Client) SetCityLocation(ctx context.Context, city string) error {
 	if resp.StatusCode >= 400 {
 		return &HTTPStatusError{Path: "LocationSelector-SetCityLocation", Status: resp.Status, StatusCode: resp.StatusCode}
 	}
+	c.forget(productListPath)
 	return nil
 }
 
+// FetchCityOptions returns the serviceable cities from the location selector
+// in the site header. It reads the home page, which caches well, and falls
+// back to the cart page when the home page has no selector.
+func (c *Client) FetchCityOptions(ctx context.Context) ([]string, error) {
+	if body, err := c.fetchCached(ctx, "/", ""); err == nil {
+		if options := ExtractCityOptions(body); len(options) > 0 {
+			return options, nil
+		}
+	}
+	cartHTML, err := c.FetchCartPage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ExtractCityOptions(cartHTML), nil
+}
+
 func (c *Client) SetSavedAddressLocation(ctx context.Context, address store.Address, addressID string) error {
 	if addressID == "" {
 		return errors.New("address ID is required to set saved address location")
@@ -390,7 +499,7 @@ func (c *Client) SetSavedAddressLocation(ctx context.Context, address store.Addr
 	if err != nil {
 		return err
 	}
-	body, _ := io.ReadAll(resp.Body)
+	body, _ := readBody(resp)
 	_ = resp.Body.Close()
 	if resp.StatusCode < 400 {
 		return nil
@@ -457,3 +566,27 @@ func validateResponsePath(resp *http.Response, expectedPrefix string) error {
 	}
 	return fmt.Errorf("unexpected redirect to %s", path)
 }
+
+// wait pauses Throttle before a request. Slots are handed out at least
+// Throttle apart, so requests running concurrently keep to the same rate as
+// sequential ones.
+func (c *Client) wait(ctx context.Context) error {
+	if c.Throttle <= 0 {
+		return nil
+	}
+	c.throttleMu.Lock()
+	slot := time.Now().Add(c.Throttle)
+	if slot.Before(c.nextSlot) {
+		slot = c.nextSlot
+	}
+	c.nextSlot = slot.Add(c.Throttle)
+	c.throttleMu.Unlock()
+	timer := time.NewTimer(time.Until(slot))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
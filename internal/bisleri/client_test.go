@@ -0,0 +1,48 @@
+package bisleri
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHeaderOptions(t *testing.T) {
+	var got []http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Clone())
+		w.Write([]byte("<html>ok</html>"))
+	}))
+	defer srv.Close()
+
+	SetHeaderOptions(HeaderOptions{
+		UserAgents:     []string{"agent-a", "agent-b"},
+		AcceptLanguage: "hi-IN,hi;q=0.9",
+		Headers:        map[string]string{"x-forwarded-for": "10.0.0.1", "Accept": "text/html"},
+	})
+	t.Cleanup(func() { SetHeaderOptions(HeaderOptions{}) })
+	c := NewClient(&http.Client{}, nil)
+	c.BaseURL = srv.URL
+	c.Throttle = 0
+	for i := 0; i < 3; i++ {
+		if _, _, err := c.FetchPage(context.Background(), "/"); err != nil {
+			t.Fatalf("FetchPage: %v", err)
+		}
+	}
+	for i, want := range []string{"agent-a", "agent-b", "agent-a"} {
+		if ua := got[i].Get("User-Agent"); ua != want {
+			t.Fatalf("request %d User-Agent = %q, want %q", i, ua, want)
+		}
+	}
+	if lang := got[0].Get("Accept-Language"); lang != "hi-IN,hi;q=0.9" {
+		t.Fatalf("Accept-Language = %q", lang)
+	}
+	if got[0].Get("X-Forwarded-For") != "10.0.0.1" || got[0].Get("Accept") != "text/html" {
+		t.Fatalf("extra headers not sent: %v", got[0])
+	}
+
+	SetHeaderOptions(HeaderOptions{})
+	if c := NewClient(nil, nil); c.UserAgent != defaultUserAgent || c.UserAgents != nil || c.Headers != nil {
+		t.Fatalf("cleared options should restore the defaults: %+v", c)
+	}
+}
@@ -39,3 +39,49 @@ func JarFromCookies(cookies []store.Cookie) (*cookiejar.Jar, error) {
 	}
 	return jar, nil
 }
+
+// Cookie kinds reported by ClassifyCookie.
+const (
+	CookieSession  = "session"  // login and basket state; needed for orders
+	CookieSite     = "site"     // other first-party state such as location or consent
+	CookieSecurity = "security" // bot-protection tokens the CDN checks
+	CookieTracking = "tracking" // analytics and ads; never needed
+	CookieUnknown  = "unknown"
+)
+
+// Cookie names by kind, lower-cased. Entries starting or ending with "_" are
+// prefixes; the rest are exact names.
+var (
+	sessionCookies  = []string{"dwsid", "sid", "dwuser", "dwcustomer", "dwanonymous_", "dwsecuretoken_", "dwac_", "cqcid", "cquid", "__cq_", "csrf_token"}
+	securityCookies = []string{"__cf_bm", "cf_clearance", "_cfuvid", "__cfruid", "ak_bmsc", "bm_", "_abck"}
+	trackingCookies = []string{"_ga", "_gid", "_gat", "_gcl_", "_fbp", "_fbc", "__utm", "_hj", "_clck", "_clsk", "_uet", "mp_", "amp_", "ajs_", "_tt_", "_pin_", "__gads", "__gpi", "ide", "_scid", "_sctr", "moe_", "wzrk_", "_dc_gtm_"}
+)
+
+// ClassifyCookie guesses what a saved cookie is for from its name and
+// domain. Cookies for domains other than bisleri.com are tracking.
+func ClassifyCookie(c store.Cookie) string {
+	domain := strings.ToLower(strings.TrimPrefix(c.Domain, "."))
+	if domain != "" && domain != "bisleri.com" && !strings.HasSuffix(domain, ".bisleri.com") {
+		return CookieTracking
+	}
+	name := strings.ToLower(c.Name)
+	for _, kind := range []struct {
+		name  string
+		names []string
+	}{
+		{CookieSession, sessionCookies},
+		{CookieSecurity, securityCookies},
+		{CookieTracking, trackingCookies},
+	} {
+		for _, pattern := range kind.names {
+			isPrefix := strings.HasPrefix(pattern, "_") || strings.HasSuffix(pattern, "_")
+			if name == pattern || isPrefix && strings.HasPrefix(name, pattern) {
+				return kind.name
+			}
+		}
+	}
+	if strings.HasPrefix(name, "dw") || strings.Contains(name, "consent") || strings.Contains(name, "location") || strings.Contains(name, "city") {
+		return CookieSite
+	}
+	return CookieUnknown
+}
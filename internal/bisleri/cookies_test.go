@@ -0,0 +1,29 @@
+package bisleri
+
+import (
+	"testing"
+
+	"bislericli/internal/store"
+)
+
+func TestClassifyCookie(t *testing.T) {
+	cases := []struct {
+		name, domain, want string
+	}{
+		{"dwsid", ".bisleri.com", CookieSession},
+		{"dwanonymous_abc123", "www.bisleri.com", CookieSession},
+		{"__cf_bm", ".bisleri.com", CookieSecurity},
+		{"_ga_XYZ", ".bisleri.com", CookieTracking},
+		{"_fbp", ".bisleri.com", CookieTracking},
+		{"anything", ".doubleclick.net", CookieTracking},
+		{"dw_dnt", "www.bisleri.com", CookieSite},
+		{"selectedCity", "www.bisleri.com", CookieSite},
+		{"mystery", "www.bisleri.com", CookieUnknown},
+		{"ide", "", CookieTracking},
+	}
+	for _, tc := range cases {
+		if got := ClassifyCookie(store.Cookie{Name: tc.name, Domain: tc.domain}); got != tc.want {
+			t.Errorf("ClassifyCookie(%s, %s) = %s, want %s", tc.name, tc.domain, got, tc.want)
+		}
+	}
+}
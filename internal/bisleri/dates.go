@@ -0,0 +1,112 @@
+package bisleri
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// dateLayouts are the date forms seen on order cards, delivery lines, older
+// history pages and CSV imports. Day and month accept one or two digits, and
+// month names match in any case, so "5/1/2026" and "05 JAN 2026" both parse.
+var dateLayouts = []string{
+	"2/1/2006",
+	"2-1-2006",
+	"2.1.2006",
+	"2006-01-02",
+	"2 Jan 2006",
+	"2 January 2006",
+	"2-Jan-2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"Jan 2 2006",
+	"January 2 2006",
+}
+
+// timeLayouts follow a date, after a comma, a space or nothing at all.
+var timeLayouts = []string{
+	"3:04 PM",
+	"3:04PM",
+	"3:04:05 PM",
+	"15:04",
+	"15:04:05",
+}
+
+// hindiMonths maps Devanagari month names, including common spelling
+// variants, to the English ones the layouts use.
+var hindiMonths = strings.NewReplacer(
+	"जनवरी", "January",
+	"फ़रवरी", "February",
+	"फरवरी", "February",
+	"मार्च", "March",
+	"अप्रैल", "April",
+	"मई", "May",
+	"जून", "June",
+	"जुलाई", "July",
+	"अगस्त", "August",
+	"सितंबर", "September",
+	"सितम्बर", "September",
+	"अक्टूबर", "October",
+	"अक्तूबर", "October",
+	"नवंबर", "November",
+	"नवम्बर", "November",
+	"दिसंबर", "December",
+	"दिसम्बर", "December",
+)
+
+var (
+	ordinalRegex  = regexp.MustCompile(`\b(\d{1,2})(?:st|nd|rd|th)\b`)
+	weekdayRegex  = regexp.MustCompile(`(?i)^(?:mon|tue|wed|thu|fri|sat|sun)[a-z]*,?\s+`)
+	meridiemRegex = regexp.MustCompile(`(?i)([\d ])([ap])\.?m\.?$`)
+	zoneRegex     = regexp.MustCompile(`\s+(?:IST|hrs)$`)
+)
+
+// ParseDate parses a date, or a date and time, as the site and imports print
+// them, in loc when the text has no zone. Days come before months in numeric
+// dates, as on the site. Ordinals ("5th"), a leading weekday, a trailing
+// "IST" and Hindi month names are accepted. ok is false when nothing matched.
+func ParseDate(s string, loc *time.Location) (t time.Time, ok bool) {
+	s = normalizeDate(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.In(loc), true
+	}
+	for _, date := range dateLayouts {
+		if t, err := time.ParseInLocation(date, s, loc); err == nil {
+			return t, true
+		}
+		for _, clock := range timeLayouts {
+			for _, sep := range []string{", ", " "} {
+				if t, err := time.ParseInLocation(date+sep+clock, s, loc); err == nil {
+					return t, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func normalizeDate(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	s = hindiMonths.Replace(s)
+	s = weekdayRegex.ReplaceAllString(s, "")
+	s = zoneRegex.ReplaceAllString(s, "")
+	s = ordinalRegex.ReplaceAllString(s, "$1")
+	// "11:49 am" and "11:49 a.m." become "11:49 AM".
+	if m := meridiemRegex.FindStringSubmatchIndex(s); m != nil {
+		s = s[:m[3]] + strings.ToUpper(s[m[4]:m[5]]) + "M"
+	}
+	return s
+}
+
+// FormatOrderDate formats an order date as "02 Jan 2006", or returns it
+// unchanged when it cannot be parsed.
+func FormatOrderDate(dateStr string) string {
+	dateStr = strings.TrimSpace(dateStr)
+	if t, ok := ParseDate(dateStr, time.Local); ok {
+		return t.Format("02 Jan 2006")
+	}
+	return dateStr
+}
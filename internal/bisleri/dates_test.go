@@ -0,0 +1,48 @@
+package bisleri
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	ist := time.FixedZone("IST", 19800)
+	for _, tc := range []struct {
+		in   string
+		want time.Time
+	}{
+		{"05/01/2026", time.Date(2026, 1, 5, 0, 0, 0, 0, ist)},
+		{"05/01/2026, 11:49 AM", time.Date(2026, 1, 5, 11, 49, 0, 0, ist)},
+		{"5/1/2026 1:05 pm", time.Date(2026, 1, 5, 13, 5, 0, 0, ist)},
+		{"05-01-2026 18:30", time.Date(2026, 1, 5, 18, 30, 0, 0, ist)},
+		{"2026-01-05", time.Date(2026, 1, 5, 0, 0, 0, 0, ist)},
+		{"5 Jan 2026", time.Date(2026, 1, 5, 0, 0, 0, 0, ist)},
+		{"05 JANUARY 2026, 9:00 a.m.", time.Date(2026, 1, 5, 9, 0, 0, 0, ist)},
+		{"Jan 5, 2026", time.Date(2026, 1, 5, 0, 0, 0, 0, ist)},
+		{"January 05, 2026 3:04PM", time.Date(2026, 1, 5, 15, 4, 0, 0, ist)},
+		{"Monday, 5th January 2026", time.Date(2026, 1, 5, 0, 0, 0, 0, ist)},
+		{"  05  Jan\n2026, 10:15 AM IST ", time.Date(2026, 1, 5, 10, 15, 0, 0, ist)},
+		{"5 जनवरी 2026", time.Date(2026, 1, 5, 0, 0, 0, 0, ist)},
+		{"12 सितम्बर 2026", time.Date(2026, 9, 12, 0, 0, 0, 0, ist)},
+		{"2026-01-05T06:19:00Z", time.Date(2026, 1, 5, 11, 49, 0, 0, ist)},
+	} {
+		got, ok := ParseDate(tc.in, ist)
+		if !ok || !got.Equal(tc.want) {
+			t.Errorf("ParseDate(%q) = %v, %v; want %v", tc.in, got, ok, tc.want)
+		}
+	}
+	for _, bad := range []string{"", "yesterday", "31/02/2026", "BS-1234"} {
+		if got, ok := ParseDate(bad, ist); ok {
+			t.Errorf("ParseDate(%q) = %v, want no match", bad, got)
+		}
+	}
+}
+
+func TestFormatOrderDate(t *testing.T) {
+	if got := FormatOrderDate(" 05/01/2026, 11:49 AM "); got != "05 Jan 2026" {
+		t.Errorf("FormatOrderDate = %q", got)
+	}
+	if got := FormatOrderDate("soon"); got != "soon" {
+		t.Errorf("FormatOrderDate kept %q, want it unchanged", got)
+	}
+}
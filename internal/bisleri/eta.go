@@ -0,0 +1,49 @@
+package bisleri
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// etaPatterns match the promised delivery text on the order confirmation page,
+// most specific first. The captured value stops at the next sentence or label.
+var etaPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(?:expected|estimated)\s+delivery(?:\s+(?:date|time|window|slot))?\s*(?:is|by|on)?\s*[:\-]?\s*([^\n]{3,60}?)(?:\s{2,}|\.\s|$)`),
+	regexp.MustCompile(`(?i)delivery\s+(?:date|slot|window)\s*[:\-]\s*([^\n]{3,60}?)(?:\s{2,}|\.\s|$)`),
+	regexp.MustCompile(`(?i)(?:will\s+be\s+)?delivered\s+(?:by|on)\s+([^\n]{3,60}?)(?:\s{2,}|\.\s|$)`),
+}
+
+// ExtractDeliveryETA returns the delivery date/window promised on the order
+// confirmation page, e.g. "17 Oct 2026, 08:00 AM - 02:00 PM".
+func ExtractDeliveryETA(html string) (string, bool) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return "", false
+	}
+	doc.Find("script, style, noscript").Remove()
+	var lines []string
+	doc.Find("body").Each(func(_ int, s *goquery.Selection) {
+		for _, line := range strings.Split(s.Text(), "\n") {
+			if line = strings.Join(strings.Fields(line), " "); line != "" {
+				lines = append(lines, line)
+			}
+		}
+	})
+	for _, re := range etaPatterns {
+		for i, line := range lines {
+			candidate := line
+			// Labels are often in their own element with the value on the next line.
+			if i+1 < len(lines) && re.FindStringSubmatch(line) == nil {
+				candidate = line + " " + lines[i+1]
+			}
+			if match := re.FindStringSubmatch(candidate); len(match) > 1 {
+				if eta := strings.Trim(strings.TrimSpace(match[1]), ":-,"); eta != "" {
+					return strings.TrimSpace(eta), true
+				}
+			}
+		}
+	}
+	return "", false
+}
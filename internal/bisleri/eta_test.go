@@ -0,0 +1,37 @@
+package bisleri
+
+import "testing"
+
+func TestExtractDeliveryETA(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "inline label",
+			html: `<html><body><div class="order-thank-you"><p>Expected Delivery: 17 Oct 2026, 08:00 AM - 02:00 PM</p></div></body></html>`,
+			want: "17 Oct 2026, 08:00 AM - 02:00 PM",
+		},
+		{
+			name: "label and value in separate elements",
+			html: "<html><body><dl><dt>Delivery Slot:</dt>\n<dd>Sat, 18 Oct | 08:00 AM - 02:00 PM</dd></dl></body></html>",
+			want: "Sat, 18 Oct | 08:00 AM - 02:00 PM",
+		},
+		{
+			name: "sentence",
+			html: `<html><body><p>Your order will be delivered by Friday, 17 October. Thank you!</p></body></html>`,
+			want: "Friday, 17 October",
+		},
+		{
+			name: "missing",
+			html: `<html><body><p>Thank you for your order.</p><script>var delivery = "x";</script></body></html>`,
+		},
+	}
+	for _, tt := range tests {
+		got, ok := ExtractDeliveryETA(tt.html)
+		if ok != (tt.want != "") || got != tt.want {
+			t.Fatalf("%s: got %q (%v), want %q", tt.name, got, ok, tt.want)
+		}
+	}
+}
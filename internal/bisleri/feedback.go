@@ -0,0 +1,132 @@
+package bisleri
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ErrFeedbackUnavailable is returned when the order history offers no way to
+// rate an order, e.g. it is not delivered yet or was already rated.
+var ErrFeedbackUnavailable = errors.New("no feedback form for this order")
+
+// ExtractFeedbackForm finds the rating form on orderID's card in the order
+// history: a form posting to a feedback, rating or review endpoint, or a
+// button carrying that endpoint in a data attribute.
+func ExtractFeedbackForm(html, orderID string) (CheckoutForm, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return CheckoutForm{}, err
+	}
+	var form *CheckoutForm
+	doc.Find(orderCardSelector()).EachWithBreak(func(_ int, card *goquery.Selection) bool {
+		if !strings.EqualFold(orderTextRegex.FindString(card.Find(".order-section").Text()), orderID) {
+			return true
+		}
+		card.Find("form").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+			action, _ := s.Attr("action")
+			if isFeedbackURL(action) {
+				f := readForm(s)
+				// Star pickers are radio groups with nothing checked yet.
+				s.Find(`input[type="radio"][name]`).Each(func(_ int, in *goquery.Selection) {
+					if name, _ := in.Attr("name"); !f.Fields.Has(name) {
+						f.Fields.Set(name, "")
+					}
+				})
+				form = &f
+				return false
+			}
+			return true
+		})
+		if form == nil {
+			btn := card.Find("[data-feedback-url], [data-rating-url], [data-review-url]").First()
+			if action := firstAttr(btn, "data-feedback-url", "data-rating-url", "data-review-url"); action != "" {
+				form = &CheckoutForm{Action: action, Method: "POST", Fields: url.Values{"orderID": {orderID}}}
+			}
+		}
+		return form == nil
+	})
+	if form == nil {
+		return CheckoutForm{}, ErrFeedbackUnavailable
+	}
+	if form.Fields.Get("csrf_token") == "" {
+		if token, err := csrfToken(html, doc); err == nil {
+			form.Fields.Set("csrf_token", token)
+		}
+	}
+	return *form, nil
+}
+
+func isFeedbackURL(action string) bool {
+	lower := strings.ToLower(action)
+	return strings.Contains(lower, "feedback") || strings.Contains(lower, "rating") || strings.Contains(lower, "review")
+}
+
+// fillFeedback sets the star rating and comment on the feedback form fields.
+func fillFeedback(fields url.Values, stars int, comment string) {
+	var rated bool
+	for name := range fields {
+		lower := strings.ToLower(name)
+		switch {
+		case strings.HasSuffix(lower, "rating") || strings.HasSuffix(lower, "stars") || strings.HasSuffix(lower, "star"):
+			fields.Set(name, strconv.Itoa(stars))
+			rated = true
+		case comment != "" && (strings.HasSuffix(lower, "comment") || strings.HasSuffix(lower, "comments") || strings.HasSuffix(lower, "feedback") || strings.HasSuffix(lower, "review")):
+			fields.Set(name, comment)
+		}
+	}
+	if !rated {
+		fields.Set("rating", strconv.Itoa(stars))
+		if comment != "" {
+			fields.Set("comment", comment)
+		}
+	}
+}
+
+// RateOrder submits a 1-5 star rating and optional comment for a delivered
+// order through the feedback form on the order history.
+func (c *Client) RateOrder(ctx context.Context, orderID string, stars int, comment string) error {
+	if stars < 1 || stars > 5 {
+		return fmt.Errorf("rating must be 1-5 stars, got %d", stars)
+	}
+	body, err := c.fetchPageWithRetry(ctx, ordersPath, ordersPath)
+	if err != nil {
+		return err
+	}
+	form, err := ExtractFeedbackForm(body, orderID)
+	if err != nil {
+		return err
+	}
+	fillFeedback(form.Fields, stars, comment)
+	action := form.Action
+	if strings.HasPrefix(action, "/") {
+		action = c.newURL(action)
+	}
+	req, err := http.NewRequest(http.MethodPost, action, strings.NewReader(form.Fields.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Origin", c.BaseURL)
+	req.Header.Set("Referer", c.newURL(ordersPath))
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return &HTTPStatusError{Path: action, Status: resp.Status, StatusCode: resp.StatusCode}
+	}
+	reply, err := readBody(resp)
+	if err != nil {
+		return err
+	}
+	return formReplyError(reply, "feedback")
+}
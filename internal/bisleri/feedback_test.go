@@ -0,0 +1,64 @@
+package bisleri
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const ratableOrders = `<html><body><input type="hidden" name="csrf_token" value="tok123">
+<div class="all-order"><div class="order-section">Order BS-1</div><div class="order-status-delivered">Delivered</div>
+<form action="/on/demandware.store/Sites-Bis-Site/default/Order-SubmitFeedback" method="post">
+<input type="hidden" name="orderID" value="BS-1"><input type="radio" name="dwfrm_feedback_rating" value="5">
+<textarea name="dwfrm_feedback_comment"></textarea></form></div>
+<div class="all-order"><div class="order-section">Order BS-2</div><div class="order-status-pending">Pending</div></div>
+</body></html>`
+
+func TestExtractFeedbackForm(t *testing.T) {
+	form, err := ExtractFeedbackForm(ratableOrders, "BS-1")
+	if err != nil {
+		t.Fatalf("ExtractFeedbackForm: %v", err)
+	}
+	if form.Fields.Get("orderID") != "BS-1" || form.Fields.Get("csrf_token") != "tok123" {
+		t.Fatalf("unexpected fields: %v", form.Fields)
+	}
+	if _, err := ExtractFeedbackForm(ratableOrders, "BS-2"); !errors.Is(err, ErrFeedbackUnavailable) {
+		t.Fatalf("pending order: err = %v, want ErrFeedbackUnavailable", err)
+	}
+
+	button := `<div class="all-order"><div class="order-section">Order BS-3</div><button data-rating-url="/Order-Rate">Rate</button></div>`
+	if form, err := ExtractFeedbackForm(button, "BS-3"); err != nil || form.Action != "/Order-Rate" || form.Fields.Get("orderID") != "BS-3" {
+		t.Fatalf("data-rating-url: %+v, %v", form, err)
+	}
+}
+
+func TestRateOrder(t *testing.T) {
+	var posted url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			io.WriteString(w, ratableOrders)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		posted, _ = url.ParseQuery(string(body))
+		io.WriteString(w, `{"success": true}`)
+	}))
+	defer srv.Close()
+
+	c := NewClient(&http.Client{}, nil)
+	c.BaseURL = srv.URL
+	c.Throttle = 0
+	if err := c.RateOrder(context.Background(), "BS-1", 4, "On time"); err != nil {
+		t.Fatalf("RateOrder: %v", err)
+	}
+	if posted.Get("dwfrm_feedback_rating") != "4" || posted.Get("dwfrm_feedback_comment") != "On time" || posted.Get("orderID") != "BS-1" {
+		t.Fatalf("posted %v", posted)
+	}
+	if err := c.RateOrder(context.Background(), "BS-1", 6, ""); err == nil {
+		t.Fatalf("expected an error for 6 stars")
+	}
+}
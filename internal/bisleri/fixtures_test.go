@@ -0,0 +1,107 @@
+package bisleri
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite testdata/fixtures/*.golden.json from the current parsers")
+
+// Pages captured with `bislericli debug order --fixtures DIR` go in
+// testdata/fixtures as <kind>[-label].html, kind being cart, shipping or
+// payment. Each is parsed with the extractors the order flow uses for that
+// page and the result compared with <name>.golden.json; run
+// `go test ./internal/bisleri -run Fixtures -update` to accept new output.
+func TestFixtures(t *testing.T) {
+	pages, err := filepath.Glob(filepath.Join("testdata", "fixtures", "*.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) == 0 {
+		t.Skip("no fixtures")
+	}
+	for _, page := range pages {
+		name := strings.TrimSuffix(filepath.Base(page), ".html")
+		t.Run(name, func(t *testing.T) {
+			html, err := os.ReadFile(page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			kind, _, _ := strings.Cut(name, "-")
+			summary := summarizeFixture(kind, string(html))
+			if summary == nil {
+				t.Skipf("no extractors for page kind %q", kind)
+			}
+			got, err := json.MarshalIndent(summary, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+			golden := strings.TrimSuffix(page, ".html") + ".golden.json"
+			if *updateGolden {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s changed:\n%s\nwant:\n%s", golden, got, want)
+			}
+		})
+	}
+}
+
+// summarizeFixture runs the extractors for a kind of page; errors are kept
+// as values so a page that stops parsing shows up in the diff.
+func summarizeFixture(kind, html string) map[string]any {
+	errText := func(err error) string {
+		if err != nil {
+			return err.Error()
+		}
+		return ""
+	}
+	switch kind {
+	case "cart":
+		page := ParseCartPage(html)
+		form, err := ExtractCheckoutForm(html)
+		return map[string]any{
+			"items":          page.Items,
+			"count":          page.Count,
+			"hasCount":       page.HasCount,
+			"selectedCity":   page.SelectedCity,
+			"checkoutAction": form.Action,
+			"checkoutMethod": form.Method,
+			"checkoutError":  errText(err),
+		}
+	case "shipping":
+		uuid, uuidErr := ExtractShipmentUUID(html)
+		addresses, addrErr := ParseAddressCandidates(html)
+		_, csrfErr := ExtractCSRFToken(html)
+		return map[string]any{
+			"shipmentUUID":   uuid,
+			"shipmentError":  errText(uuidErr),
+			"addresses":      len(addresses),
+			"addressesError": errText(addrErr),
+			"csrfError":      errText(csrfErr),
+		}
+	case "payment":
+		wallet, hasWallet := ExtractWalletBalance(html)
+		total, hasTotal := ExtractOrderTotal(html)
+		return map[string]any{
+			"wallet":    wallet,
+			"hasWallet": hasWallet,
+			"total":     total,
+			"hasTotal":  hasTotal,
+		}
+	}
+	return nil
+}
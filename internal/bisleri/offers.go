@@ -0,0 +1,160 @@
+package bisleri
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// offerPaths are the pages that list promotions, tried in order; the home
+// page banners are the fallback.
+var offerPaths = []string{"/offers", "/coupons", "/home"}
+
+var (
+	couponCodeRegex  = regexp.MustCompile(`(?i)(?:use\s+(?:code|coupon)|code|coupon)\s*[:\-]?\s*["']?([A-Z0-9]{4,20})\b`)
+	offerExpiryRegex = regexp.MustCompile(`(?i)(?:valid\s+(?:till|until|upto|up\s+to)|expires?(?:\s+on)?|ends?(?:\s+on)?)\s*:?\s*([0-9]{1,2}(?:st|nd|rd|th)?\s+[A-Za-z]{3,9}(?:,?\s+[0-9]{4})?|[0-9]{1,2}[/-][0-9]{1,2}[/-][0-9]{2,4})`)
+	offerCitiesRegex = regexp.MustCompile(`(?i)(?:only\s+)?(?:valid|available|applicable)\s+(?:only\s+)?in\s+([A-Za-z ,&]+?)(?:\.|$| only)`)
+)
+
+// Offer is a promotion from the offers page or a home page banner.
+type Offer struct {
+	Title       string
+	Description string
+	Code        string
+	Expires     string
+	// Cities limits the offer; empty means it applies everywhere.
+	Cities []string
+}
+
+// ParseOffers extracts offer cards (or promo banners) with their coupon code,
+// expiry and city restrictions, read from data attributes or the card text.
+func ParseOffers(html string) []Offer {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil
+	}
+	var offers []Offer
+	seen := map[string]bool{}
+	doc.Find(".offer-card, .offer, .coupon-card, .coupon, [data-coupon-code], .promo-banner, .hero-banner[data-promo]").Each(func(_ int, s *goquery.Selection) {
+		// Skip wrappers whose offer cards are matched on their own.
+		if s.Find(".offer-card, .coupon-card, [data-coupon-code]").Length() > 0 {
+			return
+		}
+		var o Offer
+		o.Title = cleanText(s.Find("h1, h2, h3, h4, .offer-title, .title").First().Text())
+		if o.Title == "" {
+			o.Title, _ = s.Find("img").First().Attr("alt")
+			o.Title = cleanText(o.Title)
+		}
+		o.Description = cleanText(s.Find("p, .offer-desc, .description").First().Text())
+		text := leafText(s)
+
+		o.Code = firstAttr(s, "data-coupon-code", "data-code")
+		if o.Code == "" {
+			o.Code = cleanText(s.Find(".coupon-code, .code").First().Text())
+		}
+		if o.Code == "" {
+			if m := couponCodeRegex.FindStringSubmatch(text); m != nil {
+				o.Code = m[1]
+			}
+		}
+		o.Code = strings.ToUpper(o.Code)
+
+		if o.Expires = firstAttr(s, "data-expiry", "data-valid-till"); o.Expires == "" {
+			if m := offerExpiryRegex.FindStringSubmatch(text); m != nil {
+				o.Expires = m[1]
+			}
+		}
+		cities := firstAttr(s, "data-cities", "data-city")
+		if cities == "" {
+			if m := offerCitiesRegex.FindStringSubmatch(text); m != nil {
+				cities = m[1]
+			}
+		}
+		o.Cities = splitCities(cities)
+
+		if o.Title == "" && o.Code == "" {
+			return
+		}
+		key := strings.ToLower(o.Title + "|" + o.Code)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		offers = append(offers, o)
+	})
+	return offers
+}
+
+// AppliesTo reports whether the offer is valid in city; offers without a
+// city restriction and an unknown city always match.
+func (o Offer) AppliesTo(city string) bool {
+	if len(o.Cities) == 0 || city == "" {
+		return true
+	}
+	for _, c := range o.Cities {
+		if strings.EqualFold(c, city) || strings.EqualFold(c, "all cities") {
+			return true
+		}
+	}
+	return false
+}
+
+// FetchOffers returns the promotions from the first offers page that lists any.
+func (c *Client) FetchOffers(ctx context.Context) ([]Offer, error) {
+	var lastErr error
+	for _, path := range offerPaths {
+		body, resp, err := c.fetchPage(ctx, path)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode >= 400 {
+			lastErr = &HTTPStatusError{Path: path, Status: resp.Status, StatusCode: resp.StatusCode}
+			continue
+		}
+		if offers := ParseOffers(body); len(offers) > 0 {
+			return offers, nil
+		}
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, nil
+}
+
+func firstAttr(s *goquery.Selection, names ...string) string {
+	for _, name := range names {
+		if v, ok := s.Attr(name); ok && strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+// leafText joins the text of s's leaf elements with spaces so neighbouring
+// lines don't run together.
+func leafText(s *goquery.Selection) string {
+	var parts []string
+	s.Find("*").Each(func(_ int, el *goquery.Selection) {
+		if el.Children().Length() == 0 {
+			parts = append(parts, el.Text())
+		}
+	})
+	if len(parts) == 0 {
+		parts = append(parts, s.Text())
+	}
+	return cleanText(strings.Join(parts, " "))
+}
+
+func splitCities(value string) []string {
+	var cities []string
+	for _, part := range regexp.MustCompile(`\s*(?:,|&|\band\b)\s*`).Split(value, -1) {
+		if part = strings.TrimSpace(part); part != "" {
+			cities = append(cities, part)
+		}
+	}
+	return cities
+}
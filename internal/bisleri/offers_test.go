@@ -0,0 +1,36 @@
+package bisleri
+
+import "testing"
+
+func TestParseOffers(t *testing.T) {
+	html := `<div class="offers-list">
+  <div class="offer-card" data-coupon-code="jar50">
+    <h3>Flat ₹50 off on 5 jars</h3>
+    <p>Valid till 31st Oct 2026. Valid in Mumbai, Pune &amp; Thane.</p>
+  </div>
+  <div class="offer-card">
+    <h3>Wallet cashback</h3>
+    <p>Use code WALLET10 for 10% back.</p>
+    <span>Expires on 15/11/2026</span>
+  </div>
+  <div class="offer-card"><h3>Wallet cashback</h3><p>Use code WALLET10</p></div>
+</div>`
+	offers := ParseOffers(html)
+	if len(offers) != 2 {
+		t.Fatalf("expected 2 offers, got %d: %+v", len(offers), offers)
+	}
+	first := offers[0]
+	if first.Code != "JAR50" || first.Title != "Flat ₹50 off on 5 jars" || first.Expires != "31st Oct 2026" {
+		t.Fatalf("unexpected first offer: %+v", first)
+	}
+	if len(first.Cities) != 3 || first.Cities[2] != "Thane" {
+		t.Fatalf("unexpected cities: %q", first.Cities)
+	}
+	if !first.AppliesTo("pune") || first.AppliesTo("Delhi") {
+		t.Fatalf("city filter wrong for %+v", first)
+	}
+	second := offers[1]
+	if second.Code != "WALLET10" || second.Expires != "15/11/2026" || !second.AppliesTo("Delhi") {
+		t.Fatalf("unexpected second offer: %+v", second)
+	}
+}
@@ -9,14 +9,15 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
-	"time"
 
 	"bislericli/internal/store"
 )
 
 var orderIDRegex = regexp.MustCompile(`orderID=([^&]+)`) // matches query param
 
-func (c *Client) SubmitShipping(ctx context.Context, shipmentUUID, csrfToken, timeslot string, address store.Address, addressID string) error {
+// SubmitShipping posts the shipping form. giftMessage is printed on the
+// delivery note; leave it empty for none.
+func (c *Client) SubmitShipping(ctx context.Context, shipmentUUID, csrfToken, timeslot, giftMessage string, address store.Address, addressID string) error {
 	if shipmentUUID == "" || csrfToken == "" {
 		return errors.New("missing shipment UUID or CSRF token")
 	}
@@ -39,7 +40,7 @@ func (c *Client) SubmitShipping(ctx context.Context, shipmentUUID, csrfToken, ti
 	form.Set("dwfrm_shipping_shippingAddress_addressFields_sector", "")
 	form.Set("dwfrm_shipping_shippingAddress_addressFields_phone", address.Phone)
 	form.Set("dwfrm_shipping_shippingAddress_shippingMethodID", "001")
-	form.Set("dwfrm_shipping_shippingAddress_giftMessage", "")
+	form.Set("dwfrm_shipping_shippingAddress_giftMessage", giftMessage)
 	form.Set("csrf_token", csrfToken)
 	if timeslot != "" {
 		form.Set("timeslot", timeslot)
@@ -63,10 +64,16 @@ func (c *Client) SubmitShipping(ctx context.Context, shipmentUUID, csrfToken, ti
 		return err
 	}
 	defer resp.Body.Close()
+	body, _ := readBody(resp)
+	if validationErr := parseShippingResponse(body); validationErr != nil {
+		if isSlotFullMessage(validationErr.Error()) {
+			return fmt.Errorf("%w: %v", ErrNoSlotAvailable, validationErr)
+		}
+		return validationErr
+	}
 	if resp.StatusCode >= 400 {
 		return fmt.Errorf("submit shipping failed: %s", resp.Status)
 	}
-	_, _ = io.ReadAll(resp.Body)
 	return nil
 }
 
@@ -103,11 +110,18 @@ func (c *Client) SubmitPayment(ctx context.Context, shipmentUUID, csrfToken stri
 	if resp.StatusCode >= 400 {
 		return fmt.Errorf("submit payment failed: %s", resp.Status)
 	}
-	_, _ = io.ReadAll(resp.Body)
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, MaxPageSize))
 	return nil
 }
 
-func (c *Client) PlaceOrder(ctx context.Context) (string, error) {
+// PlacedOrder is the result of a successful PlaceOrder call.
+type PlacedOrder struct {
+	OrderID string
+	// ConfirmationURL is the order confirmation page the site redirected to.
+	ConfirmationURL string
+}
+
+func (c *Client) PlaceOrder(ctx context.Context) (PlacedOrder, error) {
 	client := *c.HTTP
 	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 		return http.ErrUseLastResponse
@@ -115,34 +129,43 @@ func (c *Client) PlaceOrder(ctx context.Context) (string, error) {
 
 	req, err := http.NewRequest("GET", c.newURL("/on/demandware.store/Sites-Bis-Site/default/Wallet-WalletPlaceOrder"), nil)
 	if err != nil {
-		return "", err
+		return PlacedOrder{}, err
 	}
 	c.applyHeaders(req)
-	if c.Throttle > 0 {
-		select {
-		case <-ctx.Done():
-			return "", ctx.Err()
-		case <-time.After(c.Throttle):
-		}
+	if err := c.wait(ctx); err != nil {
+		return PlacedOrder{}, err
 	}
 	resp, err := client.Do(req.WithContext(ctx))
 	if err != nil {
-		return "", err
+		return PlacedOrder{}, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusFound && resp.StatusCode != http.StatusSeeOther {
-		return "", fmt.Errorf("place order failed: %s", resp.Status)
+		return PlacedOrder{}, fmt.Errorf("place order failed: %s", resp.Status)
 	}
 	location := resp.Header.Get("Location")
 	if location == "" {
-		return "", errors.New("no redirect location from wallet place order")
+		return PlacedOrder{}, errors.New("no redirect location from wallet place order")
 	}
 	if !strings.Contains(location, "/orderplaced") {
-		return "", fmt.Errorf("unexpected redirect location: %s", location)
+		return PlacedOrder{}, fmt.Errorf("unexpected redirect location: %s", location)
+	}
+	placed := PlacedOrder{ConfirmationURL: location}
+	if match := orderIDRegex.FindStringSubmatch(location); len(match) > 1 {
+		placed.OrderID = match[1]
+	}
+	return placed, nil
+}
+
+// FetchOrderConfirmation loads the page PlaceOrder redirected to so the
+// promised delivery window can be read from it.
+func (c *Client) FetchOrderConfirmation(ctx context.Context, placed PlacedOrder) (string, error) {
+	if placed.ConfirmationURL == "" {
+		return "", errors.New("no order confirmation location")
 	}
-	match := orderIDRegex.FindStringSubmatch(location)
-	if len(match) > 1 {
-		return match[1], nil
+	path := placed.ConfirmationURL
+	if u, err := url.Parse(path); err == nil && u.IsAbs() {
+		path = u.RequestURI()
 	}
-	return "", nil
+	return c.fetchPageWithRetry(ctx, path, "")
 }
@@ -0,0 +1,134 @@
+package bisleri
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	ordersPath = "/my-orders"
+	// maxOrderPages bounds a history crawl in case pagination links loop.
+	maxOrderPages = 60
+)
+
+// orderPageLinkSelector matches pagination controls on the order history.
+const orderPageLinkSelector = `.pagination a[href], a[rel="next"][href], a.page-link[href], .show-more [data-url], .order-history-pagination a[href]`
+
+// ExtractOrderPageLinks returns the other order-history pages a /my-orders
+// page links to, as request paths. Links off the order history are ignored.
+func ExtractOrderPageLinks(html string) []string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	var links []string
+	doc.Find(orderPageLinkSelector).Each(func(_ int, s *goquery.Selection) {
+		href := firstAttr(s, "href", "data-url")
+		u, err := url.Parse(strings.TrimSpace(href))
+		if err != nil || href == "" || strings.HasPrefix(href, "#") {
+			return
+		}
+		if u.IsAbs() && !strings.HasSuffix(u.Hostname(), "bisleri.com") {
+			return
+		}
+		if !strings.Contains(strings.ToLower(u.Path), "order") || u.RawQuery == "" {
+			return
+		}
+		path := u.RequestURI()
+		if !seen[path] {
+			seen[path] = true
+			links = append(links, path)
+		}
+	})
+	return links
+}
+
+// FetchOrderPages fetches the history pages linked from the first /my-orders
+// page, and any further pages those link to, with up to workers requests in
+// flight. Requests still go through the client's throttle. progress, when
+// set, is called after each page with the pages done and found so far. The
+// bodies come back in the order the pages were found; any failure fails the
+// crawl, since a partial history would drop orders from the local copy.
+func (c *Client) FetchOrderPages(ctx context.Context, first string, workers int, progress func(done, total int)) ([]string, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type page struct {
+		index int
+		body  string
+	}
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		seen     = map[string]bool{ordersPath: true}
+		pages    []page
+		done     int
+		firstErr error
+		slots    = make(chan struct{}, workers)
+	)
+	var enqueue func(html string)
+	enqueue = func(html string) {
+		for _, link := range ExtractOrderPageLinks(html) {
+			mu.Lock()
+			if seen[link] || len(seen) > maxOrderPages {
+				mu.Unlock()
+				continue
+			}
+			seen[link] = true
+			index := len(seen)
+			mu.Unlock()
+
+			wg.Add(1)
+			go func(link string) {
+				defer wg.Done()
+				select {
+				case slots <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				u, _ := url.Parse(link)
+				body, err := c.fetchPageWithRetry(ctx, link, u.Path)
+				<-slots
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("order history page %s: %w", link, err)
+						cancel()
+					}
+					mu.Unlock()
+					return
+				}
+				pages = append(pages, page{index: index, body: body})
+				done++
+				if progress != nil {
+					progress(done, len(seen)-1)
+				}
+				mu.Unlock()
+				enqueue(body)
+			}(link)
+		}
+	}
+	enqueue(first)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].index < pages[j].index })
+	bodies := make([]string, len(pages))
+	for i, p := range pages {
+		bodies[i] = p.body
+	}
+	return bodies, nil
+}
@@ -0,0 +1,79 @@
+package bisleri
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestExtractOrderPageLinks(t *testing.T) {
+	html := `<ul class="pagination">
+		<li><a href="#">Prev</a></li>
+		<li><a href="/my-orders?page=2">2</a></li>
+		<li><a href="https://www.bisleri.com/my-orders?page=3">3</a></li>
+		<li><a href="/my-orders?page=2">Next</a></li>
+		<li><a href="https://example.com/my-orders?page=4">4</a></li>
+		<li><a href="/products?page=2">Shop</a></li>
+	</ul>`
+	got := ExtractOrderPageLinks(html)
+	want := []string{"/my-orders?page=2", "/my-orders?page=3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ExtractOrderPageLinks = %v, want %v", got, want)
+	}
+}
+
+func TestFetchOrderPages(t *testing.T) {
+	links := map[string]string{
+		"1": `<a rel="next" href="/my-orders?page=2">2</a><ul class="pagination"><li><a href="/my-orders?page=3">3</a></li></ul>`,
+		"2": `<div class="order-section">BS-2</div>`,
+		"3": `<div class="order-section">BS-3</div><a rel="next" href="/my-orders?page=4">4</a>`,
+		"4": `<div class="order-section">BS-4</div>`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, links[r.URL.Query().Get("page")])
+	}))
+	defer srv.Close()
+
+	c := NewClient(&http.Client{}, nil)
+	c.BaseURL = srv.URL
+	c.Throttle = 0
+	var mu sync.Mutex
+	var last [2]int
+	bodies, err := c.FetchOrderPages(context.Background(), links["1"], 2, func(done, total int) {
+		mu.Lock()
+		last = [2]int{done, total}
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatalf("FetchOrderPages: %v", err)
+	}
+	if want := []string{links["2"], links["3"], links["4"]}; !reflect.DeepEqual(bodies, want) {
+		t.Fatalf("bodies = %q", bodies)
+	}
+	if last != [2]int{3, 3} {
+		t.Fatalf("final progress = %v", last)
+	}
+}
+
+func TestThrottleSpacesConcurrentRequests(t *testing.T) {
+	c := NewClient(&http.Client{}, nil)
+	c.Throttle = 20 * time.Millisecond
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.wait(context.Background())
+		}()
+	}
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Fatalf("3 concurrent requests passed the throttle in %s", elapsed)
+	}
+}
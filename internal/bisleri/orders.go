@@ -1,48 +1,128 @@
 package bisleri
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
 // Order represents a Bisleri order
 type Order struct {
-	OrderID    string
-	Date       string
-	Status     string
-	Total      string
-	Items      string
-	RawHTML    string // For debugging
+	OrderID string
+	Date    string
+	Status  string
+	Total   string
+	Items   string
+	// Delivered is the delivery time shown on delivered orders, as printed.
+	Delivered string
+	RawHTML   string // For debugging
+}
+
+// OrderDiagnostics says how much of an orders page was understood, so a
+// layout change that drops orders is noticed rather than silently shrinking
+// the history.
+type OrderDiagnostics struct {
+	// Cards counts order cards, not wrappers around other cards or nested
+	// repeats of an order already listed.
+	Cards   int
+	Parsed  int
+	Skipped []SkippedCard
+	// NoDate and NoTotal count parsed orders missing those fields, which
+	// stats cannot place or sum.
+	NoDate  int
+	NoTotal int
+}
+
+// SkippedCard is an order card that yielded no order; Card is its 1-based
+// position on the page.
+type SkippedCard struct {
+	Card   int
+	Reason string
+}
+
+// Complete reports whether every card became an order with a date and total.
+func (d OrderDiagnostics) Complete() bool {
+	return len(d.Skipped) == 0 && d.NoDate == 0 && d.NoTotal == 0
+}
+
+// Add merges the diagnostics of another page.
+func (d *OrderDiagnostics) Add(other OrderDiagnostics) {
+	d.Cards += other.Cards
+	d.Parsed += other.Parsed
+	d.Skipped = append(d.Skipped, other.Skipped...)
+	d.NoDate += other.NoDate
+	d.NoTotal += other.NoTotal
+}
+
+// String summarises the diagnostics, e.g. "parsed 9 of 10 order cards;
+// skipped 1 (no order ID in .order-section)".
+func (d OrderDiagnostics) String() string {
+	s := fmt.Sprintf("parsed %d of %d order cards", d.Parsed, d.Cards)
+	if len(d.Skipped) > 0 {
+		counts := map[string]int{}
+		var reasons []string
+		for _, c := range d.Skipped {
+			if counts[c.Reason] == 0 {
+				reasons = append(reasons, c.Reason)
+			}
+			counts[c.Reason]++
+		}
+		for i, r := range reasons {
+			if counts[r] > 1 {
+				reasons[i] = fmt.Sprintf("%s ×%d", r, counts[r])
+			}
+		}
+		s += fmt.Sprintf("; skipped %d (%s)", len(d.Skipped), strings.Join(reasons, "; "))
+	}
+	if d.NoDate > 0 {
+		s += fmt.Sprintf("; %d without a date", d.NoDate)
+	}
+	if d.NoTotal > 0 {
+		s += fmt.Sprintf("; %d without a total", d.NoTotal)
+	}
+	return s
 }
 
 // ParseOrders extracts order information from the my-orders HTML page
 func ParseOrders(html string) ([]Order, error) {
+	orders, _, err := ParseOrdersWithDiagnostics(html)
+	return orders, err
+}
+
+// ParseOrdersWithDiagnostics is ParseOrders that also reports the cards it
+// skipped and why.
+func ParseOrdersWithDiagnostics(html string) ([]Order, OrderDiagnostics, error) {
+	var diag OrderDiagnostics
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
-		return nil, err
+		return nil, diag, err
 	}
 
 	var orders []Order
-
-
+	seen := map[string]bool{}
+	selector := orderCardSelector()
 
 	// Find all order containers
 	// Based on debug HTML, orders are wrapped in .all-order
-	doc.Find(".all-order").Each(func(_ int, s *goquery.Selection) {
+	doc.Find(selector).Each(func(_ int, s *goquery.Selection) {
 		order := Order{}
-		
-		orderText := s.Find(".order-section").Text()
+
+		section := s.Find(".order-section")
+		orderText := section.Text()
 		if match := orderTextRegex.FindStringSubmatch(orderText); len(match) > 0 {
 			order.OrderID = match[0]
 		}
+		if order.OrderID == "" {
+			// A wrapper's inner cards are diagnosed on their own.
+			if s.Find(selector).Length() == 0 {
+				diag.Cards++
+				diag.Skipped = append(diag.Skipped, SkippedCard{Card: diag.Cards, Reason: missingOrderIDReason(s, section)})
+			}
+			return
+		}
 
-
-
-
-		
 		// Extract Date
 		// Structure: Found <div class="order-date">...</div> or "Order Placed" block
 		// Preference: .order-date seems most specific from grep
@@ -58,7 +138,7 @@ func ParseOrders(html string) ([]Order, error) {
 			})
 		}
 		// Clean up date (remove timestamps if needed by caller, but keeping raw here is fine)
-		// stats command parses "02/01/2006"
+		// sync parses it with ParseDate
 
 		// Extract Total
 		s.Find(".row div").Each(func(_ int, col *goquery.Selection) {
@@ -71,49 +151,59 @@ func ParseOrders(html string) ([]Order, error) {
 		// Extract Status
 		// Structure: <div class="order-status-pending">Pending</div>
 		// We try to find any element with class starting with order-status-
-		s.Find("div").EachWithBreak(func(_ int, div *goquery.Selection) bool {
-			class, _ := div.Attr("class")
-			if strings.Contains(class, "order-status-") {
-				order.Status = strings.TrimSpace(div.Text())
-				return false
-			}
-			return true
-		})
+		if div := s.Find(`div[class*="order-status-"]`).First(); div.Length() > 0 {
+			order.Status = strings.TrimSpace(div.Text())
+		}
 
 		// Items
 		order.Items = strings.TrimSpace(s.Find(".one-time-order").Text())
+		order.Delivered = deliveredAt(s)
 
-		if order.OrderID != "" {
+		// Nested order cards would otherwise list the same order twice.
+		if !seen[order.OrderID] {
+			seen[order.OrderID] = true
 			orders = append(orders, order)
+			diag.Cards++
+			diag.Parsed++
+			if order.Date == "" {
+				diag.NoDate++
+			}
+			if order.Total == "" {
+				diag.NoTotal++
+			}
 		}
 	})
-	
-	return orders, nil
+
+	return orders, diag, nil
+}
+
+// missingOrderIDReason explains why a card yielded no order ID.
+func missingOrderIDReason(card, section *goquery.Selection) string {
+	if section.Length() == 0 {
+		if id := orderTextRegex.FindString(card.Text()); id != "" {
+			return "no .order-section; " + id + " appears elsewhere on the card"
+		}
+		return "no .order-section"
+	}
+	if id := orderTextRegex.FindString(card.Text()); id != "" {
+		return "order ID " + id + " is outside .order-section"
+	}
+	return "no order ID in .order-section"
 }
 
 var orderTextRegex = regexp.MustCompile(`BS-[A-Z0-9-]+`)
 
-// FormatOrderDate attempts to parse and format the order date
-func FormatOrderDate(dateStr string) string {
-	dateStr = strings.TrimSpace(dateStr)
-	if dateStr == "" {
-		return ""
-	}
+var deliveredOnRegex = regexp.MustCompile(`(?i)delivered\s+(?:on|at)\s*:?\s*([0-9A-Za-z/ ,-]+?\d{4}(?:,?\s*\d{1,2}:\d{2}\s*(?:AM|PM)?)?)`)
 
-	// Try common formats
-	formats := []string{
-		"02/01/2006",
-		"2006-01-02",
-		"Jan 02, 2006",
-		"02 Jan 2006",
-		time.RFC3339,
+// deliveredAt returns the delivery time on a delivered order's card or
+// tracking section, from a dedicated element or a "Delivered on ..." line.
+func deliveredAt(s *goquery.Selection) string {
+	if text := strings.TrimSpace(s.Find(".delivered-date, .delivery-date, .order-delivered-date").First().Text()); text != "" {
+		return strings.Join(strings.Fields(text), " ")
 	}
-
-	for _, format := range formats {
-		if t, err := time.Parse(format, dateStr); err == nil {
-			return t.Format("02 Jan 2006")
-		}
+	text := strings.Join(strings.Fields(s.Text()), " ")
+	if match := deliveredOnRegex.FindStringSubmatch(text); len(match) > 1 {
+		return strings.TrimSpace(match[1])
 	}
-
-	return dateStr // Return as-is if can't parse
+	return ""
 }
@@ -0,0 +1,193 @@
+package bisleri
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// WatchedSelector is a CSS selector an extractor reads, with what it reads it
+// for. Keep the list in step with the parsers.
+type WatchedSelector struct {
+	Selector string
+	UsedBy   string
+}
+
+var watchedSelectors = []WatchedSelector{
+	{"input[name=csrf_token]", "csrf token"},
+	{"input[name=shipmentUUID][type=hidden]", "shipment uuid"},
+	{"[data-shipment-uuid]", "shipment uuid"},
+	{"[data-uuid]", "cart items"},
+	{"select.quantity option[selected]", "cart quantity"},
+	{"form", "checkout form"},
+	{"select#citySelect option", "city"},
+	{"[data-address-id], [data-addressid], [data-address_id]", "addresses"},
+	{".address-card, .addressCard, .address-book-card, .address-book", "addresses"},
+	{".wallet-amount-balance-green, .wallet-amount-balance", "wallet"},
+	{".bisleri-wallet", "wallet"},
+	{".grand-total-sum", "order total"},
+	{".all-order", "orders"},
+	{".order-section", "orders"},
+	{".order-date", "orders"},
+	{".product[data-pid], .product-tile[data-pid]", "products"},
+	{".offer-card, .coupon-card, [data-coupon-code]", "offers"},
+}
+
+// WatchedSelectors returns the selectors the extractors read, selector
+// overrides first.
+func WatchedSelectors() []WatchedSelector {
+	var list []WatchedSelector
+	for _, o := range []WatchedSelector{
+		{overrides.CartItem, "cart items (override)"},
+		{overrides.OrderCard, "orders (override)"},
+		{overrides.CSRFToken, "csrf token (override)"},
+		{overrides.OrderTotal, "order total (override)"},
+	} {
+		if o.Selector != "" {
+			list = append(list, o)
+		}
+	}
+	return append(list, watchedSelectors...)
+}
+
+// SelectorCount is how many elements a watched selector matches in each page.
+type SelectorCount struct {
+	WatchedSelector
+	Before, After int
+}
+
+// Changed reports whether the selector went from matching to not, or back;
+// a different number of matches (more cart lines) is not a markup change.
+func (c SelectorCount) Changed() bool {
+	return (c.Before == 0) != (c.After == 0)
+}
+
+// ValueChange is one extractor result in each page.
+type ValueChange struct {
+	Field, Before, After string
+}
+
+// PageDiff is a structural comparison of two snapshots of a page.
+type PageDiff struct {
+	Selectors      []SelectorCount
+	Values         []ValueChange
+	ClassesAdded   []string
+	ClassesRemoved []string
+	IDsAdded       []string
+	IDsRemoved     []string
+	FieldsAdded    []string
+	FieldsRemoved  []string
+}
+
+// DiffPages compares two saved pages: the watched selectors' match counts,
+// what the extractors get out of each, and the class names, element IDs and
+// form field names that appeared or disappeared.
+func DiffPages(before, after string) (PageDiff, error) {
+	docBefore, err := goquery.NewDocumentFromReader(strings.NewReader(before))
+	if err != nil {
+		return PageDiff{}, err
+	}
+	docAfter, err := goquery.NewDocumentFromReader(strings.NewReader(after))
+	if err != nil {
+		return PageDiff{}, err
+	}
+	var diff PageDiff
+	for _, w := range WatchedSelectors() {
+		diff.Selectors = append(diff.Selectors, SelectorCount{
+			WatchedSelector: w,
+			Before:          docBefore.Find(w.Selector).Length(),
+			After:           docAfter.Find(w.Selector).Length(),
+		})
+	}
+	valuesBefore, valuesAfter := pageValues(before), pageValues(after)
+	for _, field := range pageValueFields {
+		diff.Values = append(diff.Values, ValueChange{Field: field, Before: valuesBefore[field], After: valuesAfter[field]})
+	}
+	diff.ClassesAdded, diff.ClassesRemoved = setDiff(attrWords(docBefore, "class"), attrWords(docAfter, "class"))
+	diff.IDsAdded, diff.IDsRemoved = setDiff(attrWords(docBefore, "id"), attrWords(docAfter, "id"))
+	diff.FieldsAdded, diff.FieldsRemoved = setDiff(fieldNames(docBefore), fieldNames(docAfter))
+	return diff, nil
+}
+
+var pageValueFields = []string{
+	"cart items", "cart count", "checkout form", "csrf token", "shipment uuid",
+	"addresses", "selected city", "city options", "wallet", "order total",
+	"orders", "products", "timeslots",
+}
+
+// pageValues runs the extractors over a page. Tokens are reported as found
+// or missing since their values change on every load.
+func pageValues(html string) map[string]string {
+	found := func(err error) string {
+		if err != nil {
+			return "missing"
+		}
+		return "found"
+	}
+	v := map[string]string{}
+	cart := ParseCartPage(html)
+	v["cart items"] = strconv.Itoa(len(cart.Items))
+	if cart.HasCount {
+		v["cart count"] = strconv.Itoa(cart.Count)
+	}
+	if form, err := ExtractCheckoutForm(html); err == nil {
+		v["checkout form"] = strings.TrimSpace(form.Method + " " + form.Action)
+	}
+	_, err := ExtractCSRFToken(html)
+	v["csrf token"] = found(err)
+	_, err = ExtractShipmentUUID(html)
+	v["shipment uuid"] = found(err)
+	addresses, _ := ParseAddressCandidates(html)
+	v["addresses"] = strconv.Itoa(len(addresses))
+	v["selected city"] = cart.SelectedCity
+	v["city options"] = strconv.Itoa(len(cart.CityOptions))
+	v["wallet"], _ = ExtractWalletBalance(html)
+	v["order total"], _ = ExtractOrderTotal(html)
+	orders, _ := ParseOrders(html)
+	v["orders"] = strconv.Itoa(len(orders))
+	v["products"] = strconv.Itoa(len(ParseProducts(html)))
+	v["timeslots"] = strconv.Itoa(len(ExtractTimeslots(html)))
+	return v
+}
+
+// attrWords collects the space-separated words of an attribute across the
+// document.
+func attrWords(doc *goquery.Document, attr string) map[string]bool {
+	words := map[string]bool{}
+	doc.Find("[" + attr + "]").Each(func(_ int, s *goquery.Selection) {
+		value, _ := s.Attr(attr)
+		for _, w := range strings.Fields(value) {
+			words[w] = true
+		}
+	})
+	return words
+}
+
+// fieldNames collects form field names, prefixed with the tag.
+func fieldNames(doc *goquery.Document) map[string]bool {
+	names := map[string]bool{}
+	doc.Find("input[name], select[name], textarea[name], button[name]").Each(func(_ int, s *goquery.Selection) {
+		name, _ := s.Attr("name")
+		names[fmt.Sprintf("%s[name=%s]", goquery.NodeName(s), name)] = true
+	})
+	return names
+}
+
+func setDiff(before, after map[string]bool) (added, removed []string) {
+	for k := range after {
+		if !before[k] {
+			added = append(added, k)
+		}
+	}
+	for k := range before {
+		if !after[k] {
+			removed = append(removed, k)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
@@ -0,0 +1,46 @@
+package bisleri
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDiffPages(t *testing.T) {
+	before, err := os.ReadFile(filepath.Join("testdata", "fixtures", "cart.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The site renames the line item attribute, the checkout button and the
+	// header badge.
+	after := strings.NewReplacer(`data-uuid=`, `data-line-uuid=`, `name="checkout"`, `name="proceed"`, `Cart 2 Items`, `Basket`).Replace(string(before))
+
+	diff, err := DiffPages(string(before), after)
+	if err != nil {
+		t.Fatalf("DiffPages: %v", err)
+	}
+	var changed []string
+	for _, s := range diff.Selectors {
+		if s.Changed() {
+			changed = append(changed, s.Selector)
+		}
+	}
+	if !slices.Equal(changed, []string{"[data-uuid]"}) {
+		t.Errorf("changed selectors = %v", changed)
+	}
+	values := map[string]ValueChange{}
+	for _, v := range diff.Values {
+		values[v.Field] = v
+	}
+	if v := values["cart count"]; v.Before != "2" || v.After != "" {
+		t.Errorf("cart count = %+v", v)
+	}
+	if v := values["csrf token"]; v.Before != v.After {
+		t.Errorf("csrf token should not change: %+v", v)
+	}
+	if !slices.Equal(diff.FieldsRemoved, []string{"button[name=checkout]"}) || !slices.Equal(diff.FieldsAdded, []string{"button[name=proceed]"}) {
+		t.Errorf("fields removed %v, added %v", diff.FieldsRemoved, diff.FieldsAdded)
+	}
+}
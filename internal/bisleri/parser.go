@@ -5,9 +5,9 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
-	"strconv"
 	"strings"
 
+	"bislericli/internal/address"
 	"bislericli/internal/store"
 
 	"github.com/PuerkitoBio/goquery"
@@ -46,16 +46,38 @@ var (
 	cartCountAltRegex = regexp.MustCompile(`(?i)\b(\d+)\s*Item\(s\)`)
 	productIDRegex    = regexp.MustCompile(`BIS-[A-Z0-9-]+`)
 	updateQtyRegex    = regexp.MustCompile(`Cart-UpdateQuantity\?[^"'\s]+`)
+	hexIDRegex        = regexp.MustCompile(`^[a-f0-9]{16,}$`)
+	labeledTotalRegex = regexp.MustCompile(`(?i)(?:total|payable)[^₹]{0,40}₹\s*([0-9][0-9.,]*)`)
+	totalRegexes      = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)Total\s*:?\s*₹\s*([0-9][0-9.,]*)`),
+		regexp.MustCompile(`(?i)Payable\s*:?\s*₹\s*([0-9][0-9.,]*)`),
+		regexp.MustCompile(`(?i)Amount\s*:?\s*₹\s*([0-9][0-9.,]*)`),
+	}
 )
 
+// maxCartItems bounds the regex fallback of ExtractCartItems; a cart never
+// has this many lines, so more matches mean the page is not a cart.
+const maxCartItems = 50
+
 func ExtractCSRFToken(html string) (string, error) {
+	return csrfToken(html, nil)
+}
+
+// csrfToken finds the CSRF token in html, falling back to the parsed doc
+// (parsed here if nil) when the regex misses.
+func csrfToken(html string, doc *goquery.Document) (string, error) {
+	if token := csrfOverride(html); token != "" {
+		return token, nil
+	}
 	match := csrfRegex.FindStringSubmatch(html)
 	if len(match) > 1 {
 		return match[1], nil
 	}
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
-	if err != nil {
-		return "", err
+	if doc == nil {
+		var err error
+		if doc, err = goquery.NewDocumentFromReader(strings.NewReader(html)); err != nil {
+			return "", err
+		}
 	}
 	if val, ok := doc.Find("input[name=csrf_token]").Attr("value"); ok && val != "" {
 		return val, nil
@@ -71,7 +93,7 @@ func ExtractShipmentUUID(html string) (string, error) {
 		if val, ok := doc.Find("input[name=shipmentUUID][type=hidden]").Attr("value"); ok && val != "" {
 			val = strings.TrimSpace(val)
 			// Validate it's a hex string (not an address ID)
-			if regexp.MustCompile(`^[a-f0-9]{16,}$`).MatchString(val) {
+			if hexIDRegex.MatchString(val) {
 				return val, nil
 			}
 		}
@@ -191,6 +213,7 @@ func parseAddressFromText(text string) store.Address {
 	if addr.Country == "" {
 		addr.Country = "IN"
 	}
+	address.NormalizeStateCode(&addr)
 	return addr
 }
 
@@ -265,6 +288,17 @@ func ExtractOrderTotal(html string) (string, bool) {
 		return "", false
 	}
 
+	if overrides.OrderTotal != "" {
+		if val := strings.TrimSpace(doc.Find(overrides.OrderTotal).First().Text()); val != "" {
+			return val, true
+		}
+	}
+	if overrides.totalRegex != nil {
+		if match := overrides.totalRegex.FindStringSubmatch(html); len(match) > 1 && match[1] != "" {
+			return "₹" + strings.TrimPrefix(strings.TrimSpace(match[1]), "₹"), true
+		}
+	}
+
 	// Priority 1: Specific class for grand total
 	if val := strings.TrimSpace(doc.Find(".grand-total-sum").Text()); val != "" {
 		return val, true
@@ -272,63 +306,32 @@ func ExtractOrderTotal(html string) (string, bool) {
 
 	// Priority 2: Regex patterns
 	// Try stricter regex first: "Total: ₹ 200" or "Order Total ₹200"
-	regexes := []*regexp.Regexp{
-		regexp.MustCompile(`(?i)Total\s*:?\s*₹\s*([0-9.,]+)`),
-		regexp.MustCompile(`(?i)Payable\s*:?\s*₹\s*([0-9.,]+)`),
-		regexp.MustCompile(`(?i)Amount\s*:?\s*₹\s*([0-9.,]+)`),
-	}
-	
-	for _, re := range regexes {
+	for _, re := range totalRegexes {
 		if match := re.FindStringSubmatch(html); len(match) > 1 {
 			return "₹" + match[1], true
 		}
 	}
 
-	var found string
-	// Fallback: Look for elements with "total" in class or text, containing price
-	doc.Find("div, span, p, label").EachWithBreak(func(_ int, s *goquery.Selection) bool {
-		text := strings.TrimSpace(s.Text())
-		if strings.Contains(text, "₹") {
-			// Check context
-			lower := strings.ToLower(text)
-			if strings.Contains(lower, "total") || strings.Contains(lower, "payable") {
-				// Extract amount
-				re := regexp.MustCompile(`₹\s*([0-9.,]+)`)
-				if match := re.FindStringSubmatch(text); len(match) > 1 {
-					found = "₹" + match[1]
-					return false
-				}
-			}
-		}
-		return true
-	})
-
-	if found != "" {
-		return found, true
+	// Fallback: the page text, where markup between the label and the amount
+	// is gone. Matching once on the whole text keeps this linear; checking
+	// every element's text was quadratic on nested markup and matched the
+	// outermost wrapper first.
+	if match := labeledTotalRegex.FindStringSubmatch(doc.Text()); len(match) > 1 {
+		return "₹" + match[1], true
 	}
 
 	return "", false
 }
 
-func ParseINRAmount(value string) (float64, bool) {
-	clean := strings.TrimSpace(value)
-	clean = strings.TrimPrefix(clean, "₹")
-	clean = strings.ReplaceAll(clean, ",", "")
-	if clean == "" {
-		return 0, false
-	}
-	amount, err := strconv.ParseFloat(clean, 64)
-	if err != nil {
-		return 0, false
-	}
-	return amount, true
-}
-
 func ExtractCheckoutForm(html string) (CheckoutForm, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
 		return CheckoutForm{}, err
 	}
+	return checkoutForm(doc, html)
+}
+
+func checkoutForm(doc *goquery.Document, html string) (CheckoutForm, error) {
 	var form *goquery.Selection
 	bestScore := -1
 	doc.Find("form").Each(func(_ int, s *goquery.Selection) {
@@ -409,7 +412,7 @@ func ExtractCheckoutForm(html string) (CheckoutForm, error) {
 		}
 	})
 	if fields.Get("csrf_token") == "" {
-		if token, err := ExtractCSRFToken(html); err == nil {
+		if token, err := csrfToken(html, doc); err == nil {
 			fields.Set("csrf_token", token)
 		}
 	}
@@ -500,6 +503,10 @@ func ExtractSelectedCity(html string) (string, bool) {
 	if err != nil {
 		return "", false
 	}
+	return selectedCity(doc)
+}
+
+func selectedCity(doc *goquery.Document) (string, bool) {
 	sel := doc.Find("select#citySelect option[selected]").First()
 	if sel.Length() == 0 {
 		return "", false
@@ -517,6 +524,10 @@ func ExtractCityOptions(html string) []string {
 	if err != nil {
 		return nil
 	}
+	return cityOptions(doc)
+}
+
+func cityOptions(doc *goquery.Document) []string {
 	seen := map[string]bool{}
 	var options []string
 	doc.Find("select#citySelect option").Each(func(_ int, s *goquery.Selection) {
@@ -545,13 +556,49 @@ func ExtractCartItems(html string) []CartItem {
 	if err != nil {
 		return nil
 	}
+	return cartItems(doc, html)
+}
+
+func cartItems(doc *goquery.Document, html string) []CartItem {
 	var items []CartItem
+	if overrides.CartItem != "" {
+		doc.Find(overrides.CartItem).Each(func(_ int, s *goquery.Selection) {
+			uuid, ok := s.Attr("data-uuid")
+			if !ok {
+				uuid, _ = s.Find("[data-uuid]").First().Attr("data-uuid")
+			}
+			if uuid = strings.TrimSpace(uuid); uuid == "" {
+				return
+			}
+			items = append(items, CartItem{
+				ProductID: extractProductIDFromSelection(s),
+				UUID:      uuid,
+				Quantity:  extractQuantityFromSelection(s),
+			})
+		})
+		if len(items) > 0 {
+			return items
+		}
+	}
+	// A line item repeats its UUID on the quantity picker and remove button;
+	// the outermost element comes first and the inner ones only fill gaps.
+	index := map[string]int{}
 	doc.Find("[data-uuid]").Each(func(_ int, s *goquery.Selection) {
 		uuid, _ := s.Attr("data-uuid")
 		uuid = strings.TrimSpace(uuid)
 		if uuid == "" {
 			return
 		}
+		if i, ok := index[uuid]; ok {
+			if items[i].ProductID == "" {
+				items[i].ProductID = extractProductIDFromSelection(s)
+			}
+			if items[i].Quantity == 0 {
+				items[i].Quantity = extractQuantityFromSelection(s)
+			}
+			return
+		}
+		index[uuid] = len(items)
 		productID := extractProductIDFromSelection(s)
 		qty := extractQuantityFromSelection(s)
 		items = append(items, CartItem{
@@ -568,56 +615,79 @@ func ExtractCartItems(html string) []CartItem {
 	if len(actionItems) > 0 {
 		return actionItems
 	}
-	// Regex fallback (less reliable).
-	idx := 0
-	for {
-		pos := uuidRegex.FindStringSubmatchIndex(html[idx:])
-		if pos == nil {
+	// Regex fallback (less reliable): look for the product ID and quantity
+	// near each UUID, once per UUID.
+	seen := map[string]bool{}
+	for _, loc := range uuidRegex.FindAllStringSubmatchIndex(html, -1) {
+		uuid := html[loc[4]:loc[5]]
+		if seen[uuid] {
+			continue
+		}
+		seen[uuid] = true
+		if len(items) == maxCartItems {
 			break
 		}
-		match := uuidRegex.FindStringSubmatch(html[idx:])
-		if len(match) > 2 {
-			windowStart := idx + pos[0] - 800
-			if windowStart < 0 {
-				windowStart = 0
-			}
-			windowEnd := idx + pos[1] + 800
-			if windowEnd > len(html) {
-				windowEnd = len(html)
-			}
-			window := html[windowStart:windowEnd]
-			productID := ""
-			if idMatch := productIDRegex.FindStringSubmatch(window); len(idMatch) > 0 {
-				productID = idMatch[0]
-			}
-			qty := 0
-			qtyMatch := qtyRegex.FindStringSubmatch(window)
-			if len(qtyMatch) > 1 {
-				qty = atoiSafe(qtyMatch[1])
-			}
-			items = append(items, CartItem{
-				ProductID: productID,
-				UUID:      match[2],
-				Quantity:  qty,
-			})
+		window := html[max(0, loc[0]-800):min(len(html), loc[1]+800)]
+		productID := ""
+		if idMatch := productIDRegex.FindStringSubmatch(window); len(idMatch) > 0 {
+			productID = idMatch[0]
+		}
+		qty := 0
+		if qtyMatch := qtyRegex.FindStringSubmatch(window); len(qtyMatch) > 1 {
+			qty = atoiSafe(qtyMatch[1])
 		}
-		idx = idx + pos[1]
+		items = append(items, CartItem{
+			ProductID: productID,
+			UUID:      uuid,
+			Quantity:  qty,
+		})
 	}
 	return items
 }
 
 func ExtractCartCount(html string) (int, bool) {
-	match := cartCountRegex.FindStringSubmatch(html)
+	lower := asciiLower(html)
+	match := matchBefore(html, lower, "items", cartCountRegex)
 	if len(match) > 1 {
 		return atoiSafe(match[1]), true
 	}
-	match = cartCountAltRegex.FindStringSubmatch(html)
+	match = matchBefore(html, lower, "item(s)", cartCountAltRegex)
 	if len(match) > 1 {
 		return atoiSafe(match[1]), true
 	}
 	return 0, false
 }
 
+// matchBefore runs re over the text leading up to each occurrence of anchor
+// (lower-case, found in lower) instead of the whole page. A case-insensitive
+// scan of a multi-megabyte page is slow, and re's matches end in anchor.
+func matchBefore(html, lower, anchor string, re *regexp.Regexp) []string {
+	const span = 64
+	for off := 0; ; {
+		i := strings.Index(lower[off:], anchor)
+		if i < 0 {
+			return nil
+		}
+		end := off + i + len(anchor)
+		if match := re.FindStringSubmatch(html[max(0, end-len(anchor)-span):end]); match != nil {
+			return match
+		}
+		off = end
+	}
+}
+
+// asciiLower lower-cases ASCII letters only, so byte offsets into the
+// result line up with s.
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
 func ExtractCartItem(html, productID string) (string, int, bool) {
 	items := ExtractCartItems(html)
 	for _, item := range items {
@@ -628,7 +698,10 @@ func ExtractCartItem(html, productID string) (string, int, bool) {
 	return "", 0, false
 }
 
+// atoiSafe reads the first run of digits in value, saturating instead of
+// overflowing on absurdly long numbers.
 func atoiSafe(value string) int {
+	const limit = 1 << 30
 	n := 0
 	found := false
 	for _, r := range value {
@@ -639,9 +712,11 @@ func atoiSafe(value string) int {
 			continue
 		}
 		found = true
-		n = n*10 + int(r-'0')
+		if n < limit {
+			n = n*10 + int(r-'0')
+		}
 	}
-	return n
+	return min(n, limit)
 }
 
 func extractProductIDFromSelection(s *goquery.Selection) string {
@@ -676,6 +751,11 @@ func extractProductIDFromSelection(s *goquery.Selection) string {
 }
 
 func extractQuantityFromSelection(s *goquery.Selection) int {
+	if option := s.Find("select.quantity option[selected]").AddSelection(s.Filter("select").Find("option[selected]")).First(); option.Length() > 0 {
+		if qty := atoiSafe(option.AttrOr("value", option.Text())); qty > 0 {
+			return qty
+		}
+	}
 	if input := s.Find("input"); input.Length() > 0 {
 		if val, ok := input.Attr("value"); ok {
 			if qty := atoiSafe(val); qty > 0 {
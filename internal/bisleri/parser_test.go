@@ -0,0 +1,178 @@
+package bisleri
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractOrderTotalPrefersLabeledAmount(t *testing.T) {
+	html := `<div class="page"><div>Deposit <span>₹ 150.00</span></div>
+<div class="summary"><span>Order total</span> <span>₹ 200.00</span></div></div>`
+	if got, ok := ExtractOrderTotal(html); !ok || got != "₹200.00" {
+		t.Fatalf("ExtractOrderTotal = %q, %t; want ₹200.00", got, ok)
+	}
+}
+
+func TestExtractCartItemsRegexFallbackDedupes(t *testing.T) {
+	html := `<script>var line = {uuid: "abcdef0123456789", pid: "BIS-20LTR01-90", quantity: 2};
+var again = {uuid: "abcdef0123456789"};</script>`
+	items := ExtractCartItems(html)
+	if len(items) != 1 || items[0].ProductID != "BIS-20LTR01-90" || items[0].Quantity != 2 {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+}
+
+func TestAtoiSafeSaturates(t *testing.T) {
+	if got := atoiSafe("qty 99999999999999999999999"); got != 1<<30 {
+		t.Fatalf("atoiSafe overflow = %d", got)
+	}
+	if got := atoiSafe("x12y34"); got != 12 {
+		t.Fatalf("atoiSafe = %d, want 12", got)
+	}
+}
+
+func TestParseOrdersNestedCards(t *testing.T) {
+	html := `<div class="all-order"><div class="all-order"><div class="order-section">Order BS-1001</div></div></div>`
+	orders, err := ParseOrders(html)
+	if err != nil {
+		t.Fatalf("ParseOrders: %v", err)
+	}
+	if len(orders) != 1 || orders[0].OrderID != "BS-1001" {
+		t.Fatalf("unexpected orders: %+v", orders)
+	}
+}
+
+func TestParseOrdersDiagnostics(t *testing.T) {
+	html := `<div class="all-order"><div class="order-section">Order BS-1</div><div class="order-date">05/01/2026</div>
+<div class="row"><div>Total Price <span>₹200</span></div></div></div>
+<div class="all-order"><div class="order-section">Order pending</div><p>Ref BS-2</p></div>
+<div class="all-order"><p>Something new</p></div>
+<div class="all-order"><div class="all-order"><div class="order-section">Order BS-3</div></div></div>`
+	orders, diag, err := ParseOrdersWithDiagnostics(html)
+	if err != nil {
+		t.Fatalf("ParseOrdersWithDiagnostics: %v", err)
+	}
+	if len(orders) != 2 || diag.Cards != 4 || diag.Parsed != 2 || diag.NoDate != 1 || diag.NoTotal != 1 || diag.Complete() {
+		t.Fatalf("orders = %+v, diagnostics = %+v", orders, diag)
+	}
+	want := []SkippedCard{{2, "order ID BS-2 is outside .order-section"}, {3, "no .order-section"}}
+	if len(diag.Skipped) != 2 || diag.Skipped[0] != want[0] || diag.Skipped[1] != want[1] {
+		t.Fatalf("skipped = %+v, want %+v", diag.Skipped, want)
+	}
+	got := diag.String()
+	if got != "parsed 2 of 4 order cards; skipped 2 (order ID BS-2 is outside .order-section; no .order-section); 1 without a date; 1 without a total" {
+		t.Errorf("String() = %q", got)
+	}
+
+	var total OrderDiagnostics
+	total.Add(diag)
+	total.Add(OrderDiagnostics{Cards: 1, Parsed: 0, Skipped: []SkippedCard{{1, "no .order-section"}}})
+	if s := total.String(); !strings.Contains(s, "parsed 2 of 5") || !strings.Contains(s, "no .order-section ×2") {
+		t.Errorf("merged String() = %q", s)
+	}
+}
+
+func TestParseOrdersDelivered(t *testing.T) {
+	html := `<div class="all-order"><div class="order-section">Order BS-1</div>
+<div class="order-status-delivered">Delivered</div><p>Delivered on 06/01/2026, 01:15 PM</p></div>
+<div class="all-order"><div class="order-section">Order BS-2</div><span class="delivery-date"> 07/01/2026,
+ 10:05 AM </span></div>
+<div class="all-order"><div class="order-section">Order BS-3</div><div class="order-status-pending">Pending</div></div>`
+	orders, err := ParseOrders(html)
+	if err != nil {
+		t.Fatalf("ParseOrders: %v", err)
+	}
+	var got []string
+	for _, o := range orders {
+		got = append(got, o.Delivered)
+	}
+	if want := []string{"06/01/2026, 01:15 PM", "07/01/2026, 10:05 AM", ""}; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("Delivered = %q, want %q", got, want)
+	}
+}
+
+var fuzzSeeds = []string{
+	"",
+	`<input type="hidden" name="csrf_token" value="tok">`,
+	`<div data-uuid="u1" data-pid="BIS-20LTR01-90"><input value="3"></div>`,
+	`uuid: "0123456789abcdef" quantity 4 BIS-20LTR01-90`,
+	`<a href="/Cart-UpdateQuantity?pid=BIS-1&uuid=u2&quantity=2">x</a>`,
+	`<div class="grand-total-sum">₹ 240.00</div>`,
+	`<div>Payable ₹</div>` + strings.Repeat("<div>", 50) + "Total ₹1" + strings.Repeat("</div>", 50),
+	`<div class="all-order"><div class="order-section">BS-42</div><div class="row"><div>Total Price <span>₹200</span></div></div></div>`,
+	strings.Repeat("uuid=aaaaaaaaaa ", 200),
+}
+
+func FuzzExtractCartItems(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, html string) {
+		for _, item := range ExtractCartItems(html) {
+			if strings.TrimSpace(item.UUID) == "" {
+				t.Fatalf("item without UUID: %+v", item)
+			}
+			if item.Quantity < 0 || item.Quantity > 1<<30 {
+				t.Fatalf("quantity out of range: %+v", item)
+			}
+		}
+	})
+}
+
+func FuzzExtractCSRFToken(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, html string) {
+		if token, err := ExtractCSRFToken(html); err == nil && token == "" {
+			t.Fatal("empty token without an error")
+		}
+	})
+}
+
+func FuzzExtractOrderTotal(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, html string) {
+		if total, ok := ExtractOrderTotal(html); ok && strings.TrimSpace(total) == "" {
+			t.Fatal("empty total reported as found")
+		}
+	})
+}
+
+func FuzzParseOrders(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, html string) {
+		orders, err := ParseOrders(html)
+		if err != nil {
+			return
+		}
+		seen := map[string]bool{}
+		for _, o := range orders {
+			if o.OrderID == "" || seen[o.OrderID] {
+				t.Fatalf("missing or duplicate order ID in %+v", orders)
+			}
+			seen[o.OrderID] = true
+		}
+	})
+}
+
+func TestExtractCartCount(t *testing.T) {
+	cases := map[string]int{
+		`<span class="minicart">Cart 3 Items</span>`:     3,
+		`<span>cart  12 items</span>`:                    12,
+		`<p>Your basket: 2 Item(s)</p>`:                  2,
+		strings.Repeat("₹ Item ", 1000) + `CART 1 ITEMS`: 1,
+	}
+	for html, want := range cases {
+		if got, ok := ExtractCartCount(html); !ok || got != want {
+			t.Fatalf("ExtractCartCount(%.40q) = %d, %t; want %d", html, got, ok, want)
+		}
+	}
+	if _, ok := ExtractCartCount(`<p>No items here</p>`); ok {
+		t.Fatal("expected no count")
+	}
+}
@@ -0,0 +1,143 @@
+package bisleri
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Product is a catalog entry from a product listing or search page.
+type Product struct {
+	ID    string
+	Name  string
+	Price string
+	// PackSize is the volume and pack count read from the name, e.g. "1 L x 12".
+	PackSize  string
+	Available bool
+}
+
+// productListPath is the catalog listing for the session's city.
+const productListPath = "/products"
+
+var (
+	jarSizeRegex    = regexp.MustCompile(`(?i)\b20\s*(?:l|ltr|ltrs|litre|litres|liter|liters)\b`)
+	jarExcludeRegex = regexp.MustCompile(`(?i)\b(?:empty|deposit|dispenser|stand|pump)\b`)
+	volumeRegex     = regexp.MustCompile(`(?i)\b(\d+(?:\.\d+)?)\s*(ml|l|ltr|ltrs|litre|litres|liter|liters)\b`)
+	packCountRegex  = regexp.MustCompile(`(?i)(?:pack\s+of\s+|\bx\s*)(\d+)\b|\b(\d+)\s*(?:x|pcs|bottles|pack)\b`)
+	soldOutRegex    = regexp.MustCompile(`(?i)\b(?:out\s+of\s+stock|sold\s+out|unavailable|not\s+available|coming\s+soon)\b`)
+)
+
+// ParseProducts extracts product tiles (SFCC data-pid elements) with their
+// names and displayed prices.
+func ParseProducts(html string) []Product {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil
+	}
+	var products []Product
+	seen := map[string]bool{}
+	doc.Find(".product[data-pid], .product-tile[data-pid]").Each(func(_ int, s *goquery.Selection) {
+		id, _ := s.Attr("data-pid")
+		id = strings.TrimSpace(id)
+		if id == "" || seen[strings.ToLower(id)] {
+			return
+		}
+		name := strings.TrimSpace(s.Find(".pdp-link a, .product-name, .link").First().Text())
+		if name == "" {
+			name, _ = s.Find("img").First().Attr("alt")
+		}
+		price := strings.TrimSpace(s.Find(".price .sales .value, .price .value, .sales").First().Text())
+		seen[strings.ToLower(id)] = true
+		name = strings.Join(strings.Fields(name), " ")
+		products = append(products, Product{
+			ID:        id,
+			Name:      name,
+			Price:     strings.Join(strings.Fields(price), " "),
+			PackSize:  packSize(name),
+			Available: tileAvailable(s),
+		})
+	})
+	return products
+}
+
+// packSize reads the volume and pack count from a product name.
+func packSize(name string) string {
+	m := volumeRegex.FindStringSubmatch(name)
+	if m == nil {
+		return ""
+	}
+	unit := "L"
+	if strings.EqualFold(m[2], "ml") {
+		unit = "ml"
+	}
+	size := m[1] + " " + unit
+	if c := packCountRegex.FindStringSubmatch(volumeRegex.ReplaceAllString(name, "")); c != nil {
+		count := c[1]
+		if count == "" {
+			count = c[2]
+		}
+		if count != "1" {
+			size += " x " + count
+		}
+	}
+	return size
+}
+
+// tileAvailable reports whether a product tile can be added to the cart:
+// SFCC marks sold-out tiles with data-available="false", a disabled
+// add-to-cart button or an availability message.
+func tileAvailable(s *goquery.Selection) bool {
+	if v, ok := s.Attr("data-available"); ok {
+		return !strings.EqualFold(strings.TrimSpace(v), "false")
+	}
+	if btn := s.Find(".add-to-cart, button.add-to-cart-global").First(); btn.Length() > 0 {
+		if _, disabled := btn.Attr("disabled"); disabled {
+			return false
+		}
+	}
+	return !soldOutRegex.MatchString(s.Find(".availability, .availability-msg, .product-availability").Text())
+}
+
+// FindJarProduct picks the 20 litre water jar from a product list, skipping
+// the empty-jar, deposit and accessory entries.
+func FindJarProduct(products []Product) (Product, bool) {
+	for _, p := range products {
+		if jarSizeRegex.MatchString(p.Name) && !jarExcludeRegex.MatchString(p.Name) {
+			return p, true
+		}
+	}
+	return Product{}, false
+}
+
+// ListProducts returns the catalog for the session's city, falling back to
+// a catalog search when the listing page has no product tiles.
+func (c *Client) ListProducts(ctx context.Context) ([]Product, string, error) {
+	body, err := c.fetchCached(ctx, productListPath, "")
+	if err == nil {
+		if products := ParseProducts(body); len(products) > 0 {
+			city, _ := ExtractSelectedCity(body)
+			return products, city, nil
+		}
+	}
+	products, searchErr := c.SearchProducts(ctx, "bisleri")
+	if searchErr != nil {
+		if err != nil {
+			return nil, "", err
+		}
+		return nil, "", searchErr
+	}
+	return products, "", nil
+}
+
+// SearchProducts runs a catalog search and returns the product tiles found.
+func (c *Client) SearchProducts(ctx context.Context, query string) ([]Product, error) {
+	body, err := c.fetchPageWithRetry(ctx, "/search?q="+url.QueryEscape(query), "")
+	if err != nil {
+		return nil, fmt.Errorf("product search failed: %w", err)
+	}
+	return ParseProducts(body), nil
+}
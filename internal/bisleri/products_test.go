@@ -0,0 +1,54 @@
+package bisleri
+
+import "testing"
+
+func TestParseProductsAndFindJar(t *testing.T) {
+	html := `<html><body><div class="product-grid">
+<div class="product" data-pid="Bis-20LTREmpty-Product"><div class="pdp-link"><a>Empty Jar 20 Ltr</a></div></div>
+<div class="product" data-pid="BIS-1LTR-12"><div class="pdp-link"><a>Bisleri 1 Litre (Pack of 12)</a></div><div class="price"><span class="sales"><span class="value">₹ 240</span></span></div></div>
+<div class="product" data-pid="BIS-20LTR02-10"><div class="pdp-link"><a>Bisleri 20 L  Jar</a></div><div class="price"><span class="sales"><span class="value">₹ 100.00</span></span></div></div>
+<div class="product" data-pid="BIS-20LTR02-10"><div class="pdp-link"><a>duplicate</a></div></div>
+</div></body></html>`
+
+	products := ParseProducts(html)
+	if len(products) != 3 {
+		t.Fatalf("expected 3 products, got %d: %+v", len(products), products)
+	}
+	jar, ok := FindJarProduct(products)
+	if !ok {
+		t.Fatalf("expected to find the 20L jar")
+	}
+	if jar.ID != "BIS-20LTR02-10" || jar.Name != "Bisleri 20 L Jar" || jar.Price != "₹ 100.00" {
+		t.Fatalf("unexpected jar product: %+v", jar)
+	}
+	if _, ok := FindJarProduct(products[:2]); ok {
+		t.Fatalf("empty jar or other sizes must not match")
+	}
+}
+
+func TestParseProductsPackSizeAndAvailability(t *testing.T) {
+	html := `<div class="product" data-pid="A"><div class="pdp-link"><a>Bisleri 1 Litre (Pack of 12)</a></div></div>
+<div class="product" data-pid="B"><div class="pdp-link"><a>Bisleri 12 x 500 ml</a></div><button class="add-to-cart" disabled>Add</button></div>
+<div class="product" data-pid="C"><div class="pdp-link"><a>Bisleri 20 L Jar</a></div><div class="availability">In stock</div></div>
+<div class="product" data-pid="D" data-available="false"><div class="pdp-link"><a>Dispenser</a></div></div>
+<div class="product" data-pid="E"><div class="pdp-link"><a>Bisleri 2L</a></div><div class="availability">Out of Stock</div></div>`
+	products := ParseProducts(html)
+	want := []struct {
+		pack      string
+		available bool
+	}{
+		{"1 L x 12", true},
+		{"500 ml x 12", false},
+		{"20 L", true},
+		{"", false},
+		{"2 L", false},
+	}
+	if len(products) != len(want) {
+		t.Fatalf("expected %d products, got %d", len(want), len(products))
+	}
+	for i, w := range want {
+		if products[i].PackSize != w.pack || products[i].Available != w.available {
+			t.Fatalf("product %s: got pack %q available %t, want %q %t", products[i].ID, products[i].PackSize, products[i].Available, w.pack, w.available)
+		}
+	}
+}
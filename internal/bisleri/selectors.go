@@ -0,0 +1,107 @@
+package bisleri
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Selectors overrides the CSS selectors and regular expressions the parsers
+// use, so a markup change on the site can be patched without a release.
+// Overrides are tried before the built-in rules, which remain the fallback.
+// Each regex must have one capture group holding the value.
+type Selectors struct {
+	// CartItem matches one cart line; its data-uuid (or a descendant's) is
+	// the line UUID.
+	CartItem string `json:"cartItem,omitempty"`
+	// OrderCard matches one order on the my-orders page.
+	OrderCard       string `json:"orderCard,omitempty"`
+	CSRFToken       string `json:"csrfToken,omitempty"`
+	CSRFTokenRegex  string `json:"csrfTokenRegex,omitempty"`
+	OrderTotal      string `json:"orderTotal,omitempty"`
+	OrderTotalRegex string `json:"orderTotalRegex,omitempty"`
+}
+
+type compiledSelectors struct {
+	Selectors
+	csrfRegex  *regexp.Regexp
+	totalRegex *regexp.Regexp
+}
+
+var overrides compiledSelectors
+
+// SetSelectors installs selector overrides for all parsers.
+func SetSelectors(s Selectors) error {
+	compiled := compiledSelectors{Selectors: s}
+	var err error
+	if compiled.csrfRegex, err = compileOverride("csrfTokenRegex", s.CSRFTokenRegex); err != nil {
+		return err
+	}
+	if compiled.totalRegex, err = compileOverride("orderTotalRegex", s.OrderTotalRegex); err != nil {
+		return err
+	}
+	overrides = compiled
+	return nil
+}
+
+// LoadSelectors reads overrides from a selectors.json file.
+func LoadSelectors(path string) (Selectors, error) {
+	var s Selectors
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+func compileOverride(name, pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("selectors: invalid %s: %w", name, err)
+	}
+	if re.NumSubexp() < 1 {
+		return nil, fmt.Errorf("selectors: %s needs a capture group for the value", name)
+	}
+	return re, nil
+}
+
+func orderCardSelector() string {
+	if overrides.OrderCard != "" {
+		return overrides.OrderCard
+	}
+	return ".all-order"
+}
+
+// csrfOverride applies the csrfToken selector and regex overrides.
+func csrfOverride(html string) string {
+	if overrides.csrfRegex != nil {
+		if match := overrides.csrfRegex.FindStringSubmatch(html); len(match) > 1 && match[1] != "" {
+			return match[1]
+		}
+	}
+	if overrides.CSRFToken == "" {
+		return ""
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return ""
+	}
+	sel := doc.Find(overrides.CSRFToken).First()
+	if val, ok := sel.Attr("value"); ok && strings.TrimSpace(val) != "" {
+		return strings.TrimSpace(val)
+	}
+	if val, ok := sel.Attr("content"); ok && strings.TrimSpace(val) != "" {
+		return strings.TrimSpace(val)
+	}
+	return ""
+}
@@ -0,0 +1,32 @@
+package bisleri
+
+import "testing"
+
+func TestSelectorOverrides(t *testing.T) {
+	t.Cleanup(func() { _ = SetSelectors(Selectors{}) })
+	if err := SetSelectors(Selectors{OrderTotalRegex: `Grand`}); err == nil {
+		t.Fatal("expected an error for a regex without a capture group")
+	}
+	if err := SetSelectors(Selectors{
+		CartItem:        ".line",
+		OrderCard:       ".order-tile",
+		CSRFToken:       "meta[name=csrf]",
+		OrderTotalRegex: `Grand sum\s*Rs\.?\s*([0-9.,]+)`,
+	}); err != nil {
+		t.Fatalf("SetSelectors: %v", err)
+	}
+
+	if token, err := ExtractCSRFToken(`<meta name="csrf" content="abc123">`); err != nil || token != "abc123" {
+		t.Fatalf("csrf override: %q, %v", token, err)
+	}
+	if total, ok := ExtractOrderTotal(`<div>Grand sum Rs. 240.00</div>`); !ok || total != "₹240.00" {
+		t.Fatalf("total override: %q, %t", total, ok)
+	}
+	items := ExtractCartItems(`<li class="line"><span data-uuid="u1"></span><a href="/p/BIS-20LTR01-90.html">Jar</a></li>`)
+	if len(items) != 1 || items[0].UUID != "u1" || items[0].ProductID != "BIS-20LTR01-90" {
+		t.Fatalf("cart item override: %+v", items)
+	}
+	if got := orderCardSelector(); got != ".order-tile" {
+		t.Fatalf("order card selector = %q", got)
+	}
+}
@@ -0,0 +1,97 @@
+package bisleri
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const checkPincodePath = "/on/demandware.store/Sites-Bis-Site/default/LocationSelector-CheckPincode"
+
+// Serviceability is the site's answer to whether it delivers to a pincode.
+type Serviceability struct {
+	Serviceable bool
+	City        string
+	Message     string
+	Timeslots   []Timeslot
+}
+
+// ParseServiceability reads the pincode check reply. The endpoint has
+// answered with {"serviceable": true, "city": ...} and with the SFCC style
+// {"success": true, "isServiceable": true, ...}; slots are optional.
+func ParseServiceability(body []byte) (Serviceability, error) {
+	var raw struct {
+		Serviceable   *bool  `json:"serviceable"`
+		IsServiceable *bool  `json:"isServiceable"`
+		Success       *bool  `json:"success"`
+		City          string `json:"city"`
+		CityName      string `json:"cityName"`
+		Message       string `json:"message"`
+		ErrorMessage  string `json:"errorMessage"`
+		Timeslots     []struct {
+			Value     string `json:"value"`
+			Label     string `json:"label"`
+			Available *bool  `json:"available"`
+		} `json:"timeslots"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return Serviceability{}, fmt.Errorf("unexpected pincode check response: %w", err)
+	}
+	var result Serviceability
+	switch {
+	case raw.Serviceable != nil:
+		result.Serviceable = *raw.Serviceable
+	case raw.IsServiceable != nil:
+		result.Serviceable = *raw.IsServiceable
+	case raw.Success != nil:
+		result.Serviceable = *raw.Success
+	default:
+		return Serviceability{}, fmt.Errorf("pincode check response has no serviceability flag")
+	}
+	result.City = strings.TrimSpace(raw.City)
+	if result.City == "" {
+		result.City = strings.TrimSpace(raw.CityName)
+	}
+	result.Message = strings.TrimSpace(raw.Message)
+	if result.Message == "" {
+		result.Message = strings.TrimSpace(raw.ErrorMessage)
+	}
+	for _, s := range raw.Timeslots {
+		label := cleanText(s.Label)
+		if label == "" {
+			label = s.Value
+		}
+		result.Timeslots = append(result.Timeslots, Timeslot{Value: s.Value, Label: label, Available: s.Available == nil || *s.Available})
+	}
+	return result, nil
+}
+
+// CheckPincode asks the site whether it delivers to pincode.
+func (c *Client) CheckPincode(ctx context.Context, pincode string) (Serviceability, error) {
+	form := url.Values{}
+	form.Set("pincode", pincode)
+	form.Set("postalCode", pincode)
+	req, err := http.NewRequest("POST", c.newURL(checkPincodePath), strings.NewReader(form.Encode()))
+	if err != nil {
+		return Serviceability{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return Serviceability{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return Serviceability{}, &HTTPStatusError{Path: "LocationSelector-CheckPincode", Status: resp.Status, StatusCode: resp.StatusCode}
+	}
+	body, err := readBody(resp)
+	if err != nil {
+		return Serviceability{}, err
+	}
+	return ParseServiceability(body)
+}
@@ -0,0 +1,24 @@
+package bisleri
+
+import "testing"
+
+func TestParseServiceability(t *testing.T) {
+	got, err := ParseServiceability([]byte(`{"serviceable":true,"city":"Pune","timeslots":[{"value":"9-12","label":" 9 AM - 12 PM "},{"value":"12-3","available":false}]}`))
+	if err != nil {
+		t.Fatalf("ParseServiceability: %v", err)
+	}
+	if !got.Serviceable || got.City != "Pune" || len(got.Timeslots) != 2 {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+	if got.Timeslots[0].Label != "9 AM - 12 PM" || !got.Timeslots[0].Available || got.Timeslots[1].Label != "12-3" || got.Timeslots[1].Available {
+		t.Fatalf("unexpected slots: %+v", got.Timeslots)
+	}
+
+	got, err = ParseServiceability([]byte(`{"success":true,"isServiceable":false,"errorMessage":"We do not deliver here yet"}`))
+	if err != nil || got.Serviceable || got.Message != "We do not deliver here yet" {
+		t.Fatalf("unexpected result: %+v, %v", got, err)
+	}
+	if _, err := ParseServiceability([]byte(`{"ok":1}`)); err == nil {
+		t.Fatal("expected an error without a serviceability flag")
+	}
+}
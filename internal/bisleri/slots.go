@@ -0,0 +1,250 @@
+package bisleri
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ErrNoSlotAvailable means the site has no open delivery slot for the order.
+var ErrNoSlotAvailable = errors.New("no delivery slot available")
+
+// Timeslot is one delivery slot offered on the shipping page.
+type Timeslot struct {
+	Value     string
+	Label     string
+	Available bool
+}
+
+// ExtractTimeslots lists the delivery slots on the shipping page, from either
+// a timeslot <select> or radio inputs. Disabled or "full" slots are marked
+// unavailable.
+func ExtractTimeslots(html string) []Timeslot {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil
+	}
+	var slots []Timeslot
+	doc.Find(`select[name="timeslot"] option`).Each(func(_ int, s *goquery.Selection) {
+		value, _ := s.Attr("value")
+		label := cleanText(s.Text())
+		if strings.TrimSpace(value) == "" {
+			return
+		}
+		slots = append(slots, Timeslot{Value: strings.TrimSpace(value), Label: label, Available: slotEnabled(s, label)})
+	})
+	doc.Find(`input[name="timeslot"]`).Each(func(_ int, s *goquery.Selection) {
+		value, _ := s.Attr("value")
+		if strings.TrimSpace(value) == "" {
+			return
+		}
+		label := ""
+		if id, ok := s.Attr("id"); ok && id != "" {
+			label = cleanText(doc.Find(`label[for="` + id + `"]`).First().Text())
+		}
+		if label == "" {
+			label = cleanText(s.Closest("label").Text())
+		}
+		if label == "" {
+			label = strings.TrimSpace(value)
+		}
+		slots = append(slots, Timeslot{Value: strings.TrimSpace(value), Label: label, Available: slotEnabled(s, label)})
+	})
+	return slots
+}
+
+func slotEnabled(s *goquery.Selection, label string) bool {
+	if _, disabled := s.Attr("disabled"); disabled {
+		return false
+	}
+	class := strings.ToLower(s.AttrOr("class", "") + " " + s.Parent().AttrOr("class", ""))
+	text := strings.ToLower(label)
+	for _, marker := range []string{"disabled", "unavailable", "sold-out", "full"} {
+		if strings.Contains(class, marker) {
+			return false
+		}
+	}
+	return !strings.Contains(text, "full") && !strings.Contains(text, "unavailable") && !strings.Contains(text, "not available")
+}
+
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// SlotOpen reports whether want (or any slot, when want is empty) can be
+// booked. known is false when the page lists no slots or not the wanted one,
+// in which case the caller should just try the order.
+func SlotOpen(slots []Timeslot, want string) (open, known bool) {
+	if len(slots) == 0 {
+		return false, false
+	}
+	want = cleanText(want)
+	for _, s := range slots {
+		if want == "" {
+			if s.Available {
+				return true, true
+			}
+			continue
+		}
+		if strings.EqualFold(s.Value, want) || strings.EqualFold(s.Label, want) {
+			return s.Available, true
+		}
+	}
+	if want == "" {
+		return false, true
+	}
+	return false, false
+}
+
+// isSlotFullMessage recognizes shipping-form errors about a full or closed
+// delivery slot.
+func isSlotFullMessage(msg string) bool {
+	msg = strings.ToLower(msg)
+	if !strings.Contains(msg, "slot") {
+		return false
+	}
+	for _, marker := range []string{"full", "not available", "unavailable", "no slot", "booked", "closed"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrNoTimeslots means the shipping page listed no delivery slots, which the
+// site does when the cart is empty.
+var ErrNoTimeslots = errors.New("no delivery slots on the shipping page; the site lists them only when the cart has items")
+
+// FetchTimeslots lists the delivery slots the site currently offers for the
+// session's city, from the shipping page.
+func (c *Client) FetchTimeslots(ctx context.Context) ([]Timeslot, error) {
+	if err := c.BeginCheckout(ctx); err != nil {
+		c.logf("checkout init warning: %v", err)
+	}
+	html, err := c.FetchShippingPage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slots := ExtractTimeslots(html)
+	if len(slots) == 0 {
+		return nil, ErrNoTimeslots
+	}
+	return slots, nil
+}
+
+// Parts of the day a slot can be named by, from the middle of its hours.
+const (
+	PartMorning   = "morning"
+	PartAfternoon = "afternoon"
+	PartEvening   = "evening"
+)
+
+var slotTimeRe = regexp.MustCompile(`(?i)(\d{1,2})(?:[:.](\d{2}))?\s*([ap])?\.?\s*m?\b\.?`)
+
+// slotHours parses the start and end of a slot like "08:00 AM - 02:00 PM",
+// "8am-2pm" or "14:00-20:00" into minutes after midnight.
+func slotHours(s string) (start, end int, ok bool) {
+	var times []int
+	var suffixes []string
+	for _, m := range slotTimeRe.FindAllStringSubmatch(s, -1) {
+		h, _ := strconv.Atoi(m[1])
+		mins := 0
+		if m[2] != "" {
+			mins, _ = strconv.Atoi(m[2])
+		}
+		if h > 23 || mins > 59 {
+			continue
+		}
+		times = append(times, h*60+mins)
+		suffixes = append(suffixes, strings.ToLower(m[3]))
+	}
+	if len(times) != 2 {
+		return 0, 0, false
+	}
+	// "8-2pm": the first time takes the second's suffix unless that would
+	// put it after the end.
+	if suffixes[0] == "" && suffixes[1] != "" {
+		suffixes[0] = suffixes[1]
+		if suffixes[1] == "p" && to24(times[0], "p") > to24(times[1], "p") {
+			suffixes[0] = "a"
+		}
+	}
+	return to24(times[0], suffixes[0]), to24(times[1], suffixes[1]), true
+}
+
+func to24(minutes int, suffix string) int {
+	switch {
+	case suffix == "p" && minutes < 12*60:
+		return minutes + 12*60
+	case suffix == "a" && minutes >= 12*60:
+		return minutes - 12*60
+	}
+	return minutes
+}
+
+// PartOfDay names the part of the day a slot falls in by the middle of its
+// hours; "" when the hours cannot be read.
+func PartOfDay(slot string) string {
+	lower := strings.ToLower(slot)
+	for _, part := range []string{PartMorning, PartAfternoon, PartEvening} {
+		if strings.Contains(lower, part) {
+			return part
+		}
+	}
+	start, end, ok := slotHours(slot)
+	if !ok {
+		return ""
+	}
+	switch mid := (start + end) / 2; {
+	case mid < 12*60:
+		return PartMorning
+	case mid < 16*60:
+		return PartAfternoon
+	default:
+		return PartEvening
+	}
+}
+
+// MatchTimeslot finds the slot a configured timeslot means and returns it
+// with its canonical value. want matches a slot's value or label exactly
+// (ignoring case and spacing), by hours written another way ("8am-2pm"), or
+// by part of day ("morning", "evening"), preferring an open slot when
+// several fit.
+func MatchTimeslot(slots []Timeslot, want string) (Timeslot, bool) {
+	want = cleanText(want)
+	if want == "" {
+		return Timeslot{}, false
+	}
+	for _, s := range slots {
+		if strings.EqualFold(s.Value, want) || strings.EqualFold(s.Label, want) {
+			return s, true
+		}
+	}
+	var fits []Timeslot
+	if start, end, ok := slotHours(want); ok {
+		for _, s := range slots {
+			if ss, se, ok := slotHours(s.Label); ok && ss == start && se == end {
+				fits = append(fits, s)
+			}
+		}
+	} else if part := strings.ToLower(want); part == PartMorning || part == PartAfternoon || part == PartEvening {
+		for _, s := range slots {
+			if PartOfDay(s.Label) == part {
+				fits = append(fits, s)
+			}
+		}
+	}
+	for _, s := range fits {
+		if s.Available {
+			return s, true
+		}
+	}
+	if len(fits) > 0 {
+		return fits[0], true
+	}
+	return Timeslot{}, false
+}
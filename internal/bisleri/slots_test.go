@@ -0,0 +1,93 @@
+package bisleri
+
+import "testing"
+
+func TestExtractTimeslotsSelect(t *testing.T) {
+	html := `<form><select name="timeslot">
+		<option value="">Select a slot</option>
+		<option value="08:00 AM - 02:00 PM" disabled>08:00 AM - 02:00 PM (Full)</option>
+		<option value="02:00 PM - 08:00 PM">02:00 PM - 08:00 PM</option>
+	</select></form>`
+	slots := ExtractTimeslots(html)
+	if len(slots) != 2 {
+		t.Fatalf("expected 2 slots, got %+v", slots)
+	}
+	if slots[0].Available || !slots[1].Available {
+		t.Fatalf("unexpected availability: %+v", slots)
+	}
+	if open, known := SlotOpen(slots, "08:00 AM - 02:00 PM"); open || !known {
+		t.Fatalf("morning slot: open=%v known=%v", open, known)
+	}
+	if open, known := SlotOpen(slots, ""); !open || !known {
+		t.Fatalf("any slot: open=%v known=%v", open, known)
+	}
+	if _, known := SlotOpen(slots, "evening"); known {
+		t.Fatal("an unlisted slot should be unknown")
+	}
+}
+
+func TestExtractTimeslotsRadios(t *testing.T) {
+	html := `<div class="slots">
+		<div class="slot unavailable"><input type="radio" name="timeslot" id="s1" value="slot-1"><label for="s1">08:00 AM - 02:00 PM</label></div>
+		<label><input type="radio" name="timeslot" value="slot-2"> 02:00 PM - 08:00 PM</label>
+	</div>`
+	slots := ExtractTimeslots(html)
+	if len(slots) != 2 || slots[0].Label != "08:00 AM - 02:00 PM" || slots[0].Available {
+		t.Fatalf("unexpected first slot: %+v", slots)
+	}
+	if slots[1].Label != "02:00 PM - 08:00 PM" || !slots[1].Available {
+		t.Fatalf("unexpected second slot: %+v", slots[1])
+	}
+	if open, _ := SlotOpen(slots, "02:00 pm - 08:00 pm"); !open {
+		t.Fatal("expected the afternoon slot to be open")
+	}
+}
+
+func TestIsSlotFullMessage(t *testing.T) {
+	if !isSlotFullMessage("address validation failed: Selected delivery slot is full") {
+		t.Fatal("expected a full-slot message to match")
+	}
+	if isSlotFullMessage("address validation failed: pincode: invalid") {
+		t.Fatal("unrelated errors should not match")
+	}
+}
+
+func TestMatchTimeslot(t *testing.T) {
+	slots := []Timeslot{
+		{Value: "08:00 AM - 02:00 PM", Label: "08:00 AM - 02:00 PM", Available: true},
+		{Value: "02:00 PM - 08:00 PM", Label: "02:00 PM - 08:00 PM (Full)"},
+	}
+	for _, tc := range []struct {
+		want, value string
+	}{
+		{"08:00 AM - 02:00 PM", "08:00 AM - 02:00 PM"},
+		{"08:00  am - 02:00 pm", "08:00 AM - 02:00 PM"},
+		{"8am-2pm", "08:00 AM - 02:00 PM"},
+		{"8-2pm", "08:00 AM - 02:00 PM"},
+		{"14:00-20:00", "02:00 PM - 08:00 PM"},
+		{"morning", "08:00 AM - 02:00 PM"},
+		{"Evening", "02:00 PM - 08:00 PM"},
+		{"afternoon", ""},
+		{"night", ""},
+	} {
+		got, ok := MatchTimeslot(slots, tc.want)
+		if ok != (tc.value != "") || got.Value != tc.value {
+			t.Errorf("MatchTimeslot(%q) = %q, %v; want %q", tc.want, got.Value, ok, tc.value)
+		}
+	}
+}
+
+func TestPartOfDay(t *testing.T) {
+	for slot, want := range map[string]string{
+		"08:00 AM - 02:00 PM":   PartMorning,
+		"12 PM - 4 PM":          PartAfternoon,
+		"02:00 PM - 08:00 PM":   PartEvening,
+		"Evening Slot":          PartEvening,
+		"Morning (9 AM - 1 PM)": PartMorning,
+		"Standard delivery":     "",
+	} {
+		if got := PartOfDay(slot); got != want {
+			t.Errorf("PartOfDay(%q) = %q, want %q", slot, got, want)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package bisleri
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const contactPath = "/contact-us"
+
+var supportPhoneRegex = regexp.MustCompile(`(?:\+91[\s-]*)?\b(1800[\s-]?\d{3}[\s-]?\d{3,4}|[6-9]\d{9}|0\d{2,4}[\s-]?\d{6,8})\b`)
+
+// SupportPage is what the contact page offers: a ticket form when the site
+// has one, and the phone numbers and addresses it lists.
+type SupportPage struct {
+	URL    string
+	Form   *CheckoutForm
+	Phones []string
+	Emails []string
+}
+
+// Complaint is the ticket SubmitComplaint files through the contact form.
+type Complaint struct {
+	OrderID string
+	Subject string
+	Message string
+	Name    string
+	Email   string
+	Phone   string
+}
+
+// ParseSupportPage extracts the contact form (a form with a message box,
+// posting to a contact, ticket or case endpoint) and the tel:/mailto:
+// links or numbers shown on the page.
+func ParseSupportPage(html string) SupportPage {
+	var page SupportPage
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return page
+	}
+	doc.Find("form").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		action, _ := s.Attr("action")
+		lower := strings.ToLower(action)
+		if s.Find("textarea[name]").Length() == 0 {
+			return true
+		}
+		if !strings.Contains(lower, "contact") && !strings.Contains(lower, "ticket") && !strings.Contains(lower, "case") && !strings.Contains(lower, "support") && !strings.Contains(lower, "customerservice") {
+			return true
+		}
+		form := readForm(s)
+		if form.Fields.Get("csrf_token") == "" {
+			if token, err := csrfToken(html, doc); err == nil {
+				form.Fields.Set("csrf_token", token)
+			}
+		}
+		page.Form = &form
+		return false
+	})
+
+	seen := map[string]bool{}
+	add := func(list *[]string, value string) {
+		value = strings.TrimSpace(value)
+		if value != "" && !seen[strings.ToLower(value)] {
+			seen[strings.ToLower(value)] = true
+			*list = append(*list, value)
+		}
+	}
+	doc.Find(`a[href^="tel:"], a[href^="mailto:"]`).Each(func(_ int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		if phone, ok := strings.CutPrefix(href, "tel:"); ok {
+			add(&page.Phones, phone)
+		} else if email, ok := strings.CutPrefix(href, "mailto:"); ok {
+			email, _, _ = strings.Cut(email, "?")
+			add(&page.Emails, email)
+		}
+	})
+	if len(page.Phones) == 0 {
+		doc.Find("script, style, noscript, form").Remove()
+		for _, match := range supportPhoneRegex.FindAllStringSubmatch(doc.Find("body").Text(), 3) {
+			add(&page.Phones, match[1])
+		}
+	}
+	if len(page.Emails) == 0 {
+		doc.Find("script, style, noscript").Remove()
+		for _, email := range accountEmailRegex.FindAllString(doc.Find("body").Text(), 3) {
+			add(&page.Emails, email)
+		}
+	}
+	return page
+}
+
+// readForm reads a form's action and default field values, including
+// textareas.
+func readForm(s *goquery.Selection) CheckoutForm {
+	action, _ := s.Attr("action")
+	method, _ := s.Attr("method")
+	if method == "" {
+		method = "POST"
+	}
+	fields := url.Values{}
+	s.Find("input[name], select[name], textarea[name]").Each(func(_ int, in *goquery.Selection) {
+		name, _ := in.Attr("name")
+		typ, _ := in.Attr("type")
+		switch strings.ToLower(typ) {
+		case "submit", "button", "file":
+			return
+		case "checkbox", "radio":
+			if _, ok := in.Attr("checked"); !ok {
+				return
+			}
+		}
+		value, _ := in.Attr("value")
+		switch goquery.NodeName(in) {
+		case "select":
+			opt := in.Find("option[selected]").First()
+			if opt.Length() == 0 {
+				opt = in.Find("option").First()
+			}
+			value, _ = opt.Attr("value")
+		case "textarea":
+			value = in.Text()
+		}
+		fields.Set(name, strings.TrimSpace(value))
+	})
+	return CheckoutForm{
+		Action: strings.TrimSpace(action),
+		Method: strings.ToUpper(strings.TrimSpace(method)),
+		Fields: fields,
+	}
+}
+
+// fillComplaint sets the complaint on the contact form fields, matching them
+// by the end of their names as SFCC forms prefix them (dwfrm_contactus_...).
+func fillComplaint(fields url.Values, c Complaint) {
+	set := func(value string, suffixes ...string) {
+		if value == "" {
+			return
+		}
+		for name := range fields {
+			lower := strings.ToLower(name)
+			for _, suffix := range suffixes {
+				if strings.HasSuffix(lower, suffix) {
+					fields.Set(name, value)
+				}
+			}
+		}
+	}
+	first, last, _ := strings.Cut(c.Name, " ")
+	set(first, "firstname")
+	set(strings.TrimSpace(last), "lastname")
+	set(c.Name, "_name", "fullname")
+	set(c.Email, "email")
+	set(c.Phone, "phone", "mobile")
+	set(c.OrderID, "ordernumber", "orderno", "orderid")
+	set(c.Subject, "subject")
+	set(c.Message, "comment", "comments", "message", "description", "query")
+}
+
+// FetchSupportPage loads and parses the contact page.
+func (c *Client) FetchSupportPage(ctx context.Context) (SupportPage, error) {
+	body, err := c.fetchPageWithRetry(ctx, contactPath, "")
+	if err != nil {
+		return SupportPage{URL: c.newURL(contactPath)}, err
+	}
+	page := ParseSupportPage(body)
+	page.URL = c.newURL(contactPath)
+	return page, nil
+}
+
+// SubmitComplaint files the complaint through the contact form.
+func (c *Client) SubmitComplaint(ctx context.Context, form CheckoutForm, complaint Complaint) error {
+	if form.Action == "" {
+		return errors.New("contact form has no action")
+	}
+	fillComplaint(form.Fields, complaint)
+	action := form.Action
+	if strings.HasPrefix(action, "/") {
+		action = c.newURL(action)
+	}
+	req, err := http.NewRequest(http.MethodPost, action, strings.NewReader(form.Fields.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Origin", c.BaseURL)
+	req.Header.Set("Referer", c.newURL(contactPath))
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return &HTTPStatusError{Path: action, Status: resp.Status, StatusCode: resp.StatusCode}
+	}
+	body, err := readBody(resp)
+	if err != nil {
+		return err
+	}
+	return formReplyError(body, "complaint")
+}
@@ -0,0 +1,84 @@
+package bisleri
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const contactPage = `<html><body>
+<form action="/newsletter-subscribe" method="post"><input name="email"></form>
+<form action="/on/demandware.store/Sites-Bis-Site/default/ContactUs-Submit" method="post">
+<input type="hidden" name="csrf_token" value="tok123">
+<input name="dwfrm_contactus_firstname"><input name="dwfrm_contactus_lastname">
+<input name="dwfrm_contactus_email"><input name="dwfrm_contactus_phone">
+<input name="dwfrm_contactus_ordernumber">
+<select name="dwfrm_contactus_myquestion"><option value="general">General</option><option value="order">Order</option></select>
+<textarea name="dwfrm_contactus_comment"></textarea>
+<button type="submit" name="send">Send</button>
+</form>
+<p>Call us on <a href="tel:1800-121-1007">1800 121 1007</a> or write to <a href="mailto:care@example.com?subject=Hi">care@example.com</a></p>
+</body></html>`
+
+func TestParseSupportPage(t *testing.T) {
+	page := ParseSupportPage(contactPage)
+	if page.Form == nil {
+		t.Fatalf("contact form not found")
+	}
+	if !strings.HasSuffix(page.Form.Action, "ContactUs-Submit") || page.Form.Fields.Get("csrf_token") != "tok123" {
+		t.Fatalf("unexpected form: %+v", page.Form)
+	}
+	if page.Form.Fields.Get("dwfrm_contactus_myquestion") != "general" {
+		t.Fatalf("select default not read: %v", page.Form.Fields)
+	}
+	if !reflect.DeepEqual(page.Phones, []string{"1800-121-1007"}) || !reflect.DeepEqual(page.Emails, []string{"care@example.com"}) {
+		t.Fatalf("contacts = %v %v", page.Phones, page.Emails)
+	}
+
+	noForm := ParseSupportPage(`<html><body><p>Customer care: 1800 121 1007</p></body></html>`)
+	if noForm.Form != nil || !reflect.DeepEqual(noForm.Phones, []string{"1800 121 1007"}) {
+		t.Fatalf("unexpected page without form: %+v", noForm)
+	}
+}
+
+func TestSubmitComplaint(t *testing.T) {
+	var got url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got, _ = url.ParseQuery(string(body))
+		io.WriteString(w, `{"success": true}`)
+	}))
+	defer srv.Close()
+
+	c := NewClient(&http.Client{}, nil)
+	c.BaseURL = srv.URL
+	c.Throttle = 0
+	form := *ParseSupportPage(contactPage).Form
+	err := c.SubmitComplaint(context.Background(), form, Complaint{
+		OrderID: "BS-1",
+		Subject: "Late delivery - order BS-1",
+		Message: "It was late.",
+		Name:    "Asha Rao",
+		Email:   "asha@example.com",
+	})
+	if err != nil {
+		t.Fatalf("SubmitComplaint: %v", err)
+	}
+	for field, want := range map[string]string{
+		"dwfrm_contactus_firstname":   "Asha",
+		"dwfrm_contactus_lastname":    "Rao",
+		"dwfrm_contactus_email":       "asha@example.com",
+		"dwfrm_contactus_ordernumber": "BS-1",
+		"dwfrm_contactus_comment":     "It was late.",
+		"csrf_token":                  "tok123",
+	} {
+		if got.Get(field) != want {
+			t.Fatalf("%s = %q, want %q (form %v)", field, got.Get(field), want, got)
+		}
+	}
+}
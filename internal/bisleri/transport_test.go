@@ -0,0 +1,40 @@
+package bisleri
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSharedTransportReusesConnections(t *testing.T) {
+	var conns atomic.Int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>ok</html>"))
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	for i := 0; i < 3; i++ {
+		// A new Client per step, as the commands create them.
+		c := NewClient(&http.Client{}, nil)
+		if c.HTTP.Transport != Transport() {
+			t.Fatal("expected the shared transport")
+		}
+		c.BaseURL = srv.URL
+		c.Throttle = 0
+		if _, _, err := c.FetchPage(context.Background(), "/"); err != nil {
+			t.Fatalf("FetchPage: %v", err)
+		}
+	}
+	if got := conns.Load(); got != 1 {
+		t.Fatalf("expected 1 connection for 3 requests, got %d", got)
+	}
+}
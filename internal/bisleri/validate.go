@@ -0,0 +1,167 @@
+package bisleri
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"bislericli/internal/address"
+	"bislericli/internal/store"
+)
+
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// AddressValidationError lists field-level problems with a shipping address,
+// either detected locally or reported by the site's shipping form.
+type AddressValidationError struct {
+	Fields []FieldError
+	Server []string
+}
+
+func (e *AddressValidationError) Error() string {
+	var parts []string
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	parts = append(parts, e.Server...)
+	return "address validation failed: " + strings.Join(parts, "; ")
+}
+
+var shippingFieldLabels = map[string]string{
+	"firstName":      "first name",
+	"lastName":       "last name",
+	"floor":          "floor",
+	"address1":       "address line 1",
+	"address2":       "address line 2",
+	"nearByLandMark": "landmark",
+	"country":        "country",
+	"stateCode":      "state",
+	"city":           "city",
+	"postalCode":     "pincode",
+	"phone":          "phone",
+}
+
+// ValidateShippingAddress checks the fields the shipping form requires before
+// anything is submitted, so problems surface per field instead of as a 500.
+func ValidateShippingAddress(addr store.Address) error {
+	var errs []FieldError
+	require := func(field, value string) {
+		if strings.TrimSpace(value) == "" {
+			errs = append(errs, FieldError{Field: field, Message: "required"})
+		}
+	}
+	require("first name", addr.FirstName)
+	require("address line 1", addr.Address1)
+	require("city", addr.City)
+	if strings.TrimSpace(addr.PostalCode) == "" {
+		errs = append(errs, FieldError{Field: "pincode", Message: "required"})
+	} else if !address.ValidPincode(addr.PostalCode) {
+		errs = append(errs, FieldError{Field: "pincode", Message: "must be 6 digits"})
+	}
+	if _, ok := address.NormalizeState(addr.StateCode); !ok {
+		errs = append(errs, FieldError{Field: "state", Message: "unrecognized state code " + quoteOrEmpty(addr.StateCode)})
+	}
+	require("phone", addr.Phone)
+	if len(errs) == 0 {
+		return nil
+	}
+	return &AddressValidationError{Fields: errs}
+}
+
+// CheckShippingAddress is the dry run before SubmitShipping: the local field
+// checks, then the site's pincode check, so an address the site will not
+// deliver to fails on its pincode rather than on the shipping POST. The
+// shipping form validates again, so a pincode check that cannot be made is
+// logged and skipped.
+func (c *Client) CheckShippingAddress(ctx context.Context, addr store.Address) error {
+	if err := ValidateShippingAddress(addr); err != nil {
+		return err
+	}
+	result, err := c.CheckPincode(ctx, strings.TrimSpace(addr.PostalCode))
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		c.logf("pincode check skipped: %v", err)
+		return nil
+	}
+	if !result.Serviceable {
+		msg := result.Message
+		if msg == "" {
+			msg = "not serviceable"
+		}
+		return &AddressValidationError{Fields: []FieldError{{Field: "pincode", Message: msg}}}
+	}
+	return nil
+}
+
+func quoteOrEmpty(value string) string {
+	if value == "" {
+		return "(empty)"
+	}
+	return `"` + value + `"`
+}
+
+// parseShippingResponse extracts field and server errors from the JSON the
+// shipping form returns. It returns nil when the body reports no error or is
+// not JSON.
+func parseShippingResponse(body []byte) error {
+	var payload struct {
+		Error        bool            `json:"error"`
+		FieldErrors  json.RawMessage `json:"fieldErrors"`
+		ServerErrors []string        `json:"serverErrors"`
+		Message      string          `json:"errorMessage"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil
+	}
+	fields := decodeFieldErrors(payload.FieldErrors)
+	if !payload.Error && len(fields) == 0 && len(payload.ServerErrors) == 0 {
+		return nil
+	}
+	server := payload.ServerErrors
+	if payload.Message != "" {
+		server = append(server, payload.Message)
+	}
+	if len(fields) == 0 && len(server) == 0 {
+		server = []string{"shipping form rejected the address"}
+	}
+	return &AddressValidationError{Fields: fields, Server: server}
+}
+
+// decodeFieldErrors accepts both the object form {"field": "msg"} and the
+// array-of-objects form [{"field": "msg"}] used by SFCC checkout controllers.
+func decodeFieldErrors(raw json.RawMessage) []FieldError {
+	if len(raw) == 0 {
+		return nil
+	}
+	var maps []map[string]string
+	var single map[string]string
+	if err := json.Unmarshal(raw, &maps); err != nil {
+		if err := json.Unmarshal(raw, &single); err != nil {
+			return nil
+		}
+		maps = []map[string]string{single}
+	}
+	var errs []FieldError
+	for _, m := range maps {
+		for name, msg := range m {
+			errs = append(errs, FieldError{Field: fieldLabel(name), Message: msg})
+		}
+	}
+	return errs
+}
+
+func fieldLabel(formField string) string {
+	short := formField
+	if idx := strings.LastIndex(formField, "_"); idx >= 0 {
+		short = formField[idx+1:]
+	}
+	if label, ok := shippingFieldLabels[short]; ok {
+		return label
+	}
+	return short
+}
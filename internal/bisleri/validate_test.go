@@ -0,0 +1,95 @@
+package bisleri
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"bislericli/internal/store"
+)
+
+func TestValidateShippingAddress(t *testing.T) {
+	addr := store.Address{
+		FirstName:  "Asha",
+		Address1:   "12 MG Road",
+		City:       "Bengaluru",
+		StateCode:  "KA",
+		PostalCode: "560001",
+		Phone:      "9876543210",
+	}
+	if err := ValidateShippingAddress(addr); err != nil {
+		t.Fatalf("expected valid address, got %v", err)
+	}
+	addr.PostalCode = "5600"
+	addr.StateCode = ""
+	err := ValidateShippingAddress(addr)
+	var vErr *AddressValidationError
+	if !errors.As(err, &vErr) || len(vErr.Fields) != 2 {
+		t.Fatalf("expected pincode and state errors, got %v", err)
+	}
+}
+
+func TestParseShippingResponseFieldErrors(t *testing.T) {
+	body := []byte(`{"error":true,"fieldErrors":[{"dwfrm_shipping_shippingAddress_addressFields_nearByLandMark":"This field is required."},{"dwfrm_shipping_shippingAddress_addressFields_postalCode":"Pincode not serviceable"}]}`)
+	err := parseShippingResponse(body)
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "landmark: This field is required.") || !strings.Contains(msg, "pincode: Pincode not serviceable") {
+		t.Fatalf("unexpected error message: %s", msg)
+	}
+	if parseShippingResponse([]byte(`{"error":false}`)) != nil {
+		t.Fatalf("expected no error for success payload")
+	}
+	if parseShippingResponse([]byte(`<html></html>`)) != nil {
+		t.Fatalf("expected no error for non-JSON body")
+	}
+}
+
+func TestCheckShippingAddressUsesPincodeCheck(t *testing.T) {
+	reply := `{"serviceable": false, "message": "We do not deliver to 560001 yet"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != checkPincodePath {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.FormValue("pincode"); got != "560001" {
+			t.Errorf("pincode = %q", got)
+		}
+		if reply == "" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(reply))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.Client(), nil)
+	c.BaseURL = srv.URL
+	c.Throttle = 0
+	addr := store.Address{FirstName: "Asha", Address1: "12 MG Road", City: "Bengaluru", StateCode: "KA", PostalCode: "560001", Phone: "9876543210"}
+
+	err := c.CheckShippingAddress(context.Background(), addr)
+	var vErr *AddressValidationError
+	if !errors.As(err, &vErr) || !strings.Contains(err.Error(), "pincode: We do not deliver to 560001 yet") {
+		t.Fatalf("unserviceable pincode: got %v", err)
+	}
+
+	reply = `{"serviceable": true}`
+	if err := c.CheckShippingAddress(context.Background(), addr); err != nil {
+		t.Fatalf("serviceable pincode: %v", err)
+	}
+
+	// Without the endpoint the local checks still decide.
+	reply = ""
+	if err := c.CheckShippingAddress(context.Background(), addr); err != nil {
+		t.Fatalf("missing endpoint: %v", err)
+	}
+	addr.Phone = ""
+	if err := c.CheckShippingAddress(context.Background(), addr); !errors.As(err, &vErr) {
+		t.Fatalf("missing phone: got %v", err)
+	}
+}
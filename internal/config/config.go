@@ -8,23 +8,285 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
+	"time"
+	// Embedded so the zone resolves on servers without a zoneinfo database.
+	_ "time/tzdata"
+
+	"bislericli/internal/fileutil"
+	"bislericli/internal/migrate"
+	"bislericli/internal/money"
 )
 
 type Defaults struct {
-	OrderQuantity int    `json:"orderQuantity"`
-	ReturnJars    int    `json:"returnJars"`
-	Schedule      string `json:"schedule"`
-	Timeslot      string `json:"timeslot"`
+	OrderQuantity    int         `json:"orderQuantity"`
+	ReturnJars       int         `json:"returnJars"`
+	Schedule         string      `json:"schedule"`
+	ScheduleTime     string      `json:"scheduleTime"`
+	Timeslot         string      `json:"timeslot"`
+	AdaptiveQuantity bool        `json:"adaptiveQuantity"`
+	MinQuantity      int         `json:"minQuantity"`
+	MaxQuantity      int         `json:"maxQuantity"`
+	MonthlyBudget    money.Money `json:"monthlyBudget,omitempty"`
+	// SyncAfterOrder refreshes the local order history right after an order
+	// is placed so orders and stats include it without a manual sync.
+	SyncAfterOrder bool `json:"syncAfterOrder,omitempty"`
+	// PendingOrderDays is how far back order looks for an undelivered order
+	// before placing another; negative disables the check.
+	PendingOrderDays int `json:"pendingOrderDays"`
+	// MaxPerLine caps the jars in one cart line (0 = no cap). Larger requests
+	// are placed as sequential orders when BulkSplit is "orders" (the default)
+	// and refused when it is "off"; the cart merges repeated adds of the same
+	// product, so a request cannot be spread over several lines of one order.
+	MaxPerLine int    `json:"maxPerLine,omitempty"`
+	BulkSplit  string `json:"bulkSplit,omitempty"`
+}
+
+// Blackout lists days the scheduler must not place orders on. Dates are
+// "YYYY-MM-DD" or inclusive ranges "YYYY-MM-DD..YYYY-MM-DD".
+type Blackout struct {
+	Weekdays []string `json:"weekdays,omitempty"`
+	Dates    []string `json:"dates,omitempty"`
+}
+
+// Geocoding controls the optional lookup of address coordinates. It is off by
+// default because it sends the address to a third-party service.
+type Geocoding struct {
+	Enabled  bool   `json:"enabled"`
+	Endpoint string `json:"endpoint,omitempty"`
+	Email    string `json:"email,omitempty"`
+}
+
+// Redaction adds regular expressions to mask in debug dumps and logs, on top
+// of the built-in phone, pincode, email, cookie and order ID rules.
+type Redaction struct {
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// Notifications selects how unattended modes (schedule run, sync --watch)
+// report events.
+type Notifications struct {
+	Desktop bool `json:"desktop"`
+	// Digest makes the scheduler send a "week" or "month" report after each
+	// period ends.
+	Digest   string   `json:"digest,omitempty"`
+	WhatsApp WhatsApp `json:"whatsapp"`
+	Signal   Signal   `json:"signal"`
+	// Commands are shell commands run for every message with it as JSON on
+	// stdin, for services with no built-in support.
+	Commands []string `json:"commands,omitempty"`
+	// Templates rewrite the messages for an event, keyed by event name
+	// (orderPlaced, orderStatus, ...).
+	Templates map[string]Template `json:"templates,omitempty"`
+}
+
+// Template holds Go text/template sources for a message's title and body;
+// an empty one keeps the built-in text.
+type Template struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+// WhatsApp sends order updates from a WhatsApp Business number through the
+// Cloud API to the numbers in To (international form, digits only). The API
+// cannot post to groups; use Signal for a group chat.
+type WhatsApp struct {
+	Endpoint      string   `json:"endpoint,omitempty"`
+	PhoneNumberID string   `json:"phoneNumberId,omitempty"`
+	Token         string   `json:"token,omitempty"`
+	To            []string `json:"to,omitempty"`
+}
+
+// Signal sends order updates with signal-cli from Account to a Group (its
+// base64 ID) and/or the numbers in To. Command defaults to "signal-cli".
+type Signal struct {
+	Command string   `json:"command,omitempty"`
+	Account string   `json:"account,omitempty"`
+	Group   string   `json:"group,omitempty"`
+	To      []string `json:"to,omitempty"`
+}
+
+// Sheets pushes stats to a Google Sheet with a service-account key. The sheet
+// must be shared with the service account's email as an editor.
+type Sheets struct {
+	SpreadsheetID   string `json:"spreadsheetId,omitempty"`
+	CredentialsFile string `json:"credentialsFile,omitempty"`
+	// AfterSync exports automatically after every successful sync.
+	AfterSync bool `json:"afterSync,omitempty"`
+}
+
+// Hooks are shell commands run on lifecycle events with the event as JSON on
+// stdin. A failing preOrder hook aborts the order; other failures only warn.
+type Hooks struct {
+	PreOrder         []string `json:"preOrder,omitempty"`
+	PostOrderSuccess []string `json:"postOrderSuccess,omitempty"`
+	PostOrderFailure []string `json:"postOrderFailure,omitempty"`
+	PostSync         []string `json:"postSync,omitempty"`
+}
+
+// Policy holds guardrails for unattended (scheduled) orders; zero values
+// disable a rule. MinWalletBalance is the balance that must remain after the
+// order is paid.
+type Policy struct {
+	MaxJarPrice      money.Money `json:"maxJarPrice,omitempty"`
+	MaxWeeklyOrders  int         `json:"maxWeeklyOrders,omitempty"`
+	MinWalletBalance money.Money `json:"minWalletBalance,omitempty"`
+	AllowedTimeslots []string    `json:"allowedTimeslots,omitempty"`
+}
+
+// Client adjusts the headers sent to the site, for users whose requests are
+// fingerprinted or blocked with the default user agent. Rotate is "session"
+// (one of UserAgents per command, the default) or "request".
+type Client struct {
+	UserAgent      string            `json:"userAgent,omitempty"`
+	UserAgents     []string          `json:"userAgents,omitempty"`
+	Rotate         string            `json:"rotate,omitempty"`
+	AcceptLanguage string            `json:"acceptLanguage,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty"`
+}
+
+// Fingerprints records the structure (never the content) of pages a parser
+// fails on so breakages can be matched against site changes. Nothing is
+// recorded unless Enabled, and nothing leaves the machine unless Submit is
+// set, which posts new fingerprints to Endpoint.
+type Fingerprints struct {
+	Enabled  bool   `json:"enabled,omitempty"`
+	Submit   bool   `json:"submit,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// Inventory models the jars at home. DailyJars is how many are used a day;
+// zero learns it from the ordering cadence. A reminder is sent once the
+// estimate falls to ReorderAt (zero: no reminders).
+type Inventory struct {
+	DailyJars float64 `json:"dailyJars,omitempty"`
+	ReorderAt float64 `json:"reorderAt,omitempty"`
+}
+
+// Weather checks the forecast before scheduled orders. When any of the next
+// Days days (default 2) reaches HeatC °C (default 38), Action "suggest" (the
+// default) notifies that ExtraJars more (default 1) may be needed and "bump"
+// adds them to the order, up to defaults.maxQuantity. The location is the
+// profile address's coordinates unless Latitude and Longitude are set. Off
+// unless Enabled, since it sends the location to the weather service.
+type Weather struct {
+	Enabled   bool    `json:"enabled,omitempty"`
+	Endpoint  string  `json:"endpoint,omitempty"`
+	Latitude  string  `json:"latitude,omitempty"`
+	Longitude string  `json:"longitude,omitempty"`
+	HeatC     float64 `json:"heatC,omitempty"`
+	Days      int     `json:"days,omitempty"`
+	Action    string  `json:"action,omitempty"`
+	ExtraJars int     `json:"extraJars,omitempty"`
+}
+
+// Holidays makes the scheduler aware of public holidays, when deliveries
+// usually pause. State is the two-letter state code whose holidays count
+// (default: the profile address's state). Action "warn" (the default)
+// notifies before a run on a holiday and "shift" moves runs to the next day
+// that is not one. URL is where 'holidays fetch' downloads the calendar of
+// movable festivals from.
+type Holidays struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	State   string `json:"state,omitempty"`
+	Action  string `json:"action,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+// Serve configures 'bislericli serve'. Order webhooks are accepted only from
+// the sources in WebhookSecrets, signed with that source's secret and sent
+// within WebhookMaxAge seconds; each source, and each API token, may place at
+// most WebhookDailyLimit orders in 24 hours. Zero values take the built-in
+// defaults.
+type Serve struct {
+	Listen            string              `json:"listen,omitempty"`
+	WebhookSecrets    map[string]string   `json:"webhookSecrets,omitempty"`
+	WebhookMaxAge     int                 `json:"webhookMaxAge,omitempty"`
+	WebhookDailyLimit int                 `json:"webhookDailyLimit,omitempty"`
+	Tokens            map[string]APIToken `json:"tokens,omitempty"`
+	// MaxQuantity caps the jars one remote order (webhook, API or bot) may
+	// ask for. Zero means defaults.maxQuantity when adaptive quantity is on,
+	// and no cap otherwise.
+	MaxQuantity int `json:"maxQuantity,omitempty"`
+}
+
+// APIToken grants access to serve mode's API; Serve.Tokens keys tokens by
+// name. Only the token's SHA-256 hash is stored; the token itself is shown
+// once, when it is created.
+type APIToken struct {
+	Hash    string    `json:"hash"`
+	Scopes  []string  `json:"scopes"`
+	Created time.Time `json:"created"`
+}
+
+// OTPRelay lets a phone automation app (Tasker, iOS Shortcuts) forward the
+// login OTP SMS to 'auth otp-relay', and to unattended orders whose session
+// has expired. Requests must send Secret as a bearer token; relaying is off
+// while it is empty. Listen defaults to ":8989".
+type OTPRelay struct {
+	Listen string `json:"listen,omitempty"`
+	Secret string `json:"secret,omitempty"`
+}
+
+// Telegram configures 'bislericli bot'. Only chats listed in AllowedChats
+// (numeric chat IDs) are answered; orders from each chat count against
+// serve.webhookDailyLimit.
+type Telegram struct {
+	Token        string   `json:"token,omitempty"`
+	AllowedChats []string `json:"allowedChats,omitempty"`
 }
 
 type GlobalConfig struct {
-	CurrentProfile string   `json:"currentProfile"`
-	Defaults       Defaults `json:"defaults"`
+	SchemaVersion  int           `json:"schemaVersion"`
+	CurrentProfile string        `json:"currentProfile"`
+	Defaults       Defaults      `json:"defaults"`
+	Blackout       Blackout      `json:"blackout"`
+	Geocoding      Geocoding     `json:"geocoding"`
+	Redaction      Redaction     `json:"redaction"`
+	Notifications  Notifications `json:"notifications"`
+	Sheets         Sheets        `json:"sheets"`
+	Hooks          Hooks         `json:"hooks"`
+	Policy         Policy        `json:"policy"`
+	Client         Client        `json:"client"`
+	Fingerprints   Fingerprints  `json:"fingerprints"`
+	Serve          Serve         `json:"serve"`
+	Inventory      Inventory     `json:"inventory"`
+	Weather        Weather       `json:"weather"`
+	Holidays       Holidays      `json:"holidays"`
+	OTPRelay       OTPRelay      `json:"otpRelay"`
+	Telegram       Telegram      `json:"telegram"`
+	// Language selects CLI output language ("en" or "hi"); empty follows the locale.
+	Language string `json:"language,omitempty"`
+	// Timezone is the IANA zone schedules, delivery slots and stats use;
+	// empty means DefaultTimezone and "local" the machine's zone.
+	Timezone string `json:"timezone,omitempty"`
+	// Strict makes loading fail on keys no field reads, such as misspelt
+	// settings that would otherwise be silently ignored.
+	Strict bool `json:"strict,omitempty"`
+
+	// env records fields set from the environment by LoadGlobalConfig.
+	env []envOverride
+}
+
+// DefaultTimezone is the zone of the site's deliveries.
+const DefaultTimezone = "Asia/Kolkata"
+
+// Location resolves a Timezone setting.
+func Location(name string) (*time.Location, error) {
+	switch strings.TrimSpace(name) {
+	case "":
+		name = DefaultTimezone
+	case "local", "Local":
+		return time.Local, nil
+	}
+	return time.LoadLocation(name)
 }
 
 const (
-	configFileName = "config.json"
-	profilesDir    = "profiles"
+	configBaseName    = "config"
+	selectorsFileName = "selectors.json"
+	profilesDir       = "profiles"
+	legacyDataDir     = "data"
 )
 
 func ConfigDir() (string, error) {
@@ -68,12 +330,134 @@ func EnsureConfigDir() (string, error) {
 	return dir, nil
 }
 
+// DataDir returns the directory for mutable data such as order history, audit
+// logs, caches and debug artifacts, creating it if needed. On Linux it follows
+// XDG_DATA_HOME; files left in the old <config>/data location are moved over
+// on first use.
+func DataDir() (string, error) {
+	dir, err := dataDirPath()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	migrateDataOnce.Do(func() { migrateLegacyData(dir) })
+	return dir, nil
+}
+
+func dataDirPath() (string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		// Application Support is already the data location on macOS.
+		dir, err := ConfigDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, legacyDataDir), nil
+	case "windows":
+		dir, err := os.UserCacheDir() // %LocalAppData%
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "bislericli"), nil
+	default:
+		if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
+			return filepath.Join(xdg, "bislericli"), nil
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".local", "share", "bislericli"), nil
+	}
+}
+
+var migrateDataOnce sync.Once
+
+// migrateLegacyData moves files from <config>/data and <config>/debug-artifacts
+// into dataDir. Files that already exist at the destination are left alone.
+func migrateLegacyData(dataDir string) {
+	cfgDir, err := ConfigDir()
+	if err != nil {
+		return
+	}
+	moves := map[string]string{
+		filepath.Join(cfgDir, legacyDataDir):     dataDir,
+		filepath.Join(cfgDir, "debug-artifacts"): filepath.Join(dataDir, "debug-artifacts"),
+	}
+	moved := 0
+	for from, to := range moves {
+		if filepath.Clean(from) == filepath.Clean(to) {
+			continue
+		}
+		entries, err := os.ReadDir(from)
+		if err != nil {
+			continue
+		}
+		if err := os.MkdirAll(to, 0o700); err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			src := filepath.Join(from, entry.Name())
+			dst := filepath.Join(to, entry.Name())
+			if _, err := os.Stat(dst); err == nil {
+				continue
+			}
+			if err := moveFile(src, dst); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to move %s to %s: %v\n", src, dst, err)
+				continue
+			}
+			moved++
+		}
+		_ = os.Remove(from) // only succeeds once empty
+	}
+	if moved > 0 {
+		fmt.Fprintf(os.Stderr, "Moved %d data file(s) to %s\n", moved, dataDir)
+	}
+}
+
+// moveFile renames src to dst, copying when they are on different filesystems.
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := fileutil.WriteAtomic(dst, data, 0o600); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+// ConfigFilePath returns the config file in use: the first of config.yaml,
+// config.yml, config.toml and config.json that exists, else config.json.
 func ConfigFilePath() (string, error) {
 	dir, err := EnsureConfigDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(dir, configFileName), nil
+	for _, ext := range Formats {
+		path := filepath.Join(dir, configBaseName+"."+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return filepath.Join(dir, configBaseName+".json"), nil
+}
+
+// SelectorsFilePath is the optional file of parser selector overrides.
+func SelectorsFilePath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, selectorsFileName), nil
 }
 
 func ProfilesDir() (string, error) {
@@ -112,10 +496,14 @@ func DefaultConfig() GlobalConfig {
 	return GlobalConfig{
 		CurrentProfile: "default",
 		Defaults: Defaults{
-			OrderQuantity: 2,
-			ReturnJars:    2,
-			Schedule:      "twice-weekly",
-			Timeslot:      "08:00 AM - 02:00 PM",
+			OrderQuantity:    2,
+			ReturnJars:       2,
+			Schedule:         "twice-weekly",
+			ScheduleTime:     "07:00",
+			Timeslot:         "08:00 AM - 02:00 PM",
+			MinQuantity:      1,
+			MaxQuantity:      4,
+			PendingOrderDays: 2,
 		},
 	}
 }
@@ -125,21 +513,51 @@ func LoadGlobalConfig() (GlobalConfig, error) {
 	if err != nil {
 		return GlobalConfig{}, err
 	}
-	data, err := os.ReadFile(path)
+	var cfg GlobalConfig
+	err = fileutil.ReadWithRecovery(path, 0o600, func(data []byte) error {
+		data, err := ToJSON(path, data)
+		if err != nil {
+			return err
+		}
+		if cfg, err = Decode(data); err != nil {
+			return err
+		}
+		return checkStrict(filepath.Base(path), data, cfg)
+	})
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			cfg := DefaultConfig()
+			cfg = DefaultConfig()
 			if err := SaveGlobalConfig(cfg); err != nil {
 				return GlobalConfig{}, err
 			}
-			return cfg, nil
+		} else {
+			return GlobalConfig{}, err
 		}
+	}
+	st, err := loadState()
+	if err != nil {
 		return GlobalConfig{}, err
 	}
+	applyState(&cfg, st)
+	if err := ApplyEnv(&cfg); err != nil {
+		return GlobalConfig{}, err
+	}
+	return cfg, nil
+}
+
+// Decode parses a JSON config document (see ToJSON for other formats), upgrading older layouts and filling
+// unset defaults. Unknown keys are ignored even when "strict" is set; see
+// UnknownKeys.
+func Decode(data []byte) (GlobalConfig, error) {
 	var cfg GlobalConfig
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	if err := migrate.Config.Decode(data, &cfg); err != nil {
 		return GlobalConfig{}, err
 	}
+	applyDefaults(&cfg)
+	return cfg, nil
+}
+
+func applyDefaults(cfg *GlobalConfig) {
 	if cfg.CurrentProfile == "" {
 		cfg.CurrentProfile = "default"
 	}
@@ -152,20 +570,56 @@ func LoadGlobalConfig() (GlobalConfig, error) {
 	if cfg.Defaults.Schedule == "" {
 		cfg.Defaults.Schedule = "twice-weekly"
 	}
+	if cfg.Defaults.ScheduleTime == "" {
+		cfg.Defaults.ScheduleTime = "07:00"
+	}
 	if cfg.Defaults.Timeslot == "" {
 		cfg.Defaults.Timeslot = "08:00 AM - 02:00 PM"
 	}
-	return cfg, nil
+	if cfg.Defaults.MinQuantity == 0 {
+		cfg.Defaults.MinQuantity = 1
+	}
+	if cfg.Defaults.MaxQuantity == 0 {
+		cfg.Defaults.MaxQuantity = 4
+	}
+	if cfg.Defaults.PendingOrderDays == 0 {
+		cfg.Defaults.PendingOrderDays = 2
+	}
 }
 
+// SaveGlobalConfig writes cfg to the config file. A YAML or TOML config is
+// written by hand and may carry comments, so it is never rewritten: the
+// current profile and serve tokens are saved to a state file beside it, and
+// other changes fail with the keys to edit. A JSON config absorbs the state
+// file.
 func SaveGlobalConfig(cfg GlobalConfig) error {
 	path, err := ConfigFilePath()
 	if err != nil {
 		return err
 	}
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	cfg = withoutEnv(cfg)
+	if FormatOf(path) != "json" {
+		return saveState(path, cfg)
+	}
+	cfg.SchemaVersion = migrate.Config.Current()
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if data, err = FromJSON(FormatOf(path), data); err != nil {
+		return err
+	}
+	unlock, err := fileutil.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := fileutil.WriteWithBackup(path, data, 0o600); err != nil {
+		return err
+	}
+	stPath, err := statePath()
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0o600)
+	return removeState(stPath)
 }
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDataDirMigratesLegacyFiles(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("XDG layout only applies on Linux and other Unix systems")
+	}
+	cfgHome, dataHome := t.TempDir(), t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", cfgHome)
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	migrateDataOnce = sync.Once{}
+
+	legacy := filepath.Join(cfgHome, "bislericli", "data")
+	if err := os.MkdirAll(legacy, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(legacy, "orders_default.json"), []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := DataDir()
+	if err != nil {
+		t.Fatalf("DataDir: %v", err)
+	}
+	if want := filepath.Join(dataHome, "bislericli"); dir != want {
+		t.Fatalf("DataDir = %s, want %s", dir, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "orders_default.json")); err != nil {
+		t.Fatalf("legacy file not moved: %v", err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Fatalf("legacy dir should be removed once empty, stat err = %v", err)
+	}
+}
+
+func TestLocation(t *testing.T) {
+	loc, err := Location("")
+	if err != nil || loc.String() != DefaultTimezone {
+		t.Fatalf("Location(\"\") = %v, %v; want %s", loc, err, DefaultTimezone)
+	}
+	if loc, _ := Location("local"); loc != time.Local {
+		t.Errorf("Location(local) = %v, want the machine zone", loc)
+	}
+	if loc, err := Location("Europe/London"); err != nil || loc.String() != "Europe/London" {
+		t.Errorf("Location(Europe/London) = %v, %v", loc, err)
+	}
+	if _, err := Location("Mars/Olympus"); err == nil {
+		t.Error("unknown zone accepted")
+	}
+}
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix starts the environment variables that override config fields:
+// BISLERI_<SECTION>_<FIELD> for fields in a section (BISLERI_DEFAULTS_TIMESLOT)
+// and BISLERI_<FIELD> for top-level fields (BISLERI_LANGUAGE). Defaults fields
+// can also be set without the section (BISLERI_TIMESLOT). Names are the JSON
+// keys upper-cased; lists are comma-separated, and maps are comma-separated
+// name=value pairs.
+const EnvPrefix = "BISLERI_"
+
+// envOverride is one field set from the environment, with the value it
+// replaced so SaveGlobalConfig does not persist the override.
+type envOverride struct {
+	index         []int
+	before, after any
+}
+
+// envFields maps every overridable variable name to its field index in
+// GlobalConfig.
+func envFields() map[string][]int {
+	fields := map[string][]int{}
+	t := reflect.TypeOf(GlobalConfig{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := envName(f)
+		if name == "" || name == "SCHEMAVERSION" {
+			continue
+		}
+		if f.Type.Kind() != reflect.Struct {
+			fields[EnvPrefix+name] = []int{i}
+			continue
+		}
+		for j := 0; j < f.Type.NumField(); j++ {
+			sub := f.Type.Field(j)
+			subName := envName(sub)
+			if subName == "" {
+				continue
+			}
+			fields[EnvPrefix+name+"_"+subName] = []int{i, j}
+		}
+	}
+	// Shorthands for defaults, unless a top-level field has the same name.
+	defaults, _ := t.FieldByName("Defaults")
+	for j := 0; j < defaults.Type.NumField(); j++ {
+		short := EnvPrefix + envName(defaults.Type.Field(j))
+		if _, taken := fields[short]; !taken {
+			fields[short] = []int{defaults.Index[0], j}
+		}
+	}
+	return fields
+}
+
+func envName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		name = f.Name
+	}
+	return strings.ToUpper(name)
+}
+
+// EnvOverrides lists the override variables set in the environment, sorted.
+func EnvOverrides() []string {
+	var names []string
+	for name := range envFields() {
+		if _, ok := os.LookupEnv(name); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyEnv overrides fields of cfg from BISLERI_* environment variables.
+// LoadGlobalConfig already applies them.
+func ApplyEnv(cfg *GlobalConfig) error {
+	return applyEnv(cfg, os.LookupEnv)
+}
+
+// applyEnv sets the fields of cfg named by override variables found with
+// lookup. A section variable wins over the defaults shorthand for the same
+// field.
+func applyEnv(cfg *GlobalConfig, lookup func(string) (string, bool)) error {
+	fields := envFields()
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	// Shorter names first, so BISLERI_DEFAULTS_TIMESLOT is applied after
+	// (and so wins over) BISLERI_TIMESLOT.
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) < len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	root := reflect.ValueOf(cfg).Elem()
+	for _, name := range names {
+		raw, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		index := fields[name]
+		field := root.FieldByIndex(index)
+		before := field.Interface()
+		if err := setFromEnv(field, raw); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		cfg.env = append(cfg.env, envOverride{index: index, before: before, after: field.Interface()})
+	}
+	return nil
+}
+
+// setFromEnv parses raw into field according to its type.
+func setFromEnv(field reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+	if u, ok := field.Addr().Interface().(json.Unmarshaler); ok {
+		if err := u.UnmarshalJSON([]byte(raw)); err == nil {
+			return nil
+		}
+		quoted, _ := json.Marshal(raw)
+		return u.UnmarshalJSON(quoted)
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q (want true or false)", raw)
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported list type %s", field.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	case reflect.Map:
+		if field.Type().Key().Kind() != reflect.String || field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported map type %s", field.Type())
+		}
+		m := map[string]string{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			k, v, ok := strings.Cut(item, "=")
+			if !ok {
+				return fmt.Errorf("invalid entry %q (want name=value)", item)
+			}
+			m[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+		field.Set(reflect.ValueOf(m))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// withoutEnv returns cfg with fields still holding their environment value
+// put back to what the file had, so saving does not bake overrides in.
+// Fields changed since loading are kept.
+func withoutEnv(cfg GlobalConfig) GlobalConfig {
+	root := reflect.ValueOf(&cfg).Elem()
+	for i := len(cfg.env) - 1; i >= 0; i-- {
+		o := cfg.env[i]
+		field := root.FieldByIndex(o.index)
+		if reflect.DeepEqual(field.Interface(), o.after) {
+			field.Set(reflect.ValueOf(o.before))
+		}
+	}
+	cfg.env = nil
+	return cfg
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"bislericli/internal/money"
+)
+
+func TestApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"BISLERI_DEFAULTS_ORDERQUANTITY":    "4",
+		"BISLERI_TIMESLOT":                  "02:00 PM - 08:00 PM",
+		"BISLERI_NOTIFICATIONS_DESKTOP":     "true",
+		"BISLERI_POLICY_MAXJARPRICE":        "₹95",
+		"BISLERI_POLICY_ALLOWEDTIMESLOTS":   "08:00 AM - 02:00 PM, 02:00 PM - 08:00 PM",
+		"BISLERI_LANGUAGE":                  "hi",
+		"BISLERI_DEFAULTS_PENDINGORDERDAYS": "-1",
+		"BISLERI_INVENTORY_DAILYJARS":       "0.5",
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+	cfg := DefaultConfig()
+	if err := applyEnv(&cfg, lookup); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Defaults.OrderQuantity != 4 || cfg.Defaults.Timeslot != "02:00 PM - 08:00 PM" || cfg.Defaults.PendingOrderDays != -1 {
+		t.Fatalf("defaults not overridden: %+v", cfg.Defaults)
+	}
+	if !cfg.Notifications.Desktop || cfg.Language != "hi" {
+		t.Fatalf("notifications/language not overridden: %+v %q", cfg.Notifications, cfg.Language)
+	}
+	if want, _ := money.Parse("95"); cfg.Policy.MaxJarPrice != want {
+		t.Fatalf("MaxJarPrice = %v", cfg.Policy.MaxJarPrice)
+	}
+	if cfg.Inventory.DailyJars != 0.5 {
+		t.Fatalf("DailyJars = %v", cfg.Inventory.DailyJars)
+	}
+	if len(cfg.Policy.AllowedTimeslots) != 2 || cfg.Policy.AllowedTimeslots[1] != "02:00 PM - 08:00 PM" {
+		t.Fatalf("AllowedTimeslots = %q", cfg.Policy.AllowedTimeslots)
+	}
+
+	env["BISLERI_DEFAULTS_TIMESLOT"] = "08:00 AM - 02:00 PM"
+	cfg = DefaultConfig()
+	cfg.Defaults.Timeslot = "file"
+	if err := applyEnv(&cfg, lookup); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Defaults.Timeslot != "08:00 AM - 02:00 PM" {
+		t.Fatalf("section variable should win over the shorthand, got %q", cfg.Defaults.Timeslot)
+	}
+	cfg.Defaults.OrderQuantity = 3 // changed after loading: kept on save
+	saved := withoutEnv(cfg)
+	if saved.Defaults.Timeslot != "file" || saved.Language != "" || saved.Policy.AllowedTimeslots != nil || saved.Defaults.OrderQuantity != 3 {
+		t.Fatalf("withoutEnv = %+v", saved)
+	}
+
+	if err := applyEnv(&cfg, func(name string) (string, bool) {
+		return "lots", name == "BISLERI_DEFAULTS_MAXQUANTITY"
+	}); err == nil || !strings.Contains(err.Error(), "BISLERI_DEFAULTS_MAXQUANTITY") {
+		t.Fatalf("invalid value error = %v", err)
+	}
+}
+
+func TestSaveGlobalConfigSkipsEnvOverrides(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("XDG layout only applies on Linux and other Unix systems")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("BISLERI_SCHEDULE", "daily")
+	cfg, err := LoadGlobalConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Defaults.Schedule != "daily" {
+		t.Fatalf("Schedule = %q, want daily from the environment", cfg.Defaults.Schedule)
+	}
+	cfg.CurrentProfile = "work"
+	if err := SaveGlobalConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := ConfigFilePath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var onDisk GlobalConfig
+	if err := json.Unmarshal(data, &onDisk); err != nil {
+		t.Fatal(err)
+	}
+	if onDisk.Defaults.Schedule != "twice-weekly" || onDisk.CurrentProfile != "work" {
+		t.Fatalf("saved config = %+v", onDisk.Defaults)
+	}
+}
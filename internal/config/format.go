@@ -0,0 +1,524 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Config files may be written as YAML or TOML as well as JSON. Both are read
+// into the same JSON document the rest of the package decodes, so schema
+// migration, strict mode and defaults work alike for every format. Only the
+// subset a config file needs is supported: nested sections, strings, numbers,
+// booleans and lists of scalars.
+
+// Formats lists the supported config formats by file extension, in the order
+// ConfigFilePath looks for them.
+var Formats = []string{"yaml", "yml", "toml", "json"}
+
+// FormatOf returns the config format for path from its extension.
+func FormatOf(path string) string {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if ext == "yml" {
+		return "yaml"
+	}
+	return ext
+}
+
+// ToJSON converts a config document in the format of name to JSON.
+func ToJSON(name string, data []byte) ([]byte, error) {
+	switch FormatOf(name) {
+	case "json":
+		return data, nil
+	case "yaml":
+		doc, err := parseYAML(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(name), err)
+		}
+		return json.Marshal(doc)
+	case "toml":
+		doc, err := parseTOML(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(name), err)
+		}
+		return json.Marshal(doc)
+	}
+	return nil, fmt.Errorf("unsupported config format %q", filepath.Ext(name))
+}
+
+// FromJSON converts a JSON config document to format, keeping key order.
+func FromJSON(format string, data []byte) ([]byte, error) {
+	if format == "json" {
+		var out bytes.Buffer
+		if err := json.Indent(&out, data, "", "  "); err != nil {
+			return nil, err
+		}
+		return out.Bytes(), nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := readOrdered(dec)
+	if err != nil {
+		return nil, err
+	}
+	doc, ok := v.(object)
+	if !ok {
+		return nil, errors.New("config document must be an object")
+	}
+	var out bytes.Buffer
+	switch format {
+	case "yaml", "yml":
+		writeYAML(&out, doc, 0)
+	case "toml":
+		writeTOML(&out, doc, "")
+	default:
+		return nil, fmt.Errorf("unsupported config format %q", format)
+	}
+	return out.Bytes(), nil
+}
+
+// object is a JSON object with its key order preserved.
+type object []member
+
+type member struct {
+	Key   string
+	Value any
+}
+
+// readOrdered reads one JSON value, keeping object key order.
+func readOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		var obj object
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := readOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, member{Key: keyTok.(string), Value: value})
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		list := []any{}
+		for dec.More() {
+			value, err := readOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err := dec.Token()
+		return list, err
+	}
+	return tok, nil
+}
+
+// scalarText renders a scalar for YAML and TOML. Strings use JSON quoting,
+// which both formats read as a double-quoted string.
+func scalarText(v any) string {
+	switch v := v.(type) {
+	case string:
+		quoted, _ := json.Marshal(v)
+		return string(quoted)
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = scalarText(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return "null"
+}
+
+func writeYAML(out *bytes.Buffer, doc object, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for _, m := range doc {
+		key := m.Key
+		if !plainKey(key) {
+			key = scalarText(key)
+		}
+		switch v := m.Value.(type) {
+		case object:
+			if len(v) == 0 {
+				fmt.Fprintf(out, "%s%s: {}\n", pad, key)
+				continue
+			}
+			fmt.Fprintf(out, "%s%s:\n", pad, key)
+			writeYAML(out, v, indent+2)
+		case []any:
+			if len(v) == 0 {
+				fmt.Fprintf(out, "%s%s: []\n", pad, key)
+				continue
+			}
+			fmt.Fprintf(out, "%s%s:\n", pad, key)
+			for _, item := range v {
+				fmt.Fprintf(out, "%s  - %s\n", pad, scalarText(item))
+			}
+		default:
+			fmt.Fprintf(out, "%s%s: %s\n", pad, key, scalarText(v))
+		}
+	}
+}
+
+// writeTOML writes the scalars of doc as key = value lines, then each nested
+// object as a [table].
+func writeTOML(out *bytes.Buffer, doc object, table string) {
+	for _, m := range doc {
+		if _, nested := m.Value.(object); nested || m.Value == nil {
+			continue
+		}
+		fmt.Fprintf(out, "%s = %s\n", tomlKey(m.Key), scalarText(m.Value))
+	}
+	for _, m := range doc {
+		nested, ok := m.Value.(object)
+		if !ok {
+			continue
+		}
+		name := tomlKey(m.Key)
+		if table != "" {
+			name = table + "." + name
+		}
+		if out.Len() > 0 {
+			out.WriteString("\n")
+		}
+		fmt.Fprintf(out, "[%s]\n", name)
+		writeTOML(out, nested, name)
+	}
+}
+
+func tomlKey(key string) string {
+	if plainKey(key) {
+		return key
+	}
+	return scalarText(key)
+}
+
+func plainKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// parseScalar reads a YAML or TOML scalar or flow list of scalars.
+func parseScalar(s string) (any, error) {
+	switch {
+	case s == "":
+		return nil, nil
+	case strings.HasPrefix(s, `"`):
+		var out string
+		if err := json.Unmarshal([]byte(s), &out); err != nil {
+			return nil, fmt.Errorf("invalid string %s", s)
+		}
+		return out, nil
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return nil, fmt.Errorf("unterminated string %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	case strings.HasPrefix(s, "["):
+		if !strings.HasSuffix(s, "]") {
+			return nil, fmt.Errorf("unterminated list %s", s)
+		}
+		list := []any{}
+		for _, part := range splitOutsideQuotes(s[1:len(s)-1], ',') {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			v, err := parseScalar(part)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case s == "{}":
+		return map[string]any{}, nil
+	case s == "true":
+		return true, nil
+	case s == "false":
+		return false, nil
+	case s == "null" || s == "~":
+		return nil, nil
+	}
+	// What is left is a number when it is valid JSON (TOML allows 1_000).
+	if n := strings.TrimPrefix(strings.ReplaceAll(s, "_", ""), "+"); n != "" && (n[0] == '-' || n[0] >= '0' && n[0] <= '9') && json.Valid([]byte(n)) {
+		return json.Number(n), nil
+	}
+	return s, nil
+}
+
+// stripComment removes a # comment that is outside quotes.
+func stripComment(line string) string {
+	var quote rune
+	escaped := false
+	for i, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == '\\' && quote == '"' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case (r == '"' || r == '\'') && (i == 0 || strings.ContainsRune(" \t[,:=", rune(line[i-1]))):
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+func parseYAML(data []byte) (map[string]any, error) {
+	var lines []yamlLine
+	for i, raw := range strings.Split(string(data), "\n") {
+		raw = strings.TrimRight(stripComment(strings.TrimRight(raw, "\r")), " \t")
+		text := strings.TrimLeft(raw, " ")
+		if text == "" || text == "---" {
+			continue
+		}
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		lines = append(lines, yamlLine{num: i + 1, indent: len(raw) - len(text), text: text})
+	}
+	if len(lines) == 0 {
+		return map[string]any{}, nil
+	}
+	v, rest, err := parseYAMLBlock(lines, lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("line %d: unexpected indentation", rest[0].num)
+	}
+	doc, ok := v.(map[string]any)
+	if !ok {
+		return nil, errors.New("config document must be a mapping")
+	}
+	return doc, nil
+}
+
+// parseYAMLBlock reads a mapping or list whose lines are at indent, returning
+// the lines after it.
+func parseYAMLBlock(lines []yamlLine, indent int) (any, []yamlLine, error) {
+	if strings.HasPrefix(lines[0].text, "- ") || lines[0].text == "-" {
+		var list []any
+		for len(lines) > 0 && lines[0].indent == indent {
+			line := lines[0]
+			if !strings.HasPrefix(line.text, "-") {
+				return nil, nil, fmt.Errorf("line %d: expected a list item", line.num)
+			}
+			v, err := parseScalar(strings.TrimSpace(strings.TrimPrefix(line.text, "-")))
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %w", line.num, err)
+			}
+			list = append(list, v)
+			lines = lines[1:]
+		}
+		return list, lines, nil
+	}
+	doc := map[string]any{}
+	for len(lines) > 0 && lines[0].indent == indent {
+		line := lines[0]
+		key, value, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, nil, fmt.Errorf("line %d: expected \"key: value\"", line.num)
+		}
+		if _, dup := doc[key]; dup {
+			return nil, nil, fmt.Errorf("line %d: duplicate key %q", line.num, key)
+		}
+		lines = lines[1:]
+		if value != "" {
+			v, err := parseScalar(value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %w", line.num, err)
+			}
+			doc[key] = v
+			continue
+		}
+		// A list may sit at the key's own indent; a mapping must be deeper.
+		if len(lines) > 0 && (lines[0].indent > indent || lines[0].indent == indent && strings.HasPrefix(lines[0].text, "-")) {
+			v, rest, err := parseYAMLBlock(lines, lines[0].indent)
+			if err != nil {
+				return nil, nil, err
+			}
+			doc[key], lines = v, rest
+			continue
+		}
+		doc[key] = nil
+	}
+	if len(lines) > 0 && lines[0].indent > indent {
+		return nil, nil, fmt.Errorf("line %d: unexpected indentation", lines[0].num)
+	}
+	return doc, lines, nil
+}
+
+// splitYAMLKey splits "key: value" where key may be quoted.
+func splitYAMLKey(text string) (key, value string, ok bool) {
+	if strings.HasPrefix(text, `"`) || strings.HasPrefix(text, "'") {
+		end := strings.Index(text[1:], text[:1])
+		if end < 0 {
+			return "", "", false
+		}
+		k, err := parseScalar(text[:end+2])
+		if err != nil {
+			return "", "", false
+		}
+		rest := text[end+2:]
+		if !strings.HasPrefix(rest, ":") {
+			return "", "", false
+		}
+		return k.(string), strings.TrimSpace(rest[1:]), true
+	}
+	i := strings.Index(text, ": ")
+	if i < 0 {
+		if !strings.HasSuffix(text, ":") {
+			return "", "", false
+		}
+		i = len(text) - 1
+	}
+	return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+}
+
+func parseTOML(data []byte) (map[string]any, error) {
+	doc := map[string]any{}
+	table := doc
+	rawLines := strings.Split(string(data), "\n")
+	for i := 0; i < len(rawLines); i++ {
+		num := i + 1
+		line := strings.TrimSpace(stripComment(strings.TrimRight(rawLines[i], "\r")))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("line %d: unsupported table header %s", num, line)
+			}
+			var err error
+			if table, err = tomlTable(doc, strings.TrimSpace(line[1:len(line)-1])); err != nil {
+				return nil, fmt.Errorf("line %d: %w", num, err)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", num)
+		}
+		value = strings.TrimSpace(value)
+		// Arrays may span lines.
+		for strings.HasPrefix(value, "[") && strings.Count(value, "[") > strings.Count(value, "]") && i+1 < len(rawLines) {
+			i++
+			value += " " + strings.TrimSpace(stripComment(rawLines[i]))
+		}
+		v, err := parseScalar(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", num, err)
+		}
+		parts := splitDotted(strings.TrimSpace(key))
+		target, err := tomlTable(table, strings.Join(parts[:len(parts)-1], "."))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", num, err)
+		}
+		last := parts[len(parts)-1]
+		if _, dup := target[last]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", num, last)
+		}
+		target[last] = v
+	}
+	return doc, nil
+}
+
+// tomlTable returns the table at the dotted path under root, creating it.
+func tomlTable(root map[string]any, path string) (map[string]any, error) {
+	if path == "" {
+		return root, nil
+	}
+	table := root
+	for _, part := range splitDotted(path) {
+		next, exists := table[part]
+		if !exists {
+			child := map[string]any{}
+			table[part] = child
+			table = child
+			continue
+		}
+		child, ok := next.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("key %q is not a table", part)
+		}
+		table = child
+	}
+	return table, nil
+}
+
+// splitDotted splits a dotted key, unquoting quoted parts.
+func splitDotted(key string) []string {
+	var parts []string
+	for _, part := range splitOutsideQuotes(key, '.') {
+		part = strings.TrimSpace(part)
+		if v, err := parseScalar(part); err == nil {
+			if s, ok := v.(string); ok && (strings.HasPrefix(part, `"`) || strings.HasPrefix(part, "'")) {
+				part = s
+			}
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// splitOutsideQuotes splits s on sep where it is not inside a quoted string.
+func splitOutsideQuotes(s string, sep rune) []string {
+	var parts []string
+	var quote rune
+	escaped := false
+	start := 0
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == '\\' && quote == '"' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
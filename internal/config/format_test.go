@@ -0,0 +1,184 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestToJSONYAMLAndTOML(t *testing.T) {
+	yaml := `# delivery defaults
+currentProfile: home
+defaults:
+  orderQuantity: 3   # jars
+  timeslot: 08:00 AM - 02:00 PM
+  scheduleTime: "07:30"
+  monthlyBudget: 1500.5
+notifications:
+  desktop: true
+policy:
+  allowedTimeslots:
+    - 08:00 AM - 02:00 PM
+    - '02:00 PM - 08:00 PM'
+hooks:
+  postSync: ["notify-send 'synced #1'"]
+`
+	toml := `# delivery defaults
+currentProfile = "home"
+
+[defaults]
+orderQuantity = 3 # jars
+timeslot = "08:00 AM - 02:00 PM"
+scheduleTime = '07:30'
+monthlyBudget = 1500.5
+
+[notifications]
+desktop = true
+
+[policy]
+allowedTimeslots = [
+  "08:00 AM - 02:00 PM",
+  "02:00 PM - 08:00 PM",
+]
+
+[hooks]
+postSync = ["notify-send 'synced #1'"]
+`
+	var want GlobalConfig
+	for name, doc := range map[string]string{"config.yaml": yaml, "config.toml": toml} {
+		data, err := ToJSON(name, []byte(doc))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		cfg, err := Decode(data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if cfg.CurrentProfile != "home" || cfg.Defaults.OrderQuantity != 3 || cfg.Defaults.ScheduleTime != "07:30" ||
+			cfg.Defaults.Timeslot != "08:00 AM - 02:00 PM" || !cfg.Notifications.Desktop ||
+			len(cfg.Policy.AllowedTimeslots) != 2 || cfg.Policy.AllowedTimeslots[1] != "02:00 PM - 08:00 PM" ||
+			len(cfg.Hooks.PostSync) != 1 || cfg.Hooks.PostSync[0] != "notify-send 'synced #1'" {
+			t.Fatalf("%s decoded to %+v", name, cfg)
+		}
+		if want.CurrentProfile == "" {
+			want = cfg
+		} else if !reflect.DeepEqual(cfg, want) {
+			t.Fatalf("%s decoded differently:\n%+v\n%+v", name, cfg, want)
+		}
+	}
+}
+
+func TestFromJSONRoundTrip(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Policy.AllowedTimeslots = []string{`08:00 AM - 02:00 PM`, `say "hi" # not a comment`}
+	cfg.Hooks.PreOrder = []string{}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{"yaml", "toml", "json"} {
+		out, err := FromJSON(format, data)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		back, err := ToJSON("config."+format, out)
+		if err != nil {
+			t.Fatalf("%s: %v\n%s", format, err, out)
+		}
+		got, err := Decode(back)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if !reflect.DeepEqual(got.Policy, cfg.Policy) || got.Defaults != cfg.Defaults {
+			t.Fatalf("%s round trip = %+v, want %+v\n%s", format, got, cfg, out)
+		}
+	}
+}
+
+func TestConfigFilePathPrefersYAML(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("XDG layout only applies on Linux and other Unix systems")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir, err := EnsureConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"currentProfile": "json"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.toml"), []byte("currentProfile = \"toml\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadGlobalConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CurrentProfile != "toml" {
+		t.Fatalf("CurrentProfile = %q, want toml over json", cfg.CurrentProfile)
+	}
+	cfg.CurrentProfile = "work"
+	if err := SaveGlobalConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "config.toml")); string(data) != "currentProfile = \"toml\"\n" {
+		t.Fatalf("config.toml was rewritten:\n%s", data)
+	}
+	if cfg, err := LoadGlobalConfig(); err != nil || cfg.CurrentProfile != "work" {
+		t.Fatalf("reloaded CurrentProfile = %q, %v; want work", cfg.CurrentProfile, err)
+	}
+}
+
+func TestSaveKeepsHandWrittenYAML(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("XDG layout only applies on Linux and other Unix systems")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir, err := EnsureConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	yaml := "# shared with the family\ncurrentProfile: home\nserve:\n  tokens:\n    dashboard:\n      hash: abc\n      scopes: [read]\n"
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadGlobalConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.CurrentProfile = "work"
+	cfg.Serve.Tokens["ci"] = APIToken{Hash: "def", Scopes: []string{"order"}}
+	if err := SaveGlobalConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != yaml {
+		t.Fatalf("config.yaml was rewritten:\n%s", data)
+	}
+	cfg, err = LoadGlobalConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CurrentProfile != "work" || cfg.Serve.Tokens["ci"].Hash != "def" || cfg.Serve.Tokens["dashboard"].Hash != "abc" {
+		t.Fatalf("reloaded config = %q, %+v", cfg.CurrentProfile, cfg.Serve.Tokens)
+	}
+
+	// Revoking a state token works; one written in the file must be removed there.
+	delete(cfg.Serve.Tokens, "ci")
+	if err := SaveGlobalConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	delete(cfg.Serve.Tokens, "dashboard")
+	cfg.Defaults.OrderQuantity = 9
+	err = SaveGlobalConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "defaults.orderQuantity, serve.tokens.dashboard") {
+		t.Fatalf("saving file settings = %v, want the keys to edit", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != yaml {
+		t.Fatalf("config.yaml was rewritten:\n%s", data)
+	}
+}
@@ -0,0 +1,63 @@
+package config
+
+// redactedValue replaces secrets in configs shared outside the machine.
+const redactedValue = "[REDACTED]"
+
+// WithoutSecrets returns a copy of cfg with every token, secret, token hash
+// and contact detail replaced, for issue reports. Maps are copied, so cfg is
+// left untouched.
+func (cfg GlobalConfig) WithoutSecrets() GlobalConfig {
+	blank := func(s *string) {
+		if *s != "" {
+			*s = redactedValue
+		}
+	}
+	blankList := func(list []string) []string {
+		out := make([]string, len(list))
+		for i := range list {
+			out[i] = redactedValue
+		}
+		if list == nil {
+			return nil
+		}
+		return out
+	}
+
+	blank(&cfg.Geocoding.Email)
+	blank(&cfg.Notifications.WhatsApp.Token)
+	blank(&cfg.Notifications.WhatsApp.PhoneNumberID)
+	cfg.Notifications.WhatsApp.To = blankList(cfg.Notifications.WhatsApp.To)
+	blank(&cfg.Notifications.Signal.Account)
+	blank(&cfg.Notifications.Signal.Group)
+	cfg.Notifications.Signal.To = blankList(cfg.Notifications.Signal.To)
+	blank(&cfg.Sheets.CredentialsFile)
+	blank(&cfg.Weather.Latitude)
+	blank(&cfg.Weather.Longitude)
+	blank(&cfg.OTPRelay.Secret)
+	blank(&cfg.Telegram.Token)
+	cfg.Telegram.AllowedChats = blankList(cfg.Telegram.AllowedChats)
+
+	if cfg.Client.Headers != nil {
+		headers := make(map[string]string, len(cfg.Client.Headers))
+		for name := range cfg.Client.Headers {
+			headers[name] = redactedValue
+		}
+		cfg.Client.Headers = headers
+	}
+	if cfg.Serve.WebhookSecrets != nil {
+		secrets := make(map[string]string, len(cfg.Serve.WebhookSecrets))
+		for source := range cfg.Serve.WebhookSecrets {
+			secrets[source] = redactedValue
+		}
+		cfg.Serve.WebhookSecrets = secrets
+	}
+	if cfg.Serve.Tokens != nil {
+		tokens := make(map[string]APIToken, len(cfg.Serve.Tokens))
+		for name, t := range cfg.Serve.Tokens {
+			t.Hash = redactedValue
+			tokens[name] = t
+		}
+		cfg.Serve.Tokens = tokens
+	}
+	return cfg
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"bislericli/internal/fileutil"
+)
+
+// stateFileName holds the settings commands change (the current profile and
+// serve tokens) when the config is a hand-written YAML or TOML file, so saving
+// them never rewrites that file and strips its comments.
+const stateFileName = "state.json"
+
+// state overrides the matching config settings when the config is loaded.
+type state struct {
+	CurrentProfile string              `json:"currentProfile,omitempty"`
+	Tokens         map[string]APIToken `json:"tokens,omitempty"`
+}
+
+func statePath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, stateFileName), nil
+}
+
+func loadState() (state, error) {
+	var st state
+	path, err := statePath()
+	if err != nil {
+		return st, err
+	}
+	err = fileutil.ReadWithRecovery(path, 0o600, func(data []byte) error {
+		st = state{}
+		return json.Unmarshal(data, &st)
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return state{}, nil
+	}
+	return st, err
+}
+
+// applyState overlays saved state on a config read from its file.
+func applyState(cfg *GlobalConfig, st state) {
+	if st.CurrentProfile != "" {
+		cfg.CurrentProfile = st.CurrentProfile
+	}
+	if len(st.Tokens) == 0 {
+		return
+	}
+	tokens := make(map[string]APIToken, len(cfg.Serve.Tokens)+len(st.Tokens))
+	for name, t := range cfg.Serve.Tokens {
+		tokens[name] = t
+	}
+	for name, t := range st.Tokens {
+		tokens[name] = t
+	}
+	cfg.Serve.Tokens = tokens
+}
+
+// saveState records where cfg differs from the YAML or TOML config at path in
+// the state file. Any other difference would need the file rewritten, so it
+// is refused with the keys to change by hand.
+func saveState(path string, cfg GlobalConfig) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if data, err = ToJSON(path, data); err != nil {
+		return err
+	}
+	file, err := Decode(data)
+	if err != nil {
+		return err
+	}
+
+	var st state
+	if cfg.CurrentProfile != file.CurrentProfile {
+		st.CurrentProfile = cfg.CurrentProfile
+	}
+	var keys []string
+	for name, t := range cfg.Serve.Tokens {
+		if ft, ok := file.Serve.Tokens[name]; !ok || !sameJSON(ft, t) {
+			if st.Tokens == nil {
+				st.Tokens = map[string]APIToken{}
+			}
+			st.Tokens[name] = t
+		}
+	}
+	for name := range file.Serve.Tokens {
+		if _, ok := cfg.Serve.Tokens[name]; !ok {
+			keys = append(keys, "serve.tokens."+name)
+		}
+	}
+	cfg.CurrentProfile = file.CurrentProfile
+	cfg.Serve.Tokens = file.Serve.Tokens
+	cfg.SchemaVersion = file.SchemaVersion
+	keys = append(keys, changedKeys(file, cfg)...)
+	if len(keys) > 0 {
+		sort.Strings(keys)
+		return fmt.Errorf("%s is not rewritten, so its comments are kept; change %s in it by hand", filepath.Base(path), strings.Join(keys, ", "))
+	}
+
+	stPath, err := statePath()
+	if err != nil {
+		return err
+	}
+	unlock, err := fileutil.Lock(stPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if st.CurrentProfile == "" && len(st.Tokens) == 0 {
+		return removeState(stPath)
+	}
+	out, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteWithBackup(stPath, out, 0o600)
+}
+
+func removeState(path string) error {
+	for _, p := range []string{path, path + fileutil.BackupSuffix} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func sameJSON(a, b any) bool {
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && string(x) == string(y)
+}
+
+// changedKeys lists the dotted config keys whose values differ between a and b.
+func changedKeys(a, b GlobalConfig) []string {
+	var x, y any
+	if !decodeAny(a, &x) || !decodeAny(b, &y) {
+		return []string{"(config)"}
+	}
+	var keys []string
+	var walk func(prefix string, x, y any)
+	walk = func(prefix string, x, y any) {
+		mx, okX := x.(map[string]any)
+		my, okY := y.(map[string]any)
+		if !okX || !okY {
+			if !reflect.DeepEqual(x, y) {
+				keys = append(keys, prefix)
+			}
+			return
+		}
+		seen := map[string]bool{}
+		for k := range mx {
+			seen[k] = true
+		}
+		for k := range my {
+			seen[k] = true
+		}
+		for k := range seen {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			walk(key, mx[k], my[k])
+		}
+	}
+	walk("", x, y)
+	return keys
+}
+
+func decodeAny(cfg GlobalConfig, v *any) bool {
+	data, err := json.Marshal(cfg)
+	return err == nil && json.Unmarshal(data, v) == nil
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"bislericli/internal/fileutil"
+)
+
+// UnknownKey is a key in the config file that no GlobalConfig field reads, with
+// the closest known key when it looks like a typo.
+type UnknownKey struct {
+	Path       string
+	Suggestion string
+}
+
+func (k UnknownKey) String() string {
+	if k.Suggestion != "" {
+		return fmt.Sprintf("unknown key %q (did you mean %q?)", k.Path, k.Suggestion)
+	}
+	return fmt.Sprintf("unknown key %q", k.Path)
+}
+
+// UnknownKeys lists the keys in a JSON config document that GlobalConfig does
+// not know, as dotted paths sorted within each section.
+func UnknownKeys(data []byte) ([]UnknownKey, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	var unknown []UnknownKey
+	walkUnknown(doc, reflect.TypeOf(GlobalConfig{}), "", &unknown)
+	return unknown, nil
+}
+
+func walkUnknown(doc map[string]any, t reflect.Type, prefix string, unknown *[]UnknownKey) {
+	fields := jsonFields(t)
+	keys := make([]string, 0, len(doc))
+	for key := range doc {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		field, ok := fields[key]
+		if !ok {
+			*unknown = append(*unknown, UnknownKey{Path: prefix + key, Suggestion: suggestKey(key, fields, prefix)})
+			continue
+		}
+		if nested, isMap := doc[key].(map[string]any); isMap && field.Kind() == reflect.Struct {
+			walkUnknown(nested, field, prefix+key+".", unknown)
+		}
+	}
+}
+
+// jsonFields maps the JSON names of t's exported fields to their types.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// suggestKey returns the known key closest to key: one differing only in
+// case, or within two edits.
+func suggestKey(key string, fields map[string]reflect.Type, prefix string) string {
+	best, bestDist := "", 3
+	for name := range fields {
+		if strings.EqualFold(name, key) {
+			return prefix + name
+		}
+		if d := editDistance(strings.ToLower(key), strings.ToLower(name)); d < bestDist || d == bestDist && name < best {
+			best, bestDist = name, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return prefix + best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// checkStrict rejects unknown keys when the document sets "strict": true. The
+// error is not treated as corruption, so the backup is not restored over it.
+func checkStrict(name string, data []byte, cfg GlobalConfig) error {
+	if !cfg.Strict {
+		return nil
+	}
+	unknown, err := UnknownKeys(data)
+	if err != nil || len(unknown) == 0 {
+		return err
+	}
+	msgs := make([]string, len(unknown))
+	for i, k := range unknown {
+		msgs[i] = k.String()
+	}
+	return fileutil.NoRecovery(fmt.Errorf("%s: %s; fix or remove the key(s), or set \"strict\": false", name, strings.Join(msgs, "; ")))
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestUnknownKeysSuggestsTypos(t *testing.T) {
+	data := []byte(`{
+		"defaults": {"orderQuantty": 3, "timeslot": "08:00 AM - 02:00 PM"},
+		"notifications": {"Desktop": true},
+		"webhook": "https://example.com",
+		"policy": {"maxJarPrice": 90}
+	}`)
+	unknown, err := UnknownKeys(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, k := range unknown {
+		got[k.Path] = k.Suggestion
+	}
+	want := map[string]string{
+		"defaults.orderQuantty": "defaults.orderQuantity",
+		"notifications.Desktop": "notifications.desktop",
+		"webhook":               "",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("UnknownKeys = %+v, want %v", unknown, want)
+	}
+	for path, suggestion := range want {
+		if s, ok := got[path]; !ok || s != suggestion {
+			t.Fatalf("UnknownKeys[%s] = %q (found %v), want %q", path, s, ok, suggestion)
+		}
+	}
+}
+
+func TestLoadGlobalConfigStrict(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("XDG layout only applies on Linux and other Unix systems")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path, err := ConfigFilePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func(doc string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(doc), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{"defaults": {"shedule": "daily"}}`)
+	if _, err := LoadGlobalConfig(); err != nil {
+		t.Fatalf("unknown keys should be ignored without strict: %v", err)
+	}
+
+	// A clean backup must not be restored over a strict failure.
+	if err := os.WriteFile(path+".bak", []byte(`{"strict": true}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	write(`{"strict": true, "defaults": {"shedule": "daily"}}`)
+	_, err = LoadGlobalConfig()
+	if err == nil || !strings.Contains(err.Error(), `did you mean "defaults.schedule"?`) {
+		t.Fatalf("strict load error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Clean(path)); !strings.Contains(string(data), "shedule") {
+		t.Fatalf("config.json was replaced by the backup: %s", data)
+	}
+}
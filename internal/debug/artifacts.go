@@ -0,0 +1,124 @@
+package debug
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"bislericli/internal/config"
+)
+
+// DefaultMaxArtifactBytes caps the total size of saved debug artifacts; the
+// oldest files are pruned first when a new one pushes past it.
+const DefaultMaxArtifactBytes int64 = 20 << 20
+
+const artifactsDirName = "debug-artifacts"
+
+type Artifact struct {
+	Name    string
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// ArtifactsDir returns the per-user debug artifacts directory under the data
+// dir, creating it with owner-only permissions.
+func ArtifactsDir() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, artifactsDirName)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// SaveArtifact writes data under a timestamped name and prunes old artifacts.
+func SaveArtifact(name string, data []byte) (string, error) {
+	dir, err := ArtifactsDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, time.Now().Format("20060102-150405")+"-"+filepath.Base(name))
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", err
+	}
+	_, _ = PruneArtifacts(DefaultMaxArtifactBytes)
+	return path, nil
+}
+
+// ListArtifacts returns saved artifacts, newest first.
+func ListArtifacts() ([]Artifact, error) {
+	dir, err := ArtifactsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var artifacts []Artifact
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		artifacts = append(artifacts, Artifact{
+			Name:    entry.Name(),
+			Path:    filepath.Join(dir, entry.Name()),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].ModTime.After(artifacts[j].ModTime) })
+	return artifacts, nil
+}
+
+// PruneArtifacts removes the oldest artifacts until the total size is at most
+// maxBytes, returning how many were removed.
+func PruneArtifacts(maxBytes int64) (int, error) {
+	artifacts, err := ListArtifacts()
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, a := range artifacts {
+		total += a.Size
+	}
+	removed := 0
+	for i := len(artifacts) - 1; i >= 0 && total > maxBytes; i-- {
+		if err := os.Remove(artifacts[i].Path); err != nil {
+			return removed, err
+		}
+		total -= artifacts[i].Size
+		removed++
+	}
+	return removed, nil
+}
+
+// CleanArtifacts removes artifacts older than olderThan (all when zero).
+func CleanArtifacts(olderThan time.Duration) (int, error) {
+	artifacts, err := ListArtifacts()
+	if err != nil {
+		return 0, err
+	}
+	cutoff := time.Now().Add(-olderThan)
+	removed := 0
+	for _, a := range artifacts {
+		if olderThan > 0 && a.ModTime.After(cutoff) {
+			continue
+		}
+		if err := os.Remove(a.Path); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
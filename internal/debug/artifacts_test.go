@@ -0,0 +1,38 @@
+package debug
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestPruneArtifactsRemovesOldestFirst(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	dir, err := ArtifactsDir()
+	if err != nil {
+		t.Fatalf("ArtifactsDir: %v", err)
+	}
+	now := time.Now()
+	for i, name := range []string{"old.html", "mid.html", "new.html"} {
+		path := dir + "/" + name
+		if err := os.WriteFile(path, make([]byte, 100), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		mod := now.Add(time.Duration(i-3) * time.Hour)
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	removed, err := PruneArtifacts(200)
+	if err != nil {
+		t.Fatalf("PruneArtifacts: %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("expected 1 removed, got %d", removed)
+	}
+	artifacts, _ := ListArtifacts()
+	if len(artifacts) != 2 || artifacts[0].Name != "new.html" || artifacts[1].Name != "mid.html" {
+		t.Fatalf("unexpected artifacts: %+v", artifacts)
+	}
+}
@@ -0,0 +1,112 @@
+package debug
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CaptureFileName is the name of the structured export written next to page
+// fixtures.
+const CaptureFileName = "capture.json"
+
+// Exchange is one request seen during the debug order flow and the response
+// to it. Values are redacted before they are stored.
+type Exchange struct {
+	Method      string            `json:"method"`
+	URL         string            `json:"url"`
+	Type        string            `json:"type,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	HasPostData bool              `json:"hasPostData,omitempty"`
+	// PostData is the request body; Form holds its fields when it is a
+	// URL-encoded form.
+	PostData string              `json:"postData,omitempty"`
+	Form     map[string][]string `json:"form,omitempty"`
+	Status   int                 `json:"status,omitempty"`
+	MimeType string              `json:"mimeType,omitempty"`
+	Body     string              `json:"body,omitempty"`
+}
+
+// Capture is everything the debug order flow recorded in one run.
+type Capture struct {
+	Started   time.Time  `json:"started"`
+	Finished  time.Time  `json:"finished"`
+	Exchanges []Exchange `json:"exchanges"`
+}
+
+// interesting reports whether a request is worth recording: form posts and
+// anything on the cart or checkout path.
+func interesting(method, rawURL string) bool {
+	if strings.EqualFold(method, "POST") {
+		return true
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	path := strings.ToLower(u.Path)
+	return strings.Contains(path, "checkout") || strings.Contains(path, "cart")
+}
+
+// FixtureKind names the page a URL serves, as used for fixture files: cart,
+// shipping, payment or checkout. It is empty for other URLs.
+func FixtureKind(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	path := strings.ToLower(strings.TrimSuffix(u.Path, "/"))
+	switch {
+	case strings.HasSuffix(path, "/mycart"):
+		return "cart"
+	case strings.HasSuffix(path, "/checkout"):
+		switch stage := u.Query().Get("stage"); stage {
+		case "shipping", "payment":
+			return stage
+		}
+		return "checkout"
+	}
+	return ""
+}
+
+// SaveFixtures writes the capture to dir as capture.json, plus each HTML page
+// with a fixture kind as <kind>.html (the last response wins), and returns
+// the files written. Copy the pages into internal/bisleri/testdata/fixtures
+// to add them to the golden parser tests.
+func SaveFixtures(dir string, c Capture) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, CaptureFileName)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return nil, err
+	}
+	written := []string{path}
+	pages := map[string]string{}
+	var order []string
+	for _, e := range c.Exchanges {
+		kind := FixtureKind(e.URL)
+		if kind == "" || e.Body == "" || !strings.Contains(e.MimeType, "html") {
+			continue
+		}
+		if _, ok := pages[kind]; !ok {
+			order = append(order, kind)
+		}
+		pages[kind] = e.Body
+	}
+	for _, kind := range order {
+		path := filepath.Join(dir, kind+".html")
+		if err := os.WriteFile(path, []byte(pages[kind]), 0o600); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
@@ -0,0 +1,132 @@
+package debug
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"bislericli/internal/redact"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+func TestFixtureKind(t *testing.T) {
+	cases := map[string]string{
+		"https://www.bisleri.com/mycart":                  "cart",
+		"https://www.bisleri.com/checkout?stage=shipping": "shipping",
+		"https://www.bisleri.com/checkout?stage=payment":  "payment",
+		"https://www.bisleri.com/checkout":                "checkout",
+		"https://www.bisleri.com/home":                    "",
+	}
+	for u, want := range cases {
+		if got := FixtureKind(u); got != want {
+			t.Errorf("FixtureKind(%s) = %q, want %q", u, got, want)
+		}
+	}
+}
+
+func TestNetTrackerIdleAndRedirects(t *testing.T) {
+	red, err := redact.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := newNetTracker(red)
+	now := time.Now()
+	tr.handle(&network.EventRequestWillBeSent{
+		RequestID: "1",
+		Request:   &network.Request{Method: "POST", URL: "https://www.bisleri.com/cart-submit", Headers: network.Headers{"Cookie": "dwsid=abc"}},
+	}, now)
+	tr.handle(&network.EventRequestWillBeSent{
+		RequestID:        "1",
+		Request:          &network.Request{Method: "GET", URL: "https://www.bisleri.com/checkout?stage=shipping"},
+		RedirectResponse: &network.Response{Status: 302},
+	}, now)
+	tr.handle(&network.EventRequestWillBeSent{
+		RequestID: "2",
+		Request:   &network.Request{Method: "GET", URL: "https://www.bisleri.com/home.css"},
+	}, now)
+	if tr.idle(time.Second, now.Add(2*time.Second)) {
+		t.Fatal("idle with requests in flight")
+	}
+	tr.handle(&network.EventResponseReceived{RequestID: "1", Response: &network.Response{Status: 200, MimeType: "text/html"}}, now)
+	if _, id, what := tr.handle(&network.EventLoadingFinished{RequestID: "1"}, now); what != fetchBody || id != "1" {
+		t.Fatalf("expected a body fetch for request 1, got %q %d", id, what)
+	}
+	tr.handle(&network.EventLoadingFailed{RequestID: "2"}, now)
+	if !tr.idle(time.Second, now.Add(2*time.Second)) || tr.idle(time.Second, now.Add(500*time.Millisecond)) {
+		t.Fatal("idle should follow the quiet period")
+	}
+	if !tr.seen(0, isCheckoutRequest) || tr.seen(2, isCheckoutRequest) {
+		t.Fatal("seen should only look past the mark")
+	}
+
+	c := tr.capture(now, now)
+	if len(c.Exchanges) != 2 {
+		t.Fatalf("expected 2 exchanges, got %+v", c.Exchanges)
+	}
+	post, page := c.Exchanges[0], c.Exchanges[1]
+	if post.Method != "POST" || post.Status != 302 || post.Headers["Cookie"] != redact.Mask {
+		t.Errorf("redirect hop = %+v", post)
+	}
+	if FixtureKind(page.URL) != "shipping" || page.Status != 200 {
+		t.Errorf("final hop = %+v", page)
+	}
+}
+
+func TestNetTrackerPostData(t *testing.T) {
+	red, err := redact.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := newNetTracker(red)
+	form := "csrf_token=abc123&phone=9876543210&address1=Flat+12&otp=4321"
+	_, _, what := tr.handle(&network.EventRequestWillBeSent{
+		RequestID: "1",
+		Request: &network.Request{
+			Method:          "POST",
+			URL:             "https://www.bisleri.com/checkout-submit",
+			Headers:         network.Headers{"Content-Type": "application/x-www-form-urlencoded"},
+			HasPostData:     true,
+			PostDataEntries: []*network.PostDataEntry{{Bytes: base64.StdEncoding.EncodeToString([]byte(form))}},
+		},
+	}, time.Now())
+	if what != fetchNone {
+		t.Fatalf("inline post data should not need a fetch, got %d", what)
+	}
+	x, _, what := tr.handle(&network.EventRequestWillBeSent{
+		RequestID: "2",
+		Request:   &network.Request{Method: "POST", URL: "https://www.bisleri.com/big", HasPostData: true},
+	}, time.Now())
+	if what != fetchPostData || x == nil {
+		t.Fatalf("expected a post data fetch, got %d", what)
+	}
+
+	got := tr.capture(time.Now(), time.Now()).Exchanges[0].Form
+	want := map[string]string{"csrf_token": redact.Mask, "otp": redact.Mask, "phone": "[PHONE]", "address1": "Flat 12"}
+	for k, v := range want {
+		if len(got[k]) != 1 || got[k][0] != v {
+			t.Errorf("form[%s] = %v, want %s", k, got[k], v)
+		}
+	}
+}
+
+func TestSaveFixtures(t *testing.T) {
+	dir := t.TempDir()
+	files, err := SaveFixtures(dir, Capture{Exchanges: []Exchange{
+		{Method: "GET", URL: "https://www.bisleri.com/mycart", MimeType: "text/html", Body: "<html>old</html>"},
+		{Method: "GET", URL: "https://www.bisleri.com/mycart", MimeType: "text/html", Body: "<html>new</html>"},
+		{Method: "POST", URL: "https://www.bisleri.com/cart-submit", MimeType: "application/json", Body: "{}"},
+	}})
+	if err != nil {
+		t.Fatalf("SaveFixtures: %v", err)
+	}
+	if len(files) != 2 || filepath.Base(files[1]) != "cart.html" {
+		t.Fatalf("files = %v", files)
+	}
+	data, _ := os.ReadFile(files[1])
+	if string(data) != "<html>new</html>" {
+		t.Fatalf("cart.html = %q", data)
+	}
+}
@@ -0,0 +1,214 @@
+package debug
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/fileutil"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	fingerprintsFileName = "fingerprints.json"
+	// maxShapeTokens bounds a fingerprint's size on very large pages.
+	maxShapeTokens = 500
+)
+
+// Fingerprint identifies the structure of a page an extractor failed on. The
+// shape holds only tag names, class names, form field names and data-*
+// attribute names, with digits masked; never text or attribute values, so
+// it carries no personal data.
+type Fingerprint struct {
+	Hash      string    `json:"hash"`
+	Extractor string    `json:"extractor"`
+	Page      string    `json:"page,omitempty"`
+	Version   string    `json:"version"`
+	Shape     []string  `json:"shape"`
+	Count     int       `json:"count"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+	Submitted bool      `json:"submitted,omitempty"`
+}
+
+// NewFingerprint fingerprints html for a failure of extractor on page.
+func NewFingerprint(extractor, page, version, html string) Fingerprint {
+	shape := PageShape(html)
+	sum := sha256.Sum256([]byte(strings.Join(shape, "\n")))
+	now := time.Now().UTC()
+	return Fingerprint{
+		Hash:      hex.EncodeToString(sum[:8]),
+		Extractor: extractor,
+		Page:      page,
+		Version:   version,
+		Shape:     shape,
+		Count:     1,
+		FirstSeen: now,
+		LastSeen:  now,
+	}
+}
+
+// PageShape lists the distinct structural tokens of a page, sorted: "tag",
+// "tag.class", "tag[name=field]" and "[data-attr]", digits masked.
+func PageShape(html string) []string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	doc.Find("*").Each(func(_ int, s *goquery.Selection) {
+		node := s.Get(0)
+		tag := node.Data
+		seen[tag] = true
+		for _, a := range node.Attr {
+			switch {
+			case a.Key == "class":
+				for _, class := range strings.Fields(a.Val) {
+					seen[tag+"."+maskDigits(class)] = true
+				}
+			case a.Key == "name" && (tag == "input" || tag == "select" || tag == "textarea" || tag == "button" || tag == "form"):
+				seen[tag+"[name="+maskDigits(a.Val)+"]"] = true
+			case strings.HasPrefix(a.Key, "data-"):
+				seen["["+maskDigits(a.Key)+"]"] = true
+			}
+		}
+	})
+	shape := make([]string, 0, len(seen))
+	for token := range seen {
+		shape = append(shape, token)
+	}
+	sort.Strings(shape)
+	if len(shape) > maxShapeTokens {
+		shape = shape[:maxShapeTokens]
+	}
+	return shape
+}
+
+// maskDigits replaces each run of digits with one '#', so generated names
+// such as "item-42" and "item-7" compare equal.
+func maskDigits(s string) string {
+	var b strings.Builder
+	digits := false
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			if !digits {
+				b.WriteByte('#')
+			}
+			digits = true
+			continue
+		}
+		digits = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func fingerprintsPath() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fingerprintsFileName), nil
+}
+
+// LoadFingerprints returns the recorded fingerprints, most recent first.
+func LoadFingerprints() ([]Fingerprint, error) {
+	path, err := fingerprintsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var fps []Fingerprint
+	if err := json.Unmarshal(data, &fps); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	sort.SliceStable(fps, func(i, j int) bool { return fps[i].LastSeen.After(fps[j].LastSeen) })
+	return fps, nil
+}
+
+// SaveFingerprints replaces the recorded fingerprints; nil removes the file.
+func SaveFingerprints(fps []Fingerprint) error {
+	path, err := fingerprintsPath()
+	if err != nil {
+		return err
+	}
+	if len(fps) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(fps, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteAtomic(path, data, 0o600)
+}
+
+// RecordFingerprint adds fp to the local record. A fingerprint already seen
+// for the same extractor and release is counted again instead of repeated,
+// and needs submitting again only once its count has changed.
+func RecordFingerprint(fp Fingerprint) (Fingerprint, error) {
+	fps, err := LoadFingerprints()
+	if err != nil {
+		return fp, err
+	}
+	merged := false
+	for i := range fps {
+		old := &fps[i]
+		if old.Hash == fp.Hash && old.Extractor == fp.Extractor && old.Version == fp.Version {
+			old.Count++
+			old.LastSeen = fp.LastSeen
+			old.Submitted = false
+			fp, merged = *old, true
+			break
+		}
+	}
+	if !merged {
+		fps = append(fps, fp)
+	}
+	return fp, SaveFingerprints(fps)
+}
+
+// SubmitFingerprints posts fingerprints as a JSON array to endpoint.
+func SubmitFingerprints(ctx context.Context, client *http.Client, endpoint string, fps []Fingerprint) error {
+	if endpoint == "" {
+		return errors.New("no fingerprints.endpoint configured")
+	}
+	body, err := json.Marshal(fps)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("submit fingerprints: %s", resp.Status)
+	}
+	return nil
+}
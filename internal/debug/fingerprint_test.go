@@ -0,0 +1,75 @@
+package debug
+
+import (
+	"strings"
+	"testing"
+)
+
+const fingerprintPage = `<html><body>
+<div class="cart-item item-42" data-uuid="a1b2c3">
+  <span class="name">Ravi Kumar, 221B MG Road</span>
+  <input type="hidden" name="csrf_token" value="s3cret">
+  <input name="phone" value="9876543210">
+</div>
+</body></html>`
+
+func TestPageShapeHasNoContent(t *testing.T) {
+	shape := PageShape(fingerprintPage)
+	joined := strings.Join(shape, " ")
+	for _, private := range []string{"Ravi", "MG Road", "s3cret", "9876543210", "a1b2c3", "42"} {
+		if strings.Contains(joined, private) {
+			t.Errorf("shape contains %q: %v", private, shape)
+		}
+	}
+	for _, want := range []string{"div.cart-item", "div.item-#", "[data-uuid]", "input[name=csrf_token]", "span.name"} {
+		if !strings.Contains(" "+joined+" ", " "+want+" ") {
+			t.Errorf("shape lacks %q: %v", want, shape)
+		}
+	}
+}
+
+func TestFingerprintIgnoresValues(t *testing.T) {
+	other := strings.NewReplacer("Ravi Kumar", "Asha Rao", "s3cret", "other", "item-42", "item-7").Replace(fingerprintPage)
+	a := NewFingerprint("cart items", "cart", "dev", fingerprintPage)
+	b := NewFingerprint("cart items", "cart", "dev", other)
+	if a.Hash != b.Hash {
+		t.Errorf("hash changed with values: %s vs %s", a.Hash, b.Hash)
+	}
+	changed := NewFingerprint("cart items", "cart", "dev", strings.Replace(fingerprintPage, "cart-item", "basket-line", 1))
+	if changed.Hash == a.Hash {
+		t.Error("hash did not change with the markup")
+	}
+}
+
+func TestRecordFingerprintMerges(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	fp := NewFingerprint("cart items", "cart", "dev", fingerprintPage)
+	fp.Submitted = true
+	if _, err := RecordFingerprint(fp); err != nil {
+		t.Fatalf("RecordFingerprint: %v", err)
+	}
+	merged, err := RecordFingerprint(NewFingerprint("cart items", "cart", "dev", fingerprintPage))
+	if err != nil {
+		t.Fatalf("RecordFingerprint: %v", err)
+	}
+	if merged.Count != 2 || merged.Submitted {
+		t.Errorf("merged = count %d submitted %v, want 2 and false", merged.Count, merged.Submitted)
+	}
+	if _, err := RecordFingerprint(NewFingerprint("orders", "my-orders", "dev", fingerprintPage)); err != nil {
+		t.Fatalf("RecordFingerprint: %v", err)
+	}
+	fps, err := LoadFingerprints()
+	if err != nil {
+		t.Fatalf("LoadFingerprints: %v", err)
+	}
+	if len(fps) != 2 {
+		t.Fatalf("got %d fingerprints, want 2", len(fps))
+	}
+	if err := SaveFingerprints(nil); err != nil {
+		t.Fatalf("SaveFingerprints: %v", err)
+	}
+	if fps, _ := LoadFingerprints(); len(fps) != 0 {
+		t.Errorf("clear left %d fingerprints", len(fps))
+	}
+}
@@ -0,0 +1,149 @@
+package debug
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// SimulatedOrderID is the order ID the replay hands out when the fixtures
+// hold no recorded place-order response.
+const SimulatedOrderID = "SIMULATED-0001"
+
+// Replay serves a fixtures directory written by `debug order --fixtures` so
+// the order flow can run offline. Pages come from <kind>.html, other requests
+// from the recorded responses in capture.json by method and path, and the
+// rest get a stand-in that lets the flow continue: an empty JSON object, or
+// a redirect to the confirmation page for the wallet place-order call.
+type Replay struct {
+	pages     map[string]string
+	exchanges []Exchange
+
+	mu     sync.Mutex
+	served []Served
+}
+
+// Served is one request the replay answered and where the answer came from:
+// a fixture file name, "capture" or "stand-in".
+type Served struct {
+	Method, Path string
+	Status       int
+	Source       string
+}
+
+// LoadReplay reads the fixtures in dir. capture.json is optional.
+func LoadReplay(dir string) (*Replay, error) {
+	r := &Replay{pages: map[string]string{}}
+	for _, kind := range []string{"cart", "shipping", "payment", "checkout", "confirmation"} {
+		data, err := os.ReadFile(filepath.Join(dir, kind+".html"))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		r.pages[kind] = string(data)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, CaptureFileName))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		var c Capture
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("%s: %w", CaptureFileName, err)
+		}
+		r.exchanges = c.Exchanges
+	}
+	if len(r.pages) == 0 && len(r.exchanges) == 0 {
+		return nil, fmt.Errorf("no fixtures in %s (expected cart.html, shipping.html, payment.html or %s)", dir, CaptureFileName)
+	}
+	return r, nil
+}
+
+// Has reports whether there is a fixture page of the kind.
+func (r *Replay) Has(kind string) bool {
+	_, ok := r.pages[kind]
+	return ok
+}
+
+// Served returns the requests answered since the last call.
+func (r *Replay) Served() []Served {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	served := r.served
+	r.served = nil
+	return served
+}
+
+func (r *Replay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	status, source := r.respond(w, req)
+	r.mu.Lock()
+	r.served = append(r.served, Served{Method: req.Method, Path: req.URL.RequestURI(), Status: status, Source: source})
+	r.mu.Unlock()
+}
+
+func (r *Replay) respond(w http.ResponseWriter, req *http.Request) (int, string) {
+	kind := FixtureKind(req.URL.String())
+	if kind == "checkout" && !r.Has(kind) {
+		kind = ""
+	}
+	if strings.HasPrefix(strings.ToLower(req.URL.Path), "/orderplaced") {
+		kind = "confirmation"
+	}
+	if page, ok := r.pages[kind]; ok && req.Method == http.MethodGet {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(page))
+		return http.StatusOK, kind + ".html"
+	}
+	if x, ok := r.recorded(req); ok {
+		status := x.Status
+		if status == 0 || status >= 300 && status < 400 {
+			status = http.StatusOK
+		}
+		if x.MimeType != "" {
+			w.Header().Set("Content-Type", x.MimeType)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(x.Body))
+		return status, "capture"
+	}
+	switch {
+	case strings.Contains(req.URL.Path, "WalletPlaceOrder"):
+		http.Redirect(w, req, "/orderplaced?orderID="+SimulatedOrderID, http.StatusFound)
+		return http.StatusFound, "stand-in"
+	case kind == "confirmation":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<html><body>Order placed</body></html>"))
+		return http.StatusOK, "stand-in"
+	case kind != "":
+		http.Error(w, "no "+kind+" fixture", http.StatusNotFound)
+		return http.StatusNotFound, "missing"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte("{}"))
+	return http.StatusOK, "stand-in"
+}
+
+// recorded finds the last captured response with a body for the request's
+// method and path.
+func (r *Replay) recorded(req *http.Request) (Exchange, bool) {
+	for i := len(r.exchanges) - 1; i >= 0; i-- {
+		x := r.exchanges[i]
+		if x.Body == "" || !strings.EqualFold(x.Method, req.Method) {
+			continue
+		}
+		u, err := url.Parse(x.URL)
+		if err == nil && u.Path == req.URL.Path {
+			return x, true
+		}
+	}
+	return Exchange{}, false
+}
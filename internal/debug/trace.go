@@ -1,44 +1,73 @@
 package debug
 
 import (
-	"bufio"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
-	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"bislericli/internal/redact"
 	"bislericli/internal/store"
 
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
 )
 
-func RunOrderDebug(ctx context.Context, profile store.Profile) error {
+const (
+	bisleriBase = "https://www.bisleri.com"
+	// pageQuiet is how long the network must stay idle for a page to count
+	// as settled; checkoutQuiet is longer because checkout chains redirects
+	// and XHRs.
+	pageQuiet     = 1500 * time.Millisecond
+	checkoutQuiet = 3 * time.Second
+	settleTimeout = 45 * time.Second
+	loginTimeout  = 5 * time.Minute
+)
+
+// OrderDebugOptions configures RunOrderDebug.
+type OrderDebugOptions struct {
+	// FixturesDir receives capture.json and the captured pages as fixtures;
+	// when empty the capture is saved as a debug artifact.
+	FixturesDir string
+}
+
+// RunOrderDebug opens a visible Chrome with the profile's cookies, walks the
+// cart to checkout while recording the requests it makes, and exports them.
+// Each step waits for the network to go quiet rather than for a fixed time.
+func RunOrderDebug(ctx context.Context, profile store.Profile, red *redact.Redactor, opts OrderDebugOptions) error {
 	// Setup chrome options for visible window
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+	allocOpts := append(chromedp.DefaultExecAllocatorOptions[:],
 		chromedp.Flag("headless", false),
 		chromedp.Flag("disable-gpu", false),
 		chromedp.Flag("enable-automation", false),
 	)
 
-	allocCtx, cancel := chromedp.NewExecAllocator(ctx, opts...)
+	allocCtx, cancel := chromedp.NewExecAllocator(ctx, allocOpts...)
 	defer cancel()
 
 	// create context
 	ctx, cancel = chromedp.NewContext(allocCtx)
 	defer cancel()
 
+	tracker := newNetTracker(red)
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		if x, id, what := tracker.handle(ev, time.Now()); what != fetchNone {
+			tracker.fetch(ctx, x, id, what)
+		}
+	})
 	if err := chromedp.Run(ctx, network.Enable()); err != nil {
 		return err
 	}
 
 	fmt.Println("Opening visible Chrome window...")
-	
-	// Set cookies if we have them
 	if len(profile.Cookies) > 0 {
 		fmt.Println("Restoring session cookies...")
 		if err := setCookies(ctx, profile.Cookies); err != nil {
@@ -46,81 +75,422 @@ func RunOrderDebug(ctx context.Context, profile store.Profile) error {
 		}
 	}
 
-	// Navigate to home and check login status
-	fmt.Println("Navigating to home page to check login status...")
-	var currentURL string
+	started := time.Now()
+	fmt.Println("Checking login status...")
+	if err := chromedp.Run(ctx, chromedp.Navigate(bisleriBase+"/my-orders")); err != nil {
+		return err
+	}
+	if err := tracker.waitIdle(ctx, pageQuiet, settleTimeout); err != nil {
+		return err
+	}
+	if err := waitForLogin(ctx); err != nil {
+		return err
+	}
+
+	fmt.Println("Navigating to cart and listening for checkout requests...")
+	tracker.setPrint(true)
+	if err := chromedp.Run(ctx, chromedp.Navigate(bisleriBase+"/mycart")); err != nil {
+		return err
+	}
+	if err := tracker.waitIdle(ctx, pageQuiet, settleTimeout); err != nil {
+		return err
+	}
+	fmt.Println("Searching for Checkout button...")
+	mark := tracker.count()
 	if err := chromedp.Run(ctx,
-		chromedp.Navigate("https://www.bisleri.com/home"),
-		chromedp.WaitVisible("body", chromedp.ByQuery),
-		chromedp.Location(&currentURL),
+		chromedp.Click(`//a[contains(text(), "Checkout")] | //button[contains(text(), "Checkout")]`, chromedp.BySearch),
 	); err != nil {
 		return err
 	}
+	fmt.Println("Clicked Checkout. Waiting for the checkout requests...")
+	if err := tracker.waitRequest(ctx, mark, isCheckoutRequest, settleTimeout); err != nil {
+		fmt.Println("Warning:", err)
+	}
+	if err := tracker.waitIdle(ctx, checkoutQuiet, settleTimeout); err != nil {
+		fmt.Println("Warning:", err)
+	}
 
-	// Interactive login verification
-	fmt.Println("Please verify in the Chrome window: Are you logged in?")
-	fmt.Println("If not, please LOG IN MANUALLY now.")
-	fmt.Println("Press ENTER in this terminal once you are fully logged in and ready to proceed...")
-	reader := bufio.NewReader(os.Stdin)
-	_, _ = reader.ReadString('\n')
-
-	// Refresh cookies from browser to ensure we have the latest session
-	fmt.Println("Capturing latest session cookies...")
-	_, err := network.GetCookies().Do(ctx)
+	capture := tracker.capture(started, time.Now())
+	fmt.Printf("\nCaptured %d request(s).\n", len(capture.Exchanges))
+	if opts.FixturesDir != "" {
+		files, err := SaveFixtures(opts.FixturesDir, capture)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			fmt.Println("Wrote", f)
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(capture, "", "  ")
+	if err != nil {
+		return err
+	}
+	path, err := SaveArtifact("checkout-"+CaptureFileName, data)
 	if err != nil {
-		return fmt.Errorf("failed to capture cookies: %w", err)
+		return err
 	}
-	// We could save these back to profile if we wanted, but for debug we just use them in-session
+	fmt.Println("Saved capture:", path)
+	return nil
+}
 
-	fmt.Println("Navigating to cart and listening for checkout requests...")
+// waitForLogin checks the page the flow landed on; the site sends signed-out
+// visitors of /my-orders to its login page. When signed out it waits for the
+// user to log in in the window instead of asking them to press Enter.
+func waitForLogin(ctx context.Context) error {
+	onLogin := func() (bool, error) {
+		var location string
+		if err := chromedp.Run(ctx, chromedp.Location(&location)); err != nil {
+			return false, err
+		}
+		return strings.Contains(strings.ToLower(location), "login"), nil
+	}
+	signedOut, err := onLogin()
+	if err != nil || !signedOut {
+		return err
+	}
+	fmt.Printf("Not logged in. Log in in the Chrome window; waiting up to %s...\n", loginTimeout)
+	deadline := time.Now().Add(loginTimeout)
+	for signedOut {
+		if time.Now().After(deadline) {
+			return errors.New("timed out waiting for login")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+		if signedOut, err = onLogin(); err != nil {
+			return err
+		}
+	}
+	fmt.Println("✓ Logged in")
+	return nil
+}
 
-	// Listen for network events
-	chromedp.ListenTarget(ctx, func(ev interface{}) {
-		switch e := ev.(type) {
-		case *network.EventRequestWillBeSent:
-			if strings.EqualFold(e.Request.Method, "POST") || strings.Contains(e.Request.URL, "checkout") {
-				fmt.Printf("\n[Request] %s %s\n", e.Request.Method, e.Request.URL)
-				fmt.Printf("  Type: %s\n", e.Type)
-				if len(e.Request.Headers) > 0 {
-					fmt.Println("  Headers:")
-					for k, v := range e.Request.Headers {
-						val := fmt.Sprint(v)
-						if strings.EqualFold(k, "Cookie") || strings.EqualFold(k, "Authorization") {
-							val = "[REDACTED]"
-						}
-						fmt.Printf("    %s: %s\n", k, val)
-					}
-				}
-				if e.Request.HasPostData {
-					// Using println to avoid build errors with undefined fields in some versions
-					fmt.Println("  PostData: (present)")
-					// fmt.Printf("  PostData: %s\n", e.Request.PostData) 
+// isCheckoutRequest matches the requests a checkout click starts: a form
+// post or a checkout page load.
+func isCheckoutRequest(e Exchange) bool {
+	if strings.EqualFold(e.Method, "POST") {
+		return true
+	}
+	kind := FixtureKind(e.URL)
+	return kind != "" && kind != "cart"
+}
+
+// netTracker follows the page's requests: which are still in flight, when
+// the network was last active, and the exchanges worth recording.
+type netTracker struct {
+	red *redact.Redactor
+
+	mu        sync.Mutex
+	print     bool
+	inflight  map[network.RequestID]bool
+	last      time.Time
+	exchanges map[network.RequestID]*Exchange
+	order     []network.RequestID
+	bodies    sync.WaitGroup
+}
+
+func newNetTracker(red *redact.Redactor) *netTracker {
+	return &netTracker{
+		red:       red,
+		inflight:  map[network.RequestID]bool{},
+		exchanges: map[network.RequestID]*Exchange{},
+	}
+}
+
+func (t *netTracker) setPrint(on bool) {
+	t.mu.Lock()
+	t.print = on
+	t.mu.Unlock()
+}
+
+// fetchKind is a CDP read handle asks for after an event; the listener
+// cannot make the call itself.
+type fetchKind int
+
+const (
+	fetchNone fetchKind = iota
+	fetchPostData
+	fetchBody
+)
+
+// handle records a network event. It reports the exchange and request whose
+// POST data or response body should now be fetched, if any.
+func (t *netTracker) handle(ev interface{}, now time.Time) (*Exchange, network.RequestID, fetchKind) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch e := ev.(type) {
+	case *network.EventRequestWillBeSent:
+		t.inflight[e.RequestID] = true
+		t.last = now
+		if !interesting(e.Request.Method, e.Request.URL) {
+			return nil, "", fetchNone
+		}
+		x := &Exchange{
+			Method:      e.Request.Method,
+			URL:         t.red.String(e.Request.URL),
+			Type:        string(e.Type),
+			Headers:     map[string]string{},
+			HasPostData: e.Request.HasPostData,
+		}
+		for k, v := range e.Request.Headers {
+			val := t.red.String(fmt.Sprint(v))
+			if strings.EqualFold(k, "Cookie") || strings.EqualFold(k, "Authorization") {
+				val = "[REDACTED]"
+			}
+			x.Headers[k] = val
+		}
+		// A redirect reuses the request ID; move the earlier hop aside so
+		// later events for the ID land on the new one.
+		if prev, ok := t.exchanges[e.RequestID]; ok {
+			hop := e.RequestID + network.RequestID(fmt.Sprintf("#%d", len(t.order)))
+			t.exchanges[hop] = prev
+			for i, id := range t.order {
+				if id == e.RequestID {
+					t.order[i] = hop
 				}
 			}
-		case *network.EventResponseReceived:
-			if strings.Contains(e.Response.URL, "checkout") || e.Response.Status >= 400 {
-				fmt.Printf("\n[Response] (%d) %s\n", e.Response.Status, e.Response.URL)
-				fmt.Printf("  MimeType: %s\n", e.Response.MimeType)
+			if e.RedirectResponse != nil {
+				prev.Status = int(e.RedirectResponse.Status)
 			}
 		}
-	})
+		t.exchanges[e.RequestID] = x
+		t.order = append(t.order, e.RequestID)
+		if t.print {
+			printRequest(x)
+		}
+		if x.HasPostData {
+			// Chrome inlines small bodies; larger ones need a CDP call.
+			if raw, ok := inlinePostData(e.Request.PostDataEntries); ok {
+				t.setPostData(x, raw)
+			} else {
+				return x, e.RequestID, fetchPostData
+			}
+		}
+	case *network.EventResponseReceived:
+		t.last = now
+		if x, ok := t.exchanges[e.RequestID]; ok {
+			x.Status = int(e.Response.Status)
+			x.MimeType = e.Response.MimeType
+			if t.print {
+				fmt.Printf("\n[Response] (%d) %s\n", x.Status, x.URL)
+				fmt.Printf("  MimeType: %s\n", x.MimeType)
+			}
+		}
+	case *network.EventLoadingFinished:
+		delete(t.inflight, e.RequestID)
+		t.last = now
+		if x, ok := t.exchanges[e.RequestID]; ok && wantBody(x.MimeType) {
+			return x, e.RequestID, fetchBody
+		}
+	case *network.EventLoadingFailed:
+		delete(t.inflight, e.RequestID)
+		t.last = now
+	}
+	return nil, "", fetchNone
+}
 
-	return chromedp.Run(ctx,
-		chromedp.Navigate("https://www.bisleri.com/mycart"),
-		chromedp.WaitVisible("body", chromedp.ByQuery),
-		chromedp.Sleep(2*time.Second), // Wait for cart to settle
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			fmt.Println("Searching for Checkout button...")
-			return nil
-		}),
-		// Attempt to click the checkout button
-		chromedp.Click(`//a[contains(text(), "Checkout")] | //button[contains(text(), "Checkout")]`, chromedp.BySearch),
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			fmt.Println("Clicked Checkout. Waiting for network activity...")
-			return nil
-		}),
-		chromedp.Sleep(15*time.Second), // Wait long enough to capture the request
-	)
+func printRequest(x *Exchange) {
+	fmt.Printf("\n[Request] %s %s\n", x.Method, x.URL)
+	fmt.Printf("  Type: %s\n", x.Type)
+	if len(x.Headers) > 0 {
+		fmt.Println("  Headers:")
+		for k, v := range x.Headers {
+			fmt.Printf("    %s: %s\n", k, v)
+		}
+	}
+	if x.HasPostData && x.PostData != "" {
+		printPostData(x)
+	}
+}
+
+func printPostData(x *Exchange) {
+	if len(x.Form) == 0 {
+		fmt.Printf("  PostData: %s\n", x.PostData)
+		return
+	}
+	fmt.Println("  PostData:")
+	keys := make([]string, 0, len(x.Form))
+	for k := range x.Form {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range x.Form[k] {
+			fmt.Printf("    %s = %s\n", k, v)
+		}
+	}
+}
+
+func wantBody(mime string) bool {
+	return strings.Contains(mime, "html") || strings.Contains(mime, "json")
+}
+
+// fetch reads a request's POST data or a finished response's body in the
+// background; the event listener must not block on CDP calls.
+func (t *netTracker) fetch(ctx context.Context, x *Exchange, id network.RequestID, what fetchKind) {
+	t.bodies.Add(1)
+	go func() {
+		defer t.bodies.Done()
+		var data string
+		err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+			if what == fetchPostData {
+				var err error
+				data, err = network.GetRequestPostData(id).Do(ctx)
+				return err
+			}
+			body, err := network.GetResponseBody(id).Do(ctx)
+			data = string(body)
+			return err
+		}))
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		switch {
+		case err != nil && what == fetchPostData:
+			x.PostData = "(unavailable: " + err.Error() + ")"
+		case err != nil:
+		case what == fetchPostData:
+			t.setPostData(x, data)
+			if t.print {
+				fmt.Printf("\n[PostData] %s %s\n", x.Method, x.URL)
+				printPostData(x)
+			}
+		default:
+			x.Body = t.red.String(data)
+		}
+	}()
+}
+
+// setPostData stores a redacted copy of a request body. Form bodies are
+// decoded first so values like the address match the redactor's literals,
+// and fields that hold secrets are masked whole.
+func (t *netTracker) setPostData(x *Exchange, raw string) {
+	if !isFormPost(x.Headers) {
+		x.PostData = t.red.String(raw)
+		return
+	}
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		x.PostData = t.red.String(raw)
+		return
+	}
+	x.Form = map[string][]string{}
+	for k, vs := range values {
+		for _, v := range vs {
+			if secretField(k) {
+				v = redact.Mask
+			} else {
+				v = t.red.String(v)
+			}
+			x.Form[k] = append(x.Form[k], v)
+		}
+	}
+	x.PostData = url.Values(x.Form).Encode()
+}
+
+func inlinePostData(entries []*network.PostDataEntry) (string, bool) {
+	if len(entries) == 0 {
+		return "", false
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		if e == nil {
+			return "", false
+		}
+		data, err := base64.StdEncoding.DecodeString(e.Bytes)
+		if err != nil {
+			return "", false
+		}
+		b.Write(data)
+	}
+	return b.String(), true
+}
+
+func isFormPost(headers map[string]string) bool {
+	for k, v := range headers {
+		if strings.EqualFold(k, "Content-Type") {
+			return strings.Contains(strings.ToLower(v), "application/x-www-form-urlencoded")
+		}
+	}
+	return false
+}
+
+// secretField reports form fields masked regardless of value.
+func secretField(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range []string{"password", "otp", "token", "cvv", "card"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *netTracker) idle(quiet time.Duration, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.inflight) == 0 && now.Sub(t.last) >= quiet
+}
+
+func (t *netTracker) count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.order)
+}
+
+// seen reports whether a request recorded after the first mark ones matches.
+func (t *netTracker) seen(mark int, match func(Exchange) bool) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, id := range t.order[mark:] {
+		if match(*t.exchanges[id]) {
+			return true
+		}
+	}
+	return false
+}
+
+// waitIdle returns once no request has been in flight for quiet.
+func (t *netTracker) waitIdle(ctx context.Context, quiet, timeout time.Duration) error {
+	return poll(ctx, timeout, func() bool { return t.idle(quiet, time.Now()) }, "network to go idle")
+}
+
+// waitRequest returns once a request after mark matches.
+func (t *netTracker) waitRequest(ctx context.Context, mark int, match func(Exchange) bool, timeout time.Duration) error {
+	return poll(ctx, timeout, func() bool { return t.seen(mark, match) }, "checkout request")
+}
+
+func poll(ctx context.Context, timeout time.Duration, done func() bool, what string) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	tick := time.NewTicker(100 * time.Millisecond)
+	defer tick.Stop()
+	for !done() {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("timed out after %s waiting for %s", timeout, what)
+			}
+			return ctx.Err()
+		case <-tick.C:
+		}
+	}
+	return nil
+}
+
+// capture waits for pending body reads and returns the recorded exchanges
+// in request order.
+func (t *netTracker) capture(started, finished time.Time) Capture {
+	t.bodies.Wait()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := Capture{Started: started, Finished: finished}
+	for _, id := range t.order {
+		c.Exchanges = append(c.Exchanges, *t.exchanges[id])
+	}
+	return c
 }
 
 func setCookies(ctx context.Context, cookies []store.Cookie) error {
@@ -134,7 +504,7 @@ func setCookies(ctx context.Context, cookies []store.Cookie) error {
 
 			// Bypass explicit expiration setting to avoid type issues and treat as session cookies
 			// if c.Expires != 0 { ... }
-			
+
 			if err := builder.Do(ctx); err != nil {
 				return err
 			}
@@ -0,0 +1,131 @@
+// Package fileutil provides the locking and atomic-write primitives shared by
+// the config and store packages.
+package fileutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LockTimeout bounds how long Lock waits for another bislericli process.
+var LockTimeout = 10 * time.Second
+
+var ErrLockTimeout = errors.New("timed out waiting for file lock")
+
+// WriteAtomic writes data to a temp file in the same directory and renames it
+// over path, so readers never observe a partially written file.
+func WriteAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	cleanup := func() { _ = os.Remove(tmpPath) }
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		cleanup()
+		return err
+	}
+	return nil
+}
+
+// Lock takes an advisory exclusive lock on path (via path+".lock") and returns
+// a function that releases it. Locks only coordinate bislericli processes.
+func Lock(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(LockTimeout)
+	for {
+		unlock, err := tryLock(lockPath)
+		if err == nil {
+			return unlock, nil
+		}
+		if !errors.Is(err, errLocked) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w: %s", ErrLockTimeout, lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+var errLocked = errors.New("locked")
+
+// BackupSuffix names the copy of the previous version kept by WriteWithBackup.
+const BackupSuffix = ".bak"
+
+// WriteWithBackup saves the current contents of path to path+".bak" before
+// atomically replacing it with data.
+func WriteWithBackup(path string, data []byte, perm os.FileMode) error {
+	if prev, err := os.ReadFile(path); err == nil && len(prev) > 0 {
+		if err := WriteAtomic(path+BackupSuffix, prev, perm); err != nil {
+			return err
+		}
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return WriteAtomic(path, data, perm)
+}
+
+type noRecoveryError struct{ err error }
+
+func (e *noRecoveryError) Error() string { return e.err.Error() }
+func (e *noRecoveryError) Unwrap() error { return e.err }
+
+// NoRecovery marks a decode error as not caused by corruption (for example a
+// file from a newer release), so ReadWithRecovery returns it without falling
+// back to the backup.
+func NoRecovery(err error) error {
+	return &noRecoveryError{err: err}
+}
+
+// ReadWithRecovery reads path and passes it to decode. If decoding fails and
+// the backup decodes cleanly, the backup is restored over path and a warning
+// is printed. Read errors (including a missing file) are returned unchanged.
+func ReadWithRecovery(path string, perm os.FileMode, decode func([]byte) error) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	decodeErr := decode(data)
+	if decodeErr == nil {
+		return nil
+	}
+	var keep *noRecoveryError
+	if errors.As(decodeErr, &keep) {
+		return keep.err
+	}
+	backup, err := os.ReadFile(path + BackupSuffix)
+	if err != nil {
+		return decodeErr
+	}
+	if err := decode(backup); err != nil {
+		return decodeErr
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %s is corrupt (%v); recovered the previous version from %s\n", path, decodeErr, path+BackupSuffix)
+	if err := WriteAtomic(path, backup, perm); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to restore %s: %v\n", path, err)
+	}
+	return nil
+}
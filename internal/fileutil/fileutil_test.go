@@ -0,0 +1,157 @@
+package fileutil
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWriteAtomicReplacesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profile.json")
+	if err := WriteAtomic(path, []byte("one"), 0o600); err != nil {
+		t.Fatalf("WriteAtomic: %v", err)
+	}
+	if err := WriteAtomic(path, []byte("two"), 0o600); err != nil {
+		t.Fatalf("WriteAtomic: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "two" {
+		t.Fatalf("unexpected content %q (%v)", data, err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Fatalf("temp files left behind: %v", entries)
+	}
+}
+
+func TestLockIsExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	unlock, err := Lock(path)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	old := LockTimeout
+	LockTimeout = 100 * time.Millisecond
+	defer func() { LockTimeout = old }()
+	if _, err := Lock(path); !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("expected lock timeout, got %v", err)
+	}
+	unlock()
+	unlock2, err := Lock(path)
+	if err != nil {
+		t.Fatalf("Lock after unlock: %v", err)
+	}
+	unlock2()
+}
+
+func TestReadWithRecoveryRestoresBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := WriteWithBackup(path, []byte(`{"v":1}`), 0o600); err != nil {
+		t.Fatalf("WriteWithBackup: %v", err)
+	}
+	if err := WriteWithBackup(path, []byte(`{"v":2}`), 0o600); err != nil {
+		t.Fatalf("WriteWithBackup: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{"v":`), 0o600); err != nil {
+		t.Fatalf("corrupt: %v", err)
+	}
+	var got map[string]int
+	err := ReadWithRecovery(path, 0o600, func(data []byte) error {
+		got = nil
+		return json.Unmarshal(data, &got)
+	})
+	if err != nil {
+		t.Fatalf("ReadWithRecovery: %v", err)
+	}
+	if got["v"] != 1 {
+		t.Fatalf("expected backup version 1, got %v", got)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != `{"v":1}` {
+		t.Fatalf("primary not restored: %q", data)
+	}
+}
+
+func TestAcquireInstanceIsExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "order_default.lock")
+	release, err := AcquireInstance(path, "bislericli order", 0)
+	if err != nil {
+		t.Fatalf("AcquireInstance: %v", err)
+	}
+	_, err = AcquireInstance(path, "bislericli schedule run", 300*time.Millisecond)
+	var held *HeldError
+	if !errors.As(err, &held) {
+		t.Fatalf("second AcquireInstance = %v, want *HeldError", err)
+	}
+	if held.Holder.PID != os.Getpid() || held.Holder.Command != "bislericli order" {
+		t.Errorf("holder = %+v", held.Holder)
+	}
+	release()
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("lock file left after release: %v", err)
+	}
+}
+
+func TestAcquireInstanceTakesOverStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "order_default.lock")
+	host, _ := os.Hostname()
+	for _, dead := range []LockHolder{
+		{PID: 1 << 30, Host: host, Started: time.Now()},
+		{PID: os.Getpid(), Host: "elsewhere", Started: time.Now().Add(-24 * time.Hour)},
+	} {
+		data, _ := json.Marshal(dead)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		release, err := AcquireInstance(path, "bislericli order", 0)
+		if err != nil {
+			t.Fatalf("AcquireInstance over %+v: %v", dead, err)
+		}
+		release()
+	}
+}
+
+func TestAcquireInstanceStaleTakeoverHasOneWinner(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "order_default.lock")
+	host, _ := os.Hostname()
+	for round := 0; round < 200; round++ {
+		data, _ := json.Marshal(LockHolder{PID: 1 << 30, Host: host, Started: time.Now()})
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			releases []func()
+			start    = make(chan struct{})
+		)
+		for i := 0; i < 32; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				release, err := AcquireInstance(path, "bislericli order", 0)
+				var held *HeldError
+				if err != nil && !errors.As(err, &held) {
+					t.Errorf("AcquireInstance: %v", err)
+					return
+				}
+				if err == nil {
+					mu.Lock()
+					releases = append(releases, release)
+					mu.Unlock()
+				}
+			}()
+		}
+		close(start)
+		wg.Wait()
+		if len(releases) != 1 {
+			t.Fatalf("round %d: %d contenders took the lock, want 1", round, len(releases))
+		}
+		releases[0]()
+	}
+}
@@ -0,0 +1,134 @@
+package fileutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// foreignLockAge is when a lock held from another host (a synced data
+// directory) is treated as stale, since its process cannot be checked.
+const foreignLockAge = 12 * time.Hour
+
+// LockHolder describes the process holding an instance lock.
+type LockHolder struct {
+	PID     int       `json:"pid"`
+	Host    string    `json:"host"`
+	Command string    `json:"command,omitempty"`
+	Started time.Time `json:"started"`
+}
+
+// HeldError reports an instance lock held by another live process.
+type HeldError struct {
+	Path   string
+	Holder LockHolder
+}
+
+func (e *HeldError) Error() string {
+	h := e.Holder
+	what := "another bislericli process"
+	if h.Command != "" {
+		what = fmt.Sprintf("'%s'", h.Command)
+	}
+	return fmt.Sprintf("%s (pid %d) has been running since %s", what, h.PID, h.Started.Local().Format("15:04:05"))
+}
+
+// AcquireInstance takes the instance lock at path, waiting up to wait for a
+// live holder to finish. The lock file records this process, so a holder
+// that died without releasing it (crash, power loss) is detected and the
+// lock taken over. A lock still held after wait fails with *HeldError.
+func AcquireInstance(path, command string, wait time.Duration) (func(), error) {
+	host, _ := os.Hostname()
+	me := LockHolder{PID: os.Getpid(), Host: host, Command: command, Started: time.Now()}
+	data, err := json.Marshal(me)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(wait)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_, err = f.Write(data)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				_ = os.Remove(path)
+				return nil, err
+			}
+			return func() { releaseInstance(path, me) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		holder, readErr := readLockHolder(path)
+		if reclaimable(path, holder, readErr, host) {
+			if err := reclaimStale(path, host); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, &HeldError{Path: path, Holder: holder}
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
+func reclaimable(path string, h LockHolder, readErr error, host string) bool {
+	if readErr != nil {
+		return !errors.Is(readErr, os.ErrNotExist) && lockFileOld(path)
+	}
+	return stale(h, host)
+}
+
+// reclaimStale removes a stale lock file. Contenders take turns under
+// Lock and check the file again first, so one that saw the stale holder
+// cannot remove a lock another contender has taken since.
+func reclaimStale(path, host string) error {
+	unlock, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	holder, readErr := readLockHolder(path)
+	if errors.Is(readErr, os.ErrNotExist) || !reclaimable(path, holder, readErr, host) {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func readLockHolder(path string) (LockHolder, error) {
+	var h LockHolder
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return h, err
+	}
+	return h, json.Unmarshal(data, &h)
+}
+
+func stale(h LockHolder, host string) bool {
+	if h.Host != host {
+		return time.Since(h.Started) > foreignLockAge
+	}
+	return h.PID <= 0 || !ProcessAlive(h.PID)
+}
+
+// lockFileOld covers a lock file that cannot be decoded: one being written
+// right now is left alone, one left half-written is reclaimed.
+func lockFileOld(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && time.Since(info.ModTime()) > 10*time.Second
+}
+
+// releaseInstance removes the lock file if it still records this process.
+func releaseInstance(path string, me LockHolder) {
+	if h, err := readLockHolder(path); err == nil && h.PID == me.PID && h.Started.Equal(me.Started) {
+		_ = os.Remove(path)
+	}
+}
@@ -0,0 +1,38 @@
+//go:build windows
+
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// staleLockAge lets a lock file left behind by a crashed process be reclaimed.
+const staleLockAge = 2 * time.Minute
+
+func tryLock(lockPath string) (func(), error) {
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+				_ = os.Remove(lockPath)
+			}
+			return nil, errLocked
+		}
+		return nil, err
+	}
+	f.Close()
+	return func() { _ = os.Remove(lockPath) }, nil
+}
+
+// ProcessAlive reports whether a process with the PID exists; FindProcess
+// opens a handle to it on Windows and fails when there is none.
+func ProcessAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
@@ -13,5 +13,5 @@ func Timestamp(t time.Time) string {
 }
 
 func KeyValue(key, value string) string {
-	return fmt.Sprintf("%s: %s", key, value)
+	return fmt.Sprintf("%s: %s", key, Currency(value))
 }
@@ -0,0 +1,147 @@
+package format
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Capabilities describes what the attached terminal can render.
+type Capabilities struct {
+	// Unicode is false on terminals (legacy Windows conhost, C/POSIX locales)
+	// that mangle box-drawing characters and the rupee sign.
+	Unicode bool
+	// ANSI is false when escape sequences would print as garbage or the user
+	// opted out via NO_COLOR.
+	ANSI bool
+}
+
+var (
+	capsOnce sync.Once
+	caps     Capabilities
+)
+
+// Terminal returns the detected capabilities. BISLERICLI_ASCII=1 forces the
+// ASCII fallback.
+func Terminal() Capabilities {
+	capsOnce.Do(func() {
+		caps = DetectCapabilities(runtime.GOOS, os.Getenv)
+	})
+	return caps
+}
+
+// SetTerminal overrides detection, e.g. for tests.
+func SetTerminal(c Capabilities) {
+	capsOnce.Do(func() {})
+	caps = c
+}
+
+// DetectCapabilities inspects the environment for goos.
+func DetectCapabilities(goos string, getenv func(string) string) Capabilities {
+	c := Capabilities{Unicode: true, ANSI: true}
+	if goos == "windows" {
+		// Windows Terminal, VS Code and ConEmu handle UTF-8 and VT sequences;
+		// the classic console host does not by default.
+		modern := getenv("WT_SESSION") != "" ||
+			getenv("TERM_PROGRAM") == "vscode" ||
+			strings.EqualFold(getenv("ConEmuANSI"), "ON")
+		c.Unicode, c.ANSI = modern, modern
+	} else {
+		locale := getenv("LC_ALL")
+		if locale == "" {
+			locale = getenv("LC_CTYPE")
+		}
+		if locale == "" {
+			locale = getenv("LANG")
+		}
+		lower := strings.ToLower(locale)
+		if locale != "" && !strings.Contains(lower, "utf-8") && !strings.Contains(lower, "utf8") {
+			c.Unicode = false
+		}
+		if getenv("TERM") == "dumb" {
+			c.ANSI = false
+		}
+	}
+	if v := getenv("BISLERICLI_ASCII"); v != "" && v != "0" {
+		c.Unicode = false
+	}
+	if getenv("NO_COLOR") != "" {
+		c.ANSI = false
+	}
+	return c
+}
+
+// Rule returns a horizontal line of width n.
+func Rule(n int) string {
+	if Terminal().Unicode {
+		return strings.Repeat("─", n)
+	}
+	return strings.Repeat("-", n)
+}
+
+// Branch prefixes a nested detail line under a table row.
+func Branch() string {
+	if Terminal().Unicode {
+		return "└─"
+	}
+	return "`-"
+}
+
+// Check marks a completed step.
+func Check() string {
+	if Terminal().Unicode {
+		return "✓"
+	}
+	return "OK"
+}
+
+// CurrencySymbol is the rupee sign, or "Rs." where it cannot be rendered.
+func CurrencySymbol() string {
+	if Terminal().Unicode {
+		return "₹"
+	}
+	return "Rs."
+}
+
+// Currency rewrites rupee signs in s (e.g. totals scraped from the site) for
+// the current terminal.
+func Currency(s string) string {
+	if Terminal().Unicode {
+		return s
+	}
+	return strings.ReplaceAll(s, "₹", "Rs.")
+}
+
+// Highlight makes a notice stand out: bold yellow where ANSI is available,
+// "!!" markers otherwise.
+func Highlight(s string) string {
+	if Terminal().ANSI {
+		return "\x1b[1;33m" + s + "\x1b[0m"
+	}
+	return "!! " + s + " !!"
+}
+
+// Arrow separates an old and new value, e.g. a status change.
+func Arrow() string {
+	if Terminal().Unicode {
+		return "→"
+	}
+	return "->"
+}
+
+// ProgressBar renders done out of total as a bar width cells wide followed
+// by the count, e.g. "[#####-----] 3/6".
+func ProgressBar(done, total, width int) string {
+	if total < 1 {
+		total = 1
+	}
+	done = min(max(done, 0), total)
+	filled := done * width / total
+	full, empty := "#", "-"
+	if Terminal().Unicode {
+		full, empty = "█", "░"
+	}
+	return fmt.Sprintf("[%s%s] %d/%d", strings.Repeat(full, filled), strings.Repeat(empty, width-filled), done, total)
+}
@@ -0,0 +1,52 @@
+package format
+
+import "testing"
+
+func TestDetectCapabilities(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		env  map[string]string
+		want Capabilities
+	}{
+		{"linux utf8", "linux", map[string]string{"LANG": "en_IN.UTF-8"}, Capabilities{Unicode: true, ANSI: true}},
+		{"linux C locale", "linux", map[string]string{"LANG": "C"}, Capabilities{Unicode: false, ANSI: true}},
+		{"darwin unset locale", "darwin", nil, Capabilities{Unicode: true, ANSI: true}},
+		{"dumb terminal", "linux", map[string]string{"TERM": "dumb"}, Capabilities{Unicode: true, ANSI: false}},
+		{"conhost", "windows", nil, Capabilities{}},
+		{"windows terminal", "windows", map[string]string{"WT_SESSION": "abc"}, Capabilities{Unicode: true, ANSI: true}},
+		{"forced ascii", "linux", map[string]string{"BISLERICLI_ASCII": "1"}, Capabilities{Unicode: false, ANSI: true}},
+		{"no color", "windows", map[string]string{"WT_SESSION": "x", "NO_COLOR": "1"}, Capabilities{Unicode: true, ANSI: false}},
+	}
+	for _, tt := range tests {
+		got := DetectCapabilities(tt.goos, func(k string) string { return tt.env[k] })
+		if got != tt.want {
+			t.Fatalf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestASCIIFallback(t *testing.T) {
+	SetTerminal(Capabilities{})
+	defer SetTerminal(Capabilities{Unicode: true, ANSI: true})
+	if got := Currency("Total: ₹200"); got != "Total: Rs.200" {
+		t.Fatalf("Currency = %q", got)
+	}
+	if got := Rule(3); got != "---" {
+		t.Fatalf("Rule = %q", got)
+	}
+	if got := KeyValue("Wallet balance", "₹1,000"); got != "Wallet balance: Rs.1,000" {
+		t.Fatalf("KeyValue = %q", got)
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	SetTerminal(Capabilities{})
+	defer SetTerminal(Capabilities{Unicode: true, ANSI: true})
+	if got := ProgressBar(3, 6, 10); got != "[#####-----] 3/6" {
+		t.Fatalf("ProgressBar = %q", got)
+	}
+	if got := ProgressBar(0, 0, 4); got != "[----] 0/1" {
+		t.Fatalf("ProgressBar with no pages = %q", got)
+	}
+}
@@ -0,0 +1,142 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"bislericli/internal/store"
+)
+
+const (
+	DefaultEndpoint = "https://nominatim.openstreetmap.org/search"
+	userAgent       = "bislericli (+https://github.com/maheshrijal/bislericli)"
+)
+
+var ErrNoMatch = errors.New("no geocoding match for address")
+
+type Result struct {
+	Latitude    string
+	Longitude   string
+	DisplayName string
+	// City is the city, town or district the place belongs to, when known.
+	City string
+}
+
+// Client resolves addresses to coordinates using a Nominatim-compatible endpoint.
+type Client struct {
+	Endpoint string
+	Email    string
+	HTTP     *http.Client
+}
+
+func NewClient(endpoint, email string) *Client {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	return &Client{
+		Endpoint: endpoint,
+		Email:    email,
+		HTTP:     &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Lookup tries the full address first and falls back to the postal code and
+// city, which Nominatim resolves far more reliably for Indian addresses.
+func (c *Client) Lookup(ctx context.Context, addr store.Address) (Result, error) {
+	queries := []string{
+		joinNonEmpty(addr.Address1, addr.Address2, addr.City, addr.PostalCode),
+		joinNonEmpty(addr.PostalCode, addr.City),
+	}
+	var lastErr error = ErrNoMatch
+	for _, q := range queries {
+		if q == "" {
+			continue
+		}
+		result, err := c.search(ctx, q)
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+		if !errors.Is(err, ErrNoMatch) {
+			break
+		}
+	}
+	return Result{}, lastErr
+}
+
+// LookupPincode resolves a pincode to its place, mainly for the city name.
+func (c *Client) LookupPincode(ctx context.Context, pincode string) (Result, error) {
+	return c.search(ctx, pincode+", India")
+}
+
+func (c *Client) search(ctx context.Context, query string) (Result, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "json")
+	params.Set("limit", "1")
+	params.Set("countrycodes", "in")
+	params.Set("addressdetails", "1")
+	if c.Email != "" {
+		params.Set("email", c.Email)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", c.Endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return Result{}, fmt.Errorf("geocoding failed: %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Result{}, err
+	}
+	var places []struct {
+		Lat         string `json:"lat"`
+		Lon         string `json:"lon"`
+		DisplayName string `json:"display_name"`
+		Address     struct {
+			City          string `json:"city"`
+			Town          string `json:"town"`
+			StateDistrict string `json:"state_district"`
+			County        string `json:"county"`
+		} `json:"address"`
+	}
+	if err := json.Unmarshal(body, &places); err != nil {
+		return Result{}, fmt.Errorf("failed to parse geocoding response: %w", err)
+	}
+	if len(places) == 0 || places[0].Lat == "" || places[0].Lon == "" {
+		return Result{}, ErrNoMatch
+	}
+	place := places[0]
+	city := place.Address.City
+	for _, alt := range []string{place.Address.Town, place.Address.StateDistrict, place.Address.County} {
+		if city == "" {
+			city = alt
+		}
+	}
+	return Result{Latitude: place.Lat, Longitude: place.Lon, DisplayName: place.DisplayName, City: city}, nil
+}
+
+func joinNonEmpty(parts ...string) string {
+	var kept []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, ", ")
+}
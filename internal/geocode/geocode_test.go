@@ -0,0 +1,84 @@
+package geocode
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"bislericli/internal/store"
+)
+
+func TestLookup(t *testing.T) {
+	addr := store.Address{Address1: "12 MG Road", City: "Bengaluru", PostalCode: "560001"}
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter, q string)
+		want    Result
+		wantErr func(error) bool
+		queries []string
+	}{
+		{
+			name: "full address matches",
+			handler: func(w http.ResponseWriter, q string) {
+				w.Write([]byte(`[{"lat":"12.97","lon":"77.59","display_name":"MG Road, Bengaluru","address":{"city":"Bengaluru"}}]`))
+			},
+			want:    Result{Latitude: "12.97", Longitude: "77.59", DisplayName: "MG Road, Bengaluru", City: "Bengaluru"},
+			queries: []string{"12 MG Road, Bengaluru, 560001"},
+		},
+		{
+			name: "falls back to pincode and city",
+			handler: func(w http.ResponseWriter, q string) {
+				if strings.HasPrefix(q, "12 MG Road") {
+					w.Write([]byte(`[]`))
+					return
+				}
+				w.Write([]byte(`[{"lat":"12.98","lon":"77.60","display_name":"560001","address":{"state_district":"Bangalore Urban"}}]`))
+			},
+			want:    Result{Latitude: "12.98", Longitude: "77.60", DisplayName: "560001", City: "Bangalore Urban"},
+			queries: []string{"12 MG Road, Bengaluru, 560001", "560001, Bengaluru"},
+		},
+		{
+			name:    "no results",
+			handler: func(w http.ResponseWriter, q string) { w.Write([]byte(`[]`)) },
+			wantErr: func(err error) bool { return errors.Is(err, ErrNoMatch) },
+			queries: []string{"12 MG Road, Bengaluru, 560001", "560001, Bengaluru"},
+		},
+		{
+			name: "HTTP error stops the fallback",
+			handler: func(w http.ResponseWriter, q string) {
+				http.Error(w, "slow down", http.StatusTooManyRequests)
+			},
+			wantErr: func(err error) bool { return err != nil && strings.Contains(err.Error(), "429") },
+			queries: []string{"12 MG Road, Bengaluru, 560001"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queries []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.URL.Query().Get("email"); got != "me@example.com" {
+					t.Errorf("email = %q", got)
+				}
+				q := r.URL.Query().Get("q")
+				queries = append(queries, q)
+				tt.handler(w, q)
+			}))
+			defer srv.Close()
+
+			got, err := NewClient(srv.URL, "me@example.com").Lookup(context.Background(), addr)
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Fatalf("Lookup error = %v", err)
+				}
+			} else if err != nil || got != tt.want {
+				t.Fatalf("Lookup = %+v, %v; want %+v", got, err, tt.want)
+			}
+			if strings.Join(queries, " | ") != strings.Join(tt.queries, " | ") {
+				t.Errorf("queries = %q, want %q", queries, tt.queries)
+			}
+		})
+	}
+}
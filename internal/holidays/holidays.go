@@ -0,0 +1,204 @@
+// Package holidays knows the Indian public holidays on which deliveries
+// usually pause. A few fixed-date national and state holidays are bundled;
+// festivals whose dates move each year come from a calendar file fetched
+// into the data directory.
+package holidays
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/fileutil"
+)
+
+const DateLayout = "2006-01-02"
+
+// Holiday is one holiday. States lists the ISO 3166-2:IN state codes that
+// observe it; an empty list means it is observed across India.
+type Holiday struct {
+	Date   string   `json:"date"`
+	Name   string   `json:"name"`
+	States []string `json:"states,omitempty"`
+}
+
+// observedIn reports whether state (a two-letter code) observes h. With no
+// state only national holidays count.
+func (h Holiday) observedIn(state string) bool {
+	if len(h.States) == 0 {
+		return true
+	}
+	for _, s := range h.States {
+		if strings.EqualFold(s, state) {
+			return true
+		}
+	}
+	return false
+}
+
+// fixed is a holiday on the same day every year.
+type fixed struct {
+	Month  time.Month
+	Day    int
+	Name   string
+	States []string
+}
+
+// bundled are the holidays whose date never changes: the three national
+// holidays and state holidays such as formation days.
+var bundled = []fixed{
+	{time.January, 26, "Republic Day", nil},
+	{time.August, 15, "Independence Day", nil},
+	{time.October, 2, "Gandhi Jayanti", nil},
+	{time.January, 23, "Netaji Jayanti", []string{"WB"}},
+	{time.April, 1, "Utkal Divas", []string{"OD"}},
+	{time.May, 1, "Maharashtra Day", []string{"MH"}},
+	{time.May, 1, "Gujarat Day", []string{"GJ"}},
+	{time.June, 2, "Telangana Formation Day", []string{"TS"}},
+	{time.November, 1, "Karnataka Rajyotsava", []string{"KA"}},
+	{time.November, 1, "Kerala Piravi", []string{"KL"}},
+	{time.December, 19, "Goa Liberation Day", []string{"GA"}},
+}
+
+// Calendar answers which days are holidays in one state.
+type Calendar struct {
+	State   string
+	fetched []Holiday
+}
+
+// New returns the calendar for state, adding the fetched holidays to the
+// bundled ones.
+func New(state string, fetched []Holiday) Calendar {
+	return Calendar{State: strings.ToUpper(state), fetched: fetched}
+}
+
+// On returns the holiday on t's calendar day, if there is one.
+func (c Calendar) On(t time.Time) (Holiday, bool) {
+	date := t.Format(DateLayout)
+	for _, h := range c.fetched {
+		if h.Date == date && h.observedIn(c.State) {
+			return h, true
+		}
+	}
+	for _, f := range bundled {
+		h := Holiday{Date: date, Name: f.Name, States: f.States}
+		if t.Month() == f.Month && t.Day() == f.Day && h.observedIn(c.State) {
+			return h, true
+		}
+	}
+	return Holiday{}, false
+}
+
+// Between lists the holidays from from to to, inclusive, in date order.
+func (c Calendar) Between(from, to time.Time) []Holiday {
+	var out []Holiday
+	seen := map[string]bool{}
+	for _, h := range c.fetched {
+		if h.Date >= from.Format(DateLayout) && h.Date <= to.Format(DateLayout) && h.observedIn(c.State) {
+			out = append(out, h)
+			seen[h.Date] = true
+		}
+	}
+	for year := from.Year(); year <= to.Year(); year++ {
+		for _, f := range bundled {
+			day := time.Date(year, f.Month, f.Day, 0, 0, 0, 0, from.Location())
+			h := Holiday{Date: day.Format(DateLayout), Name: f.Name, States: f.States}
+			if seen[h.Date] || !h.observedIn(c.State) || h.Date < from.Format(DateLayout) || h.Date > to.Format(DateLayout) {
+				continue
+			}
+			out = append(out, h)
+			seen[h.Date] = true
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date < out[j].Date })
+	return out
+}
+
+// Path returns the fetched calendar file.
+func Path() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "holidays.json"), nil
+}
+
+// LoadFetched reads the fetched calendar; a missing file is an empty one.
+func LoadFetched() ([]Holiday, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	list, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return list, nil
+}
+
+// SaveFetched replaces the fetched calendar.
+func SaveFetched(list []Holiday) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteAtomic(path, data, 0o600)
+}
+
+// Fetch downloads a calendar: a JSON list of holidays in the Holiday form.
+func Fetch(ctx context.Context, url string) ([]Holiday, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("holiday calendar returned %s", resp.Status)
+	}
+	return parse(body)
+}
+
+func parse(data []byte) ([]Holiday, error) {
+	var list []Holiday
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("holiday calendar: %w", err)
+	}
+	for i, h := range list {
+		if _, err := time.Parse(DateLayout, h.Date); err != nil {
+			return nil, fmt.Errorf("holiday calendar: entry %d: invalid date %q (expected YYYY-MM-DD)", i+1, h.Date)
+		}
+		if strings.TrimSpace(h.Name) == "" {
+			return nil, fmt.Errorf("holiday calendar: entry %d (%s) has no name", i+1, h.Date)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Date < list[j].Date })
+	return list, nil
+}
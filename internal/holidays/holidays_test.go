@@ -0,0 +1,59 @@
+package holidays
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendar(t *testing.T) {
+	fetched, err := parse([]byte(`[
+		{"date":"2026-11-08","name":"Diwali"},
+		{"date":"2026-09-14","name":"Ganesh Chaturthi","states":["MH","KA"]}
+	]`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	day := func(s string) time.Time {
+		d, _ := time.Parse(DateLayout, s)
+		return d
+	}
+
+	mh := New("mh", fetched)
+	for date, want := range map[string]string{
+		"2026-01-26": "Republic Day",
+		"2026-05-01": "Maharashtra Day",
+		"2026-09-14": "Ganesh Chaturthi",
+		"2026-11-08": "Diwali",
+		"2026-11-01": "",
+	} {
+		h, ok := mh.On(day(date))
+		if ok != (want != "") || h.Name != want {
+			t.Errorf("MH On(%s) = %q, %v; want %q", date, h.Name, ok, want)
+		}
+	}
+	if _, ok := New("DL", fetched).On(day("2026-09-14")); ok {
+		t.Error("Ganesh Chaturthi counted for Delhi")
+	}
+	if _, ok := New("", nil).On(day("2026-05-01")); ok {
+		t.Error("state holiday counted without a state")
+	}
+
+	got := mh.Between(day("2026-08-01"), day("2027-01-31"))
+	var names []string
+	for _, h := range got {
+		names = append(names, h.Date+" "+h.Name)
+	}
+	want := []string{"2026-08-15 Independence Day", "2026-09-14 Ganesh Chaturthi", "2026-10-02 Gandhi Jayanti", "2026-11-08 Diwali", "2027-01-26 Republic Day"}
+	if len(names) != len(want) {
+		t.Fatalf("Between = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("Between[%d] = %q, want %q", i, names[i], want[i])
+		}
+	}
+
+	if _, err := parse([]byte(`[{"date":"08-11-2026","name":"Diwali"}]`)); err == nil {
+		t.Error("parse accepted a malformed date")
+	}
+}
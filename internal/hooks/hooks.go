@@ -0,0 +1,69 @@
+// Package hooks runs user commands on lifecycle events, passing the event as
+// JSON on stdin.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// Timeout bounds each hook command.
+const Timeout = 30 * time.Second
+
+// Event is the payload written to a hook's stdin.
+type Event struct {
+	Event   string    `json:"event"`
+	Time    time.Time `json:"time"`
+	Profile string    `json:"profile"`
+	Data    any       `json:"data,omitempty"`
+}
+
+// Output receives the hooks' stdout and stderr; tests replace it.
+var Output io.Writer = os.Stderr
+
+// Run executes commands through the system shell in order and stops at the
+// first one that fails. BISLERICLI_EVENT holds the event name.
+func Run(ctx context.Context, commands []string, ev Event) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	for _, command := range commands {
+		if err := runOne(ctx, command, ev.Event, payload); err != nil {
+			return fmt.Errorf("%s hook %q: %w", ev.Event, command, err)
+		}
+	}
+	return nil
+}
+
+func runOne(ctx context.Context, command, event string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	name, args := shell(runtime.GOOS, command)
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = Output
+	cmd.Stderr = Output
+	cmd.Env = append(os.Environ(), "BISLERICLI_EVENT="+event)
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timed out after %s", Timeout)
+		}
+		return err
+	}
+	return nil
+}
+
+func shell(goos, command string) (string, []string) {
+	if goos == "windows" {
+		return "cmd", []string{"/C", command}
+	}
+	return "sh", []string{"-c", command}
+}
@@ -0,0 +1,57 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunPassesEventOnStdin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	var out bytes.Buffer
+	Output = &out
+	defer func() { Output = os.Stderr }()
+
+	dest := filepath.Join(t.TempDir(), "event.json")
+	ev := Event{Event: "post-sync", Time: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC), Profile: "home", Data: map[string]int{"orders": 4}}
+	err := Run(context.Background(), []string{"cat > " + dest, "echo $BISLERICLI_EVENT"}, ev)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	data, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("read payload: %v", err)
+	}
+	var got Event
+	if err := json.Unmarshal(data, &got); err != nil || got.Event != "post-sync" || got.Profile != "home" {
+		t.Fatalf("payload = %s (%v)", data, err)
+	}
+	if strings.TrimSpace(out.String()) != "post-sync" {
+		t.Fatalf("hook output = %q", out.String())
+	}
+}
+
+func TestRunStopsAtFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	Output = &bytes.Buffer{}
+	defer func() { Output = os.Stderr }()
+
+	marker := filepath.Join(t.TempDir(), "ran")
+	err := Run(context.Background(), []string{"exit 3", "touch " + marker}, Event{Event: "pre-order"})
+	if err == nil || !strings.Contains(err.Error(), "pre-order hook") {
+		t.Fatalf("expected a pre-order hook error, got %v", err)
+	}
+	if _, statErr := os.Stat(marker); statErr == nil {
+		t.Fatal("commands after a failure should not run")
+	}
+}
@@ -0,0 +1,56 @@
+package i18n
+
+// hindi covers order progress, common errors and table headers. Formats with
+// more than one verb use explicit argument indexes where Hindi word order
+// differs from English.
+var hindi = map[string]string{
+	// Order progress
+	"Placing order: %d jar(s), returning %d jar(s)": "ऑर्डर दिया जा रहा है: %d जार, %d जार वापसी",
+	"Checking session...":                           "सत्र की जाँच हो रही है...",
+	"Preparing cart...":                             "कार्ट तैयार हो रहा है...",
+	"Updating cart quantity...":                     "कार्ट में मात्रा अपडेट हो रही है...",
+	"Cart already at desired quantity.":             "कार्ट में पहले से सही मात्रा है।",
+	"Adding product to cart...":                     "उत्पाद कार्ट में जोड़ा जा रहा है...",
+	"Setting return jars...":                        "वापसी जार सेट किए जा रहे हैं...",
+	"Fetching shipping details...":                  "डिलीवरी विवरण लाए जा रहे हैं...",
+	"Submitting shipping info...":                   "डिलीवरी जानकारी भेजी जा रही है...",
+	"Fetching payment page...":                      "भुगतान पेज लाया जा रहा है...",
+	"Wallet balance":                                "वॉलेट बैलेंस",
+	"Order total":                                   "ऑर्डर कुल",
+	"Submitting payment (Bisleri Wallet)...":        "भुगतान किया जा रहा है (बिसलेरी वॉलेट)...",
+	"Placing order...":                              "ऑर्डर दिया जा रहा है...",
+	"Order placed:":                                 "ऑर्डर हो गया:",
+	"Wallet balance (post-order)":                   "वॉलेट बैलेंस (ऑर्डर के बाद)",
+	"Estimated delivery":                            "अनुमानित डिलीवरी",
+	"You already have order %s (%s) placed on %s. Place another order? [y/N]: ": "आपका ऑर्डर %s (%s) %s को दिया गया था और अभी बाकी है। एक और ऑर्डर दें? [y/N]: ",
+	"Splitting %d jar(s) into %d orders of at most %d.":                         "%d जार को अधिकतम %[3]d के %[2]d ऑर्डर में बांटा जा रहा है।",
+	"No delivery slot open; retrying at %s...":                                  "कोई डिलीवरी स्लॉट खाली नहीं है; %s पर फिर कोशिश होगी...",
+	"Placing order %d of %d...":                                                 "%[2]d में से ऑर्डर %[1]d दिया जा रहा है...",
+	"Syncing order history...":                                                  "ऑर्डर इतिहास सिंक हो रहा है...",
+	"Retrying order after login...":                                             "लॉगिन के बाद ऑर्डर फिर से किया जा रहा है...",
+	"Warning: could not detect wallet balance":                                  "चेतावनी: वॉलेट बैलेंस का पता नहीं चला",
+
+	// Errors
+	"no cookies in profile; run 'bislericli auth login'":                          "प्रोफ़ाइल में कुकीज़ नहीं हैं; 'bislericli auth login' चलाएँ",
+	"quantity must be a positive number":                                          "मात्रा धनात्मक संख्या होनी चाहिए",
+	"return jars (%d) cannot exceed order quantity (%d)":                          "वापसी जार (%d) ऑर्डर मात्रा (%d) से अधिक नहीं हो सकते",
+	"no address found in account; set a default address on bisleri.com and retry": "खाते में कोई पता नहीं मिला; bisleri.com पर डिफ़ॉल्ट पता सेट करें और फिर कोशिश करें",
+	"insufficient wallet balance (%s) for order total (%s)":                       "ऑर्डर कुल (%[2]s) के लिए वॉलेट बैलेंस (%[1]s) पर्याप्त नहीं है",
+	"session expired; please run 'bislericli auth login'":                         "सत्र समाप्त हो गया; कृपया 'bislericli auth login' चलाएँ",
+
+	// Table headers
+	"Order History (showing %d order(s)):": "ऑर्डर इतिहास (%d ऑर्डर):",
+	"Order ID":                             "ऑर्डर आईडी",
+	"Date":                                 "तारीख",
+	"Status":                               "स्थिति",
+	"Total":                                "कुल",
+	"Most recent order: %s":                "सबसे हाल का ऑर्डर: %s",
+	"Period":                               "अवधि",
+	"Orders":                               "ऑर्डर",
+	"Average":                              "औसत",
+	"Earliest":                             "सबसे पहला",
+	"Latest":                               "सबसे हाल का",
+	"Day":                                  "दिन",
+	"Share":                                "हिस्सा",
+	"Tag":                                  "टैग",
+}
@@ -0,0 +1,75 @@
+// Package i18n translates user-facing CLI strings. Messages are looked up by
+// their English format string, so untranslated text falls back to English.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Lang string
+
+const (
+	English Lang = "en"
+	Hindi   Lang = "hi"
+)
+
+var catalogs = map[Lang]map[string]string{
+	Hindi: hindi,
+}
+
+var current = English
+
+// Supported lists the selectable languages.
+func Supported() []Lang {
+	return []Lang{English, Hindi}
+}
+
+// Set selects the language used by T. Unknown languages fall back to English.
+func Set(lang Lang) {
+	if _, ok := catalogs[lang]; ok || lang == English {
+		current = lang
+		return
+	}
+	current = English
+}
+
+func Current() Lang {
+	return current
+}
+
+// Resolve picks the language from BISLERICLI_LANG, then the config value, then
+// the locale variables (LC_ALL, LC_MESSAGES, LANG), e.g. "hi_IN.UTF-8" -> hi.
+func Resolve(configured string, getenv func(string) string) Lang {
+	candidates := []string{getenv("BISLERICLI_LANG"), configured, getenv("LC_ALL"), getenv("LC_MESSAGES"), getenv("LANG")}
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+		return parse(c)
+	}
+	return English
+}
+
+func parse(value string) Lang {
+	value = strings.ToLower(value)
+	if i := strings.IndexAny(value, "_.-@"); i >= 0 {
+		value = value[:i]
+	}
+	lang := Lang(value)
+	if _, ok := catalogs[lang]; ok {
+		return lang
+	}
+	return English
+}
+
+// T translates format and applies args like fmt.Sprintf.
+func T(format string, args ...any) string {
+	if msg, ok := catalogs[current][format]; ok {
+		format = msg
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,50 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		configured string
+		env        map[string]string
+		want       Lang
+	}{
+		{"", nil, English},
+		{"hi", nil, Hindi},
+		{"", map[string]string{"LANG": "hi_IN.UTF-8"}, Hindi},
+		{"en", map[string]string{"LANG": "hi_IN.UTF-8"}, English},
+		{"en", map[string]string{"BISLERICLI_LANG": "hi"}, Hindi},
+		{"fr", nil, English},
+	}
+	for _, tt := range tests {
+		got := Resolve(tt.configured, func(k string) string { return tt.env[k] })
+		if got != tt.want {
+			t.Fatalf("Resolve(%q, %v) = %s, want %s", tt.configured, tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	defer Set(English)
+	if got := T("Placing order: %d jar(s), returning %d jar(s)", 2, 1); got != "Placing order: 2 jar(s), returning 1 jar(s)" {
+		t.Fatalf("english = %q", got)
+	}
+	Set(Hindi)
+	got := T("insufficient wallet balance (%s) for order total (%s)", "₹100", "₹200")
+	if !strings.Contains(got, "(₹200)") || !strings.Contains(got, "(₹100)") || strings.Index(got, "₹200") > strings.Index(got, "₹100") {
+		t.Fatalf("hindi = %q", got)
+	}
+	if got := T("untranslated %d", 3); got != "untranslated 3" {
+		t.Fatalf("fallback = %q", got)
+	}
+}
+
+func TestHindiCatalogVerbs(t *testing.T) {
+	for en, hi := range hindi {
+		if strings.Count(en, "%") != strings.Count(hi, "%") {
+			t.Fatalf("verb count mismatch for %q: %q", en, hi)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Package ics writes iCalendar (RFC 5545) files.
+package ics
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// Event is a calendar entry. AllDay events use only the date of Start; others
+// run from Start to End (Start plus an hour when End is zero).
+type Event struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Summary     string
+	Description string
+}
+
+// Write renders a calendar named name containing events.
+func Write(w io.Writer, name string, events []Event, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(s string) {
+		bw.WriteString(fold(s))
+		bw.WriteString("\r\n")
+	}
+	stamp := now.UTC().Format("20060102T150405Z")
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//bislericli//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:" + escape(name))
+	for _, ev := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + escape(ev.UID))
+		line("DTSTAMP:" + stamp)
+		if ev.AllDay {
+			line("DTSTART;VALUE=DATE:" + ev.Start.Format("20060102"))
+			line("DTEND;VALUE=DATE:" + ev.Start.AddDate(0, 0, 1).Format("20060102"))
+		} else {
+			end := ev.End
+			if end.IsZero() {
+				end = ev.Start.Add(time.Hour)
+			}
+			line("DTSTART:" + ev.Start.UTC().Format("20060102T150405Z"))
+			line("DTEND:" + end.UTC().Format("20060102T150405Z"))
+		}
+		line("SUMMARY:" + escape(ev.Summary))
+		if ev.Description != "" {
+			line("DESCRIPTION:" + escape(ev.Description))
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// fold splits content lines longer than 75 octets, continuing with a space,
+// without breaking UTF-8 sequences.
+func fold(s string) string {
+	const limit = 75
+	if len(s) <= limit {
+		return s
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		n := len(string(r))
+		if width+n > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += n
+	}
+	return b.String()
+}
@@ -0,0 +1,38 @@
+package ics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	ist := time.FixedZone("IST", 5*3600+1800)
+	now := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	events := []Event{
+		{UID: "order-BS-1@bislericli", Start: time.Date(2026, 10, 14, 0, 0, 0, 0, ist), AllDay: true, Summary: "Bisleri delivery, BS-1", Description: "2 jars; ₹200"},
+		{UID: "run-1@bislericli", Start: time.Date(2026, 10, 18, 7, 0, 0, 0, ist), Summary: "Scheduled order", Description: strings.Repeat("long text ", 12)},
+	}
+	var b strings.Builder
+	if err := Write(&b, "Bisleri", events, now); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"DTSTART;VALUE=DATE:20261014\r\nDTEND;VALUE=DATE:20261015\r\n",
+		`SUMMARY:Bisleri delivery\, BS-1` + "\r\n",
+		`DESCRIPTION:2 jars\; ₹200` + "\r\n",
+		"DTSTART:20261018T013000Z\r\nDTEND:20261018T023000Z\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	for _, l := range strings.Split(out, "\r\n") {
+		if len(l) > 75 {
+			t.Fatalf("line not folded (%d octets): %q", len(l), l)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Package inventory estimates how many jars are left at home. It keeps a
+// per-profile ledger of deliveries and corrections and drains the level at a
+// daily consumption rate between them.
+package inventory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/fileutil"
+	"bislericli/internal/schedule"
+	"bislericli/internal/store"
+)
+
+// Kind is what an event did to the level.
+type Kind string
+
+const (
+	// Delivery adds jars.
+	Delivery Kind = "delivery"
+	// Set replaces the level with a counted value.
+	Set Kind = "set"
+	// Consume removes jars beyond the usual rate.
+	Consume Kind = "consume"
+)
+
+// Event is one change to the level.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Kind    Kind      `json:"kind"`
+	Jars    float64   `json:"jars"`
+	OrderID string    `json:"orderId,omitempty"`
+	Note    string    `json:"note,omitempty"`
+}
+
+// Ledger is a profile's inventory record. Reminded is set once a low-stock
+// reminder has been sent and cleared when jars are added, so each low spell
+// is reminded about once.
+type Ledger struct {
+	Events   []Event `json:"events"`
+	Reminded bool    `json:"reminded,omitempty"`
+}
+
+// Path returns the profile's ledger file.
+func Path(profileName string) (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "inventory_"+profileName+".json"), nil
+}
+
+// Load reads the profile's ledger; a missing file is an empty ledger.
+func Load(profileName string) (Ledger, error) {
+	path, err := Path(profileName)
+	if err != nil {
+		return Ledger{}, err
+	}
+	return load(path)
+}
+
+func load(path string) (Ledger, error) {
+	var l Ledger
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return l, err
+	}
+	if err := json.Unmarshal(data, &l); err != nil {
+		return l, fmt.Errorf("%s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Update applies fn to the profile's ledger under the file lock and saves it.
+func Update(profileName string, fn func(l *Ledger) error) (Ledger, error) {
+	path, err := Path(profileName)
+	if err != nil {
+		return Ledger{}, err
+	}
+	unlock, err := fileutil.Lock(path)
+	if err != nil {
+		return Ledger{}, err
+	}
+	defer unlock()
+	l, err := load(path)
+	if err != nil {
+		return l, err
+	}
+	if err := fn(&l); err != nil {
+		return l, err
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return l, err
+	}
+	return l, fileutil.WriteAtomic(path, data, 0o600)
+}
+
+// Add records an event, keeping the ledger in time order. A delivery already
+// recorded for the same order is ignored; Add reports whether the event was
+// added.
+func (l *Ledger) Add(e Event) bool {
+	if e.Kind == Delivery && e.OrderID != "" {
+		for _, old := range l.Events {
+			if old.Kind == Delivery && old.OrderID == e.OrderID {
+				return false
+			}
+		}
+	}
+	l.Events = append(l.Events, e)
+	sort.SliceStable(l.Events, func(a, b int) bool { return l.Events[a].Time.Before(l.Events[b].Time) })
+	if e.Kind != Consume {
+		l.Reminded = false
+	}
+	return true
+}
+
+// Estimate returns the jars left at now, draining rate jars a day between
+// events and never going below zero. ok is false when nothing is recorded.
+func Estimate(events []Event, rate float64, now time.Time) (jars float64, ok bool) {
+	if len(events) == 0 {
+		return 0, false
+	}
+	level := 0.0
+	at := events[0].Time
+	for _, e := range events {
+		if e.Time.After(now) {
+			break
+		}
+		level = drain(level, rate, e.Time.Sub(at))
+		at = e.Time
+		switch e.Kind {
+		case Delivery:
+			level += e.Jars
+		case Set:
+			level = e.Jars
+		case Consume:
+			level = max(0, level-e.Jars)
+		}
+	}
+	return drain(level, rate, now.Sub(at)), true
+}
+
+func drain(level, rate float64, d time.Duration) float64 {
+	if d <= 0 {
+		return level
+	}
+	return max(0, level-rate*d.Hours()/24)
+}
+
+// LearnRate infers jars used per day from the ordering cadence: the jars of
+// every order but the latest were used up over the span between the first
+// and latest order. ok is false with fewer than two dated orders or a span
+// under a day.
+func LearnRate(orders []store.SavedOrder, fallbackQty int) (rate float64, ok bool) {
+	var dated []store.SavedOrder
+	for _, o := range orders {
+		if !o.ParsedDate.IsZero() {
+			dated = append(dated, o)
+		}
+	}
+	if len(dated) < 2 {
+		return 0, false
+	}
+	sort.Slice(dated, func(i, j int) bool { return dated[i].ParsedDate.Before(dated[j].ParsedDate) })
+	spanDays := dated[len(dated)-1].ParsedDate.Sub(dated[0].ParsedDate).Hours() / 24
+	if spanDays < 1 {
+		return 0, false
+	}
+	used := 0
+	for _, o := range dated[:len(dated)-1] {
+		used += schedule.OrderQuantity(o, fallbackQty)
+	}
+	return float64(used) / spanDays, true
+}
@@ -0,0 +1,68 @@
+package inventory
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"bislericli/internal/store"
+)
+
+func day(d int) time.Time {
+	return time.Date(2026, 5, d, 10, 0, 0, 0, time.UTC)
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestEstimate(t *testing.T) {
+	if _, ok := Estimate(nil, 1, day(1)); ok {
+		t.Fatal("Estimate of an empty ledger should not be ok")
+	}
+	var l Ledger
+	l.Add(Event{Time: day(1), Kind: Delivery, Jars: 2, OrderID: "A"})
+	l.Add(Event{Time: day(5), Kind: Delivery, Jars: 3, OrderID: "B"})
+	if l.Add(Event{Time: day(5), Kind: Delivery, Jars: 3, OrderID: "B"}) {
+		t.Error("a delivery of the same order was recorded twice")
+	}
+	// Two jars at 0.5/day run out on day 5, before the second delivery.
+	for _, tc := range []struct {
+		now  time.Time
+		want float64
+	}{
+		{day(3), 1},
+		{day(5), 3},
+		{day(7), 2},
+		{day(20), 0},
+	} {
+		if got, _ := Estimate(l.Events, 0.5, tc.now); !near(got, tc.want) {
+			t.Errorf("Estimate at %s = %v, want %v", tc.now.Format("Jan 2"), got, tc.want)
+		}
+	}
+
+	l.Reminded = true
+	l.Add(Event{Time: day(6), Kind: Set, Jars: 4})
+	l.Add(Event{Time: day(6).Add(time.Hour), Kind: Consume, Jars: 1})
+	if got, _ := Estimate(l.Events, 0.5, day(8)); !near(got, 2) {
+		t.Errorf("Estimate after set and consume = %v", got)
+	}
+	if l.Reminded {
+		t.Error("adding jars should clear the reminder")
+	}
+}
+
+func TestLearnRate(t *testing.T) {
+	orders := []store.SavedOrder{
+		{ParsedDate: day(11), Items: "20L Jar Qty: 2"},
+		{ParsedDate: day(1), Items: "20L Jar Qty: 2"},
+		{ParsedDate: day(6), Items: "20L Jar Qty: 3"},
+		{Items: "undated"},
+	}
+	if rate, ok := LearnRate(orders, 2); !ok || !near(rate, 0.5) {
+		t.Errorf("LearnRate = %v, %v; want 0.5", rate, ok)
+	}
+	if _, ok := LearnRate(orders[:1], 2); ok {
+		t.Error("LearnRate of one order should not be ok")
+	}
+}
@@ -0,0 +1,302 @@
+// Package jobs keeps a persisted queue of the operations long-running modes
+// perform (placing an order, syncing history, refreshing the login), so they
+// can be listed, retried and canceled from another process.
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/fileutil"
+)
+
+// Kind is what a job does.
+type Kind string
+
+const (
+	PlaceOrder  Kind = "place-order"
+	Sync        Kind = "sync"
+	RefreshAuth Kind = "refresh-auth"
+)
+
+// Kinds lists the job kinds in display order.
+var Kinds = []Kind{PlaceOrder, Sync, RefreshAuth}
+
+// Status is where a job is in its life.
+type Status string
+
+const (
+	Queued    Status = "queued"
+	Running   Status = "running"
+	Succeeded Status = "succeeded"
+	Failed    Status = "failed"
+	Canceled  Status = "canceled"
+)
+
+// Job is one queued or finished operation. Args are the command-line flags of
+// the command the job runs.
+type Job struct {
+	ID       string    `json:"id"`
+	Kind     Kind      `json:"kind"`
+	Profile  string    `json:"profile"`
+	Args     []string  `json:"args,omitempty"`
+	Source   string    `json:"source,omitempty"`
+	Status   Status    `json:"status"`
+	Attempts int       `json:"attempts,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	PID      int       `json:"pid,omitempty"`
+	Created  time.Time `json:"created"`
+	Started  time.Time `json:"started,omitempty"`
+	Finished time.Time `json:"finished,omitempty"`
+}
+
+// Done reports whether the job has finished, successfully or not.
+func (j Job) Done() bool {
+	return j.Status == Succeeded || j.Status == Failed || j.Status == Canceled
+}
+
+var (
+	ErrNotFound = errors.New("no such job")
+	// ErrState means the job's status does not allow the change.
+	ErrState = errors.New("job cannot be changed in its current state")
+)
+
+// keepFinished bounds the history kept in the queue file.
+const keepFinished = 200
+
+const fileName = "jobs.json"
+
+type queueFile struct {
+	NextID int   `json:"nextId"`
+	Jobs   []Job `json:"jobs"`
+}
+
+// Queue is the job queue file shared by all profiles.
+type Queue struct {
+	path string
+}
+
+// Open returns the queue in the data directory.
+func Open() (*Queue, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	return OpenAt(filepath.Join(dir, fileName)), nil
+}
+
+// OpenAt returns the queue stored at path.
+func OpenAt(path string) *Queue {
+	return &Queue{path: path}
+}
+
+func (q *Queue) load() (queueFile, error) {
+	var f queueFile
+	data, err := os.ReadFile(q.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return queueFile{NextID: 1}, nil
+	}
+	if err != nil {
+		return f, err
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("%s: %w", q.path, err)
+	}
+	if f.NextID < 1 {
+		f.NextID = 1
+	}
+	return f, nil
+}
+
+// update applies fn to the queue under the file lock.
+func (q *Queue) update(fn func(f *queueFile) error) error {
+	unlock, err := fileutil.Lock(q.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	f, err := q.load()
+	if err != nil {
+		return err
+	}
+	if err := fn(&f); err != nil {
+		return err
+	}
+	prune(&f)
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteAtomic(q.path, data, 0o600)
+}
+
+// prune drops the oldest finished jobs beyond keepFinished.
+func prune(f *queueFile) {
+	finished := 0
+	for _, j := range f.Jobs {
+		if j.Done() {
+			finished++
+		}
+	}
+	if finished <= keepFinished {
+		return
+	}
+	drop := finished - keepFinished
+	kept := f.Jobs[:0]
+	for _, j := range f.Jobs {
+		if j.Done() && drop > 0 {
+			drop--
+			continue
+		}
+		kept = append(kept, j)
+	}
+	f.Jobs = kept
+}
+
+// List returns every job, newest first.
+func (q *Queue) List() ([]Job, error) {
+	f, err := q.load()
+	if err != nil {
+		return nil, err
+	}
+	jobs := f.Jobs
+	sort.SliceStable(jobs, func(a, b int) bool { return jobs[a].Created.After(jobs[b].Created) })
+	return jobs, nil
+}
+
+// Get returns the job with the ID.
+func (q *Queue) Get(id string) (Job, error) {
+	f, err := q.load()
+	if err != nil {
+		return Job{}, err
+	}
+	for _, j := range f.Jobs {
+		if j.ID == id {
+			return j, nil
+		}
+	}
+	return Job{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+// Enqueue adds a queued job and returns it with its ID.
+func (q *Queue) Enqueue(kind Kind, profile string, args []string, source string) (Job, error) {
+	job := Job{Kind: kind, Profile: profile, Args: args, Source: source, Status: Queued, Created: time.Now()}
+	err := q.update(func(f *queueFile) error {
+		job.ID = strconv.Itoa(f.NextID)
+		f.NextID++
+		f.Jobs = append(f.Jobs, job)
+		return nil
+	})
+	return job, err
+}
+
+// change applies fn to the job with the ID under the lock.
+func (q *Queue) change(id string, fn func(j *Job) error) (Job, error) {
+	var out Job
+	err := q.update(func(f *queueFile) error {
+		for i := range f.Jobs {
+			if f.Jobs[i].ID == id {
+				if err := fn(&f.Jobs[i]); err != nil {
+					return err
+				}
+				out = f.Jobs[i]
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	})
+	return out, err
+}
+
+// Claim marks a queued job as running in this process.
+func (q *Queue) Claim(id string) (Job, error) {
+	return q.change(id, func(j *Job) error {
+		if j.Status != Queued {
+			return fmt.Errorf("%w: job %s is %s", ErrState, j.ID, j.Status)
+		}
+		j.Status, j.Error = Running, ""
+		j.Attempts++
+		j.PID = os.Getpid()
+		j.Started, j.Finished = time.Now(), time.Time{}
+		return nil
+	})
+}
+
+// Next claims the oldest queued job for the profile; ok is false when there
+// is none.
+func (q *Queue) Next(profile string) (job Job, ok bool, err error) {
+	jobs, err := q.List()
+	if err != nil {
+		return Job{}, false, err
+	}
+	for i := len(jobs) - 1; i >= 0; i-- {
+		if jobs[i].Status != Queued || jobs[i].Profile != profile {
+			continue
+		}
+		job, err := q.Claim(jobs[i].ID)
+		if errors.Is(err, ErrState) {
+			// Another process claimed it first.
+			continue
+		}
+		return job, err == nil, err
+	}
+	return Job{}, false, nil
+}
+
+// Finish records a running job's outcome.
+func (q *Queue) Finish(id string, runErr error) (Job, error) {
+	return q.change(id, func(j *Job) error {
+		j.Status, j.Error = Succeeded, ""
+		if runErr != nil {
+			j.Status, j.Error = Failed, runErr.Error()
+		}
+		j.Finished = time.Now()
+		return nil
+	})
+}
+
+// Retry queues a failed or canceled job again.
+func (q *Queue) Retry(id string) (Job, error) {
+	return q.change(id, func(j *Job) error {
+		if j.Status != Failed && j.Status != Canceled {
+			return fmt.Errorf("%w: job %s is %s; only failed or canceled jobs can be retried", ErrState, j.ID, j.Status)
+		}
+		j.Status, j.Error, j.PID = Queued, "", 0
+		return nil
+	})
+}
+
+// Cancel stops a queued job from running.
+func (q *Queue) Cancel(id string) (Job, error) {
+	return q.change(id, func(j *Job) error {
+		if j.Status != Queued {
+			return fmt.Errorf("%w: job %s is %s; only queued jobs can be canceled", ErrState, j.ID, j.Status)
+		}
+		j.Status, j.Finished = Canceled, time.Now()
+		return nil
+	})
+}
+
+// Recover fails running jobs whose process is gone (killed, or the machine
+// restarted), so they can be retried. It returns how many it failed.
+func (q *Queue) Recover(alive func(pid int) bool) (int, error) {
+	n := 0
+	err := q.update(func(f *queueFile) error {
+		for i := range f.Jobs {
+			j := &f.Jobs[i]
+			if j.Status == Running && (j.PID <= 0 || !alive(j.PID)) {
+				j.Status, j.Error, j.Finished = Failed, "interrupted: the process running it exited", time.Now()
+				n++
+			}
+		}
+		return nil
+	})
+	return n, err
+}
@@ -0,0 +1,85 @@
+package jobs
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestQueueLifecycle(t *testing.T) {
+	q := OpenAt(filepath.Join(t.TempDir(), "jobs.json"))
+	first, err := q.Enqueue(PlaceOrder, "home", []string{"--qty", "2"}, "scheduler")
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	second, err := q.Enqueue(Sync, "home", nil, "cli")
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if first.ID != "1" || second.ID != "2" || first.Status != Queued {
+		t.Fatalf("unexpected jobs %+v %+v", first, second)
+	}
+
+	job, ok, err := q.Next("home")
+	if err != nil || !ok || job.ID != first.ID || job.Status != Running || job.Attempts != 1 {
+		t.Fatalf("Next = %+v, %v, %v; want job 1 running", job, ok, err)
+	}
+	if _, ok, _ := q.Next("office"); ok {
+		t.Error("Next returned a job of another profile")
+	}
+	if job, err = q.Finish(job.ID, errors.New("wallet short")); err != nil || job.Status != Failed || job.Error != "wallet short" {
+		t.Fatalf("Finish = %+v, %v", job, err)
+	}
+	if _, err := q.Cancel(job.ID); !errors.Is(err, ErrState) {
+		t.Errorf("Cancel of a failed job = %v, want ErrState", err)
+	}
+	if job, err = q.Retry(job.ID); err != nil || job.Status != Queued || job.Error != "" {
+		t.Fatalf("Retry = %+v, %v", job, err)
+	}
+	if _, err := q.Retry(job.ID); !errors.Is(err, ErrState) {
+		t.Errorf("Retry of a queued job = %v, want ErrState", err)
+	}
+	if job, err = q.Cancel(second.ID); err != nil || job.Status != Canceled {
+		t.Fatalf("Cancel = %+v, %v", job, err)
+	}
+	if _, err := q.Get("99"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(99) = %v, want ErrNotFound", err)
+	}
+
+	jobs, err := q.List()
+	if err != nil || len(jobs) != 2 {
+		t.Fatalf("List = %v, %v", jobs, err)
+	}
+	// The retried order runs again as its second attempt.
+	job, ok, _ = q.Next("home")
+	if !ok || job.ID != first.ID || job.Attempts != 2 {
+		t.Errorf("Next after retry = %+v, %v", job, ok)
+	}
+}
+
+func TestRecoverFailsOrphanedJobs(t *testing.T) {
+	q := OpenAt(filepath.Join(t.TempDir(), "jobs.json"))
+	job, _ := q.Enqueue(PlaceOrder, "home", nil, "scheduler")
+	if _, err := q.Claim(job.ID); err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	n, err := q.Recover(func(int) bool { return false })
+	if err != nil || n != 1 {
+		t.Fatalf("Recover = %d, %v; want 1", n, err)
+	}
+	if job, _ = q.Get(job.ID); job.Status != Failed {
+		t.Errorf("status = %s, want failed", job.Status)
+	}
+}
+
+func TestPruneKeepsRecentFinishedJobs(t *testing.T) {
+	f := queueFile{}
+	for i := 0; i < keepFinished+5; i++ {
+		f.Jobs = append(f.Jobs, Job{ID: string(rune('a' + i%26)), Status: Succeeded})
+	}
+	f.Jobs = append(f.Jobs, Job{ID: "queued", Status: Queued})
+	prune(&f)
+	if len(f.Jobs) != keepFinished+1 || f.Jobs[len(f.Jobs)-1].ID != "queued" {
+		t.Errorf("prune kept %d jobs", len(f.Jobs))
+	}
+}
@@ -0,0 +1,105 @@
+// Package migrate upgrades on-disk JSON documents (profiles, config, order
+// history) written by older releases to the current layout. It works on raw
+// JSON so the store and config packages can call it before decoding.
+package migrate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"bislericli/internal/fileutil"
+)
+
+var ErrNewerVersion = errors.New("schema version is newer than supported")
+
+// Step upgrades a document by one version, mutating it in place.
+type Step func(doc map[string]any) error
+
+// Schema describes one document kind. Steps[i] upgrades version i to i+1, so
+// the current version is len(Steps).
+type Schema struct {
+	Name  string
+	Steps []Step
+}
+
+const versionKey = "schemaVersion"
+
+// Files written before versioning carry no schemaVersion and are version 0.
+var (
+	Profile      = Schema{Name: "profile", Steps: []Step{stamp}}
+	Config       = Schema{Name: "config", Steps: []Step{stamp}}
+	OrderHistory = Schema{Name: "order history", Steps: []Step{stamp}}
+)
+
+func (s Schema) Current() int {
+	return len(s.Steps)
+}
+
+// Apply returns data upgraded to the current version and whether anything
+// changed. Documents from a newer release are rejected rather than silently
+// losing fields.
+func (s Schema) Apply(data []byte) ([]byte, bool, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, false, err
+	}
+	version, err := docVersion(doc)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", s.Name, err)
+	}
+	if version > s.Current() {
+		return nil, false, fmt.Errorf("%s: %w (%d > %d); upgrade bislericli", s.Name, ErrNewerVersion, version, s.Current())
+	}
+	if version == s.Current() {
+		return data, false, nil
+	}
+	for v := version; v < s.Current(); v++ {
+		if err := s.Steps[v](doc); err != nil {
+			return nil, false, fmt.Errorf("migrate %s from version %d: %w", s.Name, v, err)
+		}
+		doc[versionKey] = v + 1
+	}
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, false, err
+	}
+	return out, true, nil
+}
+
+// Decode upgrades data and unmarshals it into v. A document from a newer
+// release is marked so backup recovery does not replace it with an older copy.
+func (s Schema) Decode(data []byte, v any) error {
+	upgraded, _, err := s.Apply(data)
+	if err != nil {
+		if errors.Is(err, ErrNewerVersion) {
+			return fileutil.NoRecovery(err)
+		}
+		return err
+	}
+	return json.Unmarshal(upgraded, v)
+}
+
+func docVersion(doc map[string]any) (int, error) {
+	raw, ok := doc[versionKey]
+	if !ok || raw == nil {
+		return 0, nil
+	}
+	num, ok := raw.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("invalid %s %v", versionKey, raw)
+	}
+	v, err := num.Int64()
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid %s %v", versionKey, raw)
+	}
+	return int(v), nil
+}
+
+// stamp is the 0 -> 1 step: the layout is unchanged, only the version is added.
+func stamp(map[string]any) error {
+	return nil
+}
@@ -0,0 +1,63 @@
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestApplyUpgradesUnversioned(t *testing.T) {
+	out, changed, err := Profile.Apply([]byte(`{"name":"default","cookies":[{"expires":1760000000}]}`))
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !changed {
+		t.Fatalf("expected migration")
+	}
+	var doc struct {
+		SchemaVersion int `json:"schemaVersion"`
+		Cookies       []struct {
+			Expires int64 `json:"expires"`
+		} `json:"cookies"`
+	}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if doc.SchemaVersion != Profile.Current() || doc.Cookies[0].Expires != 1760000000 {
+		t.Fatalf("unexpected result: %s", out)
+	}
+}
+
+func TestApplyCurrentIsUnchanged(t *testing.T) {
+	in := []byte(`{"schemaVersion":1,"name":"default"}`)
+	out, changed, err := Profile.Apply(in)
+	if err != nil || changed || string(out) != string(in) {
+		t.Fatalf("expected no-op, got %s changed=%v err=%v", out, changed, err)
+	}
+}
+
+func TestApplyRejectsNewer(t *testing.T) {
+	_, _, err := Config.Apply([]byte(`{"schemaVersion":99}`))
+	if !errors.Is(err, ErrNewerVersion) || !strings.Contains(err.Error(), "upgrade bislericli") {
+		t.Fatalf("expected newer-version error, got %v", err)
+	}
+}
+
+func TestApplyRunsStepsInOrder(t *testing.T) {
+	s := Schema{Name: "test", Steps: []Step{
+		stamp,
+		func(doc map[string]any) error {
+			doc["renamed"] = doc["old"]
+			delete(doc, "old")
+			return nil
+		},
+	}}
+	out, _, err := s.Apply([]byte(`{"schemaVersion":1,"old":"x"}`))
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !strings.Contains(string(out), `"renamed": "x"`) || strings.Contains(string(out), `"old"`) {
+		t.Fatalf("unexpected output: %s", out)
+	}
+}
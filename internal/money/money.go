@@ -0,0 +1,164 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Money is an amount of Indian rupees stored as an integer number of paise so
+// sums and comparisons are exact.
+type Money int64
+
+// FromFloat converts a rupee amount to Money, rounding to the nearest paisa.
+func FromFloat(rupees float64) Money {
+	return Money(math.Round(rupees * 100))
+}
+
+// Rupees returns the amount as a float number of rupees.
+func (m Money) Rupees() float64 {
+	return float64(m) / 100
+}
+
+// Div splits the amount into n equal parts, rounding to the nearest paisa.
+func (m Money) Div(n int) Money {
+	if n == 0 {
+		return 0
+	}
+	return Money(math.Round(float64(m) / float64(n)))
+}
+
+// Symbol prefixes String output. The CLI swaps it for "Rs." on terminals that
+// cannot render the rupee sign.
+var Symbol = "₹"
+
+// String renders the amount with the rupee sign and Indian digit grouping,
+// e.g. "₹1,23,456.50".
+func (m Money) String() string {
+	return Symbol + m.Plain()
+}
+
+// Plain renders the amount with Indian digit grouping and no currency sign.
+func (m Money) Plain() string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	whole := strconv.FormatInt(v/100, 10)
+	paise := v % 100
+	return fmt.Sprintf("%s%s.%02d", sign, groupIndian(whole), paise)
+}
+
+func groupIndian(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	head, tail := digits[:len(digits)-3], digits[len(digits)-3:]
+	var parts []string
+	for len(head) > 2 {
+		parts = append([]string{head[len(head)-2:]}, parts...)
+		head = head[:len(head)-2]
+	}
+	if head != "" {
+		parts = append([]string{head}, parts...)
+	}
+	return strings.Join(append(parts, tail), ",")
+}
+
+// MarshalJSON encodes the amount as a rupee number so stored files stay
+// compatible with earlier float-based amounts.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(m.Rupees(), 'f', -1, 64)), nil
+}
+
+// UnmarshalJSON accepts either a rupee number or a display string.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var f float64
+	if err := json.Unmarshal(data, &f); err == nil {
+		*m = FromFloat(f)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("money: cannot decode %s", string(data))
+	}
+	parsed, ok := Parse(s)
+	if !ok {
+		return fmt.Errorf("money: cannot parse %q", s)
+	}
+	*m = parsed
+	return nil
+}
+
+var currencyMarkers = []string{"₹", "inr", "rs.", "rs", "/-"}
+
+// Parse extracts an amount from strings seen on the site such as "₹200",
+// "₹ 1,234.50 /-", "Rs. 1,23,456", "INR 99.5" or "Free", tolerating
+// non-breaking and thin spaces. It returns false when no amount is present.
+func Parse(value string) (Money, bool) {
+	s := strings.ToLower(strings.TrimSpace(value))
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+	if s == "free" {
+		return 0, true
+	}
+	negative := false
+	if strings.HasPrefix(s, "-") {
+		negative = true
+		s = s[1:]
+	}
+	for _, marker := range currencyMarkers {
+		s = strings.ReplaceAll(s, marker, "")
+	}
+	s = strings.TrimSuffix(s, ".")
+	if strings.HasPrefix(s, "-") {
+		negative = !negative
+		s = s[1:]
+	}
+	if s == "" {
+		return 0, false
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && r != ',' && r != '.' {
+			return 0, false
+		}
+	}
+	s = normalizeSeparators(s)
+	if s == "" || strings.Count(s, ".") > 1 {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	m := FromFloat(f)
+	if negative {
+		m = -m
+	}
+	return m, true
+}
+
+// normalizeSeparators removes thousands separators and turns a decimal comma
+// into a dot. A comma is treated as the decimal separator only when it is the
+// last separator and is followed by one or two digits ("1.234,50", "12,5").
+func normalizeSeparators(s string) string {
+	lastComma := strings.LastIndex(s, ",")
+	lastDot := strings.LastIndex(s, ".")
+	if lastComma > lastDot {
+		decimals := len(s) - lastComma - 1
+		if decimals >= 1 && decimals <= 2 {
+			intPart := strings.NewReplacer(",", "", ".", "").Replace(s[:lastComma])
+			return intPart + "." + s[lastComma+1:]
+		}
+	}
+	return strings.ReplaceAll(s, ",", "")
+}
@@ -0,0 +1,87 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		in   string
+		want Money
+		ok   bool
+	}{
+		{"₹200", 20000, true},
+		{"₹ 200", 20000, true},
+		{"₹200.00", 20000, true},
+		{"₹1,234.50 /-", 123450, true},
+		{"₹1,234.50/-", 123450, true},
+		{"₹ 1,234.50", 123450, true},
+		{"₹ 99", 9900, true},
+		{"Rs. 1,23,456", 12345600, true},
+		{"Rs 150", 15000, true},
+		{"INR 99.5", 9950, true},
+		{"  ₹ 0.00  ", 0, true},
+		{"Free", 0, true},
+		{"-₹50", -5000, true},
+		{"₹-50", -5000, true},
+		{"1.234,50", 123450, true},
+		{"12,5", 1250, true},
+		{"200", 20000, true},
+		{"₹\u00a0200", 20000, true},
+		{"₹1,234\u202f/-", 123400, true},
+		{"\u20b9 2,000.00", 200000, true},
+		{"₹200.", 20000, true},
+		{"", 0, false},
+		{"₹", 0, false},
+		{"N/A", 0, false},
+		{"₹1.2.3", 0, false},
+		{"₹12abc", 0, false},
+	}
+	for _, tc := range cases {
+		got, ok := Parse(tc.in)
+		if ok != tc.ok || got != tc.want {
+			t.Errorf("Parse(%q) = %d, %v; want %d, %v", tc.in, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	cases := map[Money]string{
+		0:         "₹0.00",
+		5:         "₹0.05",
+		20000:     "₹200.00",
+		123450:    "₹1,234.50",
+		12345600:  "₹1,23,456.00",
+		-5000:     "₹-50.00",
+		100000000: "₹10,00,000.00",
+	}
+	for in, want := range cases {
+		if got := in.String(); got != want {
+			t.Errorf("Money(%d).String() = %q; want %q", in, got, want)
+		}
+	}
+}
+
+func TestJSONRoundTripAndLegacyFloats(t *testing.T) {
+	var m Money
+	if err := json.Unmarshal([]byte("200.5"), &m); err != nil || m != 20050 {
+		t.Fatalf("unmarshal float: %d, %v", m, err)
+	}
+	if err := json.Unmarshal([]byte(`"₹1,234.50"`), &m); err != nil || m != 123450 {
+		t.Fatalf("unmarshal string: %d, %v", m, err)
+	}
+	data, err := json.Marshal(Money(123450))
+	if err != nil || string(data) != "1234.5" {
+		t.Fatalf("marshal: %s, %v", data, err)
+	}
+}
+
+func TestDiv(t *testing.T) {
+	if got := Money(1000).Div(3); got != 333 {
+		t.Fatalf("Div rounding: %d", got)
+	}
+	if got := Money(1000).Div(0); got != 0 {
+		t.Fatalf("Div by zero: %d", got)
+	}
+}
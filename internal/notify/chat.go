@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultWhatsAppEndpoint is the WhatsApp Business Cloud API.
+const DefaultWhatsAppEndpoint = "https://graph.facebook.com/v21.0"
+
+// WhatsApp sends messages from a WhatsApp Business number through the
+// Cloud API. The API reaches individual numbers only, not groups.
+type WhatsApp struct {
+	Endpoint      string
+	PhoneNumberID string
+	Token         string
+	To            []string
+	HTTP          *http.Client
+}
+
+// Notify messages every recipient and returns all failures.
+func (w WhatsApp) Notify(ctx context.Context, m Message) error {
+	endpoint := w.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultWhatsAppEndpoint
+	}
+	client := w.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: 20 * time.Second}
+	}
+	text := "*" + m.Title + "*\n" + m.Body
+	var errs []error
+	for _, to := range w.To {
+		msg, err := json.Marshal(map[string]any{
+			"messaging_product": "whatsapp",
+			"to":                to,
+			"type":              "text",
+			"text":              map[string]string{"body": text},
+		})
+		if err != nil {
+			return err
+		}
+		target := strings.TrimRight(endpoint, "/") + "/" + url.PathEscape(w.PhoneNumberID) + "/messages"
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(msg))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+w.Token)
+		req.Header.Set("Content-Type", "application/json")
+		errs = append(errs, sendRequest(client, req, "whatsapp "+to))
+	}
+	return errors.Join(errs...)
+}
+
+func sendRequest(client *http.Client, req *http.Request, what string) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", what, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	var apiErr struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+		return fmt.Errorf("%s: %s: %s", what, resp.Status, apiErr.Error.Message)
+	}
+	return fmt.Errorf("%s: %s", what, resp.Status)
+}
+
+// Signal sends messages with signal-cli from a registered account, to a
+// group, to individual numbers, or both.
+type Signal struct {
+	Command string
+	Account string
+	Group   string
+	To      []string
+}
+
+// Notify runs signal-cli once for all recipients.
+func (s Signal) Notify(_ context.Context, m Message) error {
+	name := s.Command
+	if name == "" {
+		name = "signal-cli"
+	}
+	args := []string{"-a", s.Account, "send", "-m", m.Title + "\n" + m.Body}
+	if s.Group != "" {
+		args = append(args, "-g", s.Group)
+	}
+	args = append(args, s.To...)
+	return run(name, args, nil)
+}
@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWhatsAppNotify(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/12345/messages" || r.Header.Get("Authorization") != "Bearer tok" {
+			t.Errorf("unexpected request %s %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		var msg struct {
+			To   string `json:"to"`
+			Text struct {
+				Body string `json:"body"`
+			} `json:"text"`
+		}
+		json.NewDecoder(r.Body).Decode(&msg)
+		if msg.To == "910000000000" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"message":"Recipient not in allowed list"}}`))
+			return
+		}
+		got = append(got, msg.To+": "+msg.Text.Body)
+		w.Write([]byte(`{"messages":[{"id":"wamid.1"}]}`))
+	}))
+	defer srv.Close()
+
+	wa := WhatsApp{Endpoint: srv.URL, PhoneNumberID: "12345", Token: "tok", To: []string{"919876543210", "910000000000"}}
+	err := wa.Notify(context.Background(), Message{Title: "Bisleri order BS-1", Body: "Out for delivery"})
+	if err == nil || !strings.Contains(err.Error(), "Recipient not in allowed list") {
+		t.Errorf("Notify error = %v, want the failed recipient's API error", err)
+	}
+	if len(got) != 1 || got[0] != "919876543210: *Bisleri order BS-1*\nOut for delivery" {
+		t.Errorf("sent = %q", got)
+	}
+}
+
+func TestSignalNotify(t *testing.T) {
+	orig := run
+	defer func() { run = orig }()
+	var name string
+	var args []string
+	run = func(n string, a []string, _ []string) error {
+		name, args = n, a
+		return nil
+	}
+	sig := Signal{Account: "+919876543210", Group: "Zm9vYmFy", To: []string{"+919800000000"}}
+	if err := sig.Notify(context.Background(), Message{Title: "Bisleri order placed", Body: "2 jars"}); err != nil {
+		t.Fatal(err)
+	}
+	want := "-a +919876543210 send -m Bisleri order placed\n2 jars -g Zm9vYmFy +919800000000"
+	if name != "signal-cli" || strings.Join(args, " ") != want {
+		t.Errorf("ran %s %q", name, strings.Join(args, " "))
+	}
+}
@@ -0,0 +1,64 @@
+// Package notify delivers messages from unattended modes such as the
+// scheduler and sync --watch: desktop notifications, WhatsApp and Signal
+// chats, and user commands, each behind the Notifier interface.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// ErrUnsupported is returned when no notification backend exists for the OS.
+var ErrUnsupported = errors.New("desktop notifications are not supported on this platform")
+
+// run executes the backend command; tests replace it.
+var run = func(name string, args []string, env []string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", name, err, out)
+	}
+	return nil
+}
+
+// windowsToast shows a toast through the WinRT API. Title and body come from
+// the environment so they never need PowerShell quoting.
+const windowsToast = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$t = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$n = $t.GetElementsByTagName('text')
+$n.Item(0).AppendChild($t.CreateTextNode($env:BISLERICLI_NOTIFY_TITLE)) > $null
+$n.Item(1).AppendChild($t.CreateTextNode($env:BISLERICLI_NOTIFY_BODY)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('bislericli').Show([Windows.UI.Notifications.ToastNotification]::new($t))`
+
+// command returns the backend invocation for goos: osascript on macOS,
+// notify-send on Linux and BSDs, a PowerShell toast on Windows.
+func command(goos, title, body string) (name string, args []string, env []string, err error) {
+	switch goos {
+	case "darwin":
+		return "osascript", []string{
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, body,
+		}, nil, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "notify-send", []string{"--app-name=bislericli", title, body}, nil, nil
+	case "windows":
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", windowsToast},
+			[]string{"BISLERICLI_NOTIFY_TITLE=" + title, "BISLERICLI_NOTIFY_BODY=" + body}, nil
+	default:
+		return "", nil, nil, ErrUnsupported
+	}
+}
+
+// Desktop shows a notification with title and body.
+func Desktop(title, body string) error {
+	name, args, env, err := command(runtime.GOOS, title, body)
+	if err != nil {
+		return err
+	}
+	return run(name, args, env)
+}
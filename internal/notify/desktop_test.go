@@ -0,0 +1,32 @@
+package notify
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCommandPassesTextAsArguments(t *testing.T) {
+	title, body := `Order "BS-1"`, "Out for delivery; $(rm -rf /)"
+	name, args, _, err := command("darwin", title, body)
+	if err != nil || name != "osascript" {
+		t.Fatalf("darwin: got %q, %v", name, err)
+	}
+	if args[len(args)-2] != title || args[len(args)-1] != body {
+		t.Fatalf("darwin: title/body must be passed as argv, got %q", args)
+	}
+	name, args, _, _ = command("linux", title, body)
+	if name != "notify-send" || args[len(args)-1] != body {
+		t.Fatalf("linux: got %q %q", name, args)
+	}
+	name, args, env, _ := command("windows", title, body)
+	if name != "powershell" || strings.Contains(strings.Join(args, " "), body) {
+		t.Fatalf("windows: body must not be interpolated into the script: %q", args)
+	}
+	if len(env) != 2 || env[1] != "BISLERICLI_NOTIFY_BODY="+body {
+		t.Fatalf("windows: unexpected env %q", env)
+	}
+	if _, _, _, err := command("plan9", title, body); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("plan9: expected ErrUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"bislericli/internal/hooks"
+)
+
+// Events a message can be about; templates are keyed by these names.
+const (
+	OrderNew     = "orderNew"
+	OrderStatus  = "orderStatus"
+	OrderPlaced  = "orderPlaced"
+	OrderFailed  = "orderFailed"
+	OrderBlocked = "orderBlocked"
+	OrderSkipped = "orderSkipped"
+	RunningLow   = "runningLow"
+	Heat         = "heat"
+	Holiday      = "holiday"
+	LoginNeeded  = "loginNeeded"
+	Digest       = "digest"
+)
+
+// Events lists every event, in the order the README documents them.
+var Events = []string{OrderNew, OrderStatus, OrderPlaced, OrderFailed, OrderBlocked, OrderSkipped, RunningLow, Heat, Holiday, LoginNeeded, Digest}
+
+// Message is one notification. Title and Body are the built-in wording;
+// templates can rewrite them from the other fields.
+type Message struct {
+	Event string `json:"event"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	// Order is set for events about a single order.
+	Order *Order `json:"order,omitempty"`
+	// Error is why an order failed, was blocked or was skipped.
+	Error string `json:"error,omitempty"`
+}
+
+// Order holds the order fields messages carry; unknown ones are empty.
+type Order struct {
+	ID             string `json:"id"`
+	Status         string `json:"status,omitempty"`
+	PreviousStatus string `json:"previousStatus,omitempty"`
+	Date           string `json:"date,omitempty"`
+	Total          string `json:"total,omitempty"`
+	Quantity       int    `json:"quantity,omitempty"`
+	ETA            string `json:"eta,omitempty"`
+}
+
+// Notifier delivers messages to one service. Adapters for services bislericli
+// does not know about can be written as a Command instead.
+type Notifier interface {
+	Notify(ctx context.Context, m Message) error
+}
+
+// DesktopNotifier shows messages as desktop notifications.
+type DesktopNotifier struct{}
+
+func (DesktopNotifier) Notify(_ context.Context, m Message) error {
+	return Desktop(m.Title, m.Body)
+}
+
+// Command runs a shell command per message with the message as JSON on
+// stdin, in the same envelope hooks receive, so any script can forward
+// notifications to a service of its choice.
+type Command string
+
+func (c Command) Notify(ctx context.Context, m Message) error {
+	return hooks.Run(ctx, []string{string(c)}, hooks.Event{Event: m.Event, Time: time.Now(), Data: m})
+}
+
+// Template replaces a message's title, body or both; an empty field keeps
+// the built-in text. Both are Go text/template sources executed with the
+// Message, e.g. "{{.Order.ID}} is {{.Order.Status}}".
+type Template struct {
+	Title string
+	Body  string
+}
+
+// Render applies t to m. On error m is returned unchanged with the error,
+// so callers can still send the built-in text.
+func (t Template) Render(m Message) (Message, error) {
+	out := m
+	for _, f := range []struct {
+		name string
+		src  string
+		dst  *string
+	}{{"title", t.Title, &out.Title}, {"body", t.Body, &out.Body}} {
+		if f.src == "" {
+			continue
+		}
+		text, err := execute(f.name, f.src, m)
+		if err != nil {
+			return m, err
+		}
+		*f.dst = text
+	}
+	return out, nil
+}
+
+// Check parses t without executing it, for config validation.
+func (t Template) Check() error {
+	for _, src := range []string{t.Title, t.Body} {
+		if _, err := template.New("").Option("missingkey=error").Parse(src); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func execute(name, src string, m Message) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(src)
+	if err != nil {
+		return "", err
+	}
+	if m.Order == nil {
+		// Templates shared across events may mention order fields; give
+		// them empty values rather than failing on a nil pointer.
+		m.Order = &Order{}
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, m); err != nil {
+		return "", fmt.Errorf("%s template: %w", name, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestTemplateRender(t *testing.T) {
+	m := Message{
+		Event: OrderStatus,
+		Title: "Bisleri order BS-1",
+		Body:  "Placed -> Delivered",
+		Order: &Order{ID: "BS-1", Status: "Delivered", PreviousStatus: "Placed"},
+	}
+	got, err := Template{Body: "{{.Order.ID}} is now {{.Order.Status}} (was {{.Order.PreviousStatus}})"}.Render(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != m.Title || got.Body != "BS-1 is now Delivered (was Placed)" {
+		t.Errorf("rendered %q / %q", got.Title, got.Body)
+	}
+
+	// Order fields are empty, not an error, for events without an order.
+	got, err = Template{Title: "💧 {{.Title}}{{.Order.ID}}"}.Render(Message{Event: RunningLow, Title: "Time to reorder water"})
+	if err != nil || got.Title != "💧 Time to reorder water" {
+		t.Errorf("rendered %q, %v", got.Title, err)
+	}
+
+	got, err = Template{Body: "{{.Order.Missing}}"}.Render(m)
+	if err == nil || got.Body != m.Body {
+		t.Errorf("bad field: got %q, %v; want the built-in body and an error", got.Body, err)
+	}
+	if err := (Template{Title: "{{.Title"}).Check(); err == nil {
+		t.Error("Check accepted an unterminated action")
+	}
+}
+
+func TestCommandNotify(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	dest := filepath.Join(t.TempDir(), "message.json")
+	m := Message{Event: OrderPlaced, Title: "Bisleri order placed", Body: "BS-9, ₹180", Order: &Order{ID: "BS-9", Total: "₹180"}}
+	if err := Command("cat > "+dest).Notify(context.Background(), m); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Event string  `json:"event"`
+		Data  Message `json:"data"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil || got.Event != OrderPlaced || got.Data.Order == nil || got.Data.Order.ID != "BS-9" {
+		t.Errorf("payload = %s (%v)", data, err)
+	}
+}
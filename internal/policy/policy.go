@@ -0,0 +1,65 @@
+// Package policy evaluates the guardrails configured for unattended orders.
+package policy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/money"
+)
+
+// Violation is returned when an order would break a policy rule.
+type Violation struct {
+	Rule   string
+	Detail string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("policy %s: %s", v.Rule, v.Detail)
+}
+
+// CheckTimeslot rejects a delivery slot that is not in the allowed list.
+func CheckTimeslot(p config.Policy, slot string) error {
+	if len(p.AllowedTimeslots) == 0 {
+		return nil
+	}
+	for _, allowed := range p.AllowedTimeslots {
+		if strings.EqualFold(strings.TrimSpace(allowed), strings.TrimSpace(slot)) {
+			return nil
+		}
+	}
+	return &Violation{Rule: "allowedTimeslots", Detail: fmt.Sprintf("timeslot %q is not allowed (allowed: %s)", slot, strings.Join(p.AllowedTimeslots, ", "))}
+}
+
+// CheckWeeklyOrders rejects placing count more orders when the orders placed
+// in the seven days before now would then exceed the weekly limit.
+func CheckWeeklyOrders(p config.Policy, placed []time.Time, now time.Time, count int) error {
+	if p.MaxWeeklyOrders <= 0 {
+		return nil
+	}
+	since := now.AddDate(0, 0, -7)
+	recent := 0
+	for _, t := range placed {
+		if t.After(since) && !t.After(now) {
+			recent++
+		}
+	}
+	if recent+count > p.MaxWeeklyOrders {
+		return &Violation{Rule: "maxWeeklyOrders", Detail: fmt.Sprintf("%d order(s) in the last 7 days; limit is %d", recent, p.MaxWeeklyOrders)}
+	}
+	return nil
+}
+
+// CheckCheckout applies the price and wallet rules once the payment page
+// shows them. jarPrice is zero when the per-jar price is unknown.
+func CheckCheckout(p config.Policy, jarPrice, balance, total money.Money) error {
+	if p.MaxJarPrice > 0 && jarPrice > p.MaxJarPrice {
+		return &Violation{Rule: "maxJarPrice", Detail: fmt.Sprintf("jar price %s is above the %s limit", jarPrice, p.MaxJarPrice)}
+	}
+	if p.MinWalletBalance > 0 && balance-total < p.MinWalletBalance {
+		return &Violation{Rule: "minWalletBalance", Detail: fmt.Sprintf("wallet would drop to %s, below the %s minimum", balance-total, p.MinWalletBalance)}
+	}
+	return nil
+}
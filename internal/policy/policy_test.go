@@ -0,0 +1,54 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/money"
+)
+
+func TestCheckTimeslot(t *testing.T) {
+	p := config.Policy{AllowedTimeslots: []string{"08:00 AM - 02:00 PM"}}
+	if err := CheckTimeslot(p, " 08:00 am - 02:00 pm"); err != nil {
+		t.Fatalf("expected slot to be allowed: %v", err)
+	}
+	var v *Violation
+	if err := CheckTimeslot(p, "02:00 PM - 08:00 PM"); !errors.As(err, &v) || v.Rule != "allowedTimeslots" {
+		t.Fatalf("expected an allowedTimeslots violation, got %v", err)
+	}
+	if err := CheckTimeslot(config.Policy{}, "anything"); err != nil {
+		t.Fatalf("no rule should allow any slot: %v", err)
+	}
+}
+
+func TestCheckWeeklyOrders(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	placed := []time.Time{now.AddDate(0, 0, -1), now.AddDate(0, 0, -3), now.AddDate(0, 0, -8)}
+	p := config.Policy{MaxWeeklyOrders: 3}
+	if err := CheckWeeklyOrders(p, placed, now, 1); err != nil {
+		t.Fatalf("third order of the week should pass: %v", err)
+	}
+	if err := CheckWeeklyOrders(p, placed, now, 2); err == nil {
+		t.Fatal("two more orders should exceed the limit")
+	}
+}
+
+func TestCheckCheckout(t *testing.T) {
+	rupees := func(s string) money.Money {
+		m, _ := money.Parse(s)
+		return m
+	}
+	p := config.Policy{MaxJarPrice: rupees("100"), MinWalletBalance: rupees("500")}
+	if err := CheckCheckout(p, rupees("90"), rupees("1000"), rupees("180")); err != nil {
+		t.Fatalf("expected checkout to pass: %v", err)
+	}
+	var v *Violation
+	if err := CheckCheckout(p, rupees("110"), rupees("1000"), rupees("220")); !errors.As(err, &v) || v.Rule != "maxJarPrice" {
+		t.Fatalf("expected maxJarPrice violation, got %v", err)
+	}
+	if err := CheckCheckout(p, 0, rupees("600"), rupees("180")); !errors.As(err, &v) || v.Rule != "minWalletBalance" {
+		t.Fatalf("expected minWalletBalance violation, got %v", err)
+	}
+}
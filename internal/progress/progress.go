@@ -0,0 +1,129 @@
+// Package progress reports order flow stages as machine-readable events for
+// wrappers (--progress json) and the serve-mode API, and times each stage
+// for the audit log.
+package progress
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	StatusStarted   = "started"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// Event is one stage transition. ElapsedMS is set on completed/failed events.
+type Event struct {
+	Time      time.Time         `json:"time"`
+	Stage     string            `json:"stage"`
+	Status    string            `json:"status"`
+	ElapsedMS int64             `json:"elapsedMs,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Data      map[string]string `json:"data,omitempty"`
+}
+
+// Timing is how long one stage took.
+type Timing struct {
+	Stage     string `json:"stage"`
+	ElapsedMS int64  `json:"elapsedMs"`
+}
+
+// Sink receives events.
+type Sink func(Event)
+
+// JSONLines returns a sink writing one JSON object per line to w.
+func JSONLines(w io.Writer) Sink {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	return func(ev Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = enc.Encode(ev)
+	}
+}
+
+// Tracker follows the current stage of a flow. A nil *Tracker is valid and
+// does nothing, so callers don't need to guard every call.
+type Tracker struct {
+	sinks   []Sink
+	now     func() time.Time
+	current string
+	started time.Time
+	timings []Timing
+}
+
+func NewTracker(sinks ...Sink) *Tracker {
+	return &Tracker{sinks: sinks, now: time.Now}
+}
+
+// Enter completes the current stage (if any) and starts stage.
+func (t *Tracker) Enter(stage string) {
+	if t == nil {
+		return
+	}
+	if t.current == stage {
+		return
+	}
+	t.complete()
+	t.current = stage
+	t.started = t.now()
+	t.emit(Event{Time: t.started, Stage: stage, Status: StatusStarted})
+}
+
+// Finish completes the current stage, or marks it failed when err is non-nil.
+func (t *Tracker) Finish(err error) {
+	if t == nil {
+		return
+	}
+	if err != nil && t.current != "" {
+		now := t.now()
+		elapsed := now.Sub(t.started).Milliseconds()
+		t.timings = append(t.timings, Timing{Stage: t.current, ElapsedMS: elapsed})
+		t.emit(Event{Time: now, Stage: t.current, Status: StatusFailed, ElapsedMS: elapsed, Error: err.Error()})
+		t.current = ""
+		return
+	}
+	t.complete()
+}
+
+// Result emits a completed event for a named result stage with data, such as
+// the placed order ID.
+func (t *Tracker) Result(stage string, data map[string]string) {
+	if t == nil {
+		return
+	}
+	t.complete()
+	t.emit(Event{Time: t.now(), Stage: stage, Status: StatusCompleted, Data: data})
+}
+
+func (t *Tracker) complete() {
+	if t.current == "" {
+		return
+	}
+	now := t.now()
+	elapsed := now.Sub(t.started).Milliseconds()
+	t.timings = append(t.timings, Timing{Stage: t.current, ElapsedMS: elapsed})
+	t.emit(Event{Time: now, Stage: t.current, Status: StatusCompleted, ElapsedMS: elapsed})
+	t.current = ""
+}
+
+// TakeTimings returns the durations of the stages finished since the last
+// call, in order, and forgets them.
+func (t *Tracker) TakeTimings() []Timing {
+	if t == nil {
+		return nil
+	}
+	timings := t.timings
+	t.timings = nil
+	return timings
+}
+
+func (t *Tracker) emit(ev Event) {
+	for _, sink := range t.sinks {
+		sink(ev)
+	}
+}
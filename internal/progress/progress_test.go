@@ -0,0 +1,67 @@
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrackerEmitsStageEvents(t *testing.T) {
+	var buf bytes.Buffer
+	tr := NewTracker(JSONLines(&buf))
+	clock := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	tr.now = func() time.Time {
+		clock = clock.Add(100 * time.Millisecond)
+		return clock
+	}
+
+	tr.Enter("session")
+	tr.Enter("cart")
+	tr.Finish(errors.New("cart is not empty"))
+
+	var events []Event
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var ev Event
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("bad line %q: %v", line, err)
+		}
+		events = append(events, ev)
+	}
+	want := []struct{ stage, status string }{
+		{"session", StatusStarted},
+		{"session", StatusCompleted},
+		{"cart", StatusStarted},
+		{"cart", StatusFailed},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		if events[i].Stage != w.stage || events[i].Status != w.status {
+			t.Fatalf("event %d = %s/%s, want %s/%s", i, events[i].Stage, events[i].Status, w.stage, w.status)
+		}
+	}
+	if events[3].Error != "cart is not empty" || events[3].ElapsedMS != 100 {
+		t.Fatalf("unexpected failure event: %+v", events[3])
+	}
+	timings := tr.TakeTimings()
+	if len(timings) != 2 || timings[0] != (Timing{"session", 100}) || timings[1] != (Timing{"cart", 100}) {
+		t.Fatalf("timings = %+v", timings)
+	}
+	if again := tr.TakeTimings(); again != nil {
+		t.Fatalf("timings not cleared: %+v", again)
+	}
+}
+
+func TestNilTrackerIsNoop(t *testing.T) {
+	var tr *Tracker
+	tr.Enter("session")
+	tr.Finish(nil)
+	tr.Result("order", nil)
+	if tr.TakeTimings() != nil {
+		t.Fatal("nil tracker returned timings")
+	}
+}
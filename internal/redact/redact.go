@@ -0,0 +1,116 @@
+package redact
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"bislericli/internal/store"
+)
+
+const Mask = "[REDACTED]"
+
+type rule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// defaultRules cover the personal data that shows up in Bisleri pages and
+// request logs. Order matters: session tokens before generic digit runs.
+var defaultRules = []rule{
+	{regexp.MustCompile(`(?i)((?:dwsid|dwanonymous_[a-z0-9]*|dwsecuretoken_[a-z0-9]*|sid|__cq_[a-z]+|csrf_token)["']?\s*[=:]\s*["']?)[^"'\s;&<>]+`), "${1}" + Mask},
+	{regexp.MustCompile(`(?i)((?:cookie|authorization)\s*:\s*)[^\r\n]+`), "${1}" + Mask},
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[EMAIL]"},
+	{regexp.MustCompile(`(?:\+?91[\s-]?)?\b[6-9]\d{9}\b`), "[PHONE]"},
+	{regexp.MustCompile(`\b[1-9]\d{5}\b`), "[PINCODE]"},
+	{regexp.MustCompile(`BS-[A-Z0-9-]+`), "BS-[ORDER]"},
+}
+
+// Redactor masks personal data in text. Exact values (profile phone, address
+// lines, cookie values) are replaced first, then the pattern rules.
+type Redactor struct {
+	literals []string
+	rules    []rule
+}
+
+// New returns a Redactor with the default rules plus any extra regular
+// expressions from config; matches of extra patterns are replaced by Mask.
+func New(extra []string) (*Redactor, error) {
+	r := &Redactor{rules: append([]rule(nil), defaultRules...)}
+	for _, expr := range extra {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", expr, err)
+		}
+		r.rules = append(r.rules, rule{pattern: re, replacement: Mask})
+	}
+	return r, nil
+}
+
+// AddValues registers exact strings to mask. Very short values are ignored to
+// avoid shredding unrelated text.
+func (r *Redactor) AddValues(values ...string) {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); len(v) >= 3 {
+			r.literals = append(r.literals, v)
+		}
+	}
+	// Replace longer values first so substrings don't leave partial matches.
+	sort.Slice(r.literals, func(i, j int) bool { return len(r.literals[i]) > len(r.literals[j]) })
+}
+
+// AddProfile registers the profile's phone number, addresses (including the
+// named ones) and cookie values.
+func (r *Redactor) AddProfile(p store.Profile) {
+	r.AddValues(p.PhoneNumber)
+	if p.Address != nil {
+		r.addAddress(*p.Address)
+	}
+	for _, saved := range p.Addresses {
+		r.addAddress(saved.Address)
+	}
+	for _, c := range p.Cookies {
+		r.AddValues(c.Value)
+	}
+}
+
+func (r *Redactor) addAddress(a store.Address) {
+	r.AddValues(a.FirstName, a.LastName, a.Address1, a.Address2, a.Floor, a.NearByLandmark, a.Phone, a.Latitude, a.Longitude)
+}
+
+func (r *Redactor) String(text string) string {
+	if r == nil {
+		return text
+	}
+	for _, lit := range r.literals {
+		text = strings.ReplaceAll(text, lit, Mask)
+	}
+	for _, rl := range r.rules {
+		text = rl.pattern.ReplaceAllString(text, rl.replacement)
+	}
+	return text
+}
+
+// Writer wraps w so everything written through it is redacted. Each Write is
+// redacted independently, which suits log.Logger (one Write per message).
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	return &writer{r: r, w: w}
+}
+
+type writer struct {
+	mu sync.Mutex
+	r  *Redactor
+	w  io.Writer
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := io.WriteString(w.w, w.r.String(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
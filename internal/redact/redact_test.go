@@ -0,0 +1,73 @@
+package redact
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	"bislericli/internal/store"
+)
+
+func TestRedactorMasksPersonalData(t *testing.T) {
+	r, err := New(nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	r.AddProfile(store.Profile{
+		PhoneNumber: "9876543210",
+		Address:     &store.Address{FirstName: "Asha", Address1: "42 MG Road"},
+		Cookies:     []store.Cookie{{Name: "dwsid", Value: "abcdefsecret"}},
+	})
+	in := `Asha, 42 MG Road 560001 call +91 9988776655 mail a@b.com dwsid=zzz token abcdefsecret order BS-12345`
+	out := r.String(in)
+	for _, leak := range []string{"Asha", "MG Road", "560001", "9988776655", "a@b.com", "zzz", "abcdefsecret", "12345"} {
+		if strings.Contains(out, leak) {
+			t.Fatalf("redacted output still contains %q: %s", leak, out)
+		}
+	}
+}
+
+func TestRedactorMasksNamedAddresses(t *testing.T) {
+	r, err := New(nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	r.AddProfile(store.Profile{
+		Address: &store.Address{FirstName: "Asha", Address1: "42 MG Road"},
+		Addresses: []store.SavedAddress{{Name: "office", Address: store.Address{
+			FirstName: "Ravi", LastName: "Menon", Address1: "Prestige Tech Park", Address2: "Tower B",
+			NearByLandmark: "Opp Metro Gate", Phone: "9123456780", Latitude: "12.9352", Longitude: "77.6245",
+		}}},
+	})
+	in := `deliver to Ravi Menon, Prestige Tech Park, Tower B, Opp Metro Gate, 9123456780 at 12.9352,77.6245`
+	out := r.String(in)
+	for _, leak := range []string{"Ravi", "Menon", "Prestige", "Tower B", "Metro Gate", "9123456780", "12.9352", "77.6245"} {
+		if strings.Contains(out, leak) {
+			t.Fatalf("redacted output still contains %q: %s", leak, out)
+		}
+	}
+}
+
+func TestRedactorExtraPatterns(t *testing.T) {
+	r, err := New([]string{`Flat \d+`})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if got := r.String("Flat 301, Tower B"); got != Mask+", Tower B" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+	if _, err := New([]string{"("}); err == nil {
+		t.Fatalf("expected error for invalid pattern")
+	}
+}
+
+func TestRedactorWriter(t *testing.T) {
+	r, _ := New(nil)
+	var buf bytes.Buffer
+	logger := log.New(r.Writer(&buf), "", 0)
+	logger.Printf("Cookie: dwsid=abc; other=1")
+	if strings.Contains(buf.String(), "abc") {
+		t.Fatalf("cookie leaked into log: %q", buf.String())
+	}
+}
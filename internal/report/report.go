@@ -0,0 +1,190 @@
+package report
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/redact"
+	"bislericli/internal/store"
+)
+
+type Options struct {
+	Version     string
+	ConfigPath  string
+	ProfilePath string
+	AuditPath   string
+	DebugDir    string
+	LogLines    int
+	MaxSnapshot int
+	// Patterns are extra redaction regexps from config.
+	Patterns []string
+}
+
+// Build writes a zip bundle suitable for attaching to an issue. Missing inputs
+// are noted in the manifest rather than failing the bundle.
+func Build(w io.Writer, opts Options) error {
+	var profile *store.Profile
+	if opts.ProfilePath != "" {
+		if p, err := store.LoadProfile(opts.ProfilePath); err == nil {
+			profile = &p
+		}
+	}
+	red, err := redact.New(opts.Patterns)
+	if err != nil {
+		return err
+	}
+	if profile != nil {
+		red.AddProfile(*profile)
+	}
+	zw := zip.NewWriter(w)
+	var notes []string
+
+	manifest := []string{
+		"bislericli issue report",
+		"generated: " + time.Now().Format(time.RFC3339),
+		"version: " + opts.Version,
+		"go: " + runtime.Version(),
+		"os/arch: " + runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	if data, err := redactedConfig(opts.ConfigPath); err == nil {
+		if err := writeFile(zw, filepath.Base(opts.ConfigPath), red.String(string(data))); err != nil {
+			return err
+		}
+	} else {
+		notes = append(notes, "config: "+err.Error())
+	}
+
+	if profile != nil {
+		if err := writeFile(zw, "profile.json", red.String(profileSummary(*profile))); err != nil {
+			return err
+		}
+	} else {
+		notes = append(notes, "profile: not available")
+	}
+
+	if lines, err := tailLines(opts.AuditPath, opts.LogLines); err == nil {
+		if err := writeFile(zw, "audit.jsonl", red.String(strings.Join(lines, "\n"))); err != nil {
+			return err
+		}
+	} else {
+		notes = append(notes, "audit log: "+err.Error())
+	}
+
+	snapshots, globErr := filepath.Glob(filepath.Join(opts.DebugDir, "*.html"))
+	if globErr == nil {
+		sort.Strings(snapshots)
+		for _, path := range snapshots {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			if opts.MaxSnapshot > 0 && len(data) > opts.MaxSnapshot {
+				data = data[:opts.MaxSnapshot]
+			}
+			if err := writeFile(zw, "snapshots/"+filepath.Base(path), red.String(string(data))); err != nil {
+				return err
+			}
+		}
+		manifest = append(manifest, fmt.Sprintf("snapshots: %d", len(snapshots)))
+	}
+
+	if len(notes) > 0 {
+		manifest = append(manifest, "", "notes:")
+		for _, n := range notes {
+			manifest = append(manifest, "  "+red.String(n))
+		}
+	}
+	if err := writeFile(zw, "manifest.txt", strings.Join(manifest, "\n")+"\n"); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// redactedConfig decodes the config file and re-encodes it in its own format
+// with every secret blanked. A config that does not decode is left out
+// rather than copied raw, since its secrets cannot be found reliably.
+func redactedConfig(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = config.ToJSON(path, data); err != nil {
+		return nil, err
+	}
+	cfg, err := config.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("not included: %w", err)
+	}
+	if data, err = json.Marshal(cfg.WithoutSecrets()); err != nil {
+		return nil, err
+	}
+	return config.FromJSON(config.FormatOf(path), data)
+}
+
+// profileSummary drops cookie values entirely, keeping only what helps debugging.
+func profileSummary(p store.Profile) string {
+	type cookieInfo struct {
+		Name    string `json:"name"`
+		Domain  string `json:"domain"`
+		Expires int64  `json:"expires"`
+	}
+	summary := struct {
+		Name          string           `json:"name"`
+		LastLogin     time.Time        `json:"lastLogin"`
+		Cookies       []cookieInfo     `json:"cookies"`
+		Address       *store.Address   `json:"address,omitempty"`
+		AddressSource string           `json:"addressSource,omitempty"`
+		PreferredCity string           `json:"preferredCity,omitempty"`
+		LastOrder     *store.OrderInfo `json:"lastOrder,omitempty"`
+	}{
+		Name:          p.Name,
+		LastLogin:     p.LastLogin,
+		Address:       p.Address,
+		AddressSource: p.AddressSource,
+		PreferredCity: p.PreferredCity,
+		LastOrder:     p.LastOrder,
+	}
+	for _, c := range p.Cookies {
+		summary.Cookies = append(summary.Cookies, cookieInfo{Name: c.Name, Domain: c.Domain, Expires: c.Expires})
+	}
+	data, _ := json.MarshalIndent(summary, "", "  ")
+	return string(data)
+}
+
+func tailLines(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if n > 0 && len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	return lines, scanner.Err()
+}
+
+func writeFile(zw *zip.Writer, name, content string) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(f, content)
+	return err
+}
@@ -0,0 +1,102 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildRedactsConfigSecrets(t *testing.T) {
+	secrets := []string{
+		"whsec-shopify-0001",
+		"5f1d3a0c9e8b7a6f5e4d3c2b1a09f8e7d6c5b4a3928170f6e5d4c3b2a1908f7e",
+		"otp-relay-secret-0002",
+		"123456:telegram-bot-token-0003",
+		"EAAwhatsapp-token-0004",
+		"+15550000005",
+		"+15550000006",
+	}
+	cfg := `{
+  "serve": {
+    "webhookSecrets": {"shopify": "` + secrets[0] + `"},
+    "tokens": {"ci": {"hash": "` + secrets[1] + `", "scopes": ["order"]}}
+  },
+  "otpRelay": {"secret": "` + secrets[2] + `"},
+  "telegram": {"token": "` + secrets[3] + `"},
+  "notifications": {
+    "whatsapp": {"token": "` + secrets[4] + `", "to": ["` + secrets[5] + `"]},
+    "signal": {"account": "` + secrets[6] + `"}
+  }
+}`
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(configPath, []byte(cfg), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Build(&buf, Options{ConfigPath: configPath, DebugDir: dir}); err != nil {
+		t.Fatal(err)
+	}
+	var sawConfig bool
+	for name, data := range readBundle(t, buf.Bytes()) {
+		if name == "config.json" {
+			sawConfig = true
+			if !strings.Contains(data, `"shopify"`) {
+				t.Errorf("config.json lost the webhook source name:\n%s", data)
+			}
+		}
+		for _, s := range secrets {
+			if strings.Contains(data, s) {
+				t.Errorf("%s contains secret %q", name, s)
+			}
+		}
+	}
+	if !sawConfig {
+		t.Fatal("bundle has no config.json")
+	}
+}
+
+func TestBuildLeavesOutUndecodableConfig(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"telegram": {"token": "tok-0001"`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := Build(&buf, Options{ConfigPath: configPath, DebugDir: dir}); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range readBundle(t, buf.Bytes()) {
+		if strings.Contains(data, "tok-0001") {
+			t.Errorf("%s contains the undecodable config", name)
+		}
+	}
+}
+
+// readBundle returns the uncompressed contents of every file in a bundle.
+func readBundle(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(content)
+	}
+	return files
+}
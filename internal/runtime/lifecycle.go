@@ -0,0 +1,179 @@
+// Package runtime coordinates shutdown of long-running commands (schedule
+// run, sync --watch, order --wait-for-slot) on SIGINT and SIGTERM.
+//
+// The first signal cancels the lifecycle's context, so waits and in-flight
+// requests stop, but lets critical sections (the steps that commit an order)
+// run to completion. A second signal, or the grace period running out, also
+// cancels the critical sections.
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrStopping is returned by Critical once shutdown has begun.
+var ErrStopping = errors.New("shutting down")
+
+// Lifecycle is the shutdown state of a long-running command. A nil
+// *Lifecycle is valid and never stops, for commands run without one.
+type Lifecycle struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	// abort is cancelled when critical sections must stop too.
+	abort       context.Context
+	abortCancel context.CancelFunc
+	grace       time.Duration
+	notice      io.Writer
+
+	mu       sync.Mutex
+	critical sync.WaitGroup
+	active   int
+	stopping bool
+
+	signals chan os.Signal
+	done    chan struct{}
+}
+
+// New returns a lifecycle that stops only when Stop is called. notice, if
+// not nil, is told when shutdown waits for a critical section.
+func New(grace time.Duration, notice io.Writer) *Lifecycle {
+	l := &Lifecycle{grace: grace, notice: notice, done: make(chan struct{})}
+	l.ctx, l.cancel = context.WithCancel(context.Background())
+	l.abort, l.abortCancel = context.WithCancel(context.Background())
+	return l
+}
+
+// Start returns a lifecycle that stops on SIGINT or SIGTERM. Close it to
+// restore the default signal handling.
+func Start(grace time.Duration, notice io.Writer) *Lifecycle {
+	l := New(grace, notice)
+	l.signals = make(chan os.Signal, 2)
+	signal.Notify(l.signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		for {
+			select {
+			case <-l.signals:
+				if l.Stopping() {
+					l.Abort()
+				} else {
+					l.Stop()
+				}
+			case <-l.done:
+				return
+			}
+		}
+	}()
+	return l
+}
+
+// Context is cancelled when shutdown begins.
+func (l *Lifecycle) Context() context.Context {
+	if l == nil {
+		return context.Background()
+	}
+	return l.ctx
+}
+
+// Stopping reports whether shutdown has begun.
+func (l *Lifecycle) Stopping() bool {
+	if l == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stopping
+}
+
+// Stop begins shutdown: the context is cancelled, and critical sections get
+// the grace period to finish before they are aborted.
+func (l *Lifecycle) Stop() {
+	l.mu.Lock()
+	if l.stopping {
+		l.mu.Unlock()
+		return
+	}
+	l.stopping = true
+	active := l.active
+	l.mu.Unlock()
+	l.cancel()
+	if active > 0 {
+		if l.notice != nil {
+			fmt.Fprintf(l.notice, "Stopping once the order in progress completes (up to %s); interrupt again to abort it.\n", l.grace)
+		}
+		time.AfterFunc(l.grace, l.Abort)
+	}
+}
+
+// Abort stops everything, critical sections included.
+func (l *Lifecycle) Abort() {
+	l.Stop()
+	l.abortCancel()
+}
+
+// Critical marks the start of steps that must not be cut short by a
+// shutdown request, such as submitting an order. The returned context keeps
+// parent's deadline and values but is cancelled only by parent's deadline or
+// an abort, not by Stop. Call release when the steps (and the record of
+// their outcome) are done. Critical fails with ErrStopping once shutdown has
+// begun, so no new order is started.
+func (l *Lifecycle) Critical(parent context.Context) (ctx context.Context, release func(), err error) {
+	if l == nil {
+		return parent, func() {}, nil
+	}
+	l.mu.Lock()
+	if l.stopping {
+		l.mu.Unlock()
+		return nil, nil, ErrStopping
+	}
+	l.active++
+	l.critical.Add(1)
+	l.mu.Unlock()
+
+	ctx = context.WithoutCancel(parent)
+	var cancel context.CancelFunc
+	if deadline, ok := parent.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	stopAbort := context.AfterFunc(l.abort, cancel)
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			stopAbort()
+			cancel()
+			l.mu.Lock()
+			l.active--
+			l.mu.Unlock()
+			l.critical.Done()
+		})
+	}
+	return ctx, release, nil
+}
+
+// Close waits for critical sections to finish and restores the default
+// signal handling.
+func (l *Lifecycle) Close() {
+	if l == nil {
+		return
+	}
+	l.critical.Wait()
+	if l.signals != nil {
+		signal.Stop(l.signals)
+	}
+	select {
+	case <-l.done:
+	default:
+		close(l.done)
+	}
+	l.cancel()
+	l.abortCancel()
+}
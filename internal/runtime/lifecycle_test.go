@@ -0,0 +1,109 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStopCancelsContextButNotCriticalSection(t *testing.T) {
+	var notice bytes.Buffer
+	l := New(time.Minute, &notice)
+	ctx, release, err := l.Critical(context.Background())
+	if err != nil {
+		t.Fatalf("Critical: %v", err)
+	}
+	l.Stop()
+	if l.Context().Err() == nil {
+		t.Error("lifecycle context not cancelled by Stop")
+	}
+	if ctx.Err() != nil {
+		t.Error("critical section cancelled by Stop")
+	}
+	if notice.Len() == 0 {
+		t.Error("no notice that shutdown waits for the critical section")
+	}
+	if _, _, err := l.Critical(context.Background()); !errors.Is(err, ErrStopping) {
+		t.Errorf("Critical after Stop = %v, want ErrStopping", err)
+	}
+	release()
+	if ctx.Err() == nil {
+		t.Error("critical context not cancelled on release")
+	}
+	l.Close()
+}
+
+func TestAbortCancelsCriticalSection(t *testing.T) {
+	l := New(time.Minute, nil)
+	parent, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	ctx, release, err := l.Critical(parent)
+	if err != nil {
+		t.Fatalf("Critical: %v", err)
+	}
+	defer release()
+	if _, ok := ctx.Deadline(); !ok {
+		t.Error("critical context lost the parent's deadline")
+	}
+	l.Stop()
+	l.Abort()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("critical context not cancelled by Abort")
+	}
+}
+
+func TestGraceAbortsCriticalSection(t *testing.T) {
+	l := New(10*time.Millisecond, nil)
+	ctx, release, err := l.Critical(context.Background())
+	if err != nil {
+		t.Fatalf("Critical: %v", err)
+	}
+	defer release()
+	l.Stop()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("critical context outlived the grace period")
+	}
+}
+
+func TestCloseWaitsForCriticalSection(t *testing.T) {
+	l := New(time.Minute, nil)
+	_, release, err := l.Critical(context.Background())
+	if err != nil {
+		t.Fatalf("Critical: %v", err)
+	}
+	closed := make(chan struct{})
+	go func() {
+		l.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatal("Close returned while a critical section was running")
+	case <-time.After(20 * time.Millisecond):
+	}
+	release()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Close did not return after release")
+	}
+}
+
+func TestNilLifecycleNeverStops(t *testing.T) {
+	var l *Lifecycle
+	if l.Context().Err() != nil || l.Stopping() {
+		t.Error("nil lifecycle is stopping")
+	}
+	ctx, release, err := l.Critical(context.Background())
+	if err != nil || ctx == nil {
+		t.Fatalf("Critical on nil = %v, %v", ctx, err)
+	}
+	release()
+	l.Close()
+}
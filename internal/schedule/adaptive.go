@@ -0,0 +1,75 @@
+package schedule
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"bislericli/internal/store"
+)
+
+var itemQtyRegex = regexp.MustCompile(`(?i)(?:qty\s*:?\s*(\d+))|(?:(\d+)\s*(?:x|×|jars?|nos?\.?|units?)\b)`)
+
+// OrderQuantity guesses the number of jars in a saved order from its item text,
+// falling back to the given default when nothing recognizable is present.
+func OrderQuantity(order store.SavedOrder, fallback int) int {
+	match := itemQtyRegex.FindStringSubmatch(order.Items)
+	if match == nil {
+		return fallback
+	}
+	for _, group := range match[1:] {
+		if n, err := strconv.Atoi(group); err == nil && n > 0 {
+			return n
+		}
+	}
+	return fallback
+}
+
+// AdaptiveQuantity estimates how many jars were consumed since the last delivery
+// from the ordering cadence in history and clamps it to [min, max]. It returns
+// the fallback quantity when history is too short to infer a consumption rate.
+func AdaptiveQuantity(orders []store.SavedOrder, now time.Time, fallback, min, max int) (int, string) {
+	var dated []store.SavedOrder
+	for _, o := range orders {
+		if !o.ParsedDate.IsZero() {
+			dated = append(dated, o)
+		}
+	}
+	if len(dated) < 2 {
+		return clamp(fallback, min, max), "not enough order history; using default quantity"
+	}
+	sort.Slice(dated, func(i, j int) bool { return dated[i].ParsedDate.Before(dated[j].ParsedDate) })
+
+	first := dated[0].ParsedDate
+	last := dated[len(dated)-1].ParsedDate
+	spanDays := last.Sub(first).Hours() / 24
+	if spanDays < 1 {
+		return clamp(fallback, min, max), "order history spans less than a day; using default quantity"
+	}
+	// Jars from every order except the latest were consumed over the span.
+	consumed := 0
+	for _, o := range dated[:len(dated)-1] {
+		consumed += OrderQuantity(o, fallback)
+	}
+	rate := float64(consumed) / spanDays
+	sinceLast := now.Sub(last).Hours() / 24
+	if sinceLast < 0 {
+		sinceLast = 0
+	}
+	estimate := int(math.Round(rate * sinceLast))
+	qty := clamp(estimate, min, max)
+	return qty, fmt.Sprintf("%.2f jars/day over %.0f days since last delivery ≈ %d", rate, sinceLast, estimate)
+}
+
+func clamp(value, min, max int) int {
+	if min > 0 && value < min {
+		value = min
+	}
+	if max > 0 && value > max {
+		value = max
+	}
+	return value
+}
@@ -0,0 +1,253 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"bislericli/internal/store"
+)
+
+const DateLayout = "2006-01-02"
+
+// Plan describes the weekdays and time of day on which scheduled orders run.
+type Plan struct {
+	Weekdays []time.Weekday
+	Hour     int
+	Minute   int
+	Blackout Blackout
+}
+
+// Blackout lists days on which no delivery should be scheduled. Runs that fall
+// on a blacked-out day shift to the next allowed day.
+type Blackout struct {
+	Weekdays []time.Weekday
+	Ranges   []DateRange
+}
+
+// DateRange is an inclusive range of dates in DateLayout form.
+type DateRange struct {
+	From string
+	To   string
+}
+
+var presets = map[string][]time.Weekday{
+	"daily":         {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday},
+	"weekly":        {time.Monday},
+	"twice-weekly":  {time.Monday, time.Thursday},
+	"thrice-weekly": {time.Monday, time.Wednesday, time.Friday},
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Parse builds a Plan from a schedule spec ("twice-weekly", "daily", "mon,thu")
+// and a time of day in 24h "HH:MM" form.
+func Parse(spec, at string) (Plan, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	if spec == "" {
+		return Plan{}, errors.New("schedule is empty")
+	}
+	var days []time.Weekday
+	if preset, ok := presets[spec]; ok {
+		days = append(days, preset...)
+	} else {
+		seen := map[time.Weekday]bool{}
+		for _, part := range strings.Split(spec, ",") {
+			part = strings.TrimSpace(part)
+			day, ok := weekdayNames[part]
+			if !ok {
+				return Plan{}, fmt.Errorf("unknown schedule %q (use daily, weekly, twice-weekly, thrice-weekly or weekdays like mon,thu)", spec)
+			}
+			if !seen[day] {
+				seen[day] = true
+				days = append(days, day)
+			}
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i] < days[j] })
+
+	hour, minute := 7, 0
+	if at = strings.TrimSpace(at); at != "" {
+		t, err := time.Parse("15:04", at)
+		if err != nil {
+			return Plan{}, fmt.Errorf("invalid schedule time %q (expected HH:MM)", at)
+		}
+		hour, minute = t.Hour(), t.Minute()
+	}
+	return Plan{Weekdays: days, Hour: hour, Minute: minute}, nil
+}
+
+// ParseBlackout builds a Blackout from weekday names and date entries, where each
+// date entry is either "YYYY-MM-DD" or an inclusive range "YYYY-MM-DD..YYYY-MM-DD".
+func ParseBlackout(weekdays, dates []string) (Blackout, error) {
+	var b Blackout
+	for _, name := range weekdays {
+		day, ok := weekdayNames[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return Blackout{}, fmt.Errorf("unknown blackout weekday %q", name)
+		}
+		b.Weekdays = append(b.Weekdays, day)
+	}
+	for _, entry := range dates {
+		from, to, found := strings.Cut(strings.TrimSpace(entry), "..")
+		if !found {
+			to = from
+		}
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		for _, d := range []string{from, to} {
+			if _, err := time.Parse(DateLayout, d); err != nil {
+				return Blackout{}, fmt.Errorf("invalid blackout date %q (expected YYYY-MM-DD or YYYY-MM-DD..YYYY-MM-DD)", entry)
+			}
+		}
+		if to < from {
+			return Blackout{}, fmt.Errorf("blackout range %q ends before it starts", entry)
+		}
+		b.Ranges = append(b.Ranges, DateRange{From: from, To: to})
+	}
+	return b, nil
+}
+
+// Contains reports whether the calendar day of t is blacked out.
+func (b Blackout) Contains(t time.Time) bool {
+	for _, day := range b.Weekdays {
+		if t.Weekday() == day {
+			return true
+		}
+	}
+	date := t.Format(DateLayout)
+	for _, r := range b.Ranges {
+		if date >= r.From && date <= r.To {
+			return true
+		}
+	}
+	return false
+}
+
+// isRunDay reports whether an order runs on day, either because it is a regular
+// run day or because an earlier run was shifted forward by a blackout.
+func (p Plan) isRunDay(day time.Time) bool {
+	if p.Blackout.Contains(day) {
+		return false
+	}
+	if p.runsOn(day.Weekday()) {
+		return true
+	}
+	prev := day.AddDate(0, 0, -1)
+	for i := 0; i < 366 && p.Blackout.Contains(prev); i++ {
+		if p.runsOn(prev.Weekday()) {
+			return true
+		}
+		prev = prev.AddDate(0, 0, -1)
+	}
+	return false
+}
+
+func (p Plan) runsOn(day time.Weekday) bool {
+	for _, d := range p.Weekdays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the first run time strictly after the given instant.
+func (p Plan) Next(after time.Time) time.Time {
+	day := time.Date(after.Year(), after.Month(), after.Day(), p.Hour, p.Minute, 0, 0, after.Location())
+	for i := 0; i < 400; i++ {
+		candidate := day.AddDate(0, 0, i)
+		if candidate.After(after) && p.isRunDay(candidate) {
+			return candidate
+		}
+	}
+	return time.Time{}
+}
+
+// Upcoming returns the next n run times after the given instant.
+func (p Plan) Upcoming(after time.Time, n int) []time.Time {
+	var runs []time.Time
+	cursor := after
+	for len(runs) < n {
+		next := p.Next(cursor)
+		if next.IsZero() {
+			break
+		}
+		runs = append(runs, next)
+		cursor = next
+	}
+	return runs
+}
+
+// Due returns the most recent run time at or before now that has not yet been
+// executed according to lastRun, if any.
+func (p Plan) Due(now, lastRun time.Time) (time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), p.Hour, p.Minute, 0, 0, now.Location())
+	if !p.isRunDay(today) || today.After(now) {
+		return time.Time{}, false
+	}
+	if !lastRun.IsZero() && !lastRun.Before(today) {
+		return time.Time{}, false
+	}
+	return today, true
+}
+
+// Held reports whether a run at t is suppressed by a skip date or pause, and why.
+func Held(state *store.ScheduleState, t time.Time) (bool, string) {
+	if state == nil {
+		return false, ""
+	}
+	if !state.PausedUntil.IsZero() && t.Before(state.PausedUntil) {
+		return true, "paused until " + state.PausedUntil.Format(DateLayout)
+	}
+	date := t.Format(DateLayout)
+	for _, skip := range state.SkipDates {
+		if skip == date {
+			return true, "skipped"
+		}
+	}
+	return false, ""
+}
+
+// AddSkip records a skip date, keeping the list sorted and free of duplicates.
+func AddSkip(state *store.ScheduleState, date string) {
+	for _, existing := range state.SkipDates {
+		if existing == date {
+			return
+		}
+	}
+	state.SkipDates = append(state.SkipDates, date)
+	sort.Strings(state.SkipDates)
+}
+
+// RemoveSkip drops a skip date, returning false if it was not recorded.
+func RemoveSkip(state *store.ScheduleState, date string) bool {
+	for i, existing := range state.SkipDates {
+		if existing == date {
+			state.SkipDates = append(state.SkipDates[:i], state.SkipDates[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// PruneSkips removes skip dates that are already in the past.
+func PruneSkips(state *store.ScheduleState, now time.Time) {
+	today := now.Format(DateLayout)
+	kept := state.SkipDates[:0]
+	for _, date := range state.SkipDates {
+		if date >= today {
+			kept = append(kept, date)
+		}
+	}
+	state.SkipDates = kept
+}
@@ -0,0 +1,92 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"bislericli/internal/store"
+)
+
+func TestParsePresetAndWeekdays(t *testing.T) {
+	plan, err := Parse("twice-weekly", "06:30")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(plan.Weekdays) != 2 || plan.Weekdays[0] != time.Monday || plan.Weekdays[1] != time.Thursday {
+		t.Fatalf("unexpected weekdays: %v", plan.Weekdays)
+	}
+	if plan.Hour != 6 || plan.Minute != 30 {
+		t.Fatalf("unexpected time: %02d:%02d", plan.Hour, plan.Minute)
+	}
+	if _, err := Parse("fortnightly", ""); err == nil {
+		t.Fatalf("expected error for unknown schedule")
+	}
+}
+
+func TestNextSkipsToFollowingWeekday(t *testing.T) {
+	plan, _ := Parse("mon,thu", "07:00")
+	// Monday 2026-10-12 08:00 is after the Monday run.
+	from := time.Date(2026, 10, 12, 8, 0, 0, 0, time.UTC)
+	next := plan.Next(from)
+	want := time.Date(2026, 10, 15, 7, 0, 0, 0, time.UTC)
+	if !next.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, next)
+	}
+}
+
+func TestHeldBySkipAndPause(t *testing.T) {
+	run := time.Date(2026, 10, 15, 7, 0, 0, 0, time.UTC)
+	state := &store.ScheduleState{}
+	AddSkip(state, "2026-10-15")
+	if held, reason := Held(state, run); !held || reason != "skipped" {
+		t.Fatalf("expected skipped, got %v %q", held, reason)
+	}
+	state = &store.ScheduleState{PausedUntil: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)}
+	if held, _ := Held(state, run); !held {
+		t.Fatalf("expected run to be held by pause")
+	}
+	if held, _ := Held(state, run.AddDate(0, 0, 7)); held {
+		t.Fatalf("expected run after pause to proceed")
+	}
+}
+
+func TestAdaptiveQuantityFromCadence(t *testing.T) {
+	base := time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC)
+	orders := []store.SavedOrder{
+		{ParsedDate: base, Items: "2 x 20L Jar"},
+		{ParsedDate: base.AddDate(0, 0, 4), Items: "2 x 20L Jar"},
+		{ParsedDate: base.AddDate(0, 0, 8), Items: "2 x 20L Jar"},
+	}
+	// 4 jars over 8 days = 0.5/day; 6 days since last delivery ≈ 3 jars.
+	qty, _ := AdaptiveQuantity(orders, base.AddDate(0, 0, 14), 2, 1, 4)
+	if qty != 3 {
+		t.Fatalf("expected 3 jars, got %d", qty)
+	}
+	qty, _ = AdaptiveQuantity(orders, base.AddDate(0, 0, 30), 2, 1, 4)
+	if qty != 4 {
+		t.Fatalf("expected quantity clamped to 4, got %d", qty)
+	}
+	qty, _ = AdaptiveQuantity(orders[:1], base, 2, 1, 4)
+	if qty != 2 {
+		t.Fatalf("expected fallback quantity 2, got %d", qty)
+	}
+}
+
+func TestBlackoutShiftsRunToNextAllowedDay(t *testing.T) {
+	plan, _ := Parse("mon,thu", "07:00")
+	blackout, err := ParseBlackout([]string{"fri"}, []string{"2026-10-15..2026-10-16"})
+	if err != nil {
+		t.Fatalf("ParseBlackout returned error: %v", err)
+	}
+	plan.Blackout = blackout
+	// Thursday 2026-10-15 is blacked out, Friday too; the run lands on Saturday.
+	from := time.Date(2026, 10, 12, 8, 0, 0, 0, time.UTC)
+	next := plan.Next(from)
+	want := time.Date(2026, 10, 17, 7, 0, 0, 0, time.UTC)
+	if !next.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, next)
+	}
+	if _, ok := plan.Due(want.Add(time.Hour), time.Time{}); !ok {
+		t.Fatalf("expected shifted run to be due")
+	}
+}
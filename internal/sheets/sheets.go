@@ -0,0 +1,271 @@
+// Package sheets writes tables to a Google Sheet using a service-account key.
+package sheets
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultEndpoint = "https://sheets.googleapis.com/v4/spreadsheets"
+	DefaultTokenURL = "https://oauth2.googleapis.com/token"
+	scope           = "https://www.googleapis.com/auth/spreadsheets"
+)
+
+// Credentials is the subset of a service-account JSON key the client needs.
+type Credentials struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// LoadCredentials reads a service-account key file downloaded from the
+// Google Cloud console.
+func LoadCredentials(path string) (Credentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Credentials{}, err
+	}
+	var creds Credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return Credentials{}, fmt.Errorf("invalid service-account key %s: %w", path, err)
+	}
+	if creds.ClientEmail == "" || creds.PrivateKey == "" {
+		return Credentials{}, fmt.Errorf("service-account key %s is missing client_email or private_key", path)
+	}
+	return creds, nil
+}
+
+// Client replaces sheet contents through the Sheets v4 REST API.
+type Client struct {
+	Endpoint string
+	HTTP     *http.Client
+
+	email    string
+	tokenURL string
+	key      *rsa.PrivateKey
+	token    string
+	expiry   time.Time
+}
+
+func NewClient(creds Credentials) (*Client, error) {
+	key, err := parsePrivateKey(creds.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	tokenURL := creds.TokenURI
+	if tokenURL == "" {
+		tokenURL = DefaultTokenURL
+	}
+	return &Client{
+		Endpoint: DefaultEndpoint,
+		HTTP:     &http.Client{Timeout: 30 * time.Second},
+		email:    creds.ClientEmail,
+		tokenURL: tokenURL,
+		key:      key,
+	}, nil
+}
+
+func parsePrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("service-account private_key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service-account private_key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("service-account private_key is not an RSA key")
+	}
+	return key, nil
+}
+
+// Replace clears the named tab and writes rows starting at A1, creating the
+// tab when the spreadsheet does not have it yet.
+func (c *Client) Replace(ctx context.Context, spreadsheetID, sheet string, rows [][]string) error {
+	if err := c.ensureSheet(ctx, spreadsheetID, sheet); err != nil {
+		return err
+	}
+	rng := url.PathEscape(quoteSheet(sheet))
+	base := c.Endpoint + "/" + url.PathEscape(spreadsheetID) + "/values/" + rng
+	if err := c.do(ctx, "POST", base+":clear", struct{}{}, nil); err != nil {
+		return err
+	}
+	body := struct {
+		Range          string     `json:"range"`
+		MajorDimension string     `json:"majorDimension"`
+		Values         [][]string `json:"values"`
+	}{quoteSheet(sheet), "ROWS", rows}
+	return c.do(ctx, "PUT", base+"?valueInputOption=USER_ENTERED", body, nil)
+}
+
+func (c *Client) ensureSheet(ctx context.Context, spreadsheetID, sheet string) error {
+	base := c.Endpoint + "/" + url.PathEscape(spreadsheetID)
+	var meta struct {
+		Sheets []struct {
+			Properties struct {
+				Title string `json:"title"`
+			} `json:"properties"`
+		} `json:"sheets"`
+	}
+	if err := c.do(ctx, "GET", base+"?fields=sheets.properties.title", nil, &meta); err != nil {
+		return err
+	}
+	for _, s := range meta.Sheets {
+		if s.Properties.Title == sheet {
+			return nil
+		}
+	}
+	add := map[string]any{
+		"requests": []any{
+			map[string]any{"addSheet": map[string]any{"properties": map[string]any{"title": sheet}}},
+		},
+	}
+	return c.do(ctx, "POST", base+":batchUpdate", add, nil)
+}
+
+// quoteSheet makes a tab name safe to use as an A1 range.
+func quoteSheet(name string) string {
+	return "'" + strings.ReplaceAll(name, "'", "''") + "'"
+}
+
+func (c *Client) do(ctx context.Context, method, endpoint string, in, out any) error {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("sheets API %s: %s", resp.Status, apiError(data))
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to parse sheets response: %w", err)
+		}
+	}
+	return nil
+}
+
+func apiError(body []byte) string {
+	var e struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		Description string `json:"error_description"`
+	}
+	if json.Unmarshal(body, &e) == nil {
+		if e.Error.Message != "" {
+			return e.Error.Message
+		}
+		if e.Description != "" {
+			return e.Description
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// accessToken exchanges a signed JWT assertion for an OAuth token, reusing it
+// until shortly before it expires.
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	now := time.Now()
+	if c.token != "" && now.Before(c.expiry) {
+		return c.token, nil
+	}
+	assertion, err := c.assertion(now)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+	req, err := http.NewRequestWithContext(ctx, "POST", c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("service-account token request failed: %s: %s", resp.Status, apiError(data))
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(data, &tok); err != nil || tok.AccessToken == "" {
+		return "", errors.New("service-account token response has no access_token")
+	}
+	c.token = tok.AccessToken
+	c.expiry = now.Add(time.Duration(tok.ExpiresIn)*time.Second - time.Minute)
+	return c.token, nil
+}
+
+func (c *Client) assertion(now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]any{
+		"iss":   c.email,
+		"scope": scope,
+		"aud":   c.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(nil, c.key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
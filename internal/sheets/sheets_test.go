@@ -0,0 +1,89 @@
+package sheets
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReplaceCreatesSheetAndWritesRows(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+
+	var calls []string
+	var written struct {
+		Values [][]string `json:"values"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			r.ParseForm()
+			parts := strings.Split(r.Form.Get("assertion"), ".")
+			if len(parts) != 3 {
+				t.Fatalf("assertion = %q", r.Form.Get("assertion"))
+			}
+			sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+			sum := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+			if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, sum[:], sig); err != nil {
+				t.Fatalf("bad assertion signature: %v", err)
+			}
+			w.Write([]byte(`{"access_token":"tok","expires_in":3600}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			t.Fatalf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		calls = append(calls, r.Method+" "+r.URL.EscapedPath())
+		switch {
+		case r.Method == "GET":
+			w.Write([]byte(`{"sheets":[{"properties":{"title":"Sheet1"}}]}`))
+		case r.Method == "PUT":
+			body, _ := io.ReadAll(r.Body)
+			json.Unmarshal(body, &written)
+			w.Write([]byte(`{}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Credentials{ClientEmail: "svc@example.iam.gserviceaccount.com", PrivateKey: keyPEM, TokenURI: srv.URL + "/token"})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	c.Endpoint = srv.URL + "/v4/spreadsheets"
+	rows := [][]string{{"Month", "Orders"}, {"Jan 2026", "3"}}
+	if err := c.Replace(context.Background(), "abc", "Monthly", rows); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+
+	want := []string{
+		"GET /v4/spreadsheets/abc",
+		"POST /v4/spreadsheets/abc:batchUpdate",
+		"POST /v4/spreadsheets/abc/values/%27Monthly%27:clear",
+		"PUT /v4/spreadsheets/abc/values/%27Monthly%27",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("calls =\n%s\nwant\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+	if len(written.Values) != 2 || written.Values[1][0] != "Jan 2026" {
+		t.Fatalf("written values = %v", written.Values)
+	}
+}
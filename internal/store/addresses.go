@@ -0,0 +1,83 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SavedAddress is a named delivery address from the account's address book.
+type SavedAddress struct {
+	Name    string  `json:"name"`
+	ID      string  `json:"id"`
+	Address Address `json:"address"`
+	// Timeslot is the preferred delivery slot for this address, e.g.
+	// "08:00 AM - 02:00 PM"; empty uses the configured default.
+	Timeslot string `json:"timeslot,omitempty"`
+}
+
+// FindAddress looks up a saved address by name, ignoring case.
+func (p Profile) FindAddress(name string) (SavedAddress, bool) {
+	for _, a := range p.Addresses {
+		if strings.EqualFold(a.Name, name) {
+			return a, true
+		}
+	}
+	return SavedAddress{}, false
+}
+
+// SetAddress adds or replaces the saved address with the same name. The
+// profile's active address follows it when it is the default.
+func (p *Profile) SetAddress(a SavedAddress) error {
+	a.Name = strings.TrimSpace(a.Name)
+	if a.Name == "" {
+		return fmt.Errorf("address name required")
+	}
+	replaced := false
+	for i := range p.Addresses {
+		if strings.EqualFold(p.Addresses[i].Name, a.Name) {
+			a.Name = p.Addresses[i].Name
+			p.Addresses[i] = a
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		p.Addresses = append(p.Addresses, a)
+	}
+	if strings.EqualFold(p.DefaultAddress, a.Name) {
+		p.useAddress(a)
+	}
+	return nil
+}
+
+// RemoveAddress deletes a saved address. Removing the default keeps the
+// active address but clears the default name.
+func (p *Profile) RemoveAddress(name string) error {
+	for i, a := range p.Addresses {
+		if strings.EqualFold(a.Name, name) {
+			p.Addresses = append(p.Addresses[:i], p.Addresses[i+1:]...)
+			if strings.EqualFold(p.DefaultAddress, name) {
+				p.DefaultAddress = ""
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no saved address named %q", name)
+}
+
+// SetDefaultAddress makes the named address the one orders use by default.
+func (p *Profile) SetDefaultAddress(name string) error {
+	a, ok := p.FindAddress(name)
+	if !ok {
+		return fmt.Errorf("no saved address named %q", name)
+	}
+	p.DefaultAddress = a.Name
+	p.useAddress(a)
+	return nil
+}
+
+func (p *Profile) useAddress(a SavedAddress) {
+	addr := a.Address
+	p.Address = &addr
+	p.AddressID = a.ID
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/fileutil"
+	"bislericli/internal/progress"
+)
+
+// AuditEntry records a single order attempt, successful or not, or a manual
+// correction of the jar inventory (Result "inventory").
+type AuditEntry struct {
+	Timestamp    time.Time   `json:"timestamp"`
+	Profile      string      `json:"profile"`
+	Quantity     int         `json:"quantity"`
+	ReturnJars   int         `json:"returnJars"`
+	Result       string      `json:"result"` // "success", "failure" or "inventory"
+	OrderID      string      `json:"orderId,omitempty"`
+	Total        string      `json:"total,omitempty"`
+	Timeslot     string      `json:"timeslot,omitempty"`
+	Items        []OrderItem `json:"items,omitempty"`
+	WalletBefore string      `json:"walletBefore,omitempty"`
+	WalletAfter  string      `json:"walletAfter,omitempty"`
+	DeliveryETA  string      `json:"deliveryEta,omitempty"`
+	Tag          string      `json:"tag,omitempty"` // order --for
+	Error        string      `json:"error,omitempty"`
+	// Timings are the order flow's stage durations, in order.
+	Timings []progress.Timing `json:"timings,omitempty"`
+	// Adjustment is "set" or "consume" for inventory entries, with the jar
+	// count set or consumed.
+	Adjustment string  `json:"adjustment,omitempty"`
+	Jars       float64 `json:"jars,omitempty"`
+	Note       string  `json:"note,omitempty"`
+}
+
+func GetAuditPath(profileName string) (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audit_"+profileName+".jsonl"), nil
+}
+
+// AppendAuditEntry appends one JSON line to the profile's audit log. Existing
+// lines are never rewritten.
+func AppendAuditEntry(profileName string, entry AuditEntry) error {
+	path, err := GetAuditPath(profileName)
+	if err != nil {
+		return err
+	}
+	unlock, err := fileutil.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	// Sync so the entry survives a shutdown right after an order.
+	return f.Sync()
+}
+
+// LoadAuditLog reads all entries from the profile's audit log, oldest first.
+// Lines that fail to decode are skipped.
+func LoadAuditLog(profileName string) ([]AuditEntry, error) {
+	path, err := GetAuditPath(profileName)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,30 @@
+package store
+
+import (
+	"path/filepath"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/fileutil"
+)
+
+// GetOrderLockPath is the lock file held while an order is being placed for
+// the profile.
+func GetOrderLockPath(profileName string) (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "order_"+profileName+".lock"), nil
+}
+
+// LockOrders makes this process the only one placing orders for the profile,
+// waiting up to wait for another to finish. It fails with
+// *fileutil.HeldError while one is still running.
+func LockOrders(profileName, command string, wait time.Duration) (func(), error) {
+	path, err := GetOrderLockPath(profileName)
+	if err != nil {
+		return nil, err
+	}
+	return fileutil.AcquireInstance(path, command, wait)
+}
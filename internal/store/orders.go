@@ -7,38 +7,38 @@ import (
 	"time"
 
 	"bislericli/internal/config"
+	"bislericli/internal/fileutil"
+	"bislericli/internal/migrate"
+	"bislericli/internal/money"
 )
 
 // Order represents a saved order, mirroring bisleri.Order but independent for storage
 type SavedOrder struct {
-	OrderID   string  `json:"orderId"`
-	Date      string  `json:"date"`      // String representation
-	ParsedDate time.Time `json:"parsedDate"` // Parsed for sorting
-	Status    string  `json:"status"`
-	Total     string  `json:"total"`     // "₹200"
-	Amount    float64 `json:"amount"`    // 200.00
-	Items     string  `json:"items"`
+	OrderID    string      `json:"orderId"`
+	Date       string      `json:"date"`       // String representation
+	ParsedDate time.Time   `json:"parsedDate"` // Parsed for sorting
+	Status     string      `json:"status"`
+	Total      string      `json:"total"`  // "₹200"
+	Amount     money.Money `json:"amount"` // 200.00
+	Items      string      `json:"items"`
+	// DeliveredAt is when the site shows the order as delivered, in local
+	// time; zero when it is not delivered or the site did not say.
+	DeliveredAt time.Time `json:"deliveredAt"`
+	// Imported marks rows loaded by 'orders import'; sync keeps them.
+	Imported bool `json:"imported,omitempty"`
 }
 
 type OrderHistory struct {
-	LastSynced time.Time    `json:"lastSynced"`
-	Orders     []SavedOrder `json:"orders"`
+	SchemaVersion int          `json:"schemaVersion"`
+	LastSynced    time.Time    `json:"lastSynced"`
+	Orders        []SavedOrder `json:"orders"`
 }
 
 func GetOrdersPath(profileName string) (string, error) {
-	// Use standard config directory
-	configDir, err := config.ConfigDir()
+	dir, err := config.DataDir()
 	if err != nil {
 		return "", err
 	}
-	
-	// Store in "data" subdirectory
-	dir := filepath.Join(configDir, "data")
-	
-	// Secure permissions (0700)
-	if err := os.MkdirAll(dir, 0700); err != nil {
-		return "", err
-	}
 	return filepath.Join(dir, "orders_"+profileName+".json"), nil
 }
 
@@ -47,21 +47,21 @@ func SaveOrderHistory(profileName string, orders []SavedOrder) error {
 	if err != nil {
 		return err
 	}
-
-	history := OrderHistory{
-		LastSynced: time.Now(),
-		Orders:     orders,
-	}
-
-	f, err := os.Create(path)
+	unlock, err := fileutil.Lock(path)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
+	defer unlock()
 
-	enc := json.NewEncoder(f)
-	enc.SetIndent("", "  ")
-	return enc.Encode(history)
+	// The site only lists recent orders; keep imported ones it does not show.
+	if previous, err := LoadOrderHistory(profileName); err == nil {
+		orders = mergeOrders(orders, importedOrders(previous.Orders))
+	}
+	return writeOrderHistory(path, OrderHistory{
+		SchemaVersion: migrate.OrderHistory.Current(),
+		LastSynced:    time.Now(),
+		Orders:        orders,
+	})
 }
 
 func LoadOrderHistory(profileName string) (*OrderHistory, error) {
@@ -70,15 +70,79 @@ func LoadOrderHistory(profileName string) (*OrderHistory, error) {
 		return nil, err
 	}
 
-	f, err := os.Open(path)
+	var history OrderHistory
+	err = fileutil.ReadWithRecovery(path, 0o600, func(data []byte) error {
+		history = OrderHistory{}
+		return migrate.OrderHistory.Decode(data, &history)
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
+	return &history, nil
+}
 
-	var history OrderHistory
-	if err := json.NewDecoder(f).Decode(&history); err != nil {
-		return nil, err
+// ImportOrders adds historical orders to the saved history, flagged as
+// imported, skipping order IDs already present. It returns how many were added.
+func ImportOrders(profileName string, orders []SavedOrder) (int, error) {
+	path, err := GetOrdersPath(profileName)
+	if err != nil {
+		return 0, err
 	}
-	return &history, nil
+	unlock, err := fileutil.Lock(path)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	history := OrderHistory{SchemaVersion: migrate.OrderHistory.Current()}
+	if previous, err := LoadOrderHistory(profileName); err == nil {
+		history = *previous
+		history.SchemaVersion = migrate.OrderHistory.Current()
+	} else if !os.IsNotExist(err) {
+		return 0, err
+	}
+	for i := range orders {
+		orders[i].Imported = true
+	}
+	before := len(history.Orders)
+	history.Orders = mergeOrders(history.Orders, orders)
+	added := len(history.Orders) - before
+	if added == 0 {
+		return 0, nil
+	}
+	return added, writeOrderHistory(path, history)
+}
+
+// mergeOrders appends the extra orders whose IDs are not already in orders.
+func mergeOrders(orders, extra []SavedOrder) []SavedOrder {
+	seen := make(map[string]bool, len(orders))
+	for _, o := range orders {
+		seen[o.OrderID] = true
+	}
+	for _, o := range extra {
+		if seen[o.OrderID] {
+			continue
+		}
+		seen[o.OrderID] = true
+		orders = append(orders, o)
+	}
+	return orders
+}
+
+func importedOrders(orders []SavedOrder) []SavedOrder {
+	var imported []SavedOrder
+	for _, o := range orders {
+		if o.Imported {
+			imported = append(imported, o)
+		}
+	}
+	return imported
+}
+
+func writeOrderHistory(path string, history OrderHistory) error {
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteWithBackup(path, append(data, '\n'), 0o600)
 }
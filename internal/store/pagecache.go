@@ -0,0 +1,137 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/fileutil"
+)
+
+// maxCachedPages bounds the page cache; the oldest pages are dropped first.
+const maxCachedPages = 20
+
+// CachedPage is a page body with the validators needed to revalidate it.
+type CachedPage struct {
+	Body         string    `json:"body"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+	// FreshUntil is when the page must be revalidated before reuse.
+	FreshUntil time.Time `json:"freshUntil"`
+}
+
+// PageCache holds a profile's rarely-changing pages keyed by URL. Pages can
+// be personal (the account page), so each profile has its own file. It is a
+// cache: a missing or corrupt file starts empty and save errors are ignored.
+type PageCache struct {
+	path  string
+	mu    sync.Mutex
+	pages map[string]CachedPage
+}
+
+func GetPageCachePath(profileName string) (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pages_"+profileName+".json"), nil
+}
+
+// OpenPageCache loads the page cache for a profile.
+func OpenPageCache(profileName string) *PageCache {
+	cache := &PageCache{pages: map[string]CachedPage{}}
+	path, err := GetPageCachePath(profileName)
+	if err != nil {
+		return cache
+	}
+	cache.path = path
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &cache.pages)
+	}
+	if cache.pages == nil {
+		cache.pages = map[string]CachedPage{}
+	}
+	return cache
+}
+
+func (c *PageCache) Get(url string) (CachedPage, bool) {
+	if c == nil {
+		return CachedPage{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	page, ok := c.pages[url]
+	return page, ok
+}
+
+func (c *PageCache) Put(url string, page CachedPage) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pages[url] = page
+	c.prune()
+	c.save()
+}
+
+// Delete drops a page, e.g. after a change that makes it stale.
+func (c *PageCache) Delete(url string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.pages[url]; !ok {
+		return
+	}
+	delete(c.pages, url)
+	c.save()
+}
+
+func (c *PageCache) prune() {
+	if len(c.pages) <= maxCachedPages {
+		return
+	}
+	urls := make([]string, 0, len(c.pages))
+	for url := range c.pages {
+		urls = append(urls, url)
+	}
+	sort.Slice(urls, func(i, j int) bool { return c.pages[urls[i]].FetchedAt.Before(c.pages[urls[j]].FetchedAt) })
+	for _, url := range urls[:len(urls)-maxCachedPages] {
+		delete(c.pages, url)
+	}
+}
+
+func (c *PageCache) save() {
+	if c.path == "" {
+		return
+	}
+	data, err := json.Marshal(c.pages)
+	if err != nil {
+		return
+	}
+	unlock, err := fileutil.Lock(c.path)
+	if err != nil {
+		return
+	}
+	defer unlock()
+	_ = fileutil.WriteAtomic(c.path, data, 0o600)
+}
+
+// ClearPageCache removes a profile's cached pages, e.g. on logout.
+func ClearPageCache(profileName string) error {
+	path, err := GetPageCachePath(profileName)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
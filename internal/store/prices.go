@@ -0,0 +1,80 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/fileutil"
+	"bislericli/internal/money"
+)
+
+// PriceObservation records the per-jar price seen at checkout when it was
+// first seen or differed from the previous observation.
+type PriceObservation struct {
+	Timestamp time.Time   `json:"timestamp"`
+	City      string      `json:"city,omitempty"`
+	OrderID   string      `json:"orderId,omitempty"`
+	UnitPrice money.Money `json:"unitPrice"`
+	Previous  money.Money `json:"previous,omitempty"`
+}
+
+func GetPriceHistoryPath(profileName string) (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "prices_"+profileName+".jsonl"), nil
+}
+
+// AppendPriceObservation appends one JSON line to the profile's price history.
+func AppendPriceObservation(profileName string, obs PriceObservation) error {
+	path, err := GetPriceHistoryPath(profileName)
+	if err != nil {
+		return err
+	}
+	unlock, err := fileutil.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	data, err := json.Marshal(obs)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// LoadPriceHistory reads the profile's price history, oldest first. Lines
+// that fail to decode are skipped.
+func LoadPriceHistory(profileName string) ([]PriceObservation, error) {
+	path, err := GetPriceHistoryPath(profileName)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var history []PriceObservation
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var obs PriceObservation
+		if err := json.Unmarshal(scanner.Bytes(), &obs); err != nil {
+			continue
+		}
+		history = append(history, obs)
+	}
+	return history, scanner.Err()
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"bislericli/internal/config"
+	"bislericli/internal/fileutil"
+)
+
+// CachedProduct is a product ID resolved by catalog discovery.
+type CachedProduct struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	ResolvedAt time.Time `json:"resolvedAt"`
+}
+
+// ProductCache maps a lower-cased city (or "" when unknown) to the jar
+// product the catalog listed there. It is a cache: a corrupt file is ignored.
+type ProductCache struct {
+	Cities map[string]CachedProduct `json:"cities"`
+}
+
+func GetProductCachePath() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "products.json"), nil
+}
+
+// CachedJarProduct returns the jar product discovered for city, if any.
+func CachedJarProduct(city string) (CachedProduct, bool) {
+	path, err := GetProductCachePath()
+	if err != nil {
+		return CachedProduct{}, false
+	}
+	cache, err := loadProductCache(path)
+	if err != nil {
+		return CachedProduct{}, false
+	}
+	p, ok := cache.Cities[strings.ToLower(strings.TrimSpace(city))]
+	return p, ok && p.ID != ""
+}
+
+// SaveJarProduct records the jar product discovered for city.
+func SaveJarProduct(city string, product CachedProduct) error {
+	path, err := GetProductCachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	unlock, err := fileutil.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	cache, err := loadProductCache(path)
+	if err != nil {
+		cache = ProductCache{}
+	}
+	if cache.Cities == nil {
+		cache.Cities = map[string]CachedProduct{}
+	}
+	cache.Cities[strings.ToLower(strings.TrimSpace(city))] = product
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteAtomic(path, data, 0o600)
+}
+
+func loadProductCache(path string) (ProductCache, error) {
+	var cache ProductCache
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache, err
+	}
+	err = json.Unmarshal(data, &cache)
+	return cache, err
+}
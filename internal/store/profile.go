@@ -5,6 +5,9 @@ import (
 	"errors"
 	"os"
 	"time"
+
+	"bislericli/internal/fileutil"
+	"bislericli/internal/migrate"
 )
 
 type Cookie struct {
@@ -35,42 +38,126 @@ type Address struct {
 }
 
 type OrderInfo struct {
-	OrderID    string    `json:"orderId"`
-	PlacedAt   time.Time `json:"placedAt"`
-	TotalPrice string    `json:"totalPrice"`
+	OrderID    string      `json:"orderId"`
+	PlacedAt   time.Time   `json:"placedAt"`
+	TotalPrice string      `json:"totalPrice"`
+	Quantity   int         `json:"quantity,omitempty"`
+	ReturnJars int         `json:"returnJars,omitempty"`
+	Timeslot   string      `json:"timeslot,omitempty"`
+	Items      []OrderItem `json:"items,omitempty"`
+	Tag        string      `json:"tag,omitempty"`
+	// DeliveryETA is the delivery window promised on the confirmation page.
+	DeliveryETA string `json:"deliveryEta,omitempty"`
+}
+
+// OrderItem is one cart line of a placed order.
+type OrderItem struct {
+	ProductID string `json:"productId"`
+	Quantity  int    `json:"quantity"`
+}
+
+type ScheduleState struct {
+	SkipDates   []string  `json:"skipDates,omitempty"`
+	PausedUntil time.Time `json:"pausedUntil"`
+	LastRun     time.Time `json:"lastRun"`
+	LastDigest  time.Time `json:"lastDigest"`
 }
 
 type Profile struct {
-	Name          string     `json:"name"`
-	Cookies       []Cookie   `json:"cookies"`
-	AddressID     string     `json:"addressId"`
-	Address       *Address   `json:"address,omitempty"`
-	PreferredCity string     `json:"preferredCity,omitempty"`
-	PhoneNumber   string     `json:"phoneNumber,omitempty"`
-	LastLogin     time.Time  `json:"lastLogin"`
-	LastOrder     *OrderInfo `json:"lastOrder,omitempty"`
-	AddressSource string     `json:"addressSource,omitempty"`
+	SchemaVersion int            `json:"schemaVersion"`
+	Name          string         `json:"name"`
+	Cookies       []Cookie       `json:"cookies"`
+	AddressID     string         `json:"addressId"`
+	Address       *Address       `json:"address,omitempty"`
+	PreferredCity string         `json:"preferredCity,omitempty"`
+	PhoneNumber   string         `json:"phoneNumber,omitempty"`
+	Email         string         `json:"email,omitempty"`
+	LastLogin     time.Time      `json:"lastLogin"`
+	LastOrder     *OrderInfo     `json:"lastOrder,omitempty"`
+	AddressSource string         `json:"addressSource,omitempty"`
+	Schedule      *ScheduleState `json:"schedule,omitempty"`
+	// Addresses are named alternatives to Address; DefaultAddress names the
+	// one Address/AddressID currently mirror.
+	Addresses      []SavedAddress `json:"addresses,omitempty"`
+	DefaultAddress string         `json:"defaultAddress,omitempty"`
+	// OTPSends records recent OTP requests so the login cooldown survives
+	// between runs.
+	OTPSends []OTPSend `json:"otpSends,omitempty"`
+}
+
+// OTPSend is one OTP request made for a phone number.
+type OTPSend struct {
+	Phone string    `json:"phone"`
+	At    time.Time `json:"at"`
 }
 
 func LoadProfile(path string) (Profile, error) {
-	data, err := os.ReadFile(path)
+	var profile Profile
+	err := fileutil.ReadWithRecovery(path, 0o600, func(data []byte) error {
+		profile = Profile{}
+		if err := migrate.Profile.Decode(data, &profile); err != nil {
+			return err
+		}
+		if profile.Name == "" {
+			return errors.New("profile is missing name")
+		}
+		return nil
+	})
 	if err != nil {
 		return Profile{}, err
 	}
-	var profile Profile
-	if err := json.Unmarshal(data, &profile); err != nil {
+	return profile, nil
+}
+
+func SaveProfile(path string, profile Profile) error {
+	unlock, err := fileutil.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return writeProfile(path, profile)
+}
+
+// CreateProfile saves an empty profile named name at path unless another
+// process created one first, and returns the profile on disk.
+func CreateProfile(path, name string) (Profile, error) {
+	unlock, err := fileutil.Lock(path)
+	if err != nil {
 		return Profile{}, err
 	}
-	if profile.Name == "" {
-		return Profile{}, errors.New("profile is missing name")
+	defer unlock()
+	profile, err := LoadProfile(path)
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return profile, err
 	}
-	return profile, nil
+	profile = Profile{Name: name}
+	return profile, writeProfile(path, profile)
 }
 
-func SaveProfile(path string, profile Profile) error {
+// UpdateProfile re-reads the profile under lock, applies fn and saves it, so
+// concurrent runs (scheduler and a manual order) don't clobber each other's
+// fields such as freshly captured cookies.
+func UpdateProfile(path string, fn func(*Profile) error) (Profile, error) {
+	unlock, err := fileutil.Lock(path)
+	if err != nil {
+		return Profile{}, err
+	}
+	defer unlock()
+	profile, err := LoadProfile(path)
+	if err != nil {
+		return Profile{}, err
+	}
+	if err := fn(&profile); err != nil {
+		return Profile{}, err
+	}
+	return profile, writeProfile(path, profile)
+}
+
+func writeProfile(path string, profile Profile) error {
+	profile.SchemaVersion = migrate.Profile.Current()
 	data, err := json.MarshalIndent(profile, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0o600)
+	return fileutil.WriteWithBackup(path, data, 0o600)
 }
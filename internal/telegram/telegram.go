@@ -0,0 +1,129 @@
+// Package telegram is a minimal Telegram Bot API client: long-polling for
+// messages and sending replies, which is all bot mode needs.
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultEndpoint = "https://api.telegram.org"
+
+// Update is one incoming event; bot mode only reads messages.
+type Update struct {
+	ID      int64    `json:"update_id"`
+	Message *Message `json:"message"`
+}
+
+type Message struct {
+	Chat Chat   `json:"chat"`
+	From *User  `json:"from"`
+	Text string `json:"text"`
+}
+
+type Chat struct {
+	ID int64 `json:"id"`
+}
+
+type User struct {
+	FirstName string `json:"first_name"`
+	Username  string `json:"username"`
+}
+
+// Client calls the Bot API with one bot's token.
+type Client struct {
+	Endpoint string
+	HTTP     *http.Client
+	token    string
+}
+
+func NewClient(token string) *Client {
+	return &Client{Endpoint: DefaultEndpoint, HTTP: &http.Client{Timeout: 90 * time.Second}, token: token}
+}
+
+// Updates waits up to wait for updates after offset (the last update ID
+// seen plus one).
+func (c *Client) Updates(ctx context.Context, offset int64, wait time.Duration) ([]Update, error) {
+	q := url.Values{}
+	q.Set("offset", strconv.FormatInt(offset, 10))
+	q.Set("timeout", strconv.Itoa(int(wait.Seconds())))
+	q.Set("allowed_updates", `["message"]`)
+	var updates []Update
+	err := c.call(ctx, "getUpdates?"+q.Encode(), nil, &updates)
+	return updates, err
+}
+
+// Send posts a plain-text message to a chat.
+func (c *Client) Send(ctx context.Context, chatID int64, text string) error {
+	body, err := json.Marshal(map[string]any{"chat_id": chatID, "text": text})
+	if err != nil {
+		return err
+	}
+	return c.call(ctx, "sendMessage", body, nil)
+}
+
+func (c *Client) call(ctx context.Context, method string, body []byte, result any) error {
+	httpMethod := http.MethodGet
+	var reqBody io.Reader
+	if body != nil {
+		httpMethod = http.MethodPost
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, httpMethod, strings.TrimRight(c.Endpoint, "/")+"/bot"+c.token+"/"+method, reqBody)
+	if err != nil {
+		return errors.New("invalid Telegram endpoint")
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		// The URL holds the bot token; report the cause only.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("telegram: %w", urlErr.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return err
+	}
+	var envelope struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return fmt.Errorf("telegram returned %s", resp.Status)
+	}
+	if !envelope.OK {
+		return fmt.Errorf("telegram: %s", envelope.Description)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(envelope.Result, result)
+}
+
+// ParseCommand splits a message like "/order@family_bot 2" into its
+// lower-cased command ("order") and arguments. ok is false for messages
+// that are not commands.
+func ParseCommand(text string) (cmd string, args []string, ok bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return "", nil, false
+	}
+	cmd, _, _ = strings.Cut(strings.TrimPrefix(fields[0], "/"), "@")
+	return strings.ToLower(cmd), fields[1:], cmd != ""
+}
@@ -0,0 +1,74 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient(t *testing.T) {
+	var sent map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/botT0KEN/getUpdates":
+			if r.URL.Query().Get("offset") != "7" || r.URL.Query().Get("timeout") != "30" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"ok":true,"result":[{"update_id":7,"message":{"chat":{"id":42},"from":{"first_name":"Asha"},"text":"/order 2"}}]}`))
+		case "/botT0KEN/sendMessage":
+			json.NewDecoder(r.Body).Decode(&sent)
+			w.Write([]byte(`{"ok":true,"result":{}}`))
+		default:
+			w.Write([]byte(`{"ok":false,"description":"Not Found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient("T0KEN")
+	c.Endpoint = srv.URL
+	updates, err := c.Updates(context.Background(), 7, 30*time.Second)
+	if err != nil {
+		t.Fatalf("Updates: %v", err)
+	}
+	if len(updates) != 1 || updates[0].Message == nil || updates[0].Message.Chat.ID != 42 || updates[0].Message.Text != "/order 2" {
+		t.Fatalf("updates = %+v", updates)
+	}
+	if err := c.Send(context.Background(), 42, "Queued"); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if sent["chat_id"] != float64(42) || sent["text"] != "Queued" {
+		t.Errorf("sent = %v", sent)
+	}
+
+	bad := NewClient("WRONG")
+	bad.Endpoint = srv.URL
+	if err := bad.Send(context.Background(), 42, "x"); err == nil || !strings.Contains(err.Error(), "Not Found") {
+		t.Errorf("Send with a bad token: %v", err)
+	}
+	bad.Endpoint = "http://127.0.0.1:1"
+	if err := bad.Send(context.Background(), 42, "x"); err == nil || strings.Contains(err.Error(), "WRONG") {
+		t.Errorf("network error leaks the token or is nil: %v", err)
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	for _, tc := range []struct {
+		text, cmd, args string
+		ok              bool
+	}{
+		{"/order 2", "order", "2", true},
+		{"/Order@family_bot 3", "order", "3", true},
+		{"/status", "status", "", true},
+		{"hello", "", "", false},
+		{"/", "", "", false},
+	} {
+		cmd, args, ok := ParseCommand(tc.text)
+		if cmd != tc.cmd || strings.Join(args, " ") != tc.args || ok != tc.ok {
+			t.Errorf("ParseCommand(%q) = %q, %q, %v", tc.text, cmd, args, ok)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package update
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Repo          = "maheshrijal/bislericli"
+	binaryName    = "bislericli"
+	checksumsName = "checksums.txt"
+	maxDownload   = 100 << 20
+)
+
+var releasesURL = "https://api.github.com/repos/" + Repo + "/releases/latest"
+
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+type Release struct {
+	TagName string  `json:"tag_name"`
+	HTMLURL string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Version returns the release version without the leading "v".
+func (r Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+func (r Release) asset(name string) (Asset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+var httpClient = &http.Client{Timeout: 2 * time.Minute}
+
+// LatestRelease fetches metadata for the most recent published release.
+func LatestRelease(ctx context.Context) (Release, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", releasesURL, nil)
+	if err != nil {
+		return Release{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", binaryName)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return Release{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return Release{}, fmt.Errorf("release check failed: %s", resp.Status)
+	}
+	var release Release
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&release); err != nil {
+		return Release{}, fmt.Errorf("failed to parse release metadata: %w", err)
+	}
+	if release.TagName == "" {
+		return Release{}, errors.New("release metadata missing tag")
+	}
+	return release, nil
+}
+
+// Newer reports whether latest is a higher version than current. Development
+// builds ("dev" or unparsable versions) are never considered up to date.
+func Newer(current, latest string) bool {
+	cur, okCur := parseVersion(current)
+	lat, okLat := parseVersion(latest)
+	if !okLat {
+		return false
+	}
+	if !okCur {
+		return true
+	}
+	for i := range cur {
+		if lat[i] != cur[i] {
+			return lat[i] > cur[i]
+		}
+	}
+	return false
+}
+
+func parseVersion(v string) ([3]int, bool) {
+	var out [3]int
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if idx := strings.IndexAny(v, "-+"); idx >= 0 {
+		v = v[:idx]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return out, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return out, false
+		}
+		out[i] = n
+	}
+	return out, true
+}
+
+// ArchiveName mirrors the goreleaser name template for this platform.
+func ArchiveName(version string) string {
+	return fmt.Sprintf("%s_%s_%s_%s.tar.gz", binaryName, version, runtime.GOOS, runtime.GOARCH)
+}
+
+// Apply downloads the release archive for this platform, verifies it against
+// the published SHA-256 checksums, and atomically replaces the binary at exePath.
+func Apply(ctx context.Context, release Release, exePath string) error {
+	archiveName := ArchiveName(release.Version())
+	archive, ok := release.asset(archiveName)
+	if !ok {
+		return fmt.Errorf("release %s has no build for %s/%s", release.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	sums, ok := release.asset(checksumsName)
+	if !ok {
+		return fmt.Errorf("release %s has no %s; refusing to install unverified binary", release.TagName, checksumsName)
+	}
+
+	sumsData, err := download(ctx, sums.URL)
+	if err != nil {
+		return fmt.Errorf("failed to download checksums: %w", err)
+	}
+	want, err := checksumFor(sumsData, archiveName)
+	if err != nil {
+		return err
+	}
+	archiveData, err := download(ctx, archive.URL)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", archiveName, err)
+	}
+	got := sha256.Sum256(archiveData)
+	if hex.EncodeToString(got[:]) != want {
+		return fmt.Errorf("checksum mismatch for %s", archiveName)
+	}
+
+	binary, err := extractBinary(archiveData)
+	if err != nil {
+		return err
+	}
+	return replaceExecutable(exePath, binary)
+}
+
+func download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", binaryName)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("download failed: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownload+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxDownload {
+		return nil, errors.New("download exceeds size limit")
+	}
+	return data, nil
+}
+
+func checksumFor(sums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum listed for %s", name)
+}
+
+func extractBinary(archive []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag == tar.TypeReg && filepath.Base(hdr.Name) == binaryName {
+			return io.ReadAll(io.LimitReader(tr, maxDownload))
+		}
+	}
+	return nil, fmt.Errorf("%s not found in release archive", binaryName)
+}
+
+// replaceExecutable writes the new binary beside the current one and renames it
+// into place so a failed write never leaves a truncated executable.
+func replaceExecutable(exePath string, binary []byte) error {
+	resolved, err := filepath.EvalSymlinks(exePath)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(resolved), "."+binaryName+"-update-*")
+	if err != nil {
+		return fmt.Errorf("cannot write next to %s (try with sudo or reinstall): %w", resolved, err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, info.Mode().Perm()|0o111); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, resolved)
+}
+
+// ManagedByHomebrew reports whether the executable lives inside a Homebrew
+// Cellar, in which case brew should perform upgrades.
+func ManagedByHomebrew(exePath string) bool {
+	resolved, err := filepath.EvalSymlinks(exePath)
+	if err != nil {
+		resolved = exePath
+	}
+	return strings.Contains(resolved, string(filepath.Separator)+"Cellar"+string(filepath.Separator))
+}
+
+type checkCache struct {
+	CheckedAt time.Time `json:"checkedAt"`
+	TagName   string    `json:"tagName"`
+}
+
+// CachedLatestVersion returns the latest release version, consulting the
+// cache file first and only hitting the network when it is older than ttl.
+func CachedLatestVersion(ctx context.Context, cachePath string, ttl time.Duration) (string, error) {
+	if data, err := os.ReadFile(cachePath); err == nil {
+		var cached checkCache
+		if json.Unmarshal(data, &cached) == nil && cached.TagName != "" && time.Since(cached.CheckedAt) < ttl {
+			return strings.TrimPrefix(cached.TagName, "v"), nil
+		}
+	}
+	release, err := LatestRelease(ctx)
+	if err != nil {
+		return "", err
+	}
+	if data, err := json.Marshal(checkCache{CheckedAt: time.Now(), TagName: release.TagName}); err == nil {
+		_ = os.WriteFile(cachePath, data, 0o600)
+	}
+	return release.Version(), nil
+}
@@ -0,0 +1,34 @@
+package update
+
+import "testing"
+
+func TestNewer(t *testing.T) {
+	cases := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"0.3.0", "0.4.0", true},
+		{"v0.4.0", "0.4.0", false},
+		{"0.4.1", "0.4.0", false},
+		{"0.9.9", "1.0.0", true},
+		{"dev", "0.1.0", true},
+		{"0.1.0", "garbage", false},
+		{"1.2.0-rc1", "1.2.0", false},
+	}
+	for _, tc := range cases {
+		if got := Newer(tc.current, tc.latest); got != tc.want {
+			t.Errorf("Newer(%q, %q) = %v; want %v", tc.current, tc.latest, got, tc.want)
+		}
+	}
+}
+
+func TestChecksumFor(t *testing.T) {
+	sums := []byte("abc123  bislericli_1.0.0_linux_amd64.tar.gz\ndef456  bislericli_1.0.0_darwin_arm64.tar.gz\n")
+	got, err := checksumFor(sums, "bislericli_1.0.0_darwin_arm64.tar.gz")
+	if err != nil || got != "def456" {
+		t.Fatalf("checksumFor = %q, %v", got, err)
+	}
+	if _, err := checksumFor(sums, "missing.tar.gz"); err == nil {
+		t.Fatalf("expected error for missing archive")
+	}
+}
@@ -0,0 +1,113 @@
+// Package weather reads daily maximum temperatures from an Open-Meteo
+// compatible forecast API, so scheduled orders can allow for heat waves.
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const DefaultEndpoint = "https://api.open-meteo.com/v1/forecast"
+
+// Day is one day's forecast maximum, in °C.
+type Day struct {
+	Date time.Time
+	MaxC float64
+}
+
+// Client fetches forecasts. The default endpoint needs no API key.
+type Client struct {
+	Endpoint string
+	HTTP     *http.Client
+}
+
+func NewClient(endpoint string) *Client {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	return &Client{Endpoint: endpoint, HTTP: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// Forecast returns the daily maximum for the next days days, starting today
+// in the location's own time zone.
+func (c *Client) Forecast(ctx context.Context, latitude, longitude string, days int) ([]Day, error) {
+	if _, err := strconv.ParseFloat(latitude, 64); err != nil {
+		return nil, fmt.Errorf("invalid latitude %q", latitude)
+	}
+	if _, err := strconv.ParseFloat(longitude, 64); err != nil {
+		return nil, fmt.Errorf("invalid longitude %q", longitude)
+	}
+	u, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("latitude", latitude)
+	q.Set("longitude", longitude)
+	q.Set("daily", "temperature_2m_max")
+	q.Set("timezone", "auto")
+	q.Set("forecast_days", strconv.Itoa(days))
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("weather service returned %s", resp.Status)
+	}
+	return parseForecast(body)
+}
+
+func parseForecast(body []byte) ([]Day, error) {
+	var doc struct {
+		Daily struct {
+			Time []string   `json:"time"`
+			Max  []*float64 `json:"temperature_2m_max"`
+		} `json:"daily"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("weather response: %w", err)
+	}
+	if len(doc.Daily.Time) == 0 || len(doc.Daily.Time) != len(doc.Daily.Max) {
+		return nil, errors.New("weather response has no daily maximums")
+	}
+	var days []Day
+	for i, raw := range doc.Daily.Time {
+		date, err := time.Parse("2006-01-02", raw)
+		if err != nil || doc.Daily.Max[i] == nil {
+			continue
+		}
+		days = append(days, Day{Date: date, MaxC: *doc.Daily.Max[i]})
+	}
+	return days, nil
+}
+
+// Hottest returns the day with the highest maximum; ok is false for no days.
+func Hottest(days []Day) (Day, bool) {
+	if len(days) == 0 {
+		return Day{}, false
+	}
+	hottest := days[0]
+	for _, d := range days[1:] {
+		if d.MaxC > hottest.MaxC {
+			hottest = d
+		}
+	}
+	return hottest, true
+}
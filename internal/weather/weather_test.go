@@ -0,0 +1,34 @@
+package weather
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestForecast(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("latitude") != "28.61" || q.Get("daily") != "temperature_2m_max" || q.Get("forecast_days") != "3" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"daily":{"time":["2026-05-20","2026-05-21","2026-05-22"],"temperature_2m_max":[41.2,44.5,null]}}`))
+	}))
+	defer srv.Close()
+
+	days, err := NewClient(srv.URL).Forecast(context.Background(), "28.61", "77.21", 3)
+	if err != nil {
+		t.Fatalf("Forecast: %v", err)
+	}
+	if len(days) != 2 {
+		t.Fatalf("days = %+v, want the two with a maximum", days)
+	}
+	hottest, ok := Hottest(days)
+	if !ok || hottest.MaxC != 44.5 || hottest.Date.Day() != 21 {
+		t.Errorf("Hottest = %+v, %v", hottest, ok)
+	}
+	if _, err := NewClient(srv.URL).Forecast(context.Background(), "north", "77.21", 3); err == nil {
+		t.Error("Forecast accepted an invalid latitude")
+	}
+}
@@ -0,0 +1,141 @@
+// Package webhook authenticates inbound order webhooks for serve mode. Each
+// source signs "<unix timestamp>.<body>" with its own HMAC-SHA256 secret; a
+// request is accepted only with a fresh timestamp, a valid signature that
+// has not been seen before, and while the source is under its rate limit.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Request headers a webhook sender sets.
+const (
+	HeaderSource    = "X-Bisleri-Source"
+	HeaderTimestamp = "X-Bisleri-Timestamp"
+	HeaderSignature = "X-Bisleri-Signature"
+)
+
+const signaturePrefix = "sha256="
+
+var (
+	ErrUnknownSource = errors.New("unknown webhook source")
+	ErrBadSignature  = errors.New("invalid webhook signature")
+	ErrStale         = errors.New("webhook timestamp outside the allowed window")
+	ErrReplayed      = errors.New("webhook already received")
+	ErrRateLimited   = errors.New("webhook rate limit reached for source")
+)
+
+// Sign returns the signature header value for body sent at ts.
+func Sign(secret string, ts time.Time, body []byte) string {
+	return signaturePrefix + hex.EncodeToString(mac(secret, strconv.FormatInt(ts.Unix(), 10), body))
+}
+
+func mac(secret, ts string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// Verifier checks webhooks against the sources' secrets and remembers what it
+// accepted, for replay and rate-limit checks, for the life of the server.
+type Verifier struct {
+	secrets map[string]string
+	maxAge  time.Duration
+	limit   int
+	window  time.Duration
+	// Now is the clock; tests replace it.
+	Now func() time.Time
+
+	mu       sync.Mutex
+	seen     map[string]time.Time
+	accepted map[string][]time.Time
+}
+
+// NewVerifier accepts timestamps up to maxAge from now in either direction
+// and at most limit webhooks per source within window (0 for no limit).
+func NewVerifier(secrets map[string]string, maxAge time.Duration, limit int, window time.Duration) *Verifier {
+	return &Verifier{
+		secrets:  secrets,
+		maxAge:   maxAge,
+		limit:    limit,
+		window:   window,
+		Now:      time.Now,
+		seen:     map[string]time.Time{},
+		accepted: map[string][]time.Time{},
+	}
+}
+
+// Verify authenticates a webhook and returns its source. A signature is
+// accepted once; sending the same request again fails with ErrReplayed.
+func (v *Verifier) Verify(h http.Header, body []byte) (string, error) {
+	source := strings.TrimSpace(h.Get(HeaderSource))
+	secret, ok := v.secrets[source]
+	if !ok || secret == "" {
+		return "", fmt.Errorf("%w %q", ErrUnknownSource, source)
+	}
+	rawTS := strings.TrimSpace(h.Get(HeaderTimestamp))
+	unix, err := strconv.ParseInt(rawTS, 10, 64)
+	if err != nil {
+		return source, fmt.Errorf("%w: bad %s", ErrStale, HeaderTimestamp)
+	}
+	now := v.Now()
+	if age := now.Sub(time.Unix(unix, 0)); age > v.maxAge || age < -v.maxAge {
+		return source, ErrStale
+	}
+	sig := strings.TrimSpace(h.Get(HeaderSignature))
+	got, err := hex.DecodeString(strings.TrimPrefix(sig, signaturePrefix))
+	if err != nil || !strings.HasPrefix(sig, signaturePrefix) || !hmac.Equal(got, mac(secret, rawTS, body)) {
+		return source, ErrBadSignature
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for key, at := range v.seen {
+		// Anything older is rejected as stale, so it need not be kept.
+		if now.Sub(at) > 2*v.maxAge {
+			delete(v.seen, key)
+		}
+	}
+	// Key on the decoded MAC: hex decoding ignores case, so the header text
+	// of a replay can differ from the original while still verifying.
+	key := source + ":" + hex.EncodeToString(got)
+	if _, dup := v.seen[key]; dup {
+		return source, ErrReplayed
+	}
+	v.seen[key] = now
+	return source, nil
+}
+
+// Allow counts one accepted webhook against the source's rate limit, or
+// fails with ErrRateLimited without counting it.
+func (v *Verifier) Allow(source string) error {
+	if v.limit <= 0 {
+		return nil
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	now := v.Now()
+	recent := v.accepted[source][:0]
+	for _, at := range v.accepted[source] {
+		if now.Sub(at) < v.window {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) >= v.limit {
+		v.accepted[source] = recent
+		return fmt.Errorf("%w %q (%d per %s)", ErrRateLimited, source, v.limit, v.window)
+	}
+	v.accepted[source] = append(recent, now)
+	return nil
+}
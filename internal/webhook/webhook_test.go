@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const secret = "0123456789abcdef0123"
+
+func signed(source, key string, ts time.Time, body []byte) http.Header {
+	h := http.Header{}
+	h.Set(HeaderSource, source)
+	h.Set(HeaderTimestamp, strconv.FormatInt(ts.Unix(), 10))
+	h.Set(HeaderSignature, Sign(key, ts, body))
+	return h
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	v := NewVerifier(map[string]string{"ha": secret}, 5*time.Minute, 0, 0)
+	v.Now = func() time.Time { return now }
+	body := []byte(`{"qty":2}`)
+
+	if source, err := v.Verify(signed("ha", secret, now.Add(-time.Minute), body), body); err != nil || source != "ha" {
+		t.Fatalf("Verify = %q, %v; want ha", source, err)
+	}
+	if _, err := v.Verify(signed("ha", secret, now.Add(-time.Minute), body), body); !errors.Is(err, ErrReplayed) {
+		t.Errorf("second delivery = %v, want ErrReplayed", err)
+	}
+	recased := signed("ha", secret, now.Add(-time.Minute), body)
+	sig := recased.Get(HeaderSignature)
+	recased.Set(HeaderSignature, signaturePrefix+strings.ToUpper(strings.TrimPrefix(sig, signaturePrefix)))
+	if _, err := v.Verify(recased, body); !errors.Is(err, ErrReplayed) {
+		t.Errorf("delivery with re-cased signature = %v, want ErrReplayed", err)
+	}
+	for name, tc := range map[string]struct {
+		header http.Header
+		body   []byte
+		want   error
+	}{
+		"unknown source": {signed("other", secret, now, body), body, ErrUnknownSource},
+		"wrong secret":   {signed("ha", "not-the-secret-at-all", now, body), body, ErrBadSignature},
+		"altered body":   {signed("ha", secret, now, body), []byte(`{"qty":20}`), ErrBadSignature},
+		"stale":          {signed("ha", secret, now.Add(-6*time.Minute), body), body, ErrStale},
+		"future":         {signed("ha", secret, now.Add(6*time.Minute), body), body, ErrStale},
+	} {
+		if _, err := v.Verify(tc.header, tc.body); !errors.Is(err, tc.want) {
+			t.Errorf("%s: Verify = %v, want %v", name, err, tc.want)
+		}
+	}
+
+	h := signed("ha", secret, now, body)
+	h.Set(HeaderTimestamp, strconv.FormatInt(now.Add(time.Second).Unix(), 10))
+	if _, err := v.Verify(h, body); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("timestamp changed after signing = %v, want ErrBadSignature", err)
+	}
+}
+
+func TestAllow(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	v := NewVerifier(nil, time.Minute, 2, 24*time.Hour)
+	v.Now = func() time.Time { return now }
+	for i := 0; i < 2; i++ {
+		if err := v.Allow("ha"); err != nil {
+			t.Fatalf("Allow %d: %v", i, err)
+		}
+	}
+	if err := v.Allow("ha"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("third Allow = %v, want ErrRateLimited", err)
+	}
+	if err := v.Allow("shortcuts"); err != nil {
+		t.Errorf("another source was limited: %v", err)
+	}
+	now = now.Add(24*time.Hour + time.Second)
+	if err := v.Allow("ha"); err != nil {
+		t.Errorf("Allow after the window: %v", err)
+	}
+}
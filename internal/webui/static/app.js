@@ -0,0 +1,147 @@
+"use strict";
+
+const tokenKey = "bislericli.token";
+const $ = (id) => document.getElementById(id);
+
+function token() {
+  return localStorage.getItem(tokenKey) || "";
+}
+
+async function api(method, path, body) {
+  const res = await fetch(path, {
+    method,
+    headers: {
+      "Authorization": "Bearer " + token(),
+      "Content-Type": "application/json",
+    },
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  const data = await res.json().catch(() => ({}));
+  if (!res.ok) {
+    const err = new Error(data.error || res.statusText);
+    err.status = res.status;
+    throw err;
+  }
+  return data;
+}
+
+function showLogin(message) {
+  $("dashboard").hidden = true;
+  $("login").hidden = false;
+  $("login-error").textContent = message || "";
+}
+
+async function load() {
+  if (!token()) {
+    showLogin();
+    return;
+  }
+  let status;
+  try {
+    status = await api("GET", "/api/status");
+  } catch (err) {
+    if (err.status === 401 || err.status === 403) {
+      showLogin("That token was not accepted.");
+    } else {
+      showLogin("Could not load status: " + err.message);
+    }
+    return;
+  }
+  $("login").hidden = true;
+  $("dashboard").hidden = false;
+  $("profile").textContent = status.profile;
+  $("wallet").textContent = status.wallet ? status.wallet.balance : "–";
+  $("wallet-as-of").textContent = status.wallet
+    ? "as of " + new Date(status.wallet.asOf).toLocaleString()
+    : "Seen only when an order is placed";
+  $("jars").textContent = status.jarsRemaining === null ? "–" : "≈" + status.jarsRemaining;
+  $("next-run").textContent = status.nextRun || "None";
+  $("last-order").textContent = status.lastOrder || "None";
+
+  const button = $("order");
+  button.disabled = !status.canOrder;
+  button.textContent = "Order now (" + status.orderQuantity + " jars)";
+  $("order-note").textContent = status.canOrder
+    ? ""
+    : "This token can only view. Ask for one that can order.";
+  drawChart(status.history || []);
+}
+
+function drawChart(months) {
+  const svg = $("chart");
+  svg.replaceChildren();
+  const width = 600, height = 200, pad = 20;
+  svg.setAttribute("viewBox", `0 0 ${width} ${height}`);
+  if (months.length === 0) {
+    svg.appendChild(text(width / 2, height / 2, "No synced orders yet"));
+    return;
+  }
+  const max = Math.max(...months.map((m) => m.total), 1);
+  const slot = width / months.length;
+  months.forEach((m, i) => {
+    const h = (m.total / max) * (height - 3 * pad);
+    const x = i * slot + slot * 0.15;
+    const rect = document.createElementNS("http://www.w3.org/2000/svg", "rect");
+    rect.setAttribute("x", x);
+    rect.setAttribute("y", height - pad - h);
+    rect.setAttribute("width", slot * 0.7);
+    rect.setAttribute("height", h);
+    const title = document.createElementNS("http://www.w3.org/2000/svg", "title");
+    title.textContent = `${m.month}: ₹${m.total} (${m.orders} orders)`;
+    rect.appendChild(title);
+    svg.appendChild(rect);
+    svg.appendChild(text(x + slot * 0.35, height - 5, m.month.slice(0, 3)));
+    svg.appendChild(text(x + slot * 0.35, height - pad - h - 4, "₹" + Math.round(m.total)));
+  });
+}
+
+function text(x, y, content) {
+  const t = document.createElementNS("http://www.w3.org/2000/svg", "text");
+  t.setAttribute("x", x);
+  t.setAttribute("y", y);
+  t.textContent = content;
+  return t;
+}
+
+async function order() {
+  if (!confirm("Place an order for water jars now?")) {
+    return;
+  }
+  const status = $("order-status");
+  $("order").disabled = true;
+  status.textContent = "Sending…";
+  try {
+    const queued = await api("POST", "/api/order", {});
+    status.textContent = "Placing the order…";
+    const job = await waitForJob(queued.job);
+    status.textContent = job.status === "succeeded"
+      ? "Order placed."
+      : "The order failed: " + (job.error || job.status);
+  } catch (err) {
+    status.textContent = "The order was not placed: " + err.message;
+  }
+  await load();
+}
+
+async function waitForJob(id) {
+  for (;;) {
+    const job = await api("GET", "/api/jobs/" + encodeURIComponent(id));
+    if (job.status !== "queued" && job.status !== "running") {
+      return job;
+    }
+    await new Promise((resolve) => setTimeout(resolve, 3000));
+  }
+}
+
+$("login").addEventListener("submit", (e) => {
+  e.preventDefault();
+  localStorage.setItem(tokenKey, $("token").value.trim());
+  $("token").value = "";
+  load();
+});
+$("logout").addEventListener("click", () => {
+  localStorage.removeItem(tokenKey);
+  showLogin();
+});
+$("order").addEventListener("click", order);
+load();